@@ -68,6 +68,7 @@ func SetupHandlers(r *mux.Router) *mux.Router {
 	r.HandleFunc("/config-check", getConfigCheck).Methods("GET")
 	r.HandleFunc("/config", settingshttp.Server.GetFull("")).Methods("GET")
 	r.HandleFunc("/config/list-runtime", settingshttp.Server.ListConfigurable).Methods("GET")
+	r.HandleFunc("/config/origins", settingshttp.Server.ListOrigins("")).Methods("GET")
 	r.HandleFunc("/config/{setting}", settingshttp.Server.GetValue).Methods("GET")
 	r.HandleFunc("/config/{setting}", settingshttp.Server.SetValue).Methods("POST")
 	r.HandleFunc("/tagger-list", getTaggerList).Methods("GET")
@@ -119,6 +120,12 @@ func makeFlare(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	flareProfile, err := flare.GetProfile(r.URL.Query().Get("profile"))
+	if err != nil {
+		http.Error(w, log.Errorf("Invalid flare profile: %s", err).Error(), 400)
+		return
+	}
+
 	// Reset the `server_timeout` deadline for this connection as creating a flare can take some time
 	conn := GetConnection(r)
 	_ = conn.SetDeadline(time.Time{})
@@ -131,8 +138,8 @@ func makeFlare(w http.ResponseWriter, r *http.Request) {
 	if jmxLogFile == "" {
 		jmxLogFile = common.DefaultJmxLogFile
 	}
-	log.Infof("Making a flare")
-	filePath, err := flare.CreateArchive(false, common.GetDistPath(), common.PyChecksPath, []string{logFile, jmxLogFile}, profile, nil)
+	log.Infof("Making a flare with the %q profile", flareProfile.Name)
+	filePath, err := flare.CreateArchive(false, common.GetDistPath(), common.PyChecksPath, []string{logFile, jmxLogFile}, profile, nil, flareProfile)
 	if err != nil || filePath == "" {
 		if err != nil {
 			log.Errorf("The flare failed to be created: %s", err)
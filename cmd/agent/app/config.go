@@ -21,7 +21,9 @@ import (
 )
 
 func init() {
-	AgentCmd.AddCommand(cmdconfig.Config(getSettingsClient))
+	configCmd := cmdconfig.Config(getSettingsClient)
+	configCmd.AddCommand(configLintCommand)
+	AgentCmd.AddCommand(configCmd)
 }
 
 func setupConfig() error {
@@ -69,6 +71,21 @@ func initRuntimeSettings() error {
 	if err := commonsettings.RegisterRuntimeSetting(settings.DsdCaptureDurationRuntimeSetting("dogstatsd_capture_duration")); err != nil {
 		return err
 	}
+	if err := commonsettings.RegisterRuntimeSetting(settings.DsdMetricBlocklistRuntimeSetting("statsd_metric_blocklist")); err != nil {
+		return err
+	}
+	if err := commonsettings.RegisterRuntimeSetting(settings.DsdMapperProfilesRuntimeSetting("dogstatsd_mapper_profiles")); err != nil {
+		return err
+	}
+	if err := commonsettings.RegisterRuntimeSetting(settings.LogsProcessingRulesRuntimeSetting("logs_config.processing_rules")); err != nil {
+		return err
+	}
+	if err := commonsettings.RegisterRuntimeSetting(settings.ForwarderAdditionalEndpointsRuntimeSetting("additional_endpoints")); err != nil {
+		return err
+	}
+	if err := commonsettings.RegisterRuntimeSetting(settings.HistogramAggregatesRuntimeSetting("histogram_aggregates")); err != nil {
+		return err
+	}
 	if err := commonsettings.RegisterRuntimeSetting(commonsettings.LogPayloadsRuntimeSetting{}); err != nil {
 		return err
 	}
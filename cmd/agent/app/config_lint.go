@@ -0,0 +1,59 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/DataDog/datadog-agent/cmd/agent/common"
+	"github.com/DataDog/datadog-agent/pkg/config"
+	"github.com/DataDog/datadog-agent/pkg/config/lint"
+)
+
+var lintFailOnWarnings bool
+
+func init() {
+	configLintCommand.Flags().BoolVarP(&lintFailOnWarnings, "fail-on-warnings", "w", false, "exit with an error when warnings are found")
+}
+
+var configLintCommand = &cobra.Command{
+	Use:   "lint",
+	Short: "Validate the agent configuration and the check configurations without starting the agent",
+	Long: `Load datadog.yaml and every check configuration found in conf.d without starting
+anything, and print the issues found as JSON. The command exits with an error when
+at least one error (or warning, with --fail-on-warnings) is found.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		err := common.SetupConfigWithoutSecrets(confFilePath, "")
+		if err != nil {
+			return fmt.Errorf("unable to set up global agent configuration: %v", err)
+		}
+
+		err = config.SetupLogger(loggerName, config.GetEnvDefault("DD_LOG_LEVEL", "off"), "", "", false, true, false)
+		if err != nil {
+			fmt.Printf("Cannot setup logger, exiting: %v\n", err)
+			return err
+		}
+
+		report := lint.NewReport()
+		report.Add(lint.AgentConfig(config.Datadog)...)
+		report.Add(lint.CheckConfigs(common.GetConfSearchPaths(config.Datadog.GetString("confd_path")))...)
+
+		out, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(out))
+
+		if report.HasErrors() || (lintFailOnWarnings && report.Warnings > 0) {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("configuration lint found %d error(s) and %d warning(s)", report.Errors, report.Warnings)
+		}
+		return nil
+	},
+}
@@ -9,6 +9,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"net/url"
 	"os"
 
 	"github.com/fatih/color"
@@ -31,6 +32,8 @@ var (
 	profileMutexFraction int
 	profileBlocking      bool
 	profileBlockingRate  int
+	flareProfile         string
+	flareDryRun          bool
 )
 
 func init() {
@@ -44,6 +47,8 @@ func init() {
 	flareCmd.Flags().IntVarP(&profileMutexFraction, "profile-mutex-fraction", "", 100, "Set the fraction of mutex contention events that are reported in the mutex profile")
 	flareCmd.Flags().BoolVarP(&profileBlocking, "profile-blocking", "B", false, "Add gorouting blocking profile to the performance data in the flare")
 	flareCmd.Flags().IntVarP(&profileBlockingRate, "profile-blocking-rate", "", 10000, "Set the fraction of goroutine blocking events that are reported in the blocking profile")
+	flareCmd.Flags().StringVarP(&flareProfile, "flare-profile", "", "", "Select the optional sections of the flare with a built-in (full, minimal) or configured profile, defaults to the flare_profile setting")
+	flareCmd.Flags().BoolVarP(&flareDryRun, "dry-run", "", false, "Create the flare and print the files it contains along with the redactions applied, without sending it")
 	flareCmd.SetArgs([]string{"caseID"})
 }
 
@@ -74,7 +79,7 @@ var flareCmd = &cobra.Command{
 			caseID = args[0]
 		}
 
-		if customerEmail == "" {
+		if customerEmail == "" && !flareDryRun {
 			var err error
 			customerEmail, err = input.AskForEmail()
 			if err != nil {
@@ -171,7 +176,16 @@ func makeFlare(caseID string) error {
 		profile flare.ProfileData
 		err     error
 	)
-	if profiling >= 30 {
+
+	selectedProfile, err := flare.GetProfile(flareProfile)
+	if err != nil {
+		fmt.Fprintln(color.Output, color.RedString(fmt.Sprintf("Invalid flare profile: %s", err)))
+		return err
+	}
+
+	if profiling >= 30 && !selectedProfile.Includes(flare.SectionProfiles) {
+		fmt.Fprintln(color.Output, color.YellowString(fmt.Sprintf("The %q flare profile doesn't include performance profiles, skipping their collection.", selectedProfile.Name)))
+	} else if profiling >= 30 {
 		if err := readProfileData(&profile, profiling, flare.CreatePerformanceProfile); err != nil {
 			fmt.Fprintln(color.Output, color.YellowString(fmt.Sprintf("Could not collect performance profile data: %s", err)))
 		}
@@ -182,9 +196,9 @@ func makeFlare(caseID string) error {
 
 	var filePath string
 	if forceLocal {
-		filePath, err = createArchive(logFiles, profile, nil)
+		filePath, err = createArchive(logFiles, profile, nil, selectedProfile)
 	} else {
-		filePath, err = requestArchive(logFiles, profile)
+		filePath, err = requestArchive(logFiles, profile, selectedProfile)
 	}
 
 	if err != nil {
@@ -197,6 +211,10 @@ func makeFlare(caseID string) error {
 		return err
 	}
 
+	if flareDryRun {
+		return printFlareManifest(filePath)
+	}
+
 	fmt.Fprintln(color.Output, fmt.Sprintf("%s is going to be uploaded to Datadog", color.YellowString(filePath)))
 	if !autoconfirm {
 		confirmation := input.AskForConfirmation("Are you sure you want to upload a flare? [y/N]")
@@ -214,23 +232,39 @@ func makeFlare(caseID string) error {
 	return nil
 }
 
-func requestArchive(logFiles []string, pdata flare.ProfileData) (string, error) {
+// printFlareManifest prints the files contained in the flare along with the
+// redactions applied, and removes the flare as it isn't meant to be sent
+func printFlareManifest(filePath string) error {
+	defer os.Remove(filePath)
+
+	manifest, err := flare.ReadManifest(filePath)
+	if err != nil {
+		fmt.Fprintln(color.Output, color.RedString(fmt.Sprintf("Could not read the flare manifest: %s", err)))
+		return err
+	}
+
+	fmt.Fprintln(color.Output, color.BlueString("Dry run: the flare below has not been sent and was deleted."))
+	flare.PrintManifest(color.Output, manifest)
+	return nil
+}
+
+func requestArchive(logFiles []string, pdata flare.ProfileData, flareProfile flare.Profile) (string, error) {
 	fmt.Fprintln(color.Output, color.BlueString("Asking the agent to build the flare archive."))
 	var e error
 	c := util.GetClient(false) // FIX: get certificates right then make this true
 	ipcAddress, err := config.GetIPCAddress()
 	if err != nil {
 		fmt.Fprintln(color.Output, color.RedString(fmt.Sprintf("Error getting IPC address for the agent: %s", err)))
-		return createArchive(logFiles, pdata, err)
+		return createArchive(logFiles, pdata, err, flareProfile)
 	}
 
-	urlstr := fmt.Sprintf("https://%v:%v/agent/flare", ipcAddress, config.Datadog.GetInt("cmd_port"))
+	urlstr := fmt.Sprintf("https://%v:%v/agent/flare?profile=%s", ipcAddress, config.Datadog.GetInt("cmd_port"), url.QueryEscape(flareProfile.Name))
 
 	// Set session token
 	e = util.SetAuthToken()
 	if e != nil {
 		fmt.Fprintln(color.Output, color.RedString(fmt.Sprintf("Error: %s", e)))
-		return createArchive(logFiles, pdata, e)
+		return createArchive(logFiles, pdata, e, flareProfile)
 	}
 
 	p, err := json.Marshal(pdata)
@@ -248,14 +282,14 @@ func requestArchive(logFiles []string, pdata flare.ProfileData) (string, error)
 			fmt.Fprintln(color.Output, color.RedString("The agent was unable to make the flare. (is it running?)"))
 			e = fmt.Errorf("Error getting flare from running agent: %w", e)
 		}
-		return createArchive(logFiles, pdata, e)
+		return createArchive(logFiles, pdata, e, flareProfile)
 	}
 	return string(r), nil
 }
 
-func createArchive(logFiles []string, pdata flare.ProfileData, ipcError error) (string, error) {
+func createArchive(logFiles []string, pdata flare.ProfileData, ipcError error, flareProfile flare.Profile) (string, error) {
 	fmt.Fprintln(color.Output, color.YellowString("Initiating flare locally."))
-	filePath, e := flare.CreateArchive(true, common.GetDistPath(), common.PyChecksPath, logFiles, pdata, ipcError)
+	filePath, e := flare.CreateArchive(true, common.GetDistPath(), common.PyChecksPath, logFiles, pdata, ipcError, flareProfile)
 	if e != nil {
 		fmt.Printf("The flare zipfile failed to be created: %s\n", e)
 		return "", e
@@ -0,0 +1,59 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package settings
+
+import (
+	"fmt"
+
+	"github.com/DataDog/datadog-agent/cmd/agent/common"
+	"github.com/DataDog/datadog-agent/pkg/config"
+	"github.com/DataDog/datadog-agent/pkg/config/settings"
+	"github.com/DataDog/datadog-agent/pkg/dogstatsd"
+)
+
+// DsdMapperProfilesRuntimeSetting wraps operations to change the dogstatsd mapper profiles at runtime.
+type DsdMapperProfilesRuntimeSetting string
+
+// Description returns the runtime setting's description
+func (s DsdMapperProfilesRuntimeSetting) Description() string {
+	return "Set the dogstatsd mapper profiles. Possible values: a JSON array of mapper profiles"
+}
+
+// Hidden returns whether or not this setting is hidden from the list of runtime settings
+func (s DsdMapperProfilesRuntimeSetting) Hidden() bool {
+	return false
+}
+
+// Name returns the name of the runtime setting
+func (s DsdMapperProfilesRuntimeSetting) Name() string {
+	return string(s)
+}
+
+// Get returns the current value of the runtime setting
+func (s DsdMapperProfilesRuntimeSetting) Get() (interface{}, error) {
+	return config.GetDogstatsdMappingProfiles()
+}
+
+// Set changes the value of the runtime setting. The current profiles are kept
+// if the new ones are invalid.
+func (s DsdMapperProfilesRuntimeSetting) Set(v interface{}) error {
+	var profiles []config.MappingProfile
+	if err := settings.UnmarshalValue(v, &profiles); err != nil {
+		return fmt.Errorf("DsdMapperProfilesRuntimeSetting: %v", err)
+	}
+	if err := dogstatsd.ValidateMappingProfiles(profiles); err != nil {
+		return fmt.Errorf("DsdMapperProfilesRuntimeSetting: %v", err)
+	}
+
+	if common.DSD != nil {
+		if err := common.DSD.SetMappingProfiles(profiles); err != nil {
+			return fmt.Errorf("DsdMapperProfilesRuntimeSetting: %v", err)
+		}
+	}
+
+	config.Datadog.Set("dogstatsd_mapper_profiles", profiles)
+	return nil
+}
@@ -0,0 +1,52 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package settings
+
+import (
+	"fmt"
+
+	"github.com/DataDog/datadog-agent/cmd/agent/common"
+	"github.com/DataDog/datadog-agent/pkg/config"
+	"github.com/DataDog/datadog-agent/pkg/config/settings"
+)
+
+// DsdMetricBlocklistRuntimeSetting wraps operations to change the list of metrics dropped by dogstatsd at runtime.
+type DsdMetricBlocklistRuntimeSetting string
+
+// Description returns the runtime setting's description
+func (s DsdMetricBlocklistRuntimeSetting) Description() string {
+	return "Set the list of metric names dropped by dogstatsd. Possible values: a JSON array or a comma separated list of metric names"
+}
+
+// Hidden returns whether or not this setting is hidden from the list of runtime settings
+func (s DsdMetricBlocklistRuntimeSetting) Hidden() bool {
+	return false
+}
+
+// Name returns the name of the runtime setting
+func (s DsdMetricBlocklistRuntimeSetting) Name() string {
+	return string(s)
+}
+
+// Get returns the current value of the runtime setting
+func (s DsdMetricBlocklistRuntimeSetting) Get() (interface{}, error) {
+	return config.Datadog.GetStringSlice("statsd_metric_blocklist"), nil
+}
+
+// Set changes the value of the runtime setting
+func (s DsdMetricBlocklistRuntimeSetting) Set(v interface{}) error {
+	blocklist, err := settings.GetStringSlice(v)
+	if err != nil {
+		return fmt.Errorf("DsdMetricBlocklistRuntimeSetting: %v", err)
+	}
+
+	if common.DSD != nil {
+		common.DSD.SetMetricBlocklist(blocklist)
+	}
+
+	config.Datadog.Set("statsd_metric_blocklist", blocklist)
+	return nil
+}
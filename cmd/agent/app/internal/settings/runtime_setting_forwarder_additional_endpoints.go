@@ -0,0 +1,69 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package settings
+
+import (
+	"fmt"
+
+	"github.com/DataDog/datadog-agent/pkg/aggregator"
+	"github.com/DataDog/datadog-agent/pkg/config"
+	"github.com/DataDog/datadog-agent/pkg/config/settings"
+	"github.com/DataDog/datadog-agent/pkg/util/scrubber"
+)
+
+// ForwarderAdditionalEndpointsRuntimeSetting wraps operations to change the additional endpoints of the forwarder at runtime.
+type ForwarderAdditionalEndpointsRuntimeSetting string
+
+// Description returns the runtime setting's description
+func (s ForwarderAdditionalEndpointsRuntimeSetting) Description() string {
+	return "Set the additional endpoints the metrics are sent to. Possible values: a JSON object mapping each endpoint to its list of API keys"
+}
+
+// Hidden returns whether or not this setting is hidden from the list of runtime settings
+func (s ForwarderAdditionalEndpointsRuntimeSetting) Hidden() bool {
+	return false
+}
+
+// Name returns the name of the runtime setting
+func (s ForwarderAdditionalEndpointsRuntimeSetting) Name() string {
+	return string(s)
+}
+
+// Get returns the current value of the runtime setting, with the API keys masked
+func (s ForwarderAdditionalEndpointsRuntimeSetting) Get() (interface{}, error) {
+	additionalEndpoints := config.Datadog.GetStringMapStringSlice("additional_endpoints")
+	masked := make(map[string][]string, len(additionalEndpoints))
+	for domain, keys := range additionalEndpoints {
+		maskedKeys := make([]string, 0, len(keys))
+		for _, key := range keys {
+			maskedKeys = append(maskedKeys, scrubber.HideKeyExceptLastFiveChars(key))
+		}
+		masked[domain] = maskedKeys
+	}
+	return masked, nil
+}
+
+// Set changes the value of the runtime setting. The previous endpoints are
+// restored if the forwarder can't be updated.
+func (s ForwarderAdditionalEndpointsRuntimeSetting) Set(v interface{}) error {
+	var additionalEndpoints map[string][]string
+	if err := settings.UnmarshalValue(v, &additionalEndpoints); err != nil {
+		return fmt.Errorf("ForwarderAdditionalEndpointsRuntimeSetting: %v", err)
+	}
+
+	previous := config.Datadog.GetStringMapStringSlice("additional_endpoints")
+	config.Datadog.Set("additional_endpoints", additionalEndpoints)
+
+	keysPerDomain, err := config.GetMultipleEndpoints()
+	if err == nil {
+		err = aggregator.SetForwarderKeysPerDomain(keysPerDomain)
+	}
+	if err != nil {
+		config.Datadog.Set("additional_endpoints", previous)
+		return fmt.Errorf("ForwarderAdditionalEndpointsRuntimeSetting: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package settings
+
+import (
+	"fmt"
+
+	"github.com/DataDog/datadog-agent/pkg/config"
+	"github.com/DataDog/datadog-agent/pkg/config/settings"
+	"github.com/DataDog/datadog-agent/pkg/metrics"
+)
+
+// HistogramAggregatesRuntimeSetting wraps operations to change the aggregates computed by histograms at runtime.
+type HistogramAggregatesRuntimeSetting string
+
+// Description returns the runtime setting's description
+func (s HistogramAggregatesRuntimeSetting) Description() string {
+	return "Set the aggregates computed by the new histogram contexts. Possible values: a comma separated list of max, min, median, avg, sum, count"
+}
+
+// Hidden returns whether or not this setting is hidden from the list of runtime settings
+func (s HistogramAggregatesRuntimeSetting) Hidden() bool {
+	return false
+}
+
+// Name returns the name of the runtime setting
+func (s HistogramAggregatesRuntimeSetting) Name() string {
+	return string(s)
+}
+
+// Get returns the current value of the runtime setting
+func (s HistogramAggregatesRuntimeSetting) Get() (interface{}, error) {
+	return config.Datadog.GetStringSlice("histogram_aggregates"), nil
+}
+
+// Set changes the value of the runtime setting
+func (s HistogramAggregatesRuntimeSetting) Set(v interface{}) error {
+	aggregates, err := settings.GetStringSlice(v)
+	if err != nil {
+		return fmt.Errorf("HistogramAggregatesRuntimeSetting: %v", err)
+	}
+
+	if err := metrics.SetDefaultHistogramAggregates(aggregates); err != nil {
+		return fmt.Errorf("HistogramAggregatesRuntimeSetting: %v", err)
+	}
+
+	config.Datadog.Set("histogram_aggregates", aggregates)
+	return nil
+}
@@ -0,0 +1,54 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package settings
+
+import (
+	"fmt"
+
+	"github.com/DataDog/datadog-agent/pkg/config"
+	"github.com/DataDog/datadog-agent/pkg/config/settings"
+	"github.com/DataDog/datadog-agent/pkg/logs"
+	logsconfig "github.com/DataDog/datadog-agent/pkg/logs/config"
+)
+
+// LogsProcessingRulesRuntimeSetting wraps operations to change the global logs processing rules at runtime.
+type LogsProcessingRulesRuntimeSetting string
+
+// Description returns the runtime setting's description
+func (s LogsProcessingRulesRuntimeSetting) Description() string {
+	return "Set the global logs processing rules, multi_line rules excepted. Possible values: a JSON array of processing rules"
+}
+
+// Hidden returns whether or not this setting is hidden from the list of runtime settings
+func (s LogsProcessingRulesRuntimeSetting) Hidden() bool {
+	return false
+}
+
+// Name returns the name of the runtime setting
+func (s LogsProcessingRulesRuntimeSetting) Name() string {
+	return string(s)
+}
+
+// Get returns the current value of the runtime setting
+func (s LogsProcessingRulesRuntimeSetting) Get() (interface{}, error) {
+	return logsconfig.GlobalProcessingRules()
+}
+
+// Set changes the value of the runtime setting. The current rules are kept
+// if the new ones are invalid.
+func (s LogsProcessingRulesRuntimeSetting) Set(v interface{}) error {
+	var rules []*logsconfig.ProcessingRule
+	if err := settings.UnmarshalValue(v, &rules); err != nil {
+		return fmt.Errorf("LogsProcessingRulesRuntimeSetting: %v", err)
+	}
+
+	if err := logs.SetGlobalProcessingRules(rules); err != nil {
+		return fmt.Errorf("LogsProcessingRulesRuntimeSetting: %v", err)
+	}
+
+	config.Datadog.Set("logs_config.processing_rules", rules)
+	return nil
+}
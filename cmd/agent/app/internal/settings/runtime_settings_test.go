@@ -13,7 +13,10 @@ import (
 
 	"github.com/DataDog/datadog-agent/cmd/agent/common"
 	"github.com/DataDog/datadog-agent/pkg/aggregator"
+	"github.com/DataDog/datadog-agent/pkg/config"
 	"github.com/DataDog/datadog-agent/pkg/dogstatsd"
+	logsconfig "github.com/DataDog/datadog-agent/pkg/logs/config"
+	"github.com/DataDog/datadog-agent/pkg/metrics"
 )
 
 func TestDogstatsdMetricsStats(t *testing.T) {
@@ -73,3 +76,125 @@ func TestDogstatsdMetricsStats(t *testing.T) {
 	assert.Nil(err)
 	assert.Equal(v, true)
 }
+
+func setupDogstatsd(t *testing.T) {
+	// the server of a previous test could still be listening
+	if common.DSD != nil {
+		common.DSD.Stop()
+	}
+
+	var err error
+	opts := aggregator.DefaultAgentDemultiplexerOptions(nil)
+	opts.DontStartForwarders = true
+	demux := aggregator.InitAndStartAgentDemultiplexer(opts, "hostname")
+	common.DSD, err = dogstatsd.NewServer(demux, false)
+	require.Nil(t, err)
+}
+
+func TestDogstatsdMetricBlocklist(t *testing.T) {
+	setupDogstatsd(t)
+	defer config.Datadog.Set("statsd_metric_blocklist", []string{})
+
+	s := DsdMetricBlocklistRuntimeSetting("statsd_metric_blocklist")
+
+	err := s.Set(`["foo", "bar"]`)
+	assert.Nil(t, err)
+	v, err := s.Get()
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"foo", "bar"}, v)
+
+	err = s.Set("foo")
+	assert.Nil(t, err)
+	v, err = s.Get()
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"foo"}, v)
+
+	assert.NotNil(t, s.Set(42))
+}
+
+func TestDogstatsdMapperProfiles(t *testing.T) {
+	setupDogstatsd(t)
+	defer config.Datadog.Set("dogstatsd_mapper_profiles", nil)
+
+	s := DsdMapperProfilesRuntimeSetting("dogstatsd_mapper_profiles")
+
+	err := s.Set(`[{"name": "test", "prefix": "test.", "mappings": [{"match": "test.*", "name": "test.job", "tags": {"job": "$1"}}]}]`)
+	require.Nil(t, err)
+	v, err := s.Get()
+	assert.Nil(t, err)
+	require.Len(t, v, 1)
+	assert.Equal(t, "test", v.([]config.MappingProfile)[0].Name)
+
+	// an invalid profile (no prefix) is rejected and the current ones are kept
+	err = s.Set(`[{"name": "invalid", "mappings": [{"match": "test.*", "name": "test.job"}]}]`)
+	assert.NotNil(t, err)
+	v, err = s.Get()
+	assert.Nil(t, err)
+	require.Len(t, v, 1)
+	assert.Equal(t, "test", v.([]config.MappingProfile)[0].Name)
+}
+
+func TestLogsProcessingRules(t *testing.T) {
+	defer config.Datadog.Set("logs_config.processing_rules", nil)
+
+	s := LogsProcessingRulesRuntimeSetting("logs_config.processing_rules")
+
+	err := s.Set(`[{"type": "exclude_at_match", "name": "exclude_healthchecks", "pattern": "healthcheck"}]`)
+	require.Nil(t, err)
+	v, err := s.Get()
+	assert.Nil(t, err)
+	rules := v.([]*logsconfig.ProcessingRule)
+	require.Len(t, rules, 1)
+	assert.Equal(t, "exclude_healthchecks", rules[0].Name)
+
+	// invalid and multi_line rules are rejected
+	assert.NotNil(t, s.Set(`[{"type": "exclude_at_match", "name": "broken", "pattern": "("}]`))
+	assert.NotNil(t, s.Set(`[{"type": "multi_line", "name": "multi", "pattern": "\\d+"}]`))
+	v, err = s.Get()
+	assert.Nil(t, err)
+	require.Len(t, v, 1)
+}
+
+func TestHistogramAggregates(t *testing.T) {
+	previous := config.Datadog.GetStringSlice("histogram_aggregates")
+	defer func() {
+		config.Datadog.Set("histogram_aggregates", previous)
+		metrics.SetDefaultHistogramAggregates(previous) //nolint:errcheck
+	}()
+
+	s := HistogramAggregatesRuntimeSetting("histogram_aggregates")
+
+	err := s.Set("max,sum")
+	require.Nil(t, err)
+	v, err := s.Get()
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"max", "sum"}, v)
+
+	assert.NotNil(t, s.Set("max,p95"))
+	v, err = s.Get()
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"max", "sum"}, v)
+}
+
+func TestForwarderAdditionalEndpointsRollback(t *testing.T) {
+	opts := aggregator.DefaultAgentDemultiplexerOptions(nil)
+	opts.UseNoopForwarder = true
+	opts.DontStartForwarders = true
+	aggregator.InitAndStartAgentDemultiplexer(opts, "hostname")
+
+	config.Datadog.Set("additional_endpoints", map[string][]string{"https://app.datadoghq.eu": {"abcdefabcdefabcdefabcdefabcdefab"}})
+	defer config.Datadog.Set("additional_endpoints", nil)
+
+	s := ForwarderAdditionalEndpointsRuntimeSetting("additional_endpoints")
+
+	// the noop forwarder can't be updated, the previous endpoints are restored
+	assert.NotNil(t, s.Set(`{"https://app.datadoghq.com": ["other-key"]}`))
+	assert.Equal(t, []string{"abcdefabcdefabcdefabcdefabcdefab"}, config.Datadog.GetStringMapStringSlice("additional_endpoints")["https://app.datadoghq.eu"])
+
+	// the API keys are masked
+	v, err := s.Get()
+	assert.Nil(t, err)
+	assert.Equal(t, map[string][]string{"https://app.datadoghq.eu": {"***************************defab"}}, v)
+
+	assert.NotNil(t, s.Set(`["not", "a", "map"]`))
+}
@@ -38,6 +38,7 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/dogstatsd"
 	"github.com/DataDog/datadog-agent/pkg/forwarder"
 	"github.com/DataDog/datadog-agent/pkg/logs"
+	"github.com/DataDog/datadog-agent/pkg/logs/message"
 	"github.com/DataDog/datadog-agent/pkg/metadata"
 	"github.com/DataDog/datadog-agent/pkg/metadata/host"
 	"github.com/DataDog/datadog-agent/pkg/metadata/inventories"
@@ -376,19 +377,6 @@ func StartAgent() error {
 		telemetry.RegisterStatsSender(sender)
 	}
 
-	// Start OTLP intake
-	otlpEnabled := otlp.IsEnabled(config.Datadog)
-	inventories.SetAgentMetadata(inventories.AgentOTLPEnabled, otlpEnabled)
-	if otlpEnabled {
-		var err error
-		common.OTLP, err = otlp.BuildAndStart(common.MainCtx, config.Datadog, demux.Serializer())
-		if err != nil {
-			log.Errorf("Could not start OTLP: %s", err)
-		} else {
-			log.Debug("OTLP pipeline started")
-		}
-	}
-
 	// Start SNMP trap server
 	if traps.IsEnabled() {
 		err = traps.StartServer(hostnameDetected, demux)
@@ -425,17 +413,35 @@ func StartAgent() error {
 	}
 
 	// start logs-agent.  This must happen after AutoConfig is set up (via common.LoadComponents)
+	var logsAgent *logs.Agent
 	if config.Datadog.GetBool("logs_enabled") || config.Datadog.GetBool("log_enabled") {
 		if config.Datadog.GetBool("log_enabled") {
 			log.Warn(`"log_enabled" is deprecated, use "logs_enabled" instead`)
 		}
-		if _, err := logs.Start(common.AC); err != nil {
+		if logsAgent, err = logs.Start(common.AC); err != nil {
 			log.Error("Could not start logs-agent: ", err)
 		}
 	} else {
 		log.Info("logs-agent disabled")
 	}
 
+	// Start OTLP intake. This must happen after the logs-agent is started, the OTLP logs are sent through it.
+	otlpEnabled := otlp.IsEnabled(config.Datadog)
+	inventories.SetAgentMetadata(inventories.AgentOTLPEnabled, otlpEnabled)
+	if otlpEnabled {
+		var logsAgentChannel chan *message.Message
+		if logsAgent != nil {
+			logsAgentChannel = logsAgent.GetPipelineProvider().NextPipelineChan()
+		}
+		var err error
+		common.OTLP, err = otlp.BuildAndStart(common.MainCtx, config.Datadog, demux.Serializer(), logsAgentChannel)
+		if err != nil {
+			log.Errorf("Could not start OTLP: %s", err)
+		} else {
+			log.Debug("OTLP pipeline started")
+		}
+	}
+
 	// Start NetFlow server
 	// This must happen after LoadComponents is set up (via common.LoadComponents).
 	// netflow.StartServer uses AgentDemultiplexer, that uses ContextResolver, that uses the tagger (initialized by LoadComponents)
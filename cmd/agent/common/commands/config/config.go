@@ -7,12 +7,17 @@ package config
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/DataDog/datadog-agent/pkg/config/settings"
 
 	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
 )
 
+var showOrigin bool
+
 // Config returns the main cobra config command.
 func Config(getClient settings.ClientBuilder) *cobra.Command {
 	cmd := &cobra.Command{
@@ -21,6 +26,7 @@ func Config(getClient settings.ClientBuilder) *cobra.Command {
 		Long:  ``,
 		RunE:  func(cmd *cobra.Command, args []string) error { return showRuntimeConfiguration(getClient, cmd, args) },
 	}
+	cmd.Flags().BoolVarP(&showOrigin, "show-origin", "", false, "print each setting along with where its value comes from: a configuration file, an environment variable, the runtime or the default")
 
 	cmd.AddCommand(listRuntime(getClient))
 	cmd.AddCommand(set(getClient))
@@ -72,11 +78,54 @@ func showRuntimeConfiguration(getClient settings.ClientBuilder, cmd *cobra.Comma
 		return err
 	}
 
-	fmt.Println(runtimeConfig)
+	if !showOrigin {
+		fmt.Println(runtimeConfig)
+		return nil
+	}
+
+	origins, err := c.Origins()
+	if err != nil {
+		return err
+	}
+
+	settingsMap := make(map[interface{}]interface{})
+	if err := yaml.Unmarshal([]byte(runtimeConfig), &settingsMap); err != nil {
+		return fmt.Errorf("unable to parse the runtime configuration: %v", err)
+	}
+
+	keys := make([]string, 0, len(origins))
+	for key := range origins {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		value, found := lookupSetting(settingsMap, key)
+		if !found {
+			continue
+		}
+		fmt.Printf("%s: %v # %s\n", key, value, origins[key])
+	}
 
 	return nil
 }
 
+// lookupSetting returns the value of a dotted key in the configuration,
+// whose map keys may themselves contain dots.
+func lookupSetting(settingsMap map[interface{}]interface{}, key string) (interface{}, bool) {
+	if value, found := settingsMap[key]; found {
+		return value, true
+	}
+	for k, v := range settingsMap {
+		prefix := fmt.Sprintf("%v.", k)
+		if sub, isMap := v.(map[interface{}]interface{}); isMap && strings.HasPrefix(key, prefix) {
+			if value, found := lookupSetting(sub, strings.TrimPrefix(key, prefix)); found {
+				return value, true
+			}
+		}
+	}
+	return nil, false
+}
+
 func listRuntimeConfigurableValue(getClient settings.ClientBuilder, cmd *cobra.Command, args []string) error {
 	c, err := getClient(cmd, args)
 	if err != nil {
@@ -38,14 +38,17 @@ func LoadComponents(ctx context.Context, confdPath string) {
 	// NOTICE: this will also setup the Python environment, if available
 	Coll = collector.NewCollector(GetPythonPaths()...)
 
-	// setup autodiscovery
-	confSearchPaths := []string{
+	// setup autodiscovery. must be done after the tagger is initialized
+	// because of subscription to metadata store.
+	AC = setupAutoDiscovery(GetConfSearchPaths(confdPath), scheduler.NewMetaScheduler())
+}
+
+// GetConfSearchPaths returns the paths where the file config provider looks
+// for check configurations, in order of precedence
+func GetConfSearchPaths(confdPath string) []string {
+	return []string{
 		confdPath,
 		filepath.Join(GetDistPath(), "conf.d"),
 		"",
 	}
-
-	// setup autodiscovery. must be done after the tagger is initialized
-	// because of subscription to metadata store.
-	AC = setupAutoDiscovery(confSearchPaths, scheduler.NewMetaScheduler())
 }
@@ -146,7 +146,14 @@ func makeFlare(w http.ResponseWriter, r *http.Request) {
 		jmxLogFile = common.DefaultJmxLogFile
 	}
 
-	filePath, e := flare.CreateArchive(false, common.GetDistPath(), common.PyChecksPath, []string{logFile, jmxLogFile}, nil, nil)
+	flareProfile, e := flare.GetProfile("")
+	if e != nil {
+		w.Write([]byte("Error creating flare zipfile: " + e.Error()))
+		log.Errorf("Error creating flare zipfile: " + e.Error())
+		return
+	}
+
+	filePath, e := flare.CreateArchive(false, common.GetDistPath(), common.PyChecksPath, []string{logFile, jmxLogFile}, nil, nil, flareProfile)
 	if e != nil {
 		w.Write([]byte("Error creating flare zipfile: " + e.Error()))
 		log.Errorf("Error creating flare zipfile: " + e.Error())
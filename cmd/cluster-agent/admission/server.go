@@ -36,6 +36,13 @@ const jsonContentType = "application/json"
 
 type admissionFunc func([]byte, string, dynamic.Interface) ([]byte, error)
 
+// validationFunc returns the warnings to report to the client,
+// a non-nil error denies the admission request.
+type validationFunc func([]byte, string, dynamic.Interface) ([]string, error)
+
+// reviewFunc builds the response to an admission request from the raw object and its namespace.
+type reviewFunc func([]byte, string) *admiv1.AdmissionResponse
+
 // Server TODO <container-integrations>
 type Server struct {
 	decoder runtime.Decoder
@@ -73,7 +80,20 @@ func (s *Server) initDecoder() {
 // Register must be called to register the desired webhook handlers before calling Run.
 func (s *Server) Register(uri string, f admissionFunc, dc dynamic.Interface) {
 	s.mux.HandleFunc(uri, func(w http.ResponseWriter, r *http.Request) {
-		s.mutateHandler(w, r, f, dc)
+		s.handle(w, r, func(rawObj []byte, ns string) *admiv1.AdmissionResponse {
+			jsonPatch, err := f(rawObj, ns, dc)
+			return mutationResponse(jsonPatch, err)
+		})
+	})
+}
+
+// RegisterValidation adds a validating admission webhook handler.
+// RegisterValidation must be called to register the desired webhook handlers before calling Run.
+func (s *Server) RegisterValidation(uri string, f validationFunc, dc dynamic.Interface) {
+	s.mux.HandleFunc(uri, func(w http.ResponseWriter, r *http.Request) {
+		s.handle(w, r, func(rawObj []byte, ns string) *admiv1.AdmissionResponse {
+			return validationResponse(f(rawObj, ns, dc))
+		})
 	})
 }
 
@@ -105,9 +125,9 @@ func (s *Server) Run(mainCtx context.Context, client kubernetes.Interface) error
 	return server.Shutdown(shutdownCtx)
 }
 
-// mutateHandler contains the main logic responsible for handling mutation requests.
+// handle contains the main logic responsible for handling admission requests.
 // It supports both v1 and v1beta1 requests.
-func (s *Server) mutateHandler(w http.ResponseWriter, r *http.Request, mutateFunc admissionFunc, dc dynamic.Interface) {
+func (s *Server) handle(w http.ResponseWriter, r *http.Request, review reviewFunc) {
 	metrics.WebhooksReceived.Inc()
 
 	start := time.Now()
@@ -151,8 +171,7 @@ func (s *Server) mutateHandler(w http.ResponseWriter, r *http.Request, mutateFun
 		}
 		admissionReviewResp := &admiv1.AdmissionReview{}
 		admissionReviewResp.SetGroupVersionKind(*gvk)
-		jsonPatch, err := mutateFunc(admissionReviewReq.Request.Object.Raw, admissionReviewReq.Request.Namespace, dc)
-		admissionReviewResp.Response = mutationResponse(jsonPatch, err)
+		admissionReviewResp.Response = review(admissionReviewReq.Request.Object.Raw, admissionReviewReq.Request.Namespace)
 		admissionReviewResp.Response.UID = admissionReviewReq.Request.UID
 		response = admissionReviewResp
 	case admiv1beta1.SchemeGroupVersion.WithKind("AdmissionReview"):
@@ -162,8 +181,7 @@ func (s *Server) mutateHandler(w http.ResponseWriter, r *http.Request, mutateFun
 		}
 		admissionReviewResp := &admiv1beta1.AdmissionReview{}
 		admissionReviewResp.SetGroupVersionKind(*gvk)
-		jsonPatch, err := mutateFunc(admissionReviewReq.Request.Object.Raw, admissionReviewReq.Request.Namespace, dc)
-		admissionReviewResp.Response = responseV1ToV1beta1(mutationResponse(jsonPatch, err))
+		admissionReviewResp.Response = responseV1ToV1beta1(review(admissionReviewReq.Request.Object.Raw, admissionReviewReq.Request.Namespace))
 		admissionReviewResp.Response.UID = admissionReviewReq.Request.UID
 		response = admissionReviewResp
 	default:
@@ -204,6 +222,29 @@ func mutationResponse(jsonPatch []byte, err error) *admiv1.AdmissionResponse {
 	}
 }
 
+// validationResponse returns the adequate v1.AdmissionResponse based on the validation result.
+func validationResponse(warnings []string, err error) *admiv1.AdmissionResponse {
+	if err != nil {
+		log.Debugf("Denying admission request: %v", err)
+
+		return &admiv1.AdmissionResponse{
+			Result: &metav1.Status{
+				Status:  metav1.StatusFailure,
+				Message: err.Error(),
+				Reason:  metav1.StatusReasonInvalid,
+				Code:    http.StatusUnprocessableEntity,
+			},
+			Allowed:  false,
+			Warnings: warnings,
+		}
+	}
+
+	return &admiv1.AdmissionResponse{
+		Allowed:  true,
+		Warnings: warnings,
+	}
+}
+
 // responseV1ToV1beta1 converts a v1.AdmissionResponse into a v1beta1.AdmissionResponse.
 func responseV1ToV1beta1(resp *admiv1.AdmissionResponse) *admiv1beta1.AdmissionResponse {
 	var patchType *admiv1beta1.PatchType
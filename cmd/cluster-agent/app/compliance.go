@@ -100,11 +100,8 @@ func startCompliance(stopper startstop.Stopper, apiCl *apiserver.APIClient, isLe
 	if err != nil {
 		return err
 	}
-	agent, err := agent.New(
-		reporter,
-		scheduler,
-		configDir,
-		endpoints,
+
+	options := []checks.BuilderOption{
 		checks.WithInterval(checkInterval),
 		checks.WithMaxEvents(checkMaxEvents),
 		checks.WithHostname(hname),
@@ -113,6 +110,19 @@ func startCompliance(stopper startstop.Stopper, apiCl *apiserver.APIClient, isLe
 		}),
 		checks.WithKubernetesClient(apiCl.DynamicCl, ""),
 		checks.WithIsLeader(isLeader),
+	}
+
+	if coreconfig.Datadog.GetBool("compliance_config.change_only_reporting.enabled") {
+		snapshotInterval := coreconfig.Datadog.GetDuration("compliance_config.change_only_reporting.snapshot_interval")
+		options = append(options, checks.WithChangeOnlyReporting(snapshotInterval))
+	}
+
+	agent, err := agent.New(
+		reporter,
+		scheduler,
+		configDir,
+		endpoints,
+		options...,
 	)
 	if err != nil {
 		return err
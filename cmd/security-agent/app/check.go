@@ -19,9 +19,11 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/DataDog/datadog-agent/cmd/security-agent/common"
+	"github.com/DataDog/datadog-agent/pkg/compliance"
 	"github.com/DataDog/datadog-agent/pkg/compliance/agent"
 	"github.com/DataDog/datadog-agent/pkg/compliance/checks"
 	"github.com/DataDog/datadog-agent/pkg/compliance/event"
+	"github.com/DataDog/datadog-agent/pkg/compliance/export"
 	"github.com/DataDog/datadog-agent/pkg/config"
 	coreconfig "github.com/DataDog/datadog-agent/pkg/config"
 	"github.com/DataDog/datadog-agent/pkg/util/flavor"
@@ -29,6 +31,7 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/util/kubernetes/apiserver"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
 	"github.com/DataDog/datadog-agent/pkg/util/startstop"
+	"github.com/DataDog/datadog-agent/pkg/version"
 )
 
 var (
@@ -41,6 +44,8 @@ var (
 		dumpRegoInput     string
 		dumpReports       string
 		skipRegoEval      bool
+		reportFormat      string
+		output            string
 	}{}
 )
 
@@ -53,6 +58,8 @@ func setupCheckCmd(cmd *cobra.Command) {
 	cmd.Flags().StringVarP(&checkArgs.dumpRegoInput, "dump-rego-input", "", "", "Path to file where to dump the Rego input JSON")
 	cmd.Flags().StringVarP(&checkArgs.dumpReports, "dump-reports", "", "", "Path to file where to dump reports")
 	cmd.Flags().BoolVarP(&checkArgs.skipRegoEval, "skip-rego-eval", "", false, "Skip rego evaluation")
+	cmd.Flags().StringVarP(&checkArgs.reportFormat, "report-format", "", string(export.FormatJSON), "Format of the report written to the output file (json, junit or sarif)")
+	cmd.Flags().StringVarP(&checkArgs.output, "output", "o", "", "Path to file where to write the report of the run")
 }
 
 // CheckCmd returns a cobra command to run security agent checks
@@ -75,10 +82,15 @@ func runCheck(cmd *cobra.Command, confPathArray []string, args []string) error {
 		return err
 	}
 
-	if checkArgs.skipRegoEval && checkArgs.dumpReports != "" {
+	if checkArgs.skipRegoEval && (checkArgs.dumpReports != "" || checkArgs.output != "") {
 		return errors.New("skipping the rego evaluation does not allow the generation of reports")
 	}
 
+	reportFormat, err := export.ParseFormat(checkArgs.reportFormat)
+	if err != nil {
+		return err
+	}
+
 	// We need to set before calling `SetupConfig`
 	configName := "datadog"
 	if flavor.GetFlavor() == flavor.ClusterAgent {
@@ -160,11 +172,14 @@ func runCheck(cmd *cobra.Command, confPathArray []string, args []string) error {
 
 	options = append(options, checks.WithRegoEvalSkip(checkArgs.skipRegoEval))
 
+	start := time.Now()
+
+	var statuses compliance.CheckStatusList
 	if checkArgs.file != "" {
-		err = agent.RunChecksFromFile(reporter, checkArgs.file, options...)
+		statuses, err = agent.RunChecksFromFile(reporter, checkArgs.file, options...)
 	} else {
 		configDir := config.Datadog.GetString("compliance_config.dir")
-		err = agent.RunChecks(reporter, configDir, options...)
+		statuses, err = agent.RunChecks(reporter, configDir, options...)
 	}
 
 	if err != nil {
@@ -177,6 +192,15 @@ func runCheck(cmd *cobra.Command, confPathArray []string, args []string) error {
 		return err
 	}
 
+	if checkArgs.output != "" {
+		run := export.NewRun(hname, version.AgentVersion, start, time.Now(), statuses, reporter.events)
+		if err := run.WriteFile(checkArgs.output, reportFormat); err != nil {
+			log.Errorf("Failed to write %s report to %s: %v", reportFormat, checkArgs.output, err)
+			return err
+		}
+		log.Infof("Wrote %s report of %d rules to %s", reportFormat, run.Summary.Rules, checkArgs.output)
+	}
+
 	return nil
 }
 
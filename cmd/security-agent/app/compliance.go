@@ -137,6 +137,11 @@ func startCompliance(hostname string, stopper startstop.Stopper, statsdClient *d
 		checks.MayFail(checks.WithAudit()),
 	}
 
+	if coreconfig.Datadog.GetBool("compliance_config.change_only_reporting.enabled") {
+		snapshotInterval := coreconfig.Datadog.GetDuration("compliance_config.change_only_reporting.snapshot_interval")
+		options = append(options, checks.WithChangeOnlyReporting(snapshotInterval))
+	}
+
 	if coreconfig.IsKubernetes() {
 		nodeLabels, err := agent.WaitGetNodeLabels()
 		if err != nil {
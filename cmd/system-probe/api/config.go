@@ -17,6 +17,7 @@ import (
 func setupConfigHandlers(r *mux.Router) {
 	r.HandleFunc("/config", settingshttp.Server.GetFull(getAggregatedNamespaces()...)).Methods("GET")
 	r.HandleFunc("/config/list-runtime", settingshttp.Server.ListConfigurable).Methods("GET")
+	r.HandleFunc("/config/origins", settingshttp.Server.ListOrigins(getAggregatedNamespaces()...)).Methods("GET")
 	r.HandleFunc("/config/{setting}", settingshttp.Server.GetValue).Methods("GET")
 	r.HandleFunc("/config/{setting}", settingshttp.Server.SetValue).Methods("POST")
 }
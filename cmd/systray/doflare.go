@@ -214,7 +214,13 @@ func requestFlare(caseID, customerEmail string) (response string, e error) {
 		}
 		log.Debug("Initiating flare locally.")
 
-		filePath, e = flare.CreateArchive(true, common.GetDistPath(), common.PyChecksPath, []string{logFile, jmxLogFile}, nil, e)
+		flareProfile, err := flare.GetProfile("")
+		if err != nil {
+			log.Errorf("The flare zipfile failed to be created: %s\n", err)
+			return
+		}
+
+		filePath, e = flare.CreateArchive(true, common.GetDistPath(), common.PyChecksPath, []string{logFile, jmxLogFile}, nil, e, flareProfile)
 		if e != nil {
 			log.Errorf("The flare zipfile failed to be created: %s\n", e)
 			return
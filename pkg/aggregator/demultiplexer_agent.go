@@ -6,6 +6,7 @@
 package aggregator
 
 import (
+	"errors"
 	"fmt"
 	"sync"
 	"time"
@@ -590,6 +591,16 @@ func (d *AgentDemultiplexer) Aggregator() *BufferedAggregator {
 	return d.aggregator
 }
 
+// SetForwarderKeysPerDomain updates, at runtime, the domains and the API keys
+// the shared forwarder sends payloads to.
+func (d *AgentDemultiplexer) SetForwarderKeysPerDomain(keysPerDomain map[string][]string) error {
+	fwd, ok := d.dataOutputs.forwarders.shared.(*forwarder.DefaultForwarder)
+	if !ok {
+		return errors.New("the shared forwarder can't be updated at runtime")
+	}
+	return fwd.SetKeysPerDomain(keysPerDomain)
+}
+
 // GetMetricSamplePool returns a shared resource used in the whole DogStatsD
 // pipeline to re-use metric samples slices: the server is getting a slice
 // and filling it with samples, the rest of the pipeline process them the
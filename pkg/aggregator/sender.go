@@ -145,6 +145,22 @@ func GetSender(id check.ID) (Sender, error) {
 	return demultiplexerInstance.GetSender(id)
 }
 
+// SetForwarderKeysPerDomain updates, at runtime, the domains and the API keys
+// the forwarder of the global demultiplexer sends payloads to.
+func SetForwarderKeysPerDomain(keysPerDomain map[string][]string) error {
+	demultiplexerInstanceMu.Lock()
+	defer demultiplexerInstanceMu.Unlock()
+
+	if demultiplexerInstance == nil {
+		return errors.New("Demultiplexer was not initialized")
+	}
+	demux, ok := demultiplexerInstance.(*AgentDemultiplexer)
+	if !ok {
+		return errors.New("the forwarder of this demultiplexer can't be updated at runtime")
+	}
+	return demux.SetForwarderKeysPerDomain(keysPerDomain)
+}
+
 // DestroySender frees up the resources used by the sender with passed ID (by deregistering it from the aggregator)
 // Should be called when no sender with this ID is used anymore
 // The metrics of this (these) sender(s) that haven't been flushed yet will be lost
@@ -39,6 +39,14 @@ var templateVariables = map[string]variableGetter{
 	"kube":     getAdditionalTplVariables,
 }
 
+// IsTemplateVariable returns whether the given name (the part of a
+// `%%var_param%%` pattern before the first underscore) is a supported
+// template variable.
+func IsTemplateVariable(name string) bool {
+	_, found := templateVariables[name]
+	return found
+}
+
 // SubstituteTemplateEnvVars replaces %%ENV_VARIABLE%% from environment
 // variables in the config init, instances, and logs config.
 // When there is an error, it continues replacing. When there are multiple
@@ -0,0 +1,280 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build linux
+// +build linux
+
+package listeners
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/autodiscovery/integration"
+	"github.com/DataDog/datadog-agent/pkg/config"
+	"github.com/DataDog/datadog-agent/pkg/process/procutil"
+	"github.com/DataDog/datadog-agent/pkg/util/containers"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// processADIdentifierPrefix is the prefix of the AD identifiers of the host processes
+const processADIdentifierPrefix = "process://"
+
+func init() {
+	Register("process", NewProcessListener)
+}
+
+// ProcessListener defines a listener that periodically discovers the host
+// processes listening on TCP or UDP ports
+type ProcessListener struct {
+	sync.RWMutex
+	newService    chan<- Service
+	delService    chan<- Service
+	services      map[string]*ProcessService // maps process keys to services
+	stop          chan bool
+	refreshTicker *time.Ticker
+	probe         procutil.Probe
+}
+
+// ProcessService defines a host process listening on network ports
+type ProcessService struct {
+	pid           int32
+	createTime    int64
+	adIdentifiers []string
+	hosts         map[string]string
+	ports         []ContainerPort
+}
+
+// Make sure ProcessService implements the Service interface
+var _ Service = &ProcessService{}
+
+// NewProcessListener creates a ProcessListener
+func NewProcessListener(Config) (ServiceListener, error) {
+	interval := time.Duration(config.Datadog.GetInt("ad_process_listener_poll_interval")) * time.Second
+	if interval <= 0 {
+		return nil, fmt.Errorf("invalid ad_process_listener_poll_interval: %s", interval)
+	}
+
+	return &ProcessListener{
+		services:      map[string]*ProcessService{},
+		stop:          make(chan bool),
+		refreshTicker: time.NewTicker(interval),
+		probe:         procutil.NewProcessProbe(procutil.WithListeningPorts(true)),
+	}, nil
+}
+
+// Listen periodically refreshes the services from the host processes
+func (l *ProcessListener) Listen(newSvc chan<- Service, delSvc chan<- Service) {
+	// setup the I/O channels
+	l.newService = newSvc
+	l.delService = delSvc
+
+	go func() {
+		l.refreshServices()
+		for {
+			select {
+			case <-l.stop:
+				l.refreshTicker.Stop()
+				l.probe.Close()
+				return
+			case <-l.refreshTicker.C:
+				l.refreshServices()
+			}
+		}
+	}()
+}
+
+// Stop queues a shutdown of ProcessListener
+func (l *ProcessListener) Stop() {
+	l.stop <- true
+}
+
+func (l *ProcessListener) refreshServices() {
+	log.Debug("Refreshing services via ProcessListener")
+	// make sure that we can't have two simultaneous runs of this function
+	l.Lock()
+	defer l.Unlock()
+
+	procs, err := l.probe.ProcessesByPID(time.Now(), false)
+	if err != nil {
+		log.Warnf("Unable to collect processes: %s", err)
+		return
+	}
+
+	// processes running in another network namespace than the host init process
+	// are containerized, they are discovered by the container listeners
+	var hostNetNS uint64
+	if initProc, found := procs[1]; found {
+		hostNetNS = initProc.NetNS
+	}
+
+	notSeen := make(map[string]struct{}, len(l.services))
+	for key := range l.services {
+		notSeen[key] = struct{}{}
+	}
+
+	for _, proc := range procs {
+		if len(proc.Ports) == 0 || (hostNetNS != 0 && proc.NetNS != hostNetNS) {
+			continue
+		}
+
+		svc := newProcessService(proc)
+		key := svc.GetServiceID()
+		if previous, found := l.services[key]; found {
+			delete(notSeen, key)
+			if previous.equal(svc) {
+				continue
+			}
+			// the process listens on other ports, reschedule its checks
+			l.delService <- previous
+		}
+
+		l.services[key] = svc
+		l.newService <- svc
+	}
+
+	for key := range notSeen {
+		l.delService <- l.services[key]
+		delete(l.services, key)
+	}
+}
+
+func newProcessService(proc *procutil.Process) *ProcessService {
+	svc := &ProcessService{
+		pid:   proc.Pid,
+		hosts: map[string]string{},
+	}
+	if proc.Stats != nil {
+		svc.createTime = proc.Stats.CreateTime
+	}
+
+	// the identifiers are namespaced so that they don't match the templates of
+	// container images. The name from the status file is truncated, the
+	// executable name is matched first.
+	if proc.Exe != "" {
+		svc.adIdentifiers = append(svc.adIdentifiers, processADIdentifierPrefix+filepath.Base(proc.Exe))
+	}
+	if name := processADIdentifierPrefix + proc.Name; proc.Name != "" && (len(svc.adIdentifiers) == 0 || name != svc.adIdentifiers[0]) {
+		svc.adIdentifiers = append(svc.adIdentifiers, name)
+	}
+
+	seen := make(map[ContainerPort]struct{}, len(proc.Ports))
+	for _, port := range proc.Ports {
+		containerPort := ContainerPort{Port: int(port.Port), Name: fmt.Sprintf("%s-%d", port.Protocol, port.Port)}
+		if _, found := seen[containerPort]; !found {
+			seen[containerPort] = struct{}{}
+			svc.ports = append(svc.ports, containerPort)
+		}
+
+		if _, found := svc.hosts["host"]; !found {
+			if ip := net.ParseIP(port.Address); ip != nil && ip.To4() != nil {
+				svc.hosts["host"] = reachableAddress(ip)
+			}
+		}
+	}
+	if _, found := svc.hosts["host"]; !found {
+		svc.hosts["host"] = reachableAddress(net.ParseIP(proc.Ports[0].Address))
+	}
+
+	return svc
+}
+
+// reachableAddress returns the address to reach a socket bound to ip, which is
+// the loopback address for the sockets listening on all the interfaces
+func reachableAddress(ip net.IP) string {
+	switch {
+	case ip == nil || ip.Equal(net.IPv4zero):
+		return "127.0.0.1"
+	case ip.Equal(net.IPv6unspecified):
+		return "::1"
+	default:
+		return ip.String()
+	}
+}
+
+func (s *ProcessService) equal(other *ProcessService) bool {
+	if len(s.ports) != len(other.ports) || len(s.hosts) != len(other.hosts) {
+		return false
+	}
+	for i := range s.ports {
+		if s.ports[i] != other.ports[i] {
+			return false
+		}
+	}
+	for network, host := range s.hosts {
+		if other.hosts[network] != host {
+			return false
+		}
+	}
+	return true
+}
+
+// GetServiceID returns the unique entity name linked to that service
+func (s *ProcessService) GetServiceID() string {
+	return fmt.Sprintf("process://%d-%d", s.pid, s.createTime)
+}
+
+// GetTaggerEntity returns the unique entity name linked to that service
+func (s *ProcessService) GetTaggerEntity() string {
+	return s.GetServiceID()
+}
+
+// GetADIdentifiers returns the executable and process names, prefixed with process://
+func (s *ProcessService) GetADIdentifiers(context.Context) ([]string, error) {
+	return s.adIdentifiers, nil
+}
+
+// GetHosts returns the address the process listens on
+func (s *ProcessService) GetHosts(context.Context) (map[string]string, error) {
+	return s.hosts, nil
+}
+
+// GetPorts returns the ports the process listens on, named after their protocol and number
+func (s *ProcessService) GetPorts(context.Context) ([]ContainerPort, error) {
+	return s.ports, nil
+}
+
+// GetTags returns the list of process tags - currently always empty
+func (s *ProcessService) GetTags() ([]string, error) {
+	return []string{}, nil
+}
+
+// GetPid returns the process identifier
+func (s *ProcessService) GetPid(context.Context) (int, error) {
+	return int(s.pid), nil
+}
+
+// GetHostname returns nothing - not supported
+func (s *ProcessService) GetHostname(context.Context) (string, error) {
+	return "", ErrNotSupported
+}
+
+// IsReady returns true
+func (s *ProcessService) IsReady(context.Context) bool {
+	return true
+}
+
+// GetCheckNames returns nil
+func (s *ProcessService) GetCheckNames(context.Context) []string {
+	return nil
+}
+
+// HasFilter returns false on processes
+func (s *ProcessService) HasFilter(filter containers.FilterType) bool {
+	return false
+}
+
+// GetExtraConfig isn't supported
+func (s *ProcessService) GetExtraConfig(key string) (string, error) {
+	return "", ErrNotSupported
+}
+
+// FilterTemplates does nothing.
+func (s *ProcessService) FilterTemplates(configs map[string]integration.Config) {
+}
@@ -0,0 +1,128 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build linux
+// +build linux
+
+package listeners
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/pkg/process/procutil"
+	"github.com/DataDog/datadog-agent/pkg/process/procutil/mocks"
+)
+
+const (
+	testHostNetNS      = 4026531992
+	testContainerNetNS = 4026532281
+)
+
+func newTestProcess(pid int32, exe string, netNS uint64, ports ...procutil.Port) *procutil.Process {
+	return &procutil.Process{
+		Pid:   pid,
+		Name:  "redis-server",
+		Exe:   exe,
+		NetNS: netNS,
+		Ports: ports,
+		Stats: &procutil.Stats{CreateTime: 1639000000000},
+	}
+}
+
+func TestProcessListenerRefresh(t *testing.T) {
+	probe := &mocks.Probe{}
+	newSvc := make(chan Service, 10)
+	delSvc := make(chan Service, 10)
+	l := &ProcessListener{
+		newService: newSvc,
+		delService: delSvc,
+		services:   map[string]*ProcessService{},
+		probe:      probe,
+	}
+
+	redisPort := procutil.Port{Protocol: "tcp", Address: "0.0.0.0", Port: 6379}
+	procs := map[int32]*procutil.Process{
+		1:  newTestProcess(1, "/usr/lib/systemd/systemd", testHostNetNS),
+		42: newTestProcess(42, "/usr/bin/redis-server", testHostNetNS, redisPort),
+		// containerized process
+		43: newTestProcess(43, "/usr/local/bin/redis-server", testContainerNetNS, redisPort),
+	}
+	probe.On("ProcessesByPID", mock.Anything, false).Return(procs, nil).Once()
+	l.refreshServices()
+
+	require.Len(t, newSvc, 1)
+	svc := (<-newSvc).(*ProcessService)
+	assert.Equal(t, "process://42-1639000000000", svc.GetServiceID())
+
+	ctx := context.Background()
+	adIdentifiers, _ := svc.GetADIdentifiers(ctx)
+	assert.Equal(t, []string{"process://redis-server"}, adIdentifiers)
+	hosts, _ := svc.GetHosts(ctx)
+	assert.Equal(t, map[string]string{"host": "127.0.0.1"}, hosts)
+	ports, _ := svc.GetPorts(ctx)
+	assert.Equal(t, []ContainerPort{{Port: 6379, Name: "tcp-6379"}}, ports)
+	pid, _ := svc.GetPid(ctx)
+	assert.Equal(t, 42, pid)
+
+	// unchanged processes are not notified again
+	probe.On("ProcessesByPID", mock.Anything, false).Return(procs, nil).Once()
+	l.refreshServices()
+	assert.Empty(t, newSvc)
+	assert.Empty(t, delSvc)
+
+	// the process listens on another port
+	procs[42] = newTestProcess(42, "/usr/bin/redis-server", testHostNetNS,
+		redisPort,
+		procutil.Port{Protocol: "tcp", Address: "::", Port: 6379},
+		procutil.Port{Protocol: "tcp", Address: "10.0.0.2", Port: 16379},
+	)
+	probe.On("ProcessesByPID", mock.Anything, false).Return(procs, nil).Once()
+	l.refreshServices()
+	require.Len(t, delSvc, 1)
+	assert.Equal(t, svc, <-delSvc)
+	require.Len(t, newSvc, 1)
+	svc = (<-newSvc).(*ProcessService)
+	ports, _ = svc.GetPorts(ctx)
+	assert.Equal(t, []ContainerPort{{Port: 6379, Name: "tcp-6379"}, {Port: 16379, Name: "tcp-16379"}}, ports)
+
+	// the process is gone
+	delete(procs, 42)
+	probe.On("ProcessesByPID", mock.Anything, false).Return(procs, nil).Once()
+	l.refreshServices()
+	require.Len(t, delSvc, 1)
+	assert.Equal(t, svc, <-delSvc)
+	assert.Empty(t, l.services)
+
+	probe.AssertExpectations(t)
+}
+
+func TestNewProcessService(t *testing.T) {
+	ctx := context.Background()
+
+	// the process name is truncated to 15 characters
+	proc := newTestProcess(42, "/opt/app/bin/billing-service-api", testHostNetNS,
+		procutil.Port{Protocol: "udp", Address: "::", Port: 8125},
+	)
+	proc.Name = "billing-service"
+	svc := newProcessService(proc)
+
+	adIdentifiers, _ := svc.GetADIdentifiers(ctx)
+	assert.Equal(t, []string{"process://billing-service-api", "process://billing-service"}, adIdentifiers)
+	hosts, _ := svc.GetHosts(ctx)
+	assert.Equal(t, map[string]string{"host": "::1"}, hosts)
+
+	// IPv4 addresses are preferred
+	proc.Ports = []procutil.Port{
+		{Protocol: "tcp", Address: "::1", Port: 8080},
+		{Protocol: "tcp", Address: "192.168.1.10", Port: 8080},
+	}
+	hosts, _ = newProcessService(proc).GetHosts(ctx)
+	assert.Equal(t, map[string]string{"host": "192.168.1.10"}, hosts)
+}
@@ -16,10 +16,14 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/errors"
 	"github.com/DataDog/datadog-agent/pkg/util/clusteragent"
 	"github.com/DataDog/datadog-agent/pkg/util/hostname"
+	"github.com/DataDog/datadog-agent/pkg/util/kubernetes/hostinfo"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
 )
 
-const defaultGraceDuration = 60 * time.Second
+const (
+	defaultGraceDuration      = 60 * time.Second
+	nodeLabelsRefreshInterval = 5 * time.Minute
+)
 
 // ClusterChecksConfigProvider implements the ConfigProvider interface
 // for the cluster check feature.
@@ -31,6 +35,10 @@ type ClusterChecksConfigProvider struct {
 	lastChange       int64
 	identifier       string
 	flushedConfigs   bool
+	reportNodeLabels bool
+	nodeLabels       map[string]string
+	nodeLabelsUpdate time.Time
+	nodeLabelsGetter func(context.Context) (map[string]string, error)
 }
 
 // NewClusterChecksConfigProvider returns a new ConfigProvider collecting
@@ -44,6 +52,8 @@ func NewClusterChecksConfigProvider(providerConfig *config.ConfigurationProvider
 	c := &ClusterChecksConfigProvider{
 		graceDuration:    defaultGraceDuration,
 		degradedDuration: defaultDegradedDeadline,
+		reportNodeLabels: config.Datadog.GetBool("cluster_checks.report_node_labels"),
+		nodeLabelsGetter: hostinfo.GetNodeLabels,
 	}
 
 	c.identifier = config.Datadog.GetString("clc_runner_id")
@@ -106,6 +116,7 @@ func (c *ClusterChecksConfigProvider) IsUpToDate(ctx context.Context) (bool, err
 
 	status := types.NodeStatus{
 		LastChange: c.lastChange,
+		NodeLabels: c.getNodeLabels(ctx),
 	}
 
 	reply, err := c.dcaClient.PostClusterCheckStatus(ctx, c.identifier, status)
@@ -128,6 +139,33 @@ func (c *ClusterChecksConfigProvider) IsUpToDate(ctx context.Context) (bool, err
 	return reply.IsUpToDate, nil
 }
 
+// getNodeLabels returns the labels of the node of the agent, they are
+// reported to the cluster-agent to honor the placement constraints of checks.
+// They are refreshed every nodeLabelsRefreshInterval to follow the changes of
+// the node.
+func (c *ClusterChecksConfigProvider) getNodeLabels(ctx context.Context) map[string]string {
+	if !c.reportNodeLabels || time.Since(c.nodeLabelsUpdate) < nodeLabelsRefreshInterval {
+		return c.nodeLabels
+	}
+
+	labels, err := c.nodeLabelsGetter(ctx)
+	if err != nil {
+		// keep the last known labels and retry on the next status report
+		log.Debugf("Cannot get the node labels to report to the cluster-agent: %v", err)
+		return c.nodeLabels
+	}
+	if labels == nil {
+		// not running on a kubernetes node
+		c.reportNodeLabels = false
+		c.nodeLabels = nil
+		return nil
+	}
+
+	c.nodeLabels = labels
+	c.nodeLabelsUpdate = time.Now()
+	return c.nodeLabels
+}
+
 // Collect retrieves configurations the cluster-agent dispatched to this agent
 func (c *ClusterChecksConfigProvider) Collect(ctx context.Context) ([]integration.Config, error) {
 	if c.dcaClient == nil {
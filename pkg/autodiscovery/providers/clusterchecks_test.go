@@ -0,0 +1,62 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package providers
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClusterChecksGetNodeLabels(t *testing.T) {
+	var labels map[string]string
+	var err error
+	calls := 0
+
+	c := &ClusterChecksConfigProvider{
+		reportNodeLabels: true,
+		nodeLabelsGetter: func(context.Context) (map[string]string, error) {
+			calls++
+			return labels, err
+		},
+	}
+
+	// labels can't be retrieved yet
+	err = errors.New("unavailable")
+	assert.Nil(t, c.getNodeLabels(context.Background()))
+	assert.Equal(t, 1, calls)
+
+	// retried on the next status report
+	labels, err = map[string]string{"zone": "a"}, nil
+	assert.Equal(t, map[string]string{"zone": "a"}, c.getNodeLabels(context.Background()))
+	assert.Equal(t, 2, calls)
+
+	// cached until the refresh interval elapses
+	labels = map[string]string{"zone": "b"}
+	assert.Equal(t, map[string]string{"zone": "a"}, c.getNodeLabels(context.Background()))
+	assert.Equal(t, 2, calls)
+
+	// refreshed once it elapsed
+	c.nodeLabelsUpdate = time.Now().Add(-nodeLabelsRefreshInterval)
+	assert.Equal(t, map[string]string{"zone": "b"}, c.getNodeLabels(context.Background()))
+	assert.Equal(t, 3, calls)
+
+	// the last known labels are kept when the refresh fails
+	c.nodeLabelsUpdate = time.Now().Add(-nodeLabelsRefreshInterval)
+	err = errors.New("unavailable")
+	assert.Equal(t, map[string]string{"zone": "b"}, c.getNodeLabels(context.Background()))
+	assert.Equal(t, 4, calls)
+
+	// not running on a kubernetes node
+	labels, err = nil, nil
+	assert.Nil(t, c.getNodeLabels(context.Background()))
+	assert.False(t, c.reportNodeLabels)
+	assert.Nil(t, c.getNodeLabels(context.Background()))
+	assert.Equal(t, 5, calls)
+}
@@ -9,17 +9,68 @@
 package webhook
 
 import (
+	"sort"
+
 	"github.com/DataDog/datadog-agent/pkg/clusteragent/admission/common"
 	"github.com/DataDog/datadog-agent/pkg/config"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+// namespaceNameLabelKey is the label set by Kubernetes on all namespaces with their name
+const namespaceNameLabelKey = "kubernetes.io/metadata.name"
+
 // buildLabelSelectors returns the mutating webhooks object selector based on the configuration
 func buildLabelSelectors(useNamespaceSelector bool) (namespaceSelector, objectSelector *metav1.LabelSelector) {
+	return labelSelectors(useNamespaceSelector, config.Datadog.GetBool("admission_controller.mutate_unlabelled"))
+}
+
+// buildValidationLabelSelectors returns the validating webhooks object selector.
+// Autodiscovery annotations are set without the admission label, so pods
+// are validated unless they're explicitly filtered-out.
+func buildValidationLabelSelectors(useNamespaceSelector bool) (namespaceSelector, objectSelector *metav1.LabelSelector) {
+	return labelSelectors(useNamespaceSelector, true)
+}
+
+// buildNamespacesLabelSelectors returns the selectors of the webhooks scoped to
+// the given namespaces. Their pods are accepted unless they're explicitly filtered-out.
+func buildNamespacesLabelSelectors(useNamespaceSelector bool, namespaces []string) (namespaceSelector, objectSelector *metav1.LabelSelector) {
+	namespaceSelector, objectSelector = labelSelectors(useNamespaceSelector, true)
+	return withNamespaceNames(namespaceSelector, metav1.LabelSelectorOpIn, namespaces), objectSelector
+}
+
+// withNamespaceNames returns a copy of the namespace selector with a requirement
+// on the namespace names, nil selectors are accepted.
+func withNamespaceNames(selector *metav1.LabelSelector, operator metav1.LabelSelectorOperator, namespaces []string) *metav1.LabelSelector {
+	out := &metav1.LabelSelector{}
+	if selector != nil {
+		out = selector.DeepCopy()
+	}
+
+	out.MatchExpressions = append(out.MatchExpressions, metav1.LabelSelectorRequirement{
+		Key:      namespaceNameLabelKey,
+		Operator: operator,
+		Values:   namespaces,
+	})
+
+	return out
+}
+
+// autoInstrumentationNamespaces returns the sorted namespaces opted-in to the library injection
+func autoInstrumentationNamespaces() []string {
+	namespaces := []string{}
+	for ns := range config.Datadog.GetStringMapString("admission_controller.auto_instrumentation.namespaces") {
+		namespaces = append(namespaces, ns)
+	}
+	sort.Strings(namespaces)
+
+	return namespaces
+}
+
+func labelSelectors(useNamespaceSelector, acceptUnlabelled bool) (namespaceSelector, objectSelector *metav1.LabelSelector) {
 	var labelSelector metav1.LabelSelector
 
-	if config.Datadog.GetBool("admission_controller.mutate_unlabelled") {
+	if acceptUnlabelled {
 		// Accept all, ignore pods if they're explicitly filtered-out
 		labelSelector = metav1.LabelSelector{
 			MatchExpressions: []metav1.LabelSelectorRequirement{
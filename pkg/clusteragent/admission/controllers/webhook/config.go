@@ -29,6 +29,7 @@ type Config struct {
 	timeout                  int32
 	failurePolicy            string
 	reinvocationPolicy       string
+	validationEnabled        bool
 }
 
 // NewConfig creates a webhook controller configuration
@@ -44,6 +45,7 @@ func NewConfig(admissionV1Enabled, namespaceSelectorEnabled bool) Config {
 		timeout:                  config.Datadog.GetInt32("admission_controller.timeout_seconds"),
 		failurePolicy:            config.Datadog.GetString("admission_controller.failure_policy"),
 		reinvocationPolicy:       config.Datadog.GetString("admission_controller.reinvocation_policy"),
+		validationEnabled:        config.Datadog.GetBool("admission_controller.validate_annotations.enabled"),
 	}
 }
 
@@ -58,6 +60,7 @@ func (w *Config) getServicePort() int32         { return w.svcPort }
 func (w *Config) getTimeout() int32             { return w.timeout }
 func (w *Config) getFailurePolicy() string      { return w.failurePolicy }
 func (w *Config) getReinvocationPolicy() string { return w.reinvocationPolicy }
+func (w *Config) useValidation() bool           { return w.validationEnabled }
 func (w *Config) configName(suffix string) string {
 	return strings.ReplaceAll(fmt.Sprintf("%s.%s", w.webhookName, suffix), "-", ".")
 }
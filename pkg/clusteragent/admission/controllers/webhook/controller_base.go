@@ -34,10 +34,10 @@ type Controller interface {
 // NewController returns the adequate implementation of the Controller interface.
 func NewController(client kubernetes.Interface, secretInformer coreinformers.SecretInformer, admissionInterface admissionregistration.Interface, isLeaderFunc func() bool, isLeaderNotif <-chan struct{}, config Config) Controller {
 	if config.useAdmissionV1() {
-		return NewControllerV1(client, secretInformer, admissionInterface.V1().MutatingWebhookConfigurations(), isLeaderFunc, isLeaderNotif, config)
+		return NewControllerV1(client, secretInformer, admissionInterface.V1().MutatingWebhookConfigurations(), admissionInterface.V1().ValidatingWebhookConfigurations(), isLeaderFunc, isLeaderNotif, config)
 	}
 
-	return NewControllerV1beta1(client, secretInformer, admissionInterface.V1beta1().MutatingWebhookConfigurations(), isLeaderFunc, isLeaderNotif, config)
+	return NewControllerV1beta1(client, secretInformer, admissionInterface.V1beta1().MutatingWebhookConfigurations(), admissionInterface.V1beta1().ValidatingWebhookConfigurations(), isLeaderFunc, isLeaderNotif, config)
 }
 
 // controllerBase acts as a base class for ControllerV1 and ControllerV1beta1.
@@ -49,9 +49,11 @@ type controllerBase struct {
 	secretsLister  corelisters.SecretLister
 	secretsSynced  cache.InformerSynced //nolint:structcheck
 	webhooksSynced cache.InformerSynced //nolint:structcheck
-	queue          workqueue.RateLimitingInterface
-	isLeaderFunc   func() bool
-	isLeaderNotif  <-chan struct{}
+	// validatingWebhooksSynced is nil when the annotations validation is disabled
+	validatingWebhooksSynced cache.InformerSynced //nolint:structcheck
+	queue                    workqueue.RateLimitingInterface
+	isLeaderFunc             func() bool
+	isLeaderNotif            <-chan struct{}
 }
 
 // enqueueOnLeaderNotif watches leader notifications and triggers a
@@ -70,6 +72,15 @@ func (c *controllerBase) enqueueOnLeaderNotif(stop <-chan struct{}) {
 	}
 }
 
+// informersSynced returns the functions reporting whether the informers of the controller have synced.
+func (c *controllerBase) informersSynced() []cache.InformerSynced {
+	synced := []cache.InformerSynced{c.secretsSynced, c.webhooksSynced}
+	if c.validatingWebhooksSynced != nil {
+		synced = append(synced, c.validatingWebhooksSynced)
+	}
+	return synced
+}
+
 // triggerReconciliation forces a reconciliation loop by enqueuing the webhook object name.
 func (c *controllerBase) triggerReconciliation() {
 	c.queue.Add(c.config.getWebhookName())
@@ -118,6 +118,62 @@ func TestUpdateOutdatedWebhookV1(t *testing.T) {
 	}
 }
 
+func TestCreateValidatingWebhookV1(t *testing.T) {
+	mockConfig := config.Mock(t)
+	mockConfig.Set("admission_controller.validate_annotations.enabled", true)
+	defer mockConfig.Set("admission_controller.validate_annotations.enabled", false)
+
+	f := newFixtureV1(t)
+	f.config = NewConfig(true, false)
+
+	data, err := certificate.GenerateSecretData(time.Now(), time.Now().Add(365*24*time.Hour), []string{"my.svc.dns"})
+	if err != nil {
+		t.Fatalf("Failed to create the Secret: %v", err)
+	}
+
+	secret := buildSecret(data, f.config)
+	f.populateSecretsCache(secret)
+
+	c := f.run(t)
+
+	webhook, err := c.validatingWebhooksLister.Get(f.config.getWebhookName())
+	if err != nil {
+		t.Fatalf("Failed to get the validating Webhook: %v", err)
+	}
+
+	assert.Len(t, webhook.Webhooks, 1)
+	assert.Equal(t, "datadog.webhook.annotations.validation", webhook.Webhooks[0].Name)
+	assert.Equal(t, "/validateannotations", *webhook.Webhooks[0].ClientConfig.Service.Path)
+	assert.Equal(t, certificate.GetCABundle(secret.Data), webhook.Webhooks[0].ClientConfig.CABundle)
+
+	// Pods are validated even when the mutation requires the admission label
+	_, objectSelector := buildValidationLabelSelectors(false)
+	assert.Equal(t, objectSelector, webhook.Webhooks[0].ObjectSelector)
+
+	if c.queue.Len() != 0 {
+		t.Fatal("Work queue isn't empty")
+	}
+}
+
+func TestValidatingWebhookDisabledV1(t *testing.T) {
+	f := newFixtureV1(t)
+
+	data, err := certificate.GenerateSecretData(time.Now(), time.Now().Add(365*24*time.Hour), []string{"my.svc.dns"})
+	if err != nil {
+		t.Fatalf("Failed to create the Secret: %v", err)
+	}
+
+	f.populateSecretsCache(buildSecret(data, f.config))
+
+	c := f.run(t)
+
+	assert.Nil(t, c.validatingWebhooksLister)
+	assert.Empty(t, c.validatingWebhookTemplates)
+
+	_, err = f.client.AdmissionregistrationV1().ValidatingWebhookConfigurations().Get(context.TODO(), f.config.getWebhookName(), metav1.GetOptions{})
+	assert.True(t, errors.IsNotFound(err))
+}
+
 func TestAdmissionControllerFailureModeIgnore(t *testing.T) {
 	f := newFixtureV1(t)
 	c, _ := f.createController()
@@ -338,6 +394,90 @@ func TestGenerateTemplatesV1(t *testing.T) {
 				return []admiv1.MutatingWebhook{webhook}
 			},
 		},
+		{
+			name: "lib injection with namespaces opted-in, mutate labelled",
+			setupConfig: func() {
+				mockConfig.Set("admission_controller.inject_config.enabled", false)
+				mockConfig.Set("admission_controller.mutate_unlabelled", false)
+				mockConfig.Set("admission_controller.inject_tags.enabled", false)
+				mockConfig.Set("admission_controller.auto_instrumentation.enabled", true)
+				mockConfig.Set("admission_controller.auto_instrumentation.namespaces", map[string]string{"payments": "dotnet", "billing": "ruby"})
+			},
+			configFunc: func() Config { return NewConfig(false, false) },
+			want: func() []admiv1.MutatingWebhook {
+				labelWebhook := webhook("datadog.webhook.auto.instrumentation", "/injectlib", &metav1.LabelSelector{
+					MatchLabels: map[string]string{
+						"admission.datadoghq.com/enabled": "true",
+					},
+				}, &metav1.LabelSelector{
+					MatchExpressions: []metav1.LabelSelectorRequirement{
+						{
+							Key:      "kubernetes.io/metadata.name",
+							Operator: metav1.LabelSelectorOpNotIn,
+							Values:   []string{"billing", "payments"},
+						},
+					},
+				})
+				nsWebhook := webhook("datadog.webhook.auto.instrumentation.namespaces", "/injectlib", &metav1.LabelSelector{
+					MatchExpressions: []metav1.LabelSelectorRequirement{
+						{
+							Key:      "admission.datadoghq.com/enabled",
+							Operator: metav1.LabelSelectorOpNotIn,
+							Values:   []string{"false"},
+						},
+					},
+				}, &metav1.LabelSelector{
+					MatchExpressions: []metav1.LabelSelectorRequirement{
+						{
+							Key:      "kubernetes.io/metadata.name",
+							Operator: metav1.LabelSelectorOpIn,
+							Values:   []string{"billing", "payments"},
+						},
+					},
+				})
+				return []admiv1.MutatingWebhook{labelWebhook, nsWebhook}
+			},
+		},
+		{
+			name: "lib injection with namespaces opted-in, namespace selector",
+			setupConfig: func() {
+				mockConfig.Set("admission_controller.inject_config.enabled", false)
+				mockConfig.Set("admission_controller.mutate_unlabelled", false)
+				mockConfig.Set("admission_controller.inject_tags.enabled", false)
+				mockConfig.Set("admission_controller.auto_instrumentation.enabled", true)
+				mockConfig.Set("admission_controller.auto_instrumentation.namespaces", map[string]string{"payments": "dotnet"})
+			},
+			configFunc: func() Config { return NewConfig(false, true) },
+			want: func() []admiv1.MutatingWebhook {
+				labelWebhook := webhook("datadog.webhook.auto.instrumentation", "/injectlib", nil, &metav1.LabelSelector{
+					MatchLabels: map[string]string{
+						"admission.datadoghq.com/enabled": "true",
+					},
+					MatchExpressions: []metav1.LabelSelectorRequirement{
+						{
+							Key:      "kubernetes.io/metadata.name",
+							Operator: metav1.LabelSelectorOpNotIn,
+							Values:   []string{"payments"},
+						},
+					},
+				})
+				nsWebhook := webhook("datadog.webhook.auto.instrumentation.namespaces", "/injectlib", nil, &metav1.LabelSelector{
+					MatchExpressions: []metav1.LabelSelectorRequirement{
+						{
+							Key:      "admission.datadoghq.com/enabled",
+							Operator: metav1.LabelSelectorOpNotIn,
+							Values:   []string{"false"},
+						},
+						{
+							Key:      "kubernetes.io/metadata.name",
+							Operator: metav1.LabelSelectorOpIn,
+							Values:   []string{"payments"},
+						},
+					},
+				})
+				return []admiv1.MutatingWebhook{labelWebhook, nsWebhook}
+			},
+		},
 		{
 			name: "config and tags injection, mutate labelled",
 			setupConfig: func() {
@@ -599,12 +739,14 @@ func TestGetWebhookSkeletonV1(t *testing.T) {
 
 type fixtureV1 struct {
 	fixture
+	config Config
 }
 
 func newFixtureV1(t *testing.T) *fixtureV1 {
 	f := &fixtureV1{}
 	f.t = t
 	f.client = fake.NewSimpleClientset()
+	f.config = v1Cfg
 	return f
 }
 
@@ -615,9 +757,10 @@ func (f *fixtureV1) createController() (*ControllerV1, informers.SharedInformerF
 		f.client,
 		factory.Core().V1().Secrets(),
 		factory.Admissionregistration().V1().MutatingWebhookConfigurations(),
+		factory.Admissionregistration().V1().ValidatingWebhookConfigurations(),
 		func() bool { return true },
 		make(chan struct{}),
-		v1Cfg,
+		f.config,
 	), factory
 }
 
@@ -37,10 +37,13 @@ type ControllerV1beta1 struct {
 	controllerBase
 	webhooksLister   admissionlisters.MutatingWebhookConfigurationLister
 	webhookTemplates []admiv1beta1.MutatingWebhook
+	// validatingWebhooksLister is nil when the annotations validation is disabled
+	validatingWebhooksLister   admissionlisters.ValidatingWebhookConfigurationLister
+	validatingWebhookTemplates []admiv1beta1.ValidatingWebhook
 }
 
 // NewControllerV1beta1 returns a new Webhook Controller using admissionregistration/v1beta1.
-func NewControllerV1beta1(client kubernetes.Interface, secretInformer coreinformers.SecretInformer, webhookInformer admissioninformers.MutatingWebhookConfigurationInformer, isLeaderFunc func() bool, isLeaderNotif <-chan struct{}, config Config) *ControllerV1beta1 {
+func NewControllerV1beta1(client kubernetes.Interface, secretInformer coreinformers.SecretInformer, webhookInformer admissioninformers.MutatingWebhookConfigurationInformer, validatingWebhookInformer admissioninformers.ValidatingWebhookConfigurationInformer, isLeaderFunc func() bool, isLeaderNotif <-chan struct{}, config Config) *ControllerV1beta1 {
 	controller := &ControllerV1beta1{}
 	controller.clientSet = client
 	controller.config = config
@@ -65,6 +68,18 @@ func NewControllerV1beta1(client kubernetes.Interface, secretInformer coreinform
 		DeleteFunc: controller.handleWebhook,
 	})
 
+	// The validating webhook informer is only started when needed as
+	// watching it requires additional permissions
+	if config.useValidation() {
+		controller.validatingWebhooksLister = validatingWebhookInformer.Lister()
+		controller.validatingWebhooksSynced = validatingWebhookInformer.Informer().HasSynced
+		validatingWebhookInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+			AddFunc:    controller.handleWebhook,
+			UpdateFunc: controller.handleValidatingWebhookUpdate,
+			DeleteFunc: controller.handleWebhook,
+		})
+	}
+
 	return controller
 }
 
@@ -76,7 +91,7 @@ func (c *ControllerV1beta1) Run(stopCh <-chan struct{}) {
 	log.Infof("Starting webhook controller for secret %s/%s and webhook %s - Using admissionregistration/v1beta1", c.config.getSecretNs(), c.config.getSecretName(), c.config.getWebhookName())
 	defer log.Infof("Stopping webhook controller for secret %s/%s and webhook %s", c.config.getSecretNs(), c.config.getSecretName(), c.config.getWebhookName())
 
-	if ok := cache.WaitForCacheSync(stopCh, c.secretsSynced, c.webhooksSynced); !ok {
+	if ok := cache.WaitForCacheSync(stopCh, c.informersSynced()...); !ok {
 		return
 	}
 
@@ -121,6 +136,32 @@ func (c *ControllerV1beta1) handleWebhookUpdate(oldObj, newObj interface{}) {
 	c.handleWebhook(newObj)
 }
 
+// handleValidatingWebhookUpdate handles the new validating Webhook reported in update events.
+// It can be a callback function for update events.
+func (c *ControllerV1beta1) handleValidatingWebhookUpdate(oldObj, newObj interface{}) {
+	if !c.isLeaderFunc() {
+		return
+	}
+
+	newWebhook, ok := newObj.(*admiv1beta1.ValidatingWebhookConfiguration)
+	if !ok {
+		log.Debugf("Expected ValidatingWebhookConfiguration object, got: %v", newObj)
+		return
+	}
+
+	oldWebhook, ok := oldObj.(*admiv1beta1.ValidatingWebhookConfiguration)
+	if !ok {
+		log.Debugf("Expected ValidatingWebhookConfiguration object, got: %v", oldObj)
+		return
+	}
+
+	if newWebhook.ResourceVersion == oldWebhook.ResourceVersion {
+		return
+	}
+
+	c.handleWebhook(newObj)
+}
+
 // reconcile creates/updates the webhook object on new events.
 func (c *ControllerV1beta1) reconcile() error {
 	secret, err := c.getSecret()
@@ -129,17 +170,42 @@ func (c *ControllerV1beta1) reconcile() error {
 	}
 
 	webhook, err := c.webhooksLister.Get(c.config.getWebhookName())
+	switch {
+	case errors.IsNotFound(err):
+		log.Infof("Webhook %s was not found, creating it", c.config.getWebhookName())
+		err = c.createWebhook(secret)
+	case err == nil:
+		log.Debugf("The Webhook %s was found, updating it", c.config.getWebhookName())
+		err = c.updateWebhook(secret, webhook)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return c.reconcileValidatingWebhook(secret)
+}
+
+// reconcileValidatingWebhook creates/updates the validating webhook object
+// when the annotations validation is enabled.
+func (c *ControllerV1beta1) reconcileValidatingWebhook(secret *corev1.Secret) error {
+	if c.validatingWebhooksLister == nil {
+		return nil
+	}
+
+	webhook, err := c.validatingWebhooksLister.Get(c.config.getWebhookName())
 	if err != nil {
 		if errors.IsNotFound(err) {
-			log.Infof("Webhook %s was not found, creating it", c.config.getWebhookName())
-			return c.createWebhook(secret)
+			log.Infof("Validating Webhook %s was not found, creating it", c.config.getWebhookName())
+			return c.createValidatingWebhook(secret)
 		}
+
 		return err
 	}
 
-	log.Debugf("The Webhook %s was found, updating it", c.config.getWebhookName())
+	log.Debugf("The validating Webhook %s was found, updating it", c.config.getWebhookName())
 
-	return c.updateWebhook(secret, webhook)
+	return c.updateValidatingWebhook(secret, webhook)
 }
 
 // createWebhook creates a new MutatingWebhookConfiguration object.
@@ -169,6 +235,43 @@ func (c *ControllerV1beta1) updateWebhook(secret *corev1.Secret, webhook *admiv1
 	return err
 }
 
+// createValidatingWebhook creates a new ValidatingWebhookConfiguration object.
+func (c *ControllerV1beta1) createValidatingWebhook(secret *corev1.Secret) error {
+	webhook := &admiv1beta1.ValidatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: c.config.getWebhookName(),
+		},
+		Webhooks: c.newValidatingWebhooks(secret),
+	}
+
+	_, err := c.clientSet.AdmissionregistrationV1beta1().ValidatingWebhookConfigurations().Create(context.TODO(), webhook, metav1.CreateOptions{})
+	if errors.IsAlreadyExists(err) {
+		log.Infof("Validating Webhook %s already exists", webhook.GetName())
+		return nil
+	}
+
+	return err
+}
+
+// updateValidatingWebhook stores a new configuration in the ValidatingWebhookConfiguration object.
+func (c *ControllerV1beta1) updateValidatingWebhook(secret *corev1.Secret, webhook *admiv1beta1.ValidatingWebhookConfiguration) error {
+	webhook = webhook.DeepCopy()
+	webhook.Webhooks = c.newValidatingWebhooks(secret)
+	_, err := c.clientSet.AdmissionregistrationV1beta1().ValidatingWebhookConfigurations().Update(context.TODO(), webhook, metav1.UpdateOptions{})
+	return err
+}
+
+// newValidatingWebhooks generates ValidatingWebhook objects from config templates with updated CABundle from Secret.
+func (c *ControllerV1beta1) newValidatingWebhooks(secret *corev1.Secret) []admiv1beta1.ValidatingWebhook {
+	webhooks := []admiv1beta1.ValidatingWebhook{}
+	for _, tpl := range c.validatingWebhookTemplates {
+		tpl.ClientConfig.CABundle = certificate.GetCABundle(secret.Data)
+		webhooks = append(webhooks, tpl)
+	}
+
+	return webhooks
+}
+
 // newWebhooks generates MutatingWebhook objects from config templates with updated CABundle from Secret.
 func (c *ControllerV1beta1) newWebhooks(secret *corev1.Secret) []admiv1beta1.MutatingWebhook {
 	webhooks := []admiv1beta1.MutatingWebhook{}
@@ -197,11 +300,32 @@ func (c *ControllerV1beta1) generateTemplates() {
 
 	// Auto instrumentation - lib injection
 	if config.Datadog.GetBool("admission_controller.auto_instrumentation.enabled") {
-		webhook := c.getWebhookSkeleton("auto-instrumentation", config.Datadog.GetString("admission_controller.auto_instrumentation.endpoint"))
-		webhooks = append(webhooks, webhook)
+		endpoint := config.Datadog.GetString("admission_controller.auto_instrumentation.endpoint")
+		webhook := c.getWebhookSkeleton("auto-instrumentation", endpoint)
+		if namespaces := autoInstrumentationNamespaces(); len(namespaces) > 0 {
+			// The pods of the namespaces opted-in are injected without the admission label,
+			// they're handled by a dedicated webhook scoped to these namespaces
+			webhook.NamespaceSelector = withNamespaceNames(webhook.NamespaceSelector, metav1.LabelSelectorOpNotIn, namespaces)
+
+			nsWebhook := c.getWebhookSkeleton("auto-instrumentation-namespaces", endpoint)
+			nsWebhook.NamespaceSelector, nsWebhook.ObjectSelector = buildNamespacesLabelSelectors(c.config.useNamespaceSelector(), namespaces)
+			webhooks = append(webhooks, webhook, nsWebhook)
+		} else {
+			webhooks = append(webhooks, webhook)
+		}
 	}
 
 	c.webhookTemplates = webhooks
+
+	validatingWebhooks := []admiv1beta1.ValidatingWebhook{}
+
+	// Autodiscovery annotations validation
+	if c.config.useValidation() {
+		webhook := c.getValidatingWebhookSkeleton("annotations-validation", config.Datadog.GetString("admission_controller.validate_annotations.endpoint"))
+		validatingWebhooks = append(validatingWebhooks, webhook)
+	}
+
+	c.validatingWebhookTemplates = validatingWebhooks
 }
 
 func (c *ControllerV1beta1) getWebhookSkeleton(nameSuffix, path string) admiv1beta1.MutatingWebhook {
@@ -246,6 +370,46 @@ func (c *ControllerV1beta1) getWebhookSkeleton(nameSuffix, path string) admiv1be
 	return webhook
 }
 
+func (c *ControllerV1beta1) getValidatingWebhookSkeleton(nameSuffix, path string) admiv1beta1.ValidatingWebhook {
+	matchPolicy := admiv1beta1.Exact
+	sideEffects := admiv1beta1.SideEffectClassNone
+	port := c.config.getServicePort()
+	timeout := c.config.getTimeout()
+	failurePolicy := c.getAdmiV1Beta1FailurePolicy()
+	webhook := admiv1beta1.ValidatingWebhook{
+		Name: c.config.configName(nameSuffix),
+		ClientConfig: admiv1beta1.WebhookClientConfig{
+			Service: &admiv1beta1.ServiceReference{
+				Namespace: c.config.getServiceNs(),
+				Name:      c.config.getServiceName(),
+				Port:      &port,
+				Path:      &path,
+			},
+		},
+		Rules: []admiv1beta1.RuleWithOperations{
+			{
+				Operations: []admiv1beta1.OperationType{
+					admiv1beta1.Create,
+				},
+				Rule: admiv1beta1.Rule{
+					APIGroups:   []string{""},
+					APIVersions: []string{"v1"},
+					Resources:   []string{"pods"},
+				},
+			},
+		},
+		FailurePolicy:           &failurePolicy,
+		MatchPolicy:             &matchPolicy,
+		SideEffects:             &sideEffects,
+		TimeoutSeconds:          &timeout,
+		AdmissionReviewVersions: []string{"v1beta1"},
+	}
+
+	webhook.NamespaceSelector, webhook.ObjectSelector = buildValidationLabelSelectors(c.config.useNamespaceSelector())
+
+	return webhook
+}
+
 func (c *ControllerV1beta1) getAdmiV1Beta1FailurePolicy() admiv1beta1.FailurePolicyType {
 	policy := strings.ToLower(c.config.getFailurePolicy())
 	switch policy {
@@ -65,4 +65,5 @@ func resetMockConfig(c *config.MockConfig) {
 	c.Set("admission_controller.inject_tags.enabled", true)
 	c.Set("admission_controller.namespace_selector_fallback", false)
 	c.Set("admission_controller.add_aks_selectors", false)
+	c.Set("admission_controller.auto_instrumentation.namespaces", map[string]string{})
 }
@@ -20,6 +20,7 @@ const (
 	WebhooksControllerName = "webhooks"
 	TagsMutationType       = "standard_tags"
 	ConfigMutationType     = "agent_config"
+	AnnotationsValidation  = "ad_annotations"
 )
 
 // Telemetry metrics
@@ -62,4 +63,7 @@ var (
 	LibInjectionErrors = telemetry.NewCounterWithOpts("admission_webhooks", "library_injection_errors",
 		[]string{"language"}, "Number of library injection failures by language",
 		telemetry.Options{NoDoubleUnderscoreSep: true})
+	ValidationAttempts = telemetry.NewCounterWithOpts("admission_webhooks", "validation_attempts",
+		[]string{"validation_type", "result"}, "Number of pod validation attempts by validation type and result (valid, invalid, error).",
+		telemetry.Options{NoDoubleUnderscoreSep: true})
 )
@@ -9,6 +9,7 @@
 package mutate
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"strconv"
@@ -18,6 +19,7 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/config"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
 
+	"gomodules.xyz/jsonpatch/v3"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/client-go/dynamic"
 )
@@ -39,6 +41,24 @@ const (
 	// Python config
 	pythonPathKey   = "PYTHONPATH"
 	pythonPathValue = "/datadog-lib/"
+
+	// .NET config
+	dotnetClrEnableProfilingKey   = "CORECLR_ENABLE_PROFILING"
+	dotnetClrEnableProfilingValue = "1"
+	dotnetClrProfilerIDKey        = "CORECLR_PROFILER"
+	dotnetClrProfilerIDValue      = "{846F5F1C-F9AE-4B07-969E-05C26BC060D8}"
+	dotnetClrProfilerPathKey      = "CORECLR_PROFILER_PATH"
+	dotnetClrProfilerPathValue    = "/datadog-lib/Datadog.Trace.ClrProfiler.Native.so"
+	dotnetTracerHomeKey           = "DD_DOTNET_TRACER_HOME"
+	dotnetTracerHomeValue         = "/datadog-lib"
+
+	// Ruby config
+	rubyOptKey   = "RUBYOPT"
+	rubyOptValue = "-r/datadog-lib/auto_inject"
+
+	// defaultLibVersion is used for the languages
+	// enabled in a namespace without default version
+	defaultLibVersion = "latest"
 )
 
 type language string
@@ -47,20 +67,61 @@ const (
 	java   language = "java"
 	js     language = "js"
 	python language = "python"
+	dotnet language = "dotnet"
+	ruby   language = "ruby"
 )
 
 var (
 	libVersionAnnotationKeyFormat = "admission.datadoghq.com/%s-lib.version"
 	customLibAnnotationKeyFormat  = "admission.datadoghq.com/%s-lib.custom-image"
-	supportedLanguages            = []language{java, js, python}
+	dryRunPatchAnnotationKey      = "admission.datadoghq.com/auto-instrumentation.dry-run-patch"
+	supportedLanguages            = []language{java, js, python, dotnet, ruby}
 )
 
+// libEnv is an environment variable configuring a library
+type libEnv struct {
+	key     string
+	valFunc envValFunc
+}
+
+// libEnvs contains the environment variables to inject for each language
+var libEnvs = map[language][]libEnv{
+	java:   {{key: javaToolOptionsKey, valFunc: javaEnvValFunc}},
+	js:     {{key: nodeOptionsKey, valFunc: jsEnvValFunc}},
+	python: {{key: pythonPathKey, valFunc: pythonEnvValFunc}},
+	dotnet: {
+		{key: dotnetClrEnableProfilingKey, valFunc: overrideEnvValFunc(dotnetClrEnableProfilingValue)},
+		{key: dotnetClrProfilerIDKey, valFunc: overrideEnvValFunc(dotnetClrProfilerIDValue)},
+		{key: dotnetClrProfilerPathKey, valFunc: overrideEnvValFunc(dotnetClrProfilerPathValue)},
+		{key: dotnetTracerHomeKey, valFunc: overrideEnvValFunc(dotnetTracerHomeValue)},
+	},
+	ruby: {{key: rubyOptKey, valFunc: rubyEnvValFunc}},
+}
+
+// injectionConfig holds the cluster-wide library injection settings
+type injectionConfig struct {
+	containerRegistry string
+	// namespaces maps the namespaces opted-in to the language to inject
+	namespaces map[string]string
+	// defaultVersions maps languages to the library version to inject
+	// in the namespaces opted-in
+	defaultVersions map[string]string
+}
+
+func newInjectionConfig() injectionConfig {
+	return injectionConfig{
+		containerRegistry: config.Datadog.GetString("admission_controller.auto_instrumentation.container_registry"),
+		namespaces:        config.Datadog.GetStringMapString("admission_controller.auto_instrumentation.namespaces"),
+		defaultVersions:   config.Datadog.GetStringMapString("admission_controller.auto_instrumentation.default_versions"),
+	}
+}
+
 // InjectAutoInstrumentation injects APM libraries into pods
 func InjectAutoInstrumentation(rawPod []byte, ns string, dc dynamic.Interface) ([]byte, error) {
 	return mutate(rawPod, ns, injectAutoInstrumentation, dc)
 }
 
-func injectAutoInstrumentation(pod *corev1.Pod, _ string, _ dynamic.Interface) error {
+func injectAutoInstrumentation(pod *corev1.Pod, ns string, _ dynamic.Interface) error {
 	if pod == nil {
 		return errors.New("cannot inject lib into nil pod")
 	}
@@ -72,17 +133,28 @@ func injectAutoInstrumentation(pod *corev1.Pod, _ string, _ dynamic.Interface) e
 		return nil
 	}
 
-	language, image, shouldInject := extractLibInfo(pod, config.Datadog.GetString("admission_controller.auto_instrumentation.container_registry"))
+	if _, found := pod.GetAnnotations()[dryRunPatchAnnotationKey]; found {
+		// The admission can be reinvocated for the same pod
+		log.Debugf("Dry-run patch already recorded in pod %q", podString(pod))
+		return nil
+	}
+
+	language, image, shouldInject := extractLibInfo(pod, ns, newInjectionConfig())
 	if !shouldInject {
 		return nil
 	}
 
+	if config.Datadog.GetBool("admission_controller.auto_instrumentation.dry_run") {
+		return recordDryRunPatch(pod, language, image)
+	}
+
 	return injectAutoInstruConfig(pod, language, image)
 }
 
 // extractLibInfo returns the language, the image,
-// and a boolean indicating whether the library should be injected into the pod
-func extractLibInfo(pod *corev1.Pod, containerRegistry string) (language, string, bool) {
+// and a boolean indicating whether the library should be injected into the pod.
+// Pod annotations take precedence over the namespace opt-in.
+func extractLibInfo(pod *corev1.Pod, ns string, cfg injectionConfig) (language, string, bool) {
 	podAnnotations := pod.GetAnnotations()
 	for _, lang := range supportedLanguages {
 		customLibAnnotation := strings.ToLower(fmt.Sprintf(customLibAnnotationKeyFormat, lang))
@@ -92,12 +164,32 @@ func extractLibInfo(pod *corev1.Pod, containerRegistry string) (language, string
 
 		libVersionAnnotation := strings.ToLower(fmt.Sprintf(libVersionAnnotationKeyFormat, lang))
 		if version, found := podAnnotations[libVersionAnnotation]; found {
-			image := fmt.Sprintf("%s/dd-lib-%s-init:%s", containerRegistry, lang, version)
-			return lang, image, true
+			return lang, libImage(cfg.containerRegistry, lang, version), true
 		}
 	}
 
-	return "", "", false
+	nsLang, found := cfg.namespaces[ns]
+	if !found {
+		return "", "", false
+	}
+
+	lang := language(strings.ToLower(nsLang))
+	if _, supported := libEnvs[lang]; !supported {
+		log.Warnf("Cannot inject library into pod %s: language %q of namespace %q is not supported. Supported languages are %v", podString(pod), nsLang, ns, supportedLanguages)
+		return "", "", false
+	}
+
+	version, found := cfg.defaultVersions[string(lang)]
+	if !found {
+		version = defaultLibVersion
+	}
+
+	return lang, libImage(cfg.containerRegistry, lang, version), true
+}
+
+// libImage returns the image of the library init container
+func libImage(containerRegistry string, lang language, version string) string {
+	return fmt.Sprintf("%s/dd-lib-%s-init:%s", containerRegistry, lang, version)
 }
 
 func injectAutoInstruConfig(pod *corev1.Pod, lang language, image string) error {
@@ -107,38 +199,67 @@ func injectAutoInstruConfig(pod *corev1.Pod, lang language, image string) error
 		metrics.LibInjectionAttempts.Inc(langStr, strconv.FormatBool(injected))
 	}()
 
-	switch lang {
-	case java:
-		injectLibInitContainer(pod, image)
-		err := injectLibConfig(pod, javaToolOptionsKey, javaEnvValFunc)
-		if err != nil {
-			metrics.LibInjectionErrors.Inc(langStr)
-			return err
-		}
+	if err := injectLib(pod, lang, image); err != nil {
+		metrics.LibInjectionErrors.Inc(langStr)
+		return err
+	}
 
-	case js:
-		injectLibInitContainer(pod, image)
-		err := injectLibConfig(pod, nodeOptionsKey, jsEnvValFunc)
-		if err != nil {
-			metrics.LibInjectionErrors.Inc(langStr)
-			return err
-		}
+	injected = true
 
-	case python:
-		injectLibInitContainer(pod, image)
-		err := injectLibConfig(pod, pythonPathKey, pythonEnvValFunc)
-		if err != nil {
-			metrics.LibInjectionErrors.Inc(langStr)
-			return err
-		}
+	return nil
+}
 
-	default:
-		metrics.LibInjectionErrors.Inc(langStr)
+// injectLib injects the init container, the environment variables
+// and the volume needed by the library of the given language
+func injectLib(pod *corev1.Pod, lang language, image string) error {
+	envs, found := libEnvs[lang]
+	if !found {
 		return fmt.Errorf("language %q is not supported. Supported languages are %v", lang, supportedLanguages)
 	}
 
+	injectLibInitContainer(pod, image)
+	if err := injectLibConfig(pod, envs); err != nil {
+		return err
+	}
+
 	injectLibVolume(pod)
-	injected = true
+
+	return nil
+}
+
+// recordDryRunPatch computes the JSON patch the library injection would apply
+// and records it in a pod annotation instead of applying it
+func recordDryRunPatch(pod *corev1.Pod, lang language, image string) error {
+	original, err := json.Marshal(pod)
+	if err != nil {
+		return fmt.Errorf("failed to encode the pod: %v", err)
+	}
+
+	injectedPod := pod.DeepCopy()
+	if err := injectLib(injectedPod, lang, image); err != nil {
+		return err
+	}
+
+	injected, err := json.Marshal(injectedPod)
+	if err != nil {
+		return fmt.Errorf("failed to encode the injected pod: %v", err)
+	}
+
+	patch, err := jsonpatch.CreatePatch(original, injected)
+	if err != nil {
+		return fmt.Errorf("failed to prepare the JSON patch: %v", err)
+	}
+
+	rawPatch, err := json.Marshal(patch)
+	if err != nil {
+		return fmt.Errorf("failed to encode the JSON patch: %v", err)
+	}
+
+	log.Debugf("Recording the %s library injection dry-run patch in pod %s", lang, podString(pod))
+	if pod.Annotations == nil {
+		pod.Annotations = make(map[string]string)
+	}
+	pod.Annotations[dryRunPatchAnnotationKey] = string(rawPatch)
 
 	return nil
 }
@@ -160,20 +281,22 @@ func injectLibInitContainer(pod *corev1.Pod, image string) {
 	}, pod.Spec.InitContainers...)
 }
 
-func injectLibConfig(pod *corev1.Pod, envKey string, envVal envValFunc) error {
-	for i, ctr := range pod.Spec.Containers {
-		index := envIndex(ctr.Env, envKey)
-		if index < 0 {
-			pod.Spec.Containers[i].Env = append(pod.Spec.Containers[i].Env, corev1.EnvVar{
-				Name:  envKey,
-				Value: envVal(""),
-			})
-		} else {
-			if pod.Spec.Containers[i].Env[index].ValueFrom != nil {
-				return fmt.Errorf("%q is defined via ValueFrom", envKey)
+func injectLibConfig(pod *corev1.Pod, envs []libEnv) error {
+	for i := range pod.Spec.Containers {
+		for _, env := range envs {
+			index := envIndex(pod.Spec.Containers[i].Env, env.key)
+			if index < 0 {
+				pod.Spec.Containers[i].Env = append(pod.Spec.Containers[i].Env, corev1.EnvVar{
+					Name:  env.key,
+					Value: env.valFunc(""),
+				})
+			} else {
+				if pod.Spec.Containers[i].Env[index].ValueFrom != nil {
+					return fmt.Errorf("%q is defined via ValueFrom", env.key)
+				}
+
+				pod.Spec.Containers[i].Env[index].Value = env.valFunc(pod.Spec.Containers[i].Env[index].Value)
 			}
-
-			pod.Spec.Containers[i].Env[index].Value = envVal(pod.Spec.Containers[i].Env[index].Value)
 		}
 
 		pod.Spec.Containers[i].VolumeMounts = append(pod.Spec.Containers[i].VolumeMounts, corev1.VolumeMount{Name: volumeName, MountPath: mountPath})
@@ -217,3 +340,17 @@ func pythonEnvValFunc(predefinedVal string) string {
 	}
 	return fmt.Sprintf("%s:%s", pythonPathValue, predefinedVal)
 }
+
+func rubyEnvValFunc(predefinedVal string) string {
+	if predefinedVal == "" {
+		return rubyOptValue
+	}
+	return fmt.Sprintf("%s %s", predefinedVal, rubyOptValue)
+}
+
+// overrideEnvValFunc returns an envValFunc replacing any predefined value
+func overrideEnvValFunc(val string) envValFunc {
+	return func(string) string {
+		return val
+	}
+}
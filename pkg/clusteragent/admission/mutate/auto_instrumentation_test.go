@@ -9,10 +9,14 @@
 package mutate
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	"gomodules.xyz/jsonpatch/v3"
 	corev1 "k8s.io/api/core/v1"
+
+	"github.com/DataDog/datadog-agent/pkg/config"
 )
 
 func TestInjectAutoInstruConfig(t *testing.T) {
@@ -100,6 +104,56 @@ func TestInjectAutoInstruConfig(t *testing.T) {
 			image:   "gcr.io/datadoghq/dd-lib-python-init:v1",
 			wantErr: true,
 		},
+		{
+			name:           "nominal case: dotnet",
+			pod:            fakePod("dotnet-pod"),
+			lang:           "dotnet",
+			image:          "gcr.io/datadoghq/dd-lib-dotnet-init:v1",
+			expectedEnvKey: "CORECLR_PROFILER_PATH",
+			expectedEnvVal: "/datadog-lib/Datadog.Trace.ClrProfiler.Native.so",
+			wantErr:        false,
+		},
+		{
+			name:           "CORECLR_ENABLE_PROFILING overridden",
+			pod:            fakePodWithEnvValue("dotnet-pod", "CORECLR_ENABLE_PROFILING", "0"),
+			lang:           "dotnet",
+			image:          "gcr.io/datadoghq/dd-lib-dotnet-init:v1",
+			expectedEnvKey: "CORECLR_ENABLE_PROFILING",
+			expectedEnvVal: "1",
+			wantErr:        false,
+		},
+		{
+			name:    "CORECLR_PROFILER set via ValueFrom",
+			pod:     fakePodWithEnvFieldRefValue("dotnet-pod", "CORECLR_PROFILER", "path"),
+			lang:    "dotnet",
+			image:   "gcr.io/datadoghq/dd-lib-dotnet-init:v1",
+			wantErr: true,
+		},
+		{
+			name:           "nominal case: ruby",
+			pod:            fakePod("ruby-pod"),
+			lang:           "ruby",
+			image:          "gcr.io/datadoghq/dd-lib-ruby-init:v1",
+			expectedEnvKey: "RUBYOPT",
+			expectedEnvVal: "-r/datadog-lib/auto_inject",
+			wantErr:        false,
+		},
+		{
+			name:           "RUBYOPT not empty",
+			pod:            fakePodWithEnvValue("ruby-pod", "RUBYOPT", "-W0"),
+			lang:           "ruby",
+			image:          "gcr.io/datadoghq/dd-lib-ruby-init:v1",
+			expectedEnvKey: "RUBYOPT",
+			expectedEnvVal: "-W0 -r/datadog-lib/auto_inject",
+			wantErr:        false,
+		},
+		{
+			name:    "RUBYOPT set via ValueFrom",
+			pod:     fakePodWithEnvFieldRefValue("ruby-pod", "RUBYOPT", "path"),
+			lang:    "ruby",
+			image:   "gcr.io/datadoghq/dd-lib-ruby-init:v1",
+			wantErr: true,
+		},
 		{
 			name:    "Unknown language",
 			pod:     fakePod("unknown-pod"),
@@ -165,7 +219,8 @@ func TestExtractLibInfo(t *testing.T) {
 	tests := []struct {
 		name                 string
 		pod                  *corev1.Pod
-		containerRegistry    string
+		namespace            string
+		config               injectionConfig
 		expectedLangauge     language
 		expectedImage        string
 		expectedShouldInject bool
@@ -173,7 +228,7 @@ func TestExtractLibInfo(t *testing.T) {
 		{
 			name:                 "java",
 			pod:                  fakePodWithAnnotation("admission.datadoghq.com/java-lib.version", "v1"),
-			containerRegistry:    "registry",
+			config:               injectionConfig{containerRegistry: "registry"},
 			expectedLangauge:     "java",
 			expectedImage:        "registry/dd-lib-java-init:v1",
 			expectedShouldInject: true,
@@ -181,7 +236,7 @@ func TestExtractLibInfo(t *testing.T) {
 		{
 			name:                 "js",
 			pod:                  fakePodWithAnnotation("admission.datadoghq.com/js-lib.version", "v1"),
-			containerRegistry:    "registry",
+			config:               injectionConfig{containerRegistry: "registry"},
 			expectedLangauge:     "js",
 			expectedImage:        "registry/dd-lib-js-init:v1",
 			expectedShouldInject: true,
@@ -189,7 +244,7 @@ func TestExtractLibInfo(t *testing.T) {
 		{
 			name:                 "python",
 			pod:                  fakePodWithAnnotation("admission.datadoghq.com/python-lib.version", "v1"),
-			containerRegistry:    "registry",
+			config:               injectionConfig{containerRegistry: "registry"},
 			expectedLangauge:     "python",
 			expectedImage:        "registry/dd-lib-python-init:v1",
 			expectedShouldInject: true,
@@ -197,15 +252,75 @@ func TestExtractLibInfo(t *testing.T) {
 		{
 			name:                 "custom",
 			pod:                  fakePodWithAnnotation("admission.datadoghq.com/java-lib.custom-image", "custom/image"),
-			containerRegistry:    "registry",
+			config:               injectionConfig{containerRegistry: "registry"},
 			expectedLangauge:     "java",
 			expectedImage:        "custom/image",
 			expectedShouldInject: true,
 		},
+		{
+			name:                 "ruby",
+			pod:                  fakePodWithAnnotation("admission.datadoghq.com/ruby-lib.version", "v1"),
+			config:               injectionConfig{containerRegistry: "registry"},
+			expectedLangauge:     "ruby",
+			expectedImage:        "registry/dd-lib-ruby-init:v1",
+			expectedShouldInject: true,
+		},
+		{
+			name:                 "namespace opted-in",
+			pod:                  fakePod("dotnet-pod"),
+			namespace:            "payments",
+			config:               injectionConfig{containerRegistry: "registry", namespaces: map[string]string{"payments": "dotnet"}},
+			expectedLangauge:     "dotnet",
+			expectedImage:        "registry/dd-lib-dotnet-init:latest",
+			expectedShouldInject: true,
+		},
+		{
+			name:      "namespace opted-in with a default version",
+			pod:       fakePod("dotnet-pod"),
+			namespace: "payments",
+			config: injectionConfig{
+				containerRegistry: "registry",
+				namespaces:        map[string]string{"payments": "dotnet"},
+				defaultVersions:   map[string]string{"dotnet": "v2"},
+			},
+			expectedLangauge:     "dotnet",
+			expectedImage:        "registry/dd-lib-dotnet-init:v2",
+			expectedShouldInject: true,
+		},
+		{
+			name:      "pod annotation takes precedence over the namespace",
+			pod:       fakePodWithAnnotation("admission.datadoghq.com/java-lib.version", "v1"),
+			namespace: "payments",
+			config: injectionConfig{
+				containerRegistry: "registry",
+				namespaces:        map[string]string{"payments": "dotnet"},
+			},
+			expectedLangauge:     "java",
+			expectedImage:        "registry/dd-lib-java-init:v1",
+			expectedShouldInject: true,
+		},
+		{
+			name:                 "namespace not opted-in",
+			pod:                  fakePod("dotnet-pod"),
+			namespace:            "default",
+			config:               injectionConfig{containerRegistry: "registry", namespaces: map[string]string{"payments": "dotnet"}},
+			expectedLangauge:     "",
+			expectedImage:        "",
+			expectedShouldInject: false,
+		},
+		{
+			name:                 "namespace opted-in with an unknown language",
+			pod:                  fakePod("php-pod"),
+			namespace:            "payments",
+			config:               injectionConfig{containerRegistry: "registry", namespaces: map[string]string{"payments": "php"}},
+			expectedLangauge:     "",
+			expectedImage:        "",
+			expectedShouldInject: false,
+		},
 		{
 			name:                 "unknown",
 			pod:                  fakePodWithAnnotation("admission.datadoghq.com/unknown-lib.version", "v1"),
-			containerRegistry:    "registry",
+			config:               injectionConfig{containerRegistry: "registry"},
 			expectedLangauge:     "",
 			expectedImage:        "",
 			expectedShouldInject: false,
@@ -213,10 +328,43 @@ func TestExtractLibInfo(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			lang, image, shouldInject := extractLibInfo(tt.pod, tt.containerRegistry)
+			lang, image, shouldInject := extractLibInfo(tt.pod, tt.namespace, tt.config)
 			require.Equal(t, tt.expectedLangauge, lang)
 			require.Equal(t, tt.expectedImage, image)
 			require.Equal(t, tt.expectedShouldInject, shouldInject)
 		})
 	}
 }
+
+func TestInjectAutoInstrumentationDryRun(t *testing.T) {
+	mockConfig := config.Mock(t)
+	mockConfig.Set("admission_controller.auto_instrumentation.dry_run", true)
+	defer mockConfig.Set("admission_controller.auto_instrumentation.dry_run", false)
+
+	pod := fakePod("ruby-pod")
+	pod.Annotations = map[string]string{"admission.datadoghq.com/ruby-lib.version": "v1"}
+	require.NoError(t, injectAutoInstrumentation(pod, "default", nil))
+
+	// The pod is left untouched apart from the recorded patch
+	require.Empty(t, pod.Spec.InitContainers)
+	require.Empty(t, pod.Spec.Volumes)
+	require.Empty(t, pod.Spec.Containers[0].Env)
+
+	rawPatch, found := pod.Annotations[dryRunPatchAnnotationKey]
+	require.True(t, found)
+
+	var patch []jsonpatch.Operation
+	require.NoError(t, json.Unmarshal([]byte(rawPatch), &patch))
+	require.NotEmpty(t, patch)
+
+	paths := make([]string, 0, len(patch))
+	for _, op := range patch {
+		paths = append(paths, op.Path)
+	}
+	require.Contains(t, paths, "/spec/initContainers")
+	require.Contains(t, paths, "/spec/volumes")
+
+	// The admission can be reinvocated, the patch is recorded once
+	require.NoError(t, injectAutoInstrumentation(pod, "default", nil))
+	require.Equal(t, rawPatch, pod.Annotations[dryRunPatchAnnotationKey])
+}
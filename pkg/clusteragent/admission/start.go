@@ -88,12 +88,19 @@ func StartControllers(ctx ControllerContext) error {
 		apiserver.SecretsInformer: ctx.SecretInformers.Core().V1().Secrets().Informer(),
 	}
 
+	validationEnabled := config.Datadog.GetBool("admission_controller.validate_annotations.enabled")
 	if v1Enabled {
 		informers[apiserver.WebhooksInformer] = ctx.WebhookInformers.Admissionregistration().V1().MutatingWebhookConfigurations().Informer()
 		getWebhookStatus = getWebhookStatusV1
+		if validationEnabled {
+			informers[apiserver.ValidatingWebhooksInformer] = ctx.WebhookInformers.Admissionregistration().V1().ValidatingWebhookConfigurations().Informer()
+		}
 	} else {
 		informers[apiserver.WebhooksInformer] = ctx.WebhookInformers.Admissionregistration().V1beta1().MutatingWebhookConfigurations().Informer()
 		getWebhookStatus = getWebhookStatusV1beta1
+		if validationEnabled {
+			informers[apiserver.ValidatingWebhooksInformer] = ctx.WebhookInformers.Admissionregistration().V1beta1().ValidatingWebhookConfigurations().Informer()
+		}
 	}
 
 	return apiserver.SyncInformers(informers, 0)
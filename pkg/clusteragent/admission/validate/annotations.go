@@ -0,0 +1,221 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build kubeapiserver
+// +build kubeapiserver
+
+package validate
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/DataDog/datadog-agent/pkg/autodiscovery/common/utils"
+	"github.com/DataDog/datadog-agent/pkg/autodiscovery/configresolver"
+	"github.com/DataDog/datadog-agent/pkg/autodiscovery/integration"
+	"github.com/DataDog/datadog-agent/pkg/clusteragent/admission/metrics"
+	"github.com/DataDog/datadog-agent/pkg/config"
+	logsconfig "github.com/DataDog/datadog-agent/pkg/logs/config"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+	"github.com/DataDog/datadog-agent/pkg/util/tmplvar"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/dynamic"
+)
+
+const (
+	// rejectMode denies the pods with invalid annotations,
+	// any other mode admits them with warnings
+	rejectMode = "reject"
+
+	legacyAnnotationPrefix = "service-discovery.datadoghq.com/"
+)
+
+// AutodiscoveryAnnotations validates the autodiscovery annotations of a pod
+// the same way the node agent parses them. The problems found are returned as
+// warnings, or as an error denying the pod when the validation mode is "reject".
+func AutodiscoveryAnnotations(rawPod []byte, _ string, _ dynamic.Interface) ([]string, error) {
+	var pod corev1.Pod
+	if err := json.Unmarshal(rawPod, &pod); err != nil {
+		metrics.ValidationAttempts.Inc(metrics.AnnotationsValidation, "error")
+		log.Warnf("Cannot validate autodiscovery annotations, failed to decode raw object: %v", err)
+		return nil, nil
+	}
+
+	problems := validatePod(&pod, knownChecks())
+	if len(problems) == 0 {
+		metrics.ValidationAttempts.Inc(metrics.AnnotationsValidation, "valid")
+		return nil, nil
+	}
+
+	metrics.ValidationAttempts.Inc(metrics.AnnotationsValidation, "invalid")
+	log.Debugf("Pod %s has invalid autodiscovery annotations: %s", podString(&pod), strings.Join(problems, "; "))
+
+	if strings.ToLower(config.Datadog.GetString("admission_controller.validate_annotations.mode")) == rejectMode {
+		return nil, fmt.Errorf("invalid autodiscovery annotations: %s", strings.Join(problems, "; "))
+	}
+
+	return problems, nil
+}
+
+// knownChecks returns the check names that can be scheduled through annotations,
+// nil means any check name is accepted
+func knownChecks() map[string]struct{} {
+	names := config.Datadog.GetStringSlice("admission_controller.validate_annotations.known_checks")
+	if len(names) == 0 {
+		return nil
+	}
+
+	checks := make(map[string]struct{}, len(names))
+	for _, name := range names {
+		checks[name] = struct{}{}
+	}
+
+	return checks
+}
+
+// validatePod returns the problems found in the autodiscovery annotations of a pod
+func validatePod(pod *corev1.Pod, knownChecks map[string]struct{}) []string {
+	if !hasADAnnotations(pod.Annotations) {
+		return nil
+	}
+
+	var problems []string
+
+	containers := make([]corev1.Container, 0, len(pod.Spec.Containers)+len(pod.Spec.InitContainers))
+	containers = append(containers, pod.Spec.Containers...)
+	containers = append(containers, pod.Spec.InitContainers...)
+
+	containerIdentifiers := make(map[string]struct{}, len(containers))
+	containerNames := make(map[string]struct{}, len(containers))
+	for i := range containers {
+		container := &containers[i]
+
+		adIdentifier := container.Name
+		if customADID, found := utils.ExtractCheckIDFromPodAnnotations(pod.Annotations, container.Name); found {
+			adIdentifier = customADID
+		}
+		containerIdentifiers[adIdentifier] = struct{}{}
+		containerNames[container.Name] = struct{}{}
+
+		configs, errs := utils.ExtractTemplatesFromPodAnnotations(container.Name, pod.Annotations, adIdentifier)
+		for _, err := range errs {
+			problems = append(problems, fmt.Sprintf("container %s: %v", container.Name, err))
+		}
+
+		for _, c := range configs {
+			for _, problem := range validateConfig(container, c, knownChecks) {
+				problems = append(problems, fmt.Sprintf("container %s: %s", container.Name, problem))
+			}
+		}
+	}
+
+	for _, err := range utils.ValidateAnnotationsMatching(pod.Annotations, containerIdentifiers, containerNames) {
+		problems = append(problems, err.Error())
+	}
+
+	sort.Strings(problems)
+
+	return problems
+}
+
+// validateConfig returns the problems found in a configuration extracted from the annotations
+func validateConfig(container *corev1.Container, c integration.Config, knownChecks map[string]struct{}) []string {
+	var problems []string
+
+	if !c.IsLogConfig() {
+		if c.Name == "" {
+			problems = append(problems, "empty check name")
+		} else if _, found := knownChecks[c.Name]; knownChecks != nil && !found {
+			problems = append(problems, fmt.Sprintf("unknown check %q", c.Name))
+		}
+	}
+
+	data := []integration.Data{c.InitConfig, c.LogsConfig}
+	data = append(data, c.Instances...)
+	seen := make(map[string]struct{})
+	for _, d := range data {
+		for _, v := range tmplvar.Parse(d) {
+			raw := string(v.Raw)
+			if _, found := seen[raw]; found {
+				continue
+			}
+			seen[raw] = struct{}{}
+
+			name, key := string(v.Name), string(v.Key)
+			if !configresolver.IsTemplateVariable(name) {
+				problems = append(problems, fmt.Sprintf("unknown template variable %s", raw))
+			} else if name == "port" {
+				if problem := portProblem(container, key); problem != "" {
+					problems = append(problems, fmt.Sprintf("template variable %s can't be resolved: %s", raw, problem))
+				}
+			}
+		}
+	}
+
+	if c.IsLogConfig() {
+		logsConfigs, err := logsconfig.ParseJSON(c.LogsConfig)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("invalid logs configuration: %v", err))
+		}
+		for _, lc := range logsConfigs {
+			if err := logsconfig.ValidateProcessingRules(lc.ProcessingRules); err != nil {
+				problems = append(problems, fmt.Sprintf("invalid log processing rules: %v", err))
+			}
+		}
+	}
+
+	return problems
+}
+
+// portProblem returns why a `%%port%%` template variable can't be resolved
+// from the ports of the container, the same way autodiscovery resolves it
+func portProblem(container *corev1.Container, key string) string {
+	if len(container.Ports) == 0 {
+		return "the container doesn't declare any port"
+	}
+
+	if key == "" {
+		return ""
+	}
+
+	idx, err := strconv.Atoi(key)
+	if err != nil {
+		for _, port := range container.Ports {
+			if port.Name == key {
+				return ""
+			}
+		}
+		return fmt.Sprintf("the container has no port named %s", key)
+	}
+
+	if idx < 0 || idx >= len(container.Ports) {
+		return fmt.Sprintf("the container declares %d port(s)", len(container.Ports))
+	}
+
+	return ""
+}
+
+// hasADAnnotations returns whether the annotations contain autodiscovery annotations
+func hasADAnnotations(annotations map[string]string) bool {
+	for annotation := range annotations {
+		if strings.HasPrefix(annotation, utils.KubeAnnotationPrefix) || strings.HasPrefix(annotation, legacyAnnotationPrefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// podString returns a string that helps identify the pod
+func podString(pod *corev1.Pod) string {
+	if pod.GetNamespace() == "" || pod.GetName() == "" {
+		return fmt.Sprintf("with generate name %s", pod.GetGenerateName())
+	}
+	return fmt.Sprintf("%s/%s", pod.GetNamespace(), pod.GetName())
+}
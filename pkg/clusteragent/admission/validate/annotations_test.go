@@ -0,0 +1,153 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build kubeapiserver
+// +build kubeapiserver
+
+package validate
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/DataDog/datadog-agent/pkg/config"
+)
+
+func fakePod(annotations map[string]string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "redis",
+			Namespace:   "default",
+			Annotations: annotations,
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name: "redis",
+					Ports: []corev1.ContainerPort{
+						{Name: "redis", ContainerPort: 6379},
+					},
+				},
+				{
+					Name: "sidecar",
+				},
+			},
+			InitContainers: []corev1.Container{
+				{
+					Name: "init",
+				},
+			},
+		},
+	}
+}
+
+func TestValidatePod(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		knownChecks map[string]struct{}
+		want        []string
+	}{
+		{
+			name:        "no annotations",
+			annotations: map[string]string{"foo": "bar"},
+		},
+		{
+			name: "valid v1 annotations",
+			annotations: map[string]string{
+				"ad.datadoghq.com/redis.check_names":  `["redisdb"]`,
+				"ad.datadoghq.com/redis.init_configs": `[{}]`,
+				"ad.datadoghq.com/redis.instances":    `[{"host": "%%host%%", "port": "%%port_redis%%", "tags": ["pod:%%kube_pod_name%%"]}]`,
+				"ad.datadoghq.com/redis.logs":         `[{"source": "redis", "log_processing_rules": [{"type": "exclude_at_match", "name": "exclude_info", "pattern": "INFO"}]}]`,
+			},
+			knownChecks: map[string]struct{}{"redisdb": {}},
+		},
+		{
+			name: "valid v2 annotations with a custom identifier",
+			annotations: map[string]string{
+				"ad.datadoghq.com/redis.check.id": "cache",
+				"ad.datadoghq.com/cache.checks":   `{"redisdb": {"instances": [{"host": "%%host%%", "port": "%%port_0%%"}]}}`,
+			},
+		},
+		{
+			name: "invalid JSON",
+			annotations: map[string]string{
+				"ad.datadoghq.com/redis.checks": `{"redisdb": {"instances": [{"host": "%%host%%"}]}`,
+			},
+			want: []string{"container redis: cannot parse check configuration: unexpected end of JSON input"},
+		},
+		{
+			name: "unknown check and template variables",
+			annotations: map[string]string{
+				"ad.datadoghq.com/redis.checks":   `{"redis": {"instances": [{"host": "%%hots%%", "port": "%%port_http%%"}]}}`,
+				"ad.datadoghq.com/sidecar.checks": `{"redisdb": {"instances": [{"port": "%%port%%"}]}}`,
+				"ad.datadoghq.com/init.checks":    `{"redisdb": {"instances": [{"port": "%%env_PORT%%"}]}}`,
+			},
+			knownChecks: map[string]struct{}{"redisdb": {}},
+			want: []string{
+				"container redis: template variable %%port_http%% can't be resolved: the container has no port named http",
+				"container redis: unknown check \"redis\"",
+				"container redis: unknown template variable %%hots%%",
+				"container sidecar: template variable %%port%% can't be resolved: the container doesn't declare any port",
+			},
+		},
+		{
+			name: "invalid log processing rules",
+			annotations: map[string]string{
+				"ad.datadoghq.com/redis.logs": `[{"source": "redis", "log_processing_rules": [{"type": "exclude_at_match", "name": "exclude_info", "pattern": "(INFO"}]}]`,
+			},
+			want: []string{"container redis: invalid log processing rules: invalid pattern (INFO for processing rule: exclude_info"},
+		},
+		{
+			name: "annotation not matching a container",
+			annotations: map[string]string{
+				"ad.datadoghq.com/redis-server.logs": `[{"source": "redis"}]`,
+			},
+			want: []string{"annotation ad.datadoghq.com/redis-server.logs is invalid: redis-server doesn't match a container identifier [init redis sidecar]"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, validatePod(fakePod(tt.annotations), tt.knownChecks))
+		})
+	}
+}
+
+func TestAutodiscoveryAnnotations(t *testing.T) {
+	mockConfig := config.Mock(t)
+
+	rawPod, err := json.Marshal(fakePod(map[string]string{
+		"ad.datadoghq.com/redis.checks": `{"redisdb": {"instances": [{"host": "%%hots%%"}]}}`,
+	}))
+	require.NoError(t, err)
+
+	mockConfig.Set("admission_controller.validate_annotations.mode", "warn")
+	warnings, err := AutodiscoveryAnnotations(rawPod, "default", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"container redis: unknown template variable %%hots%%"}, warnings)
+
+	mockConfig.Set("admission_controller.validate_annotations.mode", "reject")
+	defer mockConfig.Set("admission_controller.validate_annotations.mode", "warn")
+	warnings, err = AutodiscoveryAnnotations(rawPod, "default", nil)
+	assert.EqualError(t, err, "invalid autodiscovery annotations: container redis: unknown template variable %%hots%%")
+	assert.Empty(t, warnings)
+
+	validPod, err := json.Marshal(fakePod(nil))
+	require.NoError(t, err)
+	warnings, err = AutodiscoveryAnnotations(validPod, "default", nil)
+	assert.NoError(t, err)
+	assert.Empty(t, warnings)
+
+	// objects that can't be decoded are not validated
+	warnings, err = AutodiscoveryAnnotations([]byte("{"), "default", nil)
+	assert.NoError(t, err)
+	assert.Empty(t, warnings)
+}
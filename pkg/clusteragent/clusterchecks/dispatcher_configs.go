@@ -28,8 +28,14 @@ func (d *dispatcher) getState() (types.StateResponse, error) {
 	defer d.store.RUnlock()
 
 	response := types.StateResponse{
-		Warmup:   !d.store.active,
-		Dangling: makeConfigArray(d.store.danglingConfigs),
+		Warmup:          !d.store.active,
+		Dangling:        makeConfigArray(d.store.danglingConfigs),
+		DanglingReasons: make(map[string]string, len(d.store.danglingReasons)),
+	}
+	for id, digest := range d.store.idToDigest {
+		if reason, found := d.store.danglingReasons[digest]; found {
+			response.DanglingReasons[string(id)] = reason
+		}
 	}
 	for _, node := range d.store.nodes {
 		n := types.StateNodeResponse{
@@ -50,7 +56,8 @@ func (d *dispatcher) addConfig(config integration.Config, targetNodeName string)
 	digest := config.Digest()
 	d.store.digestToConfig[digest] = config
 	for _, instance := range config.Instances {
-		checkID := check.BuildID(config.Name, instance, config.InitConfig)
+		// Node agents build the check IDs from the instances they receive
+		checkID := check.BuildID(config.Name, instanceWithoutPlacement(instance), config.InitConfig)
 		d.store.idToDigest[checkID] = digest
 		if targetNodeName != "" {
 			configsInfo.Set(1.0, targetNodeName, string(checkID), le.JoinLeaderValue)
@@ -63,6 +70,7 @@ func (d *dispatcher) addConfig(config integration.Config, targetNodeName string)
 		d.store.danglingConfigs[digest] = config
 		return
 	}
+	delete(d.store.danglingReasons, digest)
 
 	currentNode, foundCurrent := d.store.getNodeStore(d.store.digestToNode[digest])
 	targetNode := d.store.getOrCreateNodeStore(targetNodeName, "")
@@ -92,10 +100,14 @@ func (d *dispatcher) removeConfig(digest string) {
 	delete(d.store.digestToNode, digest)
 	delete(d.store.digestToConfig, digest)
 	delete(d.store.danglingConfigs, digest)
+	delete(d.store.danglingReasons, digest)
+	delete(d.store.digestToPlacement, digest)
 
 	for k, v := range d.store.idToDigest {
 		if v == digest {
-			configsInfo.Delete(node.name, string(k), le.JoinLeaderValue)
+			if found {
+				configsInfo.Delete(node.name, string(k), le.JoinLeaderValue)
+			}
 			delete(d.store.idToDigest, k)
 		}
 	}
@@ -108,6 +120,29 @@ func (d *dispatcher) removeConfig(digest string) {
 	}
 }
 
+// setPlacement stores the placement constraints of a config, they are
+// used to choose the nodes it can be moved to
+func (d *dispatcher) setPlacement(digest string, constraints *placementConstraints) {
+	d.store.Lock()
+	defer d.store.Unlock()
+
+	if constraints == nil {
+		delete(d.store.digestToPlacement, digest)
+		return
+	}
+	d.store.digestToPlacement[digest] = constraints
+}
+
+// setDanglingReason stores why a dangling config could not be dispatched
+func (d *dispatcher) setDanglingReason(digest, reason string) {
+	d.store.Lock()
+	defer d.store.Unlock()
+
+	if _, found := d.store.danglingConfigs[digest]; found {
+		d.store.danglingReasons[digest] = reason
+	}
+}
+
 // shouldDispatchDanling returns true if there are dangling configs
 // and node registered, available for dispatching.
 func (d *dispatcher) shouldDispatchDanling() bool {
@@ -33,14 +33,18 @@ type dispatcher struct {
 	extraTags             []string
 	clcRunnersClient      clusteragent.CLCRunnerClientInterface
 	advancedDispatching   bool
+	zoneLabel             string
+	getEndpointsZones     endpointsZonesFunc
 }
 
 func newDispatcher() *dispatcher {
 	d := &dispatcher{
-		store: newClusterStore(),
+		store:             newClusterStore(),
+		getEndpointsZones: getEndpointsZones,
 	}
 	d.nodeExpirationSeconds = config.Datadog.GetInt64("cluster_checks.node_expiration_timeout")
 	d.extraTags = config.Datadog.GetStringSlice("cluster_checks.extra_tags")
+	d.zoneLabel = config.Datadog.GetString("cluster_checks.zone_label")
 
 	hname, _ := hostname.Get(context.TODO())
 	clusterTagValue := clustername.GetClusterName(context.TODO(), hname)
@@ -131,15 +135,30 @@ func (d *dispatcher) reschedule(configs []integration.Config) {
 
 // add stores and delegates a given configuration
 func (d *dispatcher) add(config integration.Config) {
-	target := d.getLeastBusyNode()
+	digest := config.Digest()
+
+	constraints, err := resolvePlacement(config, d.zoneLabel, d.getEndpointsZones)
+	if err != nil {
+		reason := fmt.Sprintf("invalid placement constraints: %v", err)
+		log.Warnf("Cannot dispatch %s:%s: %s, will retry later", config.Name, digest, reason)
+		d.addConfig(config, "")
+		d.setDanglingReason(digest, reason)
+		return
+	}
+	d.setPlacement(digest, constraints)
+
+	target, reason := d.getLeastBusyNode(digest, constraints)
 	if target == "" {
 		// If no node is found, store it in the danglingConfigs map for retrying later.
-		log.Warnf("No available node to dispatch %s:%s on, will retry later", config.Name, config.Digest())
+		log.Warnf("No available node to dispatch %s:%s on: %s, will retry later", config.Name, digest, reason)
 	} else {
-		log.Infof("Dispatching configuration %s:%s to node %s", config.Name, config.Digest(), target)
+		log.Infof("Dispatching configuration %s:%s to node %s", config.Name, digest, target)
 	}
 
 	d.addConfig(config, target)
+	if target == "" {
+		d.setDanglingReason(digest, reason)
+	}
 }
 
 // remove deletes a given configuration
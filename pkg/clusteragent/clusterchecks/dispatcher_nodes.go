@@ -33,7 +33,14 @@ func (d *dispatcher) getClusterCheckConfigs(nodeName string) ([]integration.Conf
 
 	node.RLock()
 	defer node.RUnlock()
-	return makeConfigArray(node.digestToConfig), node.lastConfigChange, nil
+
+	// Placement constraints are kept in the store to re-dispatch the
+	// configs, node agents don't need them
+	configs := makeConfigArray(node.digestToConfig)
+	for i := range configs {
+		configs[i] = withoutPlacement(configs[i])
+	}
+	return configs, node.lastConfigChange, nil
 }
 
 // processNodeStatus keeps the node's status in the store, and returns true
@@ -46,6 +53,10 @@ func (d *dispatcher) processNodeStatus(nodeName, clientIP string, status types.N
 		warmingUp = true
 	}
 	node := d.store.getOrCreateNodeStore(nodeName, clientIP)
+	if status.NodeLabels != nil {
+		// node labels are read by the dispatching logic with the store lock held
+		node.nodeLabels = status.NodeLabels
+	}
 	d.store.Unlock()
 
 	node.Lock()
@@ -70,12 +81,15 @@ func (d *dispatcher) processNodeStatus(nodeName, clientIP string, status types.N
 }
 
 // getLeastBusyNode returns the name of the node that is assigned
-// the lowest number of checks. In case of equality, one is chosen
+// the lowest number of checks, among the nodes satisfying the placement
+// constraints of the config. In case of equality, one is chosen
 // randomly, based on map iterations being randomized.
-func (d *dispatcher) getLeastBusyNode() string {
+// If no node is found, the reason is returned instead.
+func (d *dispatcher) getLeastBusyNode(digest string, constraints *placementConstraints) (string, string) {
 	var leastBusyNode string
 	minCheckCount := int(-1)
 	minBusyness := int(-1)
+	reasons := make(map[string]int)
 
 	d.store.RLock()
 	defer d.store.RUnlock()
@@ -84,6 +98,10 @@ func (d *dispatcher) getLeastBusyNode() string {
 		if name == "" {
 			continue
 		}
+		if reason := constraints.unsatisfiedBy(d.store, store, digest); reason != "" {
+			reasons[reason]++
+			continue
+		}
 		if d.advancedDispatching && store.busyness > defaultBusynessValue {
 			// dispatching based on clc runners stats
 			// only when advancedDispatching is true and
@@ -100,7 +118,11 @@ func (d *dispatcher) getLeastBusyNode() string {
 			}
 		}
 	}
-	return leastBusyNode
+
+	if leastBusyNode == "" {
+		return "", placementFailure(reasons)
+	}
+	return leastBusyNode, ""
 }
 
 // expireNodes iterates over nodes and removes the ones that have not
@@ -125,6 +147,7 @@ func (d *dispatcher) expireNodes() {
 				delete(d.store.digestToNode, digest)
 				log.Debugf("Adding %s:%s as a dangling Cluster Check config", config.Name, digest)
 				d.store.danglingConfigs[digest] = config
+				d.store.danglingReasons[digest] = fmt.Sprintf("node %s stopped reporting", name)
 				danglingConfigs.Inc(le.JoinLeaderValue)
 
 				// TODO: Use partial label matching when it becomes available:
@@ -114,21 +114,43 @@ func (d *dispatcher) pickCheckToMove(nodeName string) (string, int, error) {
 // if it satisfies the following
 // Diff(Ni) < Diff(Nj) (for each j != i, 0 <= j < len(nodes))
 // where Diff(N) is the difference between the busyness on N and the total average busyness.
-func pickNode(diffMap map[string]int, sourceNode string) string {
+// Only the nodes for which canRun returns an empty reason are considered,
+// the reasons of the others are returned if no node is picked.
+func pickNode(diffMap map[string]int, sourceNode string, canRun func(nodeName string) string) (string, map[string]int) {
 	firstItr := true
 	minDiff := 0
 	pickedNode := ""
+	reasons := make(map[string]int)
 	for _, node := range orderedKeys(diffMap) {
 		if node == sourceNode {
 			continue
 		}
+		if reason := canRun(node); reason != "" {
+			reasons[reason]++
+			continue
+		}
 		if diffMap[node] < minDiff || firstItr {
 			minDiff = diffMap[node]
 			pickedNode = node
 			firstItr = false
 		}
 	}
-	return pickedNode
+	return pickedNode, reasons
+}
+
+// placementFilter returns a function checking whether a node satisfies the
+// placement constraints of a config, for pickNode
+func (d *dispatcher) placementFilter(digest string) func(nodeName string) string {
+	return func(nodeName string) string {
+		d.store.RLock()
+		defer d.store.RUnlock()
+
+		node, found := d.store.getNodeStore(nodeName)
+		if !found {
+			return "unknown node"
+		}
+		return d.store.digestToPlacement[digest].unsatisfiedBy(d.store, node, digest)
+	}
 }
 
 // moveCheck moves a check by its ID from a node to another
@@ -157,7 +179,12 @@ func (d *dispatcher) moveCheck(src, dest, checkID string) error {
 	config, digest := d.getConfigAndDigest(checkID)
 	log.Tracef("Moving check %s with digest %s and config %s from %s to %s", checkID, digest, config.String(), src, dest)
 
+	d.store.RLock()
+	constraints := d.store.digestToPlacement[digest]
+	d.store.RUnlock()
+
 	d.removeConfig(digest)
+	d.setPlacement(digest, constraints)
 	d.addConfig(config, dest)
 
 	log.Debugf("Check %s moved from %s to %s", checkID, src, dest)
@@ -197,7 +224,12 @@ func (d *dispatcher) rebalance() []types.RebalanceResponse {
 				break
 			}
 
-			destNodeName := pickNode(diffMap, sourceNodeName)
+			_, digest := d.getConfigAndDigest(checkID)
+			destNodeName, reasons := pickNode(diffMap, sourceNodeName, d.placementFilter(digest))
+			if destNodeName == "" {
+				log.Debugf("Cannot move check %s from node %s: %s", checkID, sourceNodeName, placementFailure(reasons))
+				break
+			}
 			sourceDiff := diffMap[sourceNodeName]
 			destDiff := diffMap[destNodeName]
 
@@ -240,24 +240,28 @@ func TestProcessNodeStatus(t *testing.T) {
 
 func TestGetLeastBusyNode(t *testing.T) {
 	dispatcher := newDispatcher()
+	getLeastBusyNode := func() string {
+		node, _ := dispatcher.getLeastBusyNode("", nil)
+		return node
+	}
 
 	// No node registered -> empty string
-	assert.Equal(t, "", dispatcher.getLeastBusyNode())
+	assert.Equal(t, "", getLeastBusyNode())
 
 	// 1 config on node1, 2 on node2
 	dispatcher.addConfig(generateIntegration("A"), "node1")
 	dispatcher.addConfig(generateIntegration("B"), "node2")
 	dispatcher.addConfig(generateIntegration("C"), "node2")
-	assert.Equal(t, "node1", dispatcher.getLeastBusyNode())
+	assert.Equal(t, "node1", getLeastBusyNode())
 
 	// 3 configs on node1, 2 on node2
 	dispatcher.addConfig(generateIntegration("D"), "node1")
 	dispatcher.addConfig(generateIntegration("E"), "node1")
-	assert.Equal(t, "node2", dispatcher.getLeastBusyNode())
+	assert.Equal(t, "node2", getLeastBusyNode())
 
 	// Add an empty node3
 	dispatcher.processNodeStatus("node3", "10.0.0.3", types.NodeStatus{})
-	assert.Equal(t, "node3", dispatcher.getLeastBusyNode())
+	assert.Equal(t, "node3", getLeastBusyNode())
 
 	requireNotLocked(t, dispatcher.store)
 }
@@ -0,0 +1,260 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build clusterchecks
+// +build clusterchecks
+
+package clusterchecks
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/DataDog/datadog-agent/pkg/autodiscovery/integration"
+)
+
+// placementField is the instance field holding the placement constraints of a cluster check
+const placementField = "cluster_check_placement"
+
+// placementConfig holds the placement constraints set on a cluster check
+// instance, under the cluster_check_placement field. The node labels are the
+// ones reported by the agents.
+type placementConfig struct {
+	// NodeLabels lists the labels the node of the agent must have
+	NodeLabels map[string]string `yaml:"node_labels"`
+
+	// Zones restricts the zones the check can run in
+	Zones []string `yaml:"zones"`
+
+	// SameZoneAsEndpoints restricts the zones the check can run in to the ones
+	// of the endpoints of the kubernetes service the check was resolved from
+	SameZoneAsEndpoints bool `yaml:"same_zone_as_endpoints"`
+
+	// AntiAffinity prevents checks of the same group from running on the same node
+	AntiAffinity string `yaml:"anti_affinity"`
+}
+
+type placementInstance struct {
+	Placement *placementConfig `yaml:"cluster_check_placement"`
+}
+
+// placementConstraints are the resolved constraints of a configuration,
+// merged from the constraints of all its instances.
+// A nil *placementConstraints doesn't constrain the placement.
+type placementConstraints struct {
+	nodeLabels map[string]string
+	// zones is nil when the zone is not constrained
+	zones         map[string]struct{}
+	zoneLabel     string
+	antiAffinity  map[string]struct{}
+	endpointsZone bool
+}
+
+// endpointsZonesFunc returns the zones of the nodes running the endpoints of a kubernetes service
+type endpointsZonesFunc func(serviceID, zoneLabel string) ([]string, error)
+
+// parsePlacement extracts the placement constraints of a configuration
+func parsePlacement(config integration.Config) ([]placementConfig, error) {
+	var placements []placementConfig
+	for _, data := range config.Instances {
+		instance := placementInstance{}
+		if err := yaml.Unmarshal(data, &instance); err != nil {
+			return nil, fmt.Errorf("invalid %s: %v", placementField, err)
+		}
+		if instance.Placement != nil {
+			placements = append(placements, *instance.Placement)
+		}
+	}
+	return placements, nil
+}
+
+// withoutPlacement returns a copy of the configuration without the placement
+// constraints of its instances, they are only meaningful to the cluster agent
+func withoutPlacement(config integration.Config) integration.Config {
+	out := config
+	copied := false
+	for i, data := range config.Instances {
+		stripped := instanceWithoutPlacement(data)
+		if bytes.Equal(stripped, data) {
+			continue
+		}
+		if !copied {
+			// Copy the instances to avoid modifying the original
+			out.Instances = make([]integration.Data, len(config.Instances))
+			copy(out.Instances, config.Instances)
+			copied = true
+		}
+		out.Instances[i] = stripped
+	}
+	return out
+}
+
+// instanceWithoutPlacement removes the placement field of an instance,
+// instances without it are returned as is
+func instanceWithoutPlacement(data integration.Data) integration.Data {
+	if !bytes.Contains(data, []byte(placementField)) {
+		return data
+	}
+
+	var instance integration.RawMap
+	if err := yaml.Unmarshal(data, &instance); err != nil {
+		return data
+	}
+	if _, found := instance[placementField]; !found {
+		return data
+	}
+	delete(instance, placementField)
+
+	out, err := yaml.Marshal(instance)
+	if err != nil {
+		return data
+	}
+	return out
+}
+
+// resolvePlacement returns the merged placement constraints of a configuration,
+// resolving the zones of its endpoints if needed
+func resolvePlacement(config integration.Config, zoneLabel string, getEndpointsZones endpointsZonesFunc) (*placementConstraints, error) {
+	placements, err := parsePlacement(config)
+	if err != nil || len(placements) == 0 {
+		return nil, err
+	}
+
+	c := &placementConstraints{
+		nodeLabels:   make(map[string]string),
+		zoneLabel:    zoneLabel,
+		antiAffinity: make(map[string]struct{}),
+	}
+
+	for _, p := range placements {
+		for key, value := range p.NodeLabels {
+			if current, found := c.nodeLabels[key]; found && current != value {
+				return nil, fmt.Errorf("conflicting values %q and %q for node label %q", current, value, key)
+			}
+			c.nodeLabels[key] = value
+		}
+
+		if len(p.Zones) > 0 {
+			c.restrictZones(p.Zones)
+		}
+
+		if p.SameZoneAsEndpoints && !c.endpointsZone {
+			c.endpointsZone = true
+			if getEndpointsZones == nil {
+				return nil, fmt.Errorf("same_zone_as_endpoints is not supported by this cluster agent")
+			}
+			zones, err := getEndpointsZones(config.ServiceID, zoneLabel)
+			if err != nil {
+				return nil, fmt.Errorf("cannot get the zones of the endpoints of %q: %v", config.ServiceID, err)
+			}
+			c.restrictZones(zones)
+		}
+
+		if p.AntiAffinity != "" {
+			c.antiAffinity[p.AntiAffinity] = struct{}{}
+		}
+	}
+
+	return c, nil
+}
+
+// restrictZones intersects the allowed zones with the given ones
+func (c *placementConstraints) restrictZones(zones []string) {
+	allowed := make(map[string]struct{}, len(zones))
+	for _, zone := range zones {
+		if _, found := c.zones[zone]; c.zones == nil || found {
+			allowed[zone] = struct{}{}
+		}
+	}
+	c.zones = allowed
+}
+
+// sharesAntiAffinity returns whether both constraints have an anti-affinity group in common
+func (c *placementConstraints) sharesAntiAffinity(other *placementConstraints) bool {
+	if c == nil || other == nil {
+		return false
+	}
+	for group := range c.antiAffinity {
+		if _, found := other.antiAffinity[group]; found {
+			return true
+		}
+	}
+	return false
+}
+
+// unsatisfiedBy returns why the node can't run a configuration with these
+// constraints, or an empty string if it can.
+// The store lock must be held by the caller.
+func (c *placementConstraints) unsatisfiedBy(store *clusterStore, node *nodeStore, digest string) string {
+	if c == nil {
+		return ""
+	}
+
+	keys := make([]string, 0, len(c.nodeLabels))
+	for key := range c.nodeLabels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if actual, found := node.nodeLabels[key]; !found {
+			return fmt.Sprintf("missing node label %s", key)
+		} else if actual != c.nodeLabels[key] {
+			return fmt.Sprintf("node label %s is not %s", key, c.nodeLabels[key])
+		}
+	}
+
+	if c.zones != nil {
+		zone, found := node.nodeLabels[c.zoneLabel]
+		if !found {
+			return fmt.Sprintf("missing zone label %s", c.zoneLabel)
+		}
+		if _, allowed := c.zones[zone]; !allowed {
+			return fmt.Sprintf("zone %s is not in %s", zone, c.allowedZones())
+		}
+	}
+
+	if len(c.antiAffinity) > 0 {
+		for otherDigest := range node.digestToConfig {
+			if otherDigest != digest && c.sharesAntiAffinity(store.digestToPlacement[otherDigest]) {
+				return "anti-affinity with a check already running on the node"
+			}
+		}
+	}
+
+	return ""
+}
+
+func (c *placementConstraints) allowedZones() string {
+	zones := make([]string, 0, len(c.zones))
+	for zone := range c.zones {
+		zones = append(zones, zone)
+	}
+	sort.Strings(zones)
+	return "[" + strings.Join(zones, ", ") + "]"
+}
+
+// placementFailure summarizes why no node could run a configuration,
+// grouping nodes by reason
+func placementFailure(reasons map[string]int) string {
+	if len(reasons) == 0 {
+		return "no node available"
+	}
+
+	keys := make([]string, 0, len(reasons))
+	for reason := range reasons {
+		keys = append(keys, reason)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys))
+	for _, reason := range keys {
+		parts = append(parts, fmt.Sprintf("%d node(s): %s", reasons[reason], reason))
+	}
+	return "no node satisfies the placement constraints (" + strings.Join(parts, "; ") + ")"
+}
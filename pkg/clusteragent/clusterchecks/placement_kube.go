@@ -0,0 +1,78 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build clusterchecks && kubeapiserver
+// +build clusterchecks,kubeapiserver
+
+package clusterchecks
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/DataDog/datadog-agent/pkg/util/kubernetes/apiserver"
+)
+
+const kubeServiceIDPrefix = "kube_service://"
+
+// getEndpointsZones returns the zones of the nodes running the ready endpoints
+// of the kubernetes service identified by serviceID
+func getEndpointsZones(serviceID, zoneLabel string) ([]string, error) {
+	if !strings.HasPrefix(serviceID, kubeServiceIDPrefix) {
+		return nil, fmt.Errorf("the check was not resolved from a kubernetes service")
+	}
+	namespace, name, found := splitNamespacedName(strings.TrimPrefix(serviceID, kubeServiceIDPrefix))
+	if !found {
+		return nil, fmt.Errorf("invalid service ID %q", serviceID)
+	}
+
+	client, err := apiserver.GetAPIClient()
+	if err != nil {
+		return nil, err
+	}
+
+	endpoints, err := client.Cl.CoreV1().Endpoints(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	zones := []string{}
+	seen := make(map[string]struct{})
+	for _, subset := range endpoints.Subsets {
+		for _, address := range subset.Addresses {
+			if address.NodeName == nil {
+				continue
+			}
+			if _, found := seen[*address.NodeName]; found {
+				continue
+			}
+			seen[*address.NodeName] = struct{}{}
+
+			labels, err := apiserver.GetNodeLabels(client, *address.NodeName)
+			if err != nil {
+				return nil, err
+			}
+			if zone, found := labels[zoneLabel]; found {
+				zones = append(zones, zone)
+			}
+		}
+	}
+
+	if len(zones) == 0 {
+		return nil, fmt.Errorf("no ready endpoint with a %s node label", zoneLabel)
+	}
+	return zones, nil
+}
+
+func splitNamespacedName(s string) (string, string, bool) {
+	parts := strings.SplitN(s, "/", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", false
+	}
+	return parts[0], parts[1], true
+}
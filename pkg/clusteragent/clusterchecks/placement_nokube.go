@@ -0,0 +1,17 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build clusterchecks && !kubeapiserver
+// +build clusterchecks,!kubeapiserver
+
+package clusterchecks
+
+import (
+	"errors"
+)
+
+func getEndpointsZones(serviceID, zoneLabel string) ([]string, error) {
+	return nil, errors.New("kubernetes apiserver support not compiled in")
+}
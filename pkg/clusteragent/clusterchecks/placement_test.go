@@ -0,0 +1,218 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build clusterchecks
+// +build clusterchecks
+
+package clusterchecks
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/pkg/autodiscovery/integration"
+	"github.com/DataDog/datadog-agent/pkg/clusteragent/clusterchecks/types"
+	"github.com/DataDog/datadog-agent/pkg/collector/check"
+)
+
+const testZoneLabel = "topology.kubernetes.io/zone"
+
+func generatePlacedIntegration(name string, instances ...string) integration.Config {
+	config := generateIntegration(name)
+	for _, instance := range instances {
+		config.Instances = append(config.Instances, integration.Data(instance))
+	}
+	return config
+}
+
+func registerNode(d *dispatcher, name, zone string, labels map[string]string) {
+	nodeLabels := map[string]string{testZoneLabel: zone}
+	for k, v := range labels {
+		nodeLabels[k] = v
+	}
+	d.processNodeStatus(name, "", types.NodeStatus{NodeLabels: nodeLabels})
+}
+
+func TestResolvePlacement(t *testing.T) {
+	endpointsZones := func(serviceID, zoneLabel string) ([]string, error) {
+		if serviceID != "kube_service://default/db" {
+			return nil, errors.New("not a service")
+		}
+		return []string{"zone-a", "zone-b"}, nil
+	}
+
+	tests := []struct {
+		name      string
+		config    integration.Config
+		expected  *placementConstraints
+		expectErr bool
+	}{
+		{
+			name:   "no placement",
+			config: generatePlacedIntegration("A", "host: foo"),
+		},
+		{
+			name: "merged instances",
+			config: generatePlacedIntegration("A",
+				"cluster_check_placement:\n  node_labels:\n    pool: db\n  zones: [zone-a, zone-c]\n  anti_affinity: pg",
+				"cluster_check_placement:\n  zones: [zone-a, zone-b]",
+			),
+			expected: &placementConstraints{
+				nodeLabels:   map[string]string{"pool": "db"},
+				zones:        map[string]struct{}{"zone-a": {}},
+				zoneLabel:    testZoneLabel,
+				antiAffinity: map[string]struct{}{"pg": {}},
+			},
+		},
+		{
+			name: "same zone as endpoints",
+			config: integration.Config{
+				Name:      "A",
+				ServiceID: "kube_service://default/db",
+				Instances: []integration.Data{integration.Data("cluster_check_placement:\n  same_zone_as_endpoints: true\n  zones: [zone-b, zone-c]")},
+			},
+			expected: &placementConstraints{
+				nodeLabels:    map[string]string{},
+				zones:         map[string]struct{}{"zone-b": {}},
+				zoneLabel:     testZoneLabel,
+				antiAffinity:  map[string]struct{}{},
+				endpointsZone: true,
+			},
+		},
+		{
+			name:      "same zone as endpoints without service",
+			config:    generatePlacedIntegration("A", "cluster_check_placement:\n  same_zone_as_endpoints: true"),
+			expectErr: true,
+		},
+		{
+			name: "conflicting node labels",
+			config: generatePlacedIntegration("A",
+				"cluster_check_placement:\n  node_labels:\n    pool: db",
+				"cluster_check_placement:\n  node_labels:\n    pool: web",
+			),
+			expectErr: true,
+		},
+		{
+			name:      "invalid placement",
+			config:    generatePlacedIntegration("A", "cluster_check_placement: [foo]"),
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			constraints, err := resolvePlacement(tt.config, testZoneLabel, endpointsZones)
+			if tt.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, constraints)
+		})
+	}
+}
+
+func TestWithoutPlacement(t *testing.T) {
+	config := generatePlacedIntegration("placed",
+		"cluster_check_placement:\n  zones: [zone-a]\nhost: db.local\nport: 5432",
+		"host: other.local",
+	)
+
+	stripped := withoutPlacement(config)
+	assert.Equal(t, integration.Data("host: db.local\nport: 5432\n"), stripped.Instances[0])
+	assert.Equal(t, integration.Data("host: other.local"), stripped.Instances[1])
+
+	// The original config is left untouched
+	assert.Contains(t, string(config.Instances[0]), placementField)
+}
+
+func TestDispatchWithPlacement(t *testing.T) {
+	dispatcher := newDispatcher()
+	dispatcher.zoneLabel = testZoneLabel
+
+	registerNode(dispatcher, "node-a1", "zone-a", map[string]string{"pool": "db"})
+	registerNode(dispatcher, "node-a2", "zone-a", map[string]string{"pool": "default"})
+	registerNode(dispatcher, "node-b1", "zone-b", map[string]string{"pool": "db"})
+
+	// Only node-b1 is in zone-b and in the db pool
+	zoneB := generatePlacedIntegration("zone-b", "cluster_check_placement:\n  zones: [zone-b]\n  node_labels:\n    pool: db")
+	dispatcher.add(zoneB)
+	assert.Equal(t, "node-b1", dispatcher.store.digestToNode[zoneB.Digest()])
+
+	// The node agent doesn't receive the placement constraints
+	configs, _, err := dispatcher.getClusterCheckConfigs("node-b1")
+	require.NoError(t, err)
+	require.Len(t, configs, 1)
+	assert.NotContains(t, string(configs[0].Instances[0]), placementField)
+
+	// Anti-affinity keeps the checks of the pg group on different nodes
+	pg1 := generatePlacedIntegration("pg1", "cluster_check_placement:\n  node_labels:\n    pool: db\n  anti_affinity: pg")
+	pg2 := generatePlacedIntegration("pg2", "cluster_check_placement:\n  node_labels:\n    pool: db\n  anti_affinity: pg")
+	pg3 := generatePlacedIntegration("pg3", "cluster_check_placement:\n  node_labels:\n    pool: db\n  anti_affinity: pg")
+	dispatcher.add(pg1)
+	dispatcher.add(pg2)
+	assert.NotEqual(t, dispatcher.store.digestToNode[pg1.Digest()], dispatcher.store.digestToNode[pg2.Digest()])
+	assert.NotEqual(t, "node-a2", dispatcher.store.digestToNode[pg1.Digest()])
+	assert.NotEqual(t, "node-a2", dispatcher.store.digestToNode[pg2.Digest()])
+
+	// No db node is left for pg3, the reason is reported in the state
+	dispatcher.add(pg3)
+	assert.Contains(t, dispatcher.store.danglingConfigs, pg3.Digest())
+	state, err := dispatcher.getState()
+	require.NoError(t, err)
+	assert.Equal(t,
+		"no node satisfies the placement constraints (2 node(s): anti-affinity with a check already running on the node; 1 node(s): node label pool is not db)",
+		state.DanglingReasons[string(check.BuildID(pg3.Name, instanceWithoutPlacement(pg3.Instances[0]), pg3.InitConfig))],
+	)
+
+	// Invalid constraints are reported as well
+	invalid := generatePlacedIntegration("invalid", "cluster_check_placement:\n  same_zone_as_endpoints: true")
+	dispatcher.getEndpointsZones = func(serviceID, zoneLabel string) ([]string, error) {
+		return nil, errors.New("no service")
+	}
+	dispatcher.add(invalid)
+	assert.Contains(t, dispatcher.store.danglingReasons[invalid.Digest()], "invalid placement constraints")
+
+	// Reasons are removed with the configs
+	dispatcher.removeConfig(pg3.Digest())
+	assert.NotContains(t, dispatcher.store.danglingReasons, pg3.Digest())
+	assert.NotContains(t, dispatcher.store.digestToPlacement, pg3.Digest())
+
+	requireNotLocked(t, dispatcher.store)
+}
+
+func TestRebalanceWithPlacement(t *testing.T) {
+	dispatcher := newDispatcher()
+	dispatcher.zoneLabel = testZoneLabel
+
+	registerNode(dispatcher, "node-a", "zone-a", nil)
+	registerNode(dispatcher, "node-b", "zone-b", nil)
+
+	pinned := generatePlacedIntegration("pinned", "cluster_check_placement:\n  zones: [zone-a]")
+	dispatcher.add(pinned)
+	require.Equal(t, "node-a", dispatcher.store.digestToNode[pinned.Digest()])
+
+	// pickNode must not consider node-b for the pinned config
+	diffMap := map[string]int{"node-a": 50, "node-b": -50}
+	node, reasons := pickNode(diffMap, "node-a", dispatcher.placementFilter(pinned.Digest()))
+	assert.Equal(t, "", node)
+	assert.Equal(t, map[string]int{"zone zone-b is not in [zone-a]": 1}, reasons)
+
+	// moving a check keeps its constraints
+	stats := types.CLCRunnerStats{AverageExecutionTime: 100, IsClusterCheck: true}
+	checkID := ""
+	for id := range dispatcher.store.idToDigest {
+		checkID = string(id)
+	}
+	nodeA, _ := dispatcher.store.getNodeStore("node-a")
+	nodeA.AddRunnerStats(checkID, stats)
+	require.NoError(t, dispatcher.moveCheck("node-a", "node-b", checkID))
+	assert.NotNil(t, dispatcher.store.digestToPlacement[pinned.Digest()])
+
+	requireNotLocked(t, dispatcher.store)
+}
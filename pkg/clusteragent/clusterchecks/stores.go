@@ -24,13 +24,15 @@ import (
 // operations involving several calls.
 type clusterStore struct {
 	sync.RWMutex
-	active           bool
-	digestToConfig   map[string]integration.Config            // All configurations to dispatch
-	digestToNode     map[string]string                        // Node running a config
-	nodes            map[string]*nodeStore                    // All nodes known to the cluster-agent
-	danglingConfigs  map[string]integration.Config            // Configs we could not dispatch to any node
-	endpointsConfigs map[string]map[string]integration.Config // Endpoints configs to be consumed by node agents
-	idToDigest       map[check.ID]string                      // link check IDs to check configs
+	active            bool
+	digestToConfig    map[string]integration.Config            // All configurations to dispatch
+	digestToNode      map[string]string                        // Node running a config
+	nodes             map[string]*nodeStore                    // All nodes known to the cluster-agent
+	danglingConfigs   map[string]integration.Config            // Configs we could not dispatch to any node
+	danglingReasons   map[string]string                        // Why dangling configs could not be dispatched
+	digestToPlacement map[string]*placementConstraints         // Placement constraints of configs, if any
+	endpointsConfigs  map[string]map[string]integration.Config // Endpoints configs to be consumed by node agents
+	idToDigest        map[check.ID]string                      // link check IDs to check configs
 }
 
 func newClusterStore() *clusterStore {
@@ -46,6 +48,8 @@ func (s *clusterStore) reset() {
 	s.digestToNode = make(map[string]string)
 	s.nodes = make(map[string]*nodeStore)
 	s.danglingConfigs = make(map[string]integration.Config)
+	s.danglingReasons = make(map[string]string)
+	s.digestToPlacement = make(map[string]*placementConstraints)
 	s.endpointsConfigs = make(map[string]map[string]integration.Config)
 	s.idToDigest = make(map[check.ID]string)
 }
@@ -76,6 +80,7 @@ func (s *clusterStore) getOrCreateNodeStore(nodeName, clientIP string) *nodeStor
 // clearDangling resets the danglingConfigs map to a new empty one
 func (s *clusterStore) clearDangling() {
 	s.danglingConfigs = make(map[string]integration.Config)
+	s.danglingReasons = make(map[string]string)
 }
 
 // nodeStore holds the state store for one node.
@@ -87,6 +92,7 @@ type nodeStore struct {
 	lastStatus       types.NodeStatus
 	lastConfigChange int64
 	digestToConfig   map[string]integration.Config
+	nodeLabels       map[string]string
 	clientIP         string
 	clcRunnerStats   types.CLCRunnersStats
 	busyness         int
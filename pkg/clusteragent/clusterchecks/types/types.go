@@ -11,7 +11,8 @@ import (
 
 // NodeStatus holds the status report from the node-agent
 type NodeStatus struct {
-	LastChange int64 `json:"last_change"`
+	LastChange int64             `json:"last_change"`
+	NodeLabels map[string]string `json:"node_labels,omitempty"`
 }
 
 // StatusResponse holds the DCA response for a status report
@@ -43,6 +44,8 @@ type StateResponse struct {
 	Warmup     bool                 `json:"warmup"`
 	Nodes      []StateNodeResponse  `json:"nodes"`
 	Dangling   []integration.Config `json:"dangling"`
+	// DanglingReasons explains why dangling configs could not be dispatched, by check ID
+	DanglingReasons map[string]string `json:"dangling_reasons,omitempty"`
 }
 
 // StateNodeResponse is a chunk of StateResponse
@@ -96,8 +96,6 @@ func (mr *MetricsRetriever) retrieveMetricsValues() {
 			log.Debugf("QueryResult from DD for %q: %v", query, queryResult)
 
 			if queryResult.Valid {
-				datadogMetricFromStore.Value = queryResult.Value
-
 				// If we get a valid but old metric, flag it as invalid
 				maxAge := datadogMetric.MaxAge
 				if maxAge == 0 {
@@ -105,27 +103,19 @@ func (mr *MetricsRetriever) retrieveMetricsValues() {
 				}
 
 				if time.Duration(currentTime.Unix()-queryResult.Timestamp)*time.Second <= maxAge {
-					datadogMetricFromStore.Valid = true
-					datadogMetricFromStore.Error = nil
-					datadogMetricFromStore.UpdateTime = time.Unix(queryResult.Timestamp, 0).UTC()
-				} else {
-					datadogMetricFromStore.Valid = false
-					datadogMetricFromStore.Error = fmt.Errorf(invalidMetricOutdatedErrorMessage, query)
-					datadogMetricFromStore.UpdateTime = currentTime
+					datadogMetricFromStore.SetQueryResult(queryResult.Value, time.Unix(queryResult.Timestamp, 0).UTC())
+				} else if !datadogMetricFromStore.SetQueryError(fmt.Errorf(invalidMetricOutdatedErrorMessage, query), currentTime) {
+					datadogMetricFromStore.Value = queryResult.Value
 				}
 			} else {
-				datadogMetricFromStore.Valid = false
-				datadogMetricFromStore.Error = fmt.Errorf(invalidMetricBackendErrorMessage, query)
-				datadogMetricFromStore.UpdateTime = currentTime
+				datadogMetricFromStore.SetQueryError(fmt.Errorf(invalidMetricBackendErrorMessage, query), currentTime)
 			}
 		} else {
-			datadogMetricFromStore.Valid = false
 			if globalError {
-				datadogMetricFromStore.Error = fmt.Errorf(invalidMetricGlobalErrorMessage)
+				datadogMetricFromStore.SetQueryError(fmt.Errorf(invalidMetricGlobalErrorMessage), currentTime)
 			} else {
-				datadogMetricFromStore.Error = fmt.Errorf(invalidMetricNoDataErrorMessage, query)
+				datadogMetricFromStore.SetQueryError(fmt.Errorf(invalidMetricNoDataErrorMessage, query), currentTime)
 			}
-			datadogMetricFromStore.UpdateTime = currentTime
 		}
 
 		mr.store.UnlockSet(datadogMetric.ID, *datadogMetricFromStore, metricRetrieverStoreID)
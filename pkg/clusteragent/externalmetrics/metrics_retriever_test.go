@@ -630,3 +630,60 @@ func TestRetrieveMetricsNotActive(t *testing.T) {
 		})
 	}
 }
+
+func TestRetrieveMetricsStaleValuePolicy(t *testing.T) {
+	testTime := time.Now().Add(time.Duration(-1) * time.Second).UTC().Truncate(time.Second)
+	fallback := 1.0
+
+	store := NewDatadogMetricsInternalStore()
+	held := model.DatadogMetricInternal{
+		ID:      "metric0",
+		Active:  true,
+		Options: model.DatadogMetricOptions{HoldLastValue: 5 * time.Minute, Smoothing: model.SmoothingMax, SmoothingWindow: 2},
+	}
+	held.SetQueries("query-metric0")
+	store.Set(held.ID, held, "utest")
+
+	withFallback := model.DatadogMetricInternal{
+		ID:      "metric1",
+		Active:  true,
+		Options: model.DatadogMetricOptions{FallbackValue: &fallback},
+	}
+	withFallback.SetQueries("query-metric1")
+	store.Set(withFallback.ID, withFallback, "utest")
+
+	mockedProcessor := mockedProcessor{}
+	metricsRetriever, err := NewMetricsRetriever(0, 30, &mockedProcessor, getIsLeaderFunction(true), &store)
+	assert.Nil(t, err)
+
+	// Successive valid results are smoothed
+	for i, value := range []float64{10.0, 4.0} {
+		timestamp := testTime.Add(time.Duration(i-1) * time.Second).Unix()
+		mockedProcessor.points = map[string]autoscalers.Point{
+			"query-metric0": {Value: value, Timestamp: timestamp, Valid: true},
+			"query-metric1": {Value: value, Timestamp: timestamp, Valid: true},
+		}
+		metricsRetriever.retrieveMetricsValues()
+	}
+
+	datadogMetric := store.Get("metric0")
+	assert.True(t, datadogMetric.Valid)
+	assert.Equal(t, 10.0, datadogMetric.Value)
+	assert.Equal(t, model.ValueSourceSmoothedQuery, datadogMetric.Source)
+
+	// Backend outage, last value is held or fallback value is used
+	mockedProcessor.points = nil
+	mockedProcessor.err = fmt.Errorf("Backend error 500")
+	metricsRetriever.retrieveMetricsValues()
+
+	datadogMetric = store.Get("metric0")
+	assert.True(t, datadogMetric.Valid)
+	assert.Equal(t, 10.0, datadogMetric.Value)
+	assert.Equal(t, model.ValueSourceLastValid, datadogMetric.Source)
+	assert.Equal(t, fmt.Errorf(invalidMetricGlobalErrorMessage), datadogMetric.Error)
+
+	datadogMetric = store.Get("metric1")
+	assert.True(t, datadogMetric.Valid)
+	assert.Equal(t, 1.0, datadogMetric.Value)
+	assert.Equal(t, model.ValueSourceFallback, datadogMetric.Source)
+}
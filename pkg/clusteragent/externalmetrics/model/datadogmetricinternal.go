@@ -43,6 +43,10 @@ type DatadogMetricInternal struct {
 	UpdateTime           time.Time
 	Error                error
 	MaxAge               time.Duration
+	Options              DatadogMetricOptions
+	Source               ValueSource
+	lastValidTime        time.Time
+	results              []float64
 }
 
 // NewDatadogMetricInternal returns a `DatadogMetricInternal` object from a `DatadogMetric` CRD Object
@@ -57,6 +61,7 @@ func NewDatadogMetricInternal(id string, datadogMetric datadoghq.DatadogMetric)
 		Autogen:              false,
 		AutoscalerReferences: datadogMetric.Status.AutoscalerReferences,
 		MaxAge:               datadogMetric.Spec.MaxAge.Duration,
+		Options:              parseDatadogMetricOptions(id, datadogMetric.Annotations),
 	}
 
 	if len(datadogMetric.Spec.ExternalMetricName) > 0 {
@@ -74,9 +79,19 @@ func NewDatadogMetricInternal(id string, datadogMetric datadoghq.DatadogMetric)
 			internal.UpdateTime = condition.LastUpdateTime.UTC()
 		case condition.Type == datadoghq.DatadogMetricConditionTypeError && condition.Status == corev1.ConditionTrue:
 			internal.Error = errors.New(condition.Message)
+		case condition.Type == DatadogMetricConditionTypeStale:
+			internal.Source = ValueSource(condition.Reason)
+			if internal.Source == ValueSourceLastValid {
+				// The last valid value was received before the value started to be held
+				internal.lastValidTime = condition.LastTransitionTime.UTC()
+			}
 		}
 	}
 
+	if internal.Valid && (internal.Source == ValueSourceQuery || internal.Source == ValueSourceSmoothedQuery) {
+		internal.lastValidTime = internal.UpdateTime
+	}
+
 	internal.resolveQuery(internal.query)
 
 	// If UpdateTime is not set, it means it's a newly created DatadogMetric
@@ -127,13 +142,25 @@ func (d *DatadogMetricInternal) RawQuery() string {
 	return d.query
 }
 
-// UpdateFrom updates the `DatadogMetricInternal` from `DatadogMetric` Spec
-func (d *DatadogMetricInternal) UpdateFrom(currentSpec datadoghq.DatadogMetricSpec) {
+// UpdateFrom updates the `DatadogMetricInternal` from `DatadogMetric` Spec and annotations
+func (d *DatadogMetricInternal) UpdateFrom(current datadoghq.DatadogMetric) {
+	currentSpec := current.Spec
 	if d.shouldResolveQuery(currentSpec) {
 		d.resolveQuery(currentSpec.Query)
+		// Previous results are not relevant for the new query
+		d.results = nil
 	}
 	d.query = currentSpec.Query
 	d.MaxAge = currentSpec.MaxAge.Duration
+
+	d.Options = parseDatadogMetricOptions(d.ID, current.Annotations)
+	if !d.Options.Enabled() {
+		d.Source = ValueSourceNone
+		d.lastValidTime = time.Time{}
+	}
+	if d.Options.Smoothing == SmoothingNone {
+		d.results = nil
+	}
 }
 
 // shouldResolveQuery returns whether we should try to resolve a new query
@@ -188,9 +215,22 @@ func (d *DatadogMetricInternal) BuildStatus(currentStatus *datadoghq.DatadogMetr
 		errorCondition.Message = d.Error.Error()
 	}
 
+	conditions := []datadoghq.DatadogMetricCondition{activeCondition, validCondition, updatedCondition, errorCondition}
+	if d.Options.Enabled() {
+		var prevStaleCondition *datadoghq.DatadogMetricCondition
+		if currentStatus != nil {
+			for i := range currentStatus.Conditions {
+				if currentStatus.Conditions[i].Type == DatadogMetricConditionTypeStale {
+					prevStaleCondition = &currentStatus.Conditions[i]
+				}
+			}
+		}
+		conditions = append(conditions, d.staleCondition(updateTime, prevStaleCondition))
+	}
+
 	newStatus := datadoghq.DatadogMetricStatus{
 		Value:                formatDatadogMetricValue(d.Value),
-		Conditions:           []datadoghq.DatadogMetricCondition{activeCondition, validCondition, updatedCondition, errorCondition},
+		Conditions:           conditions,
 		AutoscalerReferences: d.AutoscalerReferences,
 	}
 
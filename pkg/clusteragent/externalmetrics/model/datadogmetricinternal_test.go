@@ -145,7 +145,7 @@ func TestDatadogMetricInternal_UpdateFrom(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			tt.ddmInternal.UpdateFrom(tt.newSpec)
+			tt.ddmInternal.UpdateFrom(datadoghq.DatadogMetric{Spec: tt.newSpec})
 			assert.Equal(t, tt.expectedQuery, tt.ddmInternal.query)
 			if tt.expectedResolvedQuery == nil {
 				assert.Nil(t, tt.ddmInternal.resolvedQuery)
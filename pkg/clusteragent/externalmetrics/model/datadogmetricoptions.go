@@ -0,0 +1,241 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build kubeapiserver
+// +build kubeapiserver
+
+package model
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	datadoghq "github.com/DataDog/datadog-operator/apis/datadoghq/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// Annotations used to configure the stale-value policy and the smoothing of a `DatadogMetric`
+const (
+	FallbackValueAnnotation   string = "datadogmetric.datadoghq.com/fallback-value"
+	HoldLastValueAnnotation   string = "datadogmetric.datadoghq.com/hold-last-value"
+	SmoothingAnnotation       string = "datadogmetric.datadoghq.com/smoothing"
+	SmoothingWindowAnnotation string = "datadogmetric.datadoghq.com/smoothing-window"
+
+	defaultSmoothingWindow int = 3
+)
+
+// DatadogMetricConditionTypeStale is the condition exposing the source of the value of a `DatadogMetric`,
+// it's only part of the status of `DatadogMetric` using a stale-value policy or smoothing.
+// Its status is true when the value doesn't come from the latest query results.
+const DatadogMetricConditionTypeStale datadoghq.DatadogMetricConditionType = "Stale"
+
+// SmoothingFunction aggregates the last query results of a `DatadogMetric`
+type SmoothingFunction string
+
+// Supported smoothing functions
+const (
+	SmoothingNone    SmoothingFunction = ""
+	SmoothingMax     SmoothingFunction = "max"
+	SmoothingAverage SmoothingFunction = "avg"
+)
+
+// ValueSource describes where the current value of a `DatadogMetric` comes from
+type ValueSource string
+
+// Possible value sources, used as reason of the Stale condition
+const (
+	ValueSourceNone          ValueSource = ""
+	ValueSourceQuery         ValueSource = "QueryResult"
+	ValueSourceSmoothedQuery ValueSource = "SmoothedQueryResults"
+	ValueSourceLastValid     ValueSource = "LastValidValue"
+	ValueSourceFallback      ValueSource = "FallbackValue"
+	ValueSourceNoValue       ValueSource = "NoValue"
+)
+
+// DatadogMetricOptions holds the stale-value policy and smoothing of a `DatadogMetric`
+type DatadogMetricOptions struct {
+	// FallbackValue is used when no valid value is available
+	FallbackValue *float64
+	// HoldLastValue is the duration the last valid value is kept when the backend returns no valid value
+	HoldLastValue time.Duration
+	// Smoothing is applied over the last SmoothingWindow query results
+	Smoothing       SmoothingFunction
+	SmoothingWindow int
+}
+
+// Enabled returns true if any option is set
+func (o DatadogMetricOptions) Enabled() bool {
+	return o.FallbackValue != nil || o.HoldLastValue > 0 || o.Smoothing != SmoothingNone
+}
+
+// parseDatadogMetricOptions reads the options of a `DatadogMetric` from its annotations,
+// invalid options are ignored.
+func parseDatadogMetricOptions(id string, annotations map[string]string) DatadogMetricOptions {
+	var options DatadogMetricOptions
+
+	if value, found := annotations[FallbackValueAnnotation]; found {
+		fallback, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			log.Warnf("Ignoring invalid %s annotation on DatadogMetric %s: %v", FallbackValueAnnotation, id, err)
+		} else {
+			options.FallbackValue = &fallback
+		}
+	}
+
+	if value, found := annotations[HoldLastValueAnnotation]; found {
+		hold, err := time.ParseDuration(value)
+		if err != nil || hold < 0 {
+			log.Warnf("Ignoring invalid %s annotation on DatadogMetric %s: %q", HoldLastValueAnnotation, id, value)
+		} else {
+			options.HoldLastValue = hold
+		}
+	}
+
+	if value, found := annotations[SmoothingAnnotation]; found {
+		switch smoothing := SmoothingFunction(value); smoothing {
+		case SmoothingMax, SmoothingAverage:
+			options.Smoothing = smoothing
+			options.SmoothingWindow = defaultSmoothingWindow
+		default:
+			log.Warnf("Ignoring invalid %s annotation on DatadogMetric %s: %q, expected %s or %s", SmoothingAnnotation, id, value, SmoothingMax, SmoothingAverage)
+		}
+	}
+
+	if value, found := annotations[SmoothingWindowAnnotation]; found && options.Smoothing != SmoothingNone {
+		window, err := strconv.Atoi(value)
+		if err != nil || window < 1 {
+			log.Warnf("Ignoring invalid %s annotation on DatadogMetric %s: %q", SmoothingWindowAnnotation, id, value)
+		} else {
+			options.SmoothingWindow = window
+		}
+	}
+
+	return options
+}
+
+// SetQueryResult updates the `DatadogMetricInternal` with a valid query result,
+// smoothing it with the previous results if configured
+func (d *DatadogMetricInternal) SetQueryResult(value float64, updateTime time.Time) {
+	d.Valid = true
+	d.Error = nil
+	d.UpdateTime = updateTime
+	d.Value = value
+
+	if !d.Options.Enabled() {
+		return
+	}
+
+	previousTime := d.lastValidTime
+	d.lastValidTime = updateTime
+	d.Source = ValueSourceQuery
+
+	if d.Options.Smoothing == SmoothingNone {
+		d.results = nil
+		return
+	}
+
+	// Queries return the same point until a newer one is available, it's only part of the results once
+	if len(d.results) == 0 || !updateTime.Equal(previousTime) {
+		// Always allocating a new slice as `DatadogMetricInternal` are copied in and out of the store
+		start := 0
+		if len(d.results) >= d.Options.SmoothingWindow {
+			start = len(d.results) - d.Options.SmoothingWindow + 1
+		}
+		results := make([]float64, 0, d.Options.SmoothingWindow)
+		results = append(results, d.results[start:]...)
+		d.results = append(results, value)
+	}
+
+	d.Value = smooth(d.Options.Smoothing, d.results)
+	if len(d.results) > 1 {
+		d.Source = ValueSourceSmoothedQuery
+	}
+}
+
+// SetQueryError updates the `DatadogMetricInternal` when no valid query result is available.
+// It returns true if the stale-value policy keeps the `DatadogMetricInternal` valid,
+// in which case the error is still reported.
+func (d *DatadogMetricInternal) SetQueryError(err error, currentTime time.Time) bool {
+	d.Error = err
+	d.UpdateTime = currentTime
+
+	if d.Options.HoldLastValue > 0 && !d.lastValidTime.IsZero() && currentTime.Sub(d.lastValidTime) <= d.Options.HoldLastValue {
+		// Value is kept as is
+		d.Valid = true
+		d.Source = ValueSourceLastValid
+		return true
+	}
+
+	if d.Options.FallbackValue != nil {
+		d.Valid = true
+		d.Value = *d.Options.FallbackValue
+		d.Source = ValueSourceFallback
+		return true
+	}
+
+	d.Valid = false
+	if d.Options.Enabled() {
+		d.Source = ValueSourceNoValue
+	}
+
+	return false
+}
+
+// staleCondition builds the Stale condition exposing the source of the value
+func (d *DatadogMetricInternal) staleCondition(updateTime metav1.Time, prevCondition *datadoghq.DatadogMetricCondition) datadoghq.DatadogMetricCondition {
+	source := d.Source
+	if source == ValueSourceNone {
+		source = ValueSourceNoValue
+		if d.Valid {
+			source = ValueSourceQuery
+		}
+	}
+
+	condition := d.newCondition(source == ValueSourceLastValid || source == ValueSourceFallback, updateTime, DatadogMetricConditionTypeStale, prevCondition)
+	condition.Reason = string(source)
+
+	switch source {
+	case ValueSourceQuery:
+		condition.Message = "Value from the latest query result"
+	case ValueSourceSmoothedQuery:
+		condition.Message = fmt.Sprintf("%s of the last %d query results", d.Options.Smoothing, len(d.results))
+	case ValueSourceLastValid:
+		condition.Message = fmt.Sprintf("Last valid value from %s held until %s", d.lastValidTime.Format(time.RFC3339), d.lastValidTime.Add(d.Options.HoldLastValue).Format(time.RFC3339))
+	case ValueSourceFallback:
+		condition.Message = "Fallback value used as no valid value is available"
+	case ValueSourceNoValue:
+		condition.Message = "No valid value available"
+	}
+
+	return condition
+}
+
+// smooth aggregates the results with the smoothing function
+func smooth(smoothing SmoothingFunction, results []float64) float64 {
+	if len(results) == 0 {
+		return 0
+	}
+
+	aggregated := results[0]
+	for _, value := range results[1:] {
+		switch smoothing {
+		case SmoothingMax:
+			if value > aggregated {
+				aggregated = value
+			}
+		default:
+			aggregated += value
+		}
+	}
+
+	if smoothing == SmoothingAverage {
+		aggregated /= float64(len(results))
+	}
+
+	return aggregated
+}
@@ -0,0 +1,274 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build kubeapiserver
+// +build kubeapiserver
+
+package model
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	datadoghq "github.com/DataDog/datadog-operator/apis/datadoghq/v1alpha1"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_parseDatadogMetricOptions(t *testing.T) {
+	fallback := 2.5
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		expected    DatadogMetricOptions
+	}{
+		{
+			name:     "no annotations",
+			expected: DatadogMetricOptions{},
+		},
+		{
+			name: "all options",
+			annotations: map[string]string{
+				FallbackValueAnnotation:   "2.5",
+				HoldLastValueAnnotation:   "5m",
+				SmoothingAnnotation:       "avg",
+				SmoothingWindowAnnotation: "5",
+			},
+			expected: DatadogMetricOptions{
+				FallbackValue:   &fallback,
+				HoldLastValue:   5 * time.Minute,
+				Smoothing:       SmoothingAverage,
+				SmoothingWindow: 5,
+			},
+		},
+		{
+			name: "default smoothing window",
+			annotations: map[string]string{
+				SmoothingAnnotation: "max",
+			},
+			expected: DatadogMetricOptions{
+				Smoothing:       SmoothingMax,
+				SmoothingWindow: defaultSmoothingWindow,
+			},
+		},
+		{
+			name: "invalid options are ignored",
+			annotations: map[string]string{
+				FallbackValueAnnotation:   "two",
+				HoldLastValueAnnotation:   "5 minutes",
+				SmoothingAnnotation:       "median",
+				SmoothingWindowAnnotation: "5",
+			},
+			expected: DatadogMetricOptions{},
+		},
+		{
+			name: "invalid smoothing window",
+			annotations: map[string]string{
+				SmoothingAnnotation:       "max",
+				SmoothingWindowAnnotation: "0",
+			},
+			expected: DatadogMetricOptions{
+				Smoothing:       SmoothingMax,
+				SmoothingWindow: defaultSmoothingWindow,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, parseDatadogMetricOptions("default/dd-metric-0", tt.annotations))
+		})
+	}
+}
+
+func TestDatadogMetricInternal_SetQueryResult(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+
+	// Without options, the result is used as is
+	ddm := DatadogMetricInternal{}
+	ddm.SetQueryResult(10, now)
+	ddm.SetQueryResult(4, now)
+	assert.Equal(t, DatadogMetricInternal{Valid: true, Value: 4, UpdateTime: now}, ddm)
+
+	tests := []struct {
+		name           string
+		smoothing      SmoothingFunction
+		results        []float64
+		expectedValue  float64
+		expectedSource ValueSource
+	}{
+		{
+			name:           "single result",
+			smoothing:      SmoothingMax,
+			results:        []float64{10},
+			expectedValue:  10,
+			expectedSource: ValueSourceQuery,
+		},
+		{
+			name:           "max over the window",
+			smoothing:      SmoothingMax,
+			results:        []float64{10, 4, 2},
+			expectedValue:  10,
+			expectedSource: ValueSourceSmoothedQuery,
+		},
+		{
+			name:           "max of the last results",
+			smoothing:      SmoothingMax,
+			results:        []float64{10, 4, 2, 3},
+			expectedValue:  4,
+			expectedSource: ValueSourceSmoothedQuery,
+		},
+		{
+			name:           "average of the last results",
+			smoothing:      SmoothingAverage,
+			results:        []float64{10, 4, 2, 3},
+			expectedValue:  3,
+			expectedSource: ValueSourceSmoothedQuery,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ddm := DatadogMetricInternal{
+				Options: DatadogMetricOptions{Smoothing: tt.smoothing, SmoothingWindow: 3},
+			}
+			for i, result := range tt.results {
+				ddm.SetQueryResult(result, now.Add(time.Duration(i)*time.Minute))
+			}
+
+			assert.True(t, ddm.Valid)
+			assert.Nil(t, ddm.Error)
+			assert.Equal(t, tt.expectedValue, ddm.Value)
+			assert.Equal(t, tt.expectedSource, ddm.Source)
+			assert.LessOrEqual(t, len(ddm.results), 3)
+		})
+	}
+
+	// The same point returned by several queries is smoothed once
+	ddm = DatadogMetricInternal{
+		Options: DatadogMetricOptions{Smoothing: SmoothingAverage, SmoothingWindow: 3},
+	}
+	ddm.SetQueryResult(10, now)
+	ddm.SetQueryResult(10, now)
+	ddm.SetQueryResult(4, now.Add(time.Minute))
+	assert.Equal(t, []float64{10, 4}, ddm.results)
+	assert.Equal(t, 7.0, ddm.Value)
+}
+
+func TestDatadogMetricInternal_SetQueryError(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+	fallback := 1.0
+	queryErr := errors.New("No data from backend")
+
+	tests := []struct {
+		name           string
+		options        DatadogMetricOptions
+		lastValidTime  time.Time
+		expectedValid  bool
+		expectedValue  float64
+		expectedSource ValueSource
+	}{
+		{
+			name:           "no options",
+			lastValidTime:  now.Add(-time.Minute),
+			expectedValid:  false,
+			expectedValue:  10,
+			expectedSource: ValueSourceNone,
+		},
+		{
+			name:           "hold last value",
+			options:        DatadogMetricOptions{HoldLastValue: 5 * time.Minute, FallbackValue: &fallback},
+			lastValidTime:  now.Add(-time.Minute),
+			expectedValid:  true,
+			expectedValue:  10,
+			expectedSource: ValueSourceLastValid,
+		},
+		{
+			name:           "hold expired, no fallback",
+			options:        DatadogMetricOptions{HoldLastValue: 5 * time.Minute},
+			lastValidTime:  now.Add(-10 * time.Minute),
+			expectedValid:  false,
+			expectedValue:  10,
+			expectedSource: ValueSourceNoValue,
+		},
+		{
+			name:           "hold expired, fallback",
+			options:        DatadogMetricOptions{HoldLastValue: 5 * time.Minute, FallbackValue: &fallback},
+			lastValidTime:  now.Add(-10 * time.Minute),
+			expectedValid:  true,
+			expectedValue:  1,
+			expectedSource: ValueSourceFallback,
+		},
+		{
+			name:           "never valid, hold",
+			options:        DatadogMetricOptions{HoldLastValue: 5 * time.Minute},
+			expectedValid:  false,
+			expectedValue:  10,
+			expectedSource: ValueSourceNoValue,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ddm := DatadogMetricInternal{
+				Valid:         true,
+				Value:         10,
+				Options:       tt.options,
+				lastValidTime: tt.lastValidTime,
+			}
+
+			assert.Equal(t, tt.expectedValid, ddm.SetQueryError(queryErr, now))
+			assert.Equal(t, tt.expectedValid, ddm.Valid)
+			assert.Equal(t, tt.expectedValue, ddm.Value)
+			assert.Equal(t, tt.expectedSource, ddm.Source)
+			assert.Equal(t, queryErr, ddm.Error)
+			assert.Equal(t, now, ddm.UpdateTime)
+		})
+	}
+}
+
+func TestDatadogMetricInternal_StaleCondition(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+	lastValidTime := now.Add(-time.Minute)
+
+	// No Stale condition without options
+	ddm := DatadogMetricInternal{Valid: true, UpdateTime: now}
+	assert.Len(t, ddm.BuildStatus(nil).Conditions, 4)
+
+	ddm = DatadogMetricInternal{
+		ID:            "default/dd-metric-0",
+		Valid:         true,
+		Active:        true,
+		Value:         10,
+		Error:         errors.New("No data from backend"),
+		UpdateTime:    now,
+		Options:       DatadogMetricOptions{HoldLastValue: 5 * time.Minute},
+		Source:        ValueSourceLastValid,
+		lastValidTime: lastValidTime,
+	}
+
+	status := ddm.BuildStatus(nil)
+	assert.Len(t, status.Conditions, 5)
+	staleCondition := status.Conditions[4]
+	assert.Equal(t, DatadogMetricConditionTypeStale, staleCondition.Type)
+	assert.Equal(t, corev1.ConditionTrue, staleCondition.Status)
+	assert.Equal(t, string(ValueSourceLastValid), staleCondition.Reason)
+	assert.Equal(t, v1.NewTime(now), staleCondition.LastTransitionTime)
+
+	// The source and the hold start are restored from the status
+	restored := NewDatadogMetricInternal("default/dd-metric-0", datadoghq.DatadogMetric{
+		ObjectMeta: v1.ObjectMeta{
+			Annotations: map[string]string{HoldLastValueAnnotation: "5m"},
+		},
+		Spec:   datadoghq.DatadogMetricSpec{Query: simpleQuery},
+		Status: *status,
+	})
+	assert.True(t, restored.Valid)
+	assert.Equal(t, 10.0, restored.Value)
+	assert.Equal(t, ValueSourceLastValid, restored.Source)
+	assert.Equal(t, now, restored.lastValidTime)
+	assert.Equal(t, 5*time.Minute, restored.Options.HoldLastValue)
+}
@@ -0,0 +1,45 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package check
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ResourceStats holds the resources used by a check instance
+type ResourceStats struct {
+	// MemoryInuseBytes is the memory allocated by the check and not freed yet
+	MemoryInuseBytes int64
+	// LastCPUTime is the CPU time used by the most recent run
+	LastCPUTime time.Duration
+}
+
+// ResourceReporter is implemented by the checks accounting the resources they use
+type ResourceReporter interface {
+	// GetResourceStats returns the resources used by the check
+	GetResourceStats() ResourceStats
+}
+
+// ResourceLimitError is returned by the run of a check that exceeded one of
+// its hard resource limits. Such a check is unscheduled.
+type ResourceLimitError struct {
+	Resource string
+	Usage    string
+	Limit    string
+}
+
+func (e *ResourceLimitError) Error() string {
+	return fmt.Sprintf("check unscheduled: %s usage (%s) exceeded the hard limit (%s)", e.Resource, e.Usage, e.Limit)
+}
+
+// IsResourceLimitError returns whether err was caused by a check exceeding
+// one of its hard resource limits
+func IsResourceLimitError(err error) bool {
+	var limitErr *ResourceLimitError
+	return errors.As(err, &limitErr)
+}
@@ -46,6 +46,10 @@ var (
 		[]string{"check_name"}, "Histogram buckets count")
 	tlmExecutionTime = telemetry.NewGauge("checks", "execution_time",
 		[]string{"check_name"}, "Check execution time")
+	tlmMemoryInuse = telemetry.NewGauge("checks", "memory_inuse_bytes",
+		[]string{"check_name"}, "Memory allocated by the check and not freed yet")
+	tlmCPUTime = telemetry.NewCounter("checks", "cpu_time",
+		[]string{"check_name"}, "CPU time used by the check in milliseconds")
 )
 
 // SenderStats contains statistics showing the count of various types of telemetry sent by a check sender
@@ -101,6 +105,9 @@ type Stats struct {
 	LastError                string    // error that occurred in the last run, if any
 	LastWarnings             []string  // warnings that occurred in the last run, if any
 	UpdateTimestamp          int64     // latest update to this instance, unix timestamp in seconds
+	MemoryInuseBytes         int64     // memory allocated by the check and not freed yet, for checks accounting their resources
+	LastCPUTime              int64     // CPU time used by the most recent run in milliseconds, for checks accounting their resources
+	TotalCPUTime             int64     // CPU time used by all the runs in milliseconds, for checks accounting their resources
 	m                        sync.Mutex
 	telemetry                bool // do we want telemetry on this Check
 }
@@ -212,6 +219,21 @@ func (cs *Stats) Add(t time.Duration, err error, warnings []error, metricStats S
 	}
 }
 
+// SetResourceStats tracks the resources used by the last run
+func (cs *Stats) SetResourceStats(rs ResourceStats) {
+	cs.m.Lock()
+	defer cs.m.Unlock()
+
+	cpuTime := rs.LastCPUTime.Milliseconds()
+	cs.MemoryInuseBytes = rs.MemoryInuseBytes
+	cs.LastCPUTime = cpuTime
+	cs.TotalCPUTime += cpuTime
+	if cs.telemetry {
+		tlmMemoryInuse.Set(float64(rs.MemoryInuseBytes), cs.CheckName)
+		tlmCPUTime.Add(float64(cpuTime), cs.CheckName)
+	}
+}
+
 type aggStats struct {
 	EventPlatformEvents       map[string]interface{}
 	EventPlatformEventsErrors map[string]interface{}
@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
@@ -104,6 +105,17 @@ func TestNewStatsStateTelemetryInitializedWhenGloballyEnabled(t *testing.T) {
 	)
 }
 
+func TestSetResourceStats(t *testing.T) {
+	stats := NewStats(newMockCheck())
+
+	stats.SetResourceStats(ResourceStats{MemoryInuseBytes: 2048, LastCPUTime: 150 * time.Millisecond})
+	stats.SetResourceStats(ResourceStats{MemoryInuseBytes: 1024, LastCPUTime: 50 * time.Millisecond})
+
+	assert.Equal(t, int64(1024), stats.MemoryInuseBytes)
+	assert.Equal(t, int64(50), stats.LastCPUTime)
+	assert.Equal(t, int64(200), stats.TotalCPUTime)
+}
+
 func TestTranslateEventPlatformEventTypes(t *testing.T) {
 	original := map[string]interface{}{
 		"EventPlatformEvents": map[string]interface{}{
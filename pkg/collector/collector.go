@@ -81,6 +81,7 @@ func (c *Collector) Start() {
 
 	// let the runner some visibility into the scheduler
 	run.SetScheduler(sched)
+	run.SetUnscheduleCheckFunc(c.unscheduleCheck)
 	sched.Run()
 
 	c.scheduler = sched
@@ -147,6 +148,17 @@ func (c *Collector) RunCheck(ch check.Check) (check.ID, error) {
 
 // StopCheck halts a check and remove the instance
 func (c *Collector) StopCheck(id check.ID) error {
+	return c.stopCheck(id, false)
+}
+
+// unscheduleCheck halts a check that exceeded its resource limits and removes
+// the instance. Unlike StopCheck, the stats of its last run are kept so that
+// its error shows in the status page.
+func (c *Collector) unscheduleCheck(id check.ID) error {
+	return c.stopCheck(id, true)
+}
+
+func (c *Collector) stopCheck(id check.ID, keepStats bool) error {
 	if !c.started() {
 		return fmt.Errorf("the collector is not running")
 	}
@@ -175,7 +187,9 @@ func (c *Collector) StopCheck(id check.ID) error {
 	}
 
 	// remove the check from the stats map
-	expvars.RemoveCheckStats(id)
+	if !keepStats {
+		expvars.RemoveCheckStats(id)
+	}
 	inventories.RemoveCheckMetadata(string(id))
 
 	// vaporize the check
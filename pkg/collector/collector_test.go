@@ -15,6 +15,7 @@ import (
 	"github.com/stretchr/testify/suite"
 
 	"github.com/DataDog/datadog-agent/pkg/collector/check"
+	"github.com/DataDog/datadog-agent/pkg/collector/runner/expvars"
 )
 
 // FIXTURE
@@ -131,6 +132,29 @@ func (suite *CollectorTestSuite) TestStopCheck() {
 	ch.AssertNumberOfCalls(suite.T(), "Cancel", 1)
 }
 
+func (suite *CollectorTestSuite) TestUnscheduleCheck() {
+	ch := NewCheck()
+	defer expvars.RemoveCheckStats(ch.ID())
+
+	_, err := suite.c.RunCheck(ch)
+	assert.Nil(suite.T(), err)
+	expvars.AddCheckStats(ch, time.Second, &check.ResourceLimitError{Resource: "memory", Usage: "12 MiB", Limit: "10 MiB"}, nil, check.SenderStats{})
+
+	err = suite.c.unscheduleCheck("TestCheck")
+	assert.Nil(suite.T(), err)
+	assert.Zero(suite.T(), len(suite.c.checks))
+	ch.AssertNumberOfCalls(suite.T(), "Cancel", 1)
+
+	// the stats of the last run are kept
+	stats, found := expvars.CheckStats("TestCheck")
+	assert.True(suite.T(), found)
+	assert.Contains(suite.T(), stats.LastError, "exceeded the hard limit")
+
+	// the check can be scheduled again
+	_, err = suite.c.RunCheck(ch)
+	assert.Nil(suite.T(), err)
+}
+
 func (suite *CollectorTestSuite) TestCancelCheck_TimeoutIsApplied() {
 	ch := NewCheckSlowCancel(10 * time.Second)
 
@@ -0,0 +1,558 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package customresources
+
+// This file provides a metric family generator factory for arbitrary custom
+// resources, configured declaratively instead of being hand-written like the
+// job, cronjob and pdb ones.
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+
+	"k8s.io/kube-state-metrics/v2/pkg/customresource"
+	"k8s.io/kube-state-metrics/v2/pkg/metric"
+	generator "k8s.io/kube-state-metrics/v2/pkg/metric_generator"
+)
+
+const (
+	// MetricTypeGauge is the type of the metrics whose value is read from a field of the resource
+	MetricTypeGauge = "gauge"
+	// MetricTypeInfo is the type of the metrics always equal to 1, only used
+	// to carry labels for label joins
+	MetricTypeInfo = "info"
+)
+
+var metricNameRegexp = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// CustomResourceConfig defines the metrics generated from the objects of a custom resource.
+// Example: Report the readiness of cert-manager certificates.
+// group: cert-manager.io
+// version: v1
+// kind: Certificate
+// metrics:
+//   - name: ready
+//     path: status.conditions[type=Ready].status
+//     value_mapping:
+//       "True": 1
+//       "False": 0
+//   - name: spec
+//     type: info
+//     labels_from_path:
+//       issuer_name: spec.issuerRef.name
+type CustomResourceConfig struct {
+	// Group, Version and Kind identify the custom resource
+	Group   string `yaml:"group"`
+	Version string `yaml:"version"`
+	Kind    string `yaml:"kind"`
+
+	// Resource is the plural name of the resource, defaults to the lowercase kind followed by "s"
+	Resource string `yaml:"resource"`
+
+	// ClusterScoped must be set for resources that aren't namespaced
+	ClusterScoped bool `yaml:"cluster_scoped"`
+
+	// MetricNamePrefix is used to name the metrics kube_<prefix>_<metric>,
+	// defaults to the kind in snake case
+	MetricNamePrefix string `yaml:"metric_name_prefix"`
+
+	// Metrics lists the metrics generated for each object
+	Metrics []CustomResourceMetricConfig `yaml:"metrics"`
+}
+
+// CustomResourceMetricConfig defines a metric generated from the fields of a custom resource object
+type CustomResourceMetricConfig struct {
+	// Name of the metric, gauges are named kube_<prefix>_<name> and info metrics kube_<prefix>_<name>_info
+	Name string `yaml:"name"`
+
+	// Help describes the metric
+	Help string `yaml:"help"`
+
+	// Type is either gauge (default) or info
+	Type string `yaml:"type"`
+
+	// Path is the path of the field holding the value of a gauge.
+	// Fields are separated by dots, list items can be selected by index
+	// (`containers[0]`) or by the value of one of their fields (`conditions[type=Ready]`),
+	// and keys containing dots can be quoted (`annotations["example.com/key"]`).
+	Path string `yaml:"path"`
+
+	// ValueMapping converts the field value to a number, mostly useful for string fields
+	ValueMapping map[string]float64 `yaml:"value_mapping"`
+
+	// LabelsFromPath adds labels whose value is read from the given paths
+	LabelsFromPath map[string]string `yaml:"labels_from_path"`
+}
+
+// GroupVersionResource returns the group, version and plural name of the resource
+func (c *CustomResourceConfig) GroupVersionResource() schema.GroupVersionResource {
+	resource := c.Resource
+	if resource == "" {
+		resource = strings.ToLower(c.Kind) + "s"
+	}
+	return schema.GroupVersionResource{Group: c.Group, Version: c.Version, Resource: resource}
+}
+
+// ResourceName returns the name of the collector of the resource, in the resource.group format
+func (c *CustomResourceConfig) ResourceName() string {
+	gvr := c.GroupVersionResource()
+	if gvr.Group == "" {
+		return gvr.Resource
+	}
+	return gvr.Resource + "." + gvr.Group
+}
+
+// MetricPrefix returns the prefix of the KSM metric names of the resource,
+// which is also the label holding the name of the objects
+func (c *CustomResourceConfig) MetricPrefix() string {
+	if c.MetricNamePrefix != "" {
+		return c.MetricNamePrefix
+	}
+	return toSnakeCase(c.Kind)
+}
+
+// LabelsMetricName returns the name of the metric carrying the labels of the objects
+func (c *CustomResourceConfig) LabelsMetricName() string {
+	return "kube_" + c.MetricPrefix() + "_labels"
+}
+
+// MetricName returns the KSM name of the given metric
+func (c *CustomResourceConfig) MetricName(m *CustomResourceMetricConfig) string {
+	name := "kube_" + c.MetricPrefix() + "_" + m.Name
+	if m.Type == MetricTypeInfo {
+		name += "_info"
+	}
+	return name
+}
+
+// LabelsToMatch returns the labels identifying an object of the resource, to be used in label joins
+func (c *CustomResourceConfig) LabelsToMatch() []string {
+	if c.ClusterScoped {
+		return []string{c.MetricPrefix()}
+	}
+	return []string{c.MetricPrefix(), "namespace"}
+}
+
+// Validate checks the configuration of the custom resource
+func (c *CustomResourceConfig) Validate() error {
+	if c.Version == "" || c.Kind == "" {
+		return fmt.Errorf("custom resource %q: version and kind are required", c.Kind)
+	}
+	if !metricNameRegexp.MatchString(c.MetricPrefix()) {
+		return fmt.Errorf("custom resource %q: invalid metric name prefix %q", c.Kind, c.MetricPrefix())
+	}
+	if len(c.Metrics) == 0 {
+		return fmt.Errorf("custom resource %q: no metrics defined", c.Kind)
+	}
+
+	for i := range c.Metrics {
+		m := &c.Metrics[i]
+		if m.Type == "" {
+			m.Type = MetricTypeGauge
+		}
+		if !metricNameRegexp.MatchString(m.Name) {
+			return fmt.Errorf("custom resource %q: invalid metric name %q", c.Kind, m.Name)
+		}
+		switch m.Type {
+		case MetricTypeGauge:
+			if m.Path == "" {
+				return fmt.Errorf("custom resource %q: metric %q: path is required for gauges", c.Kind, m.Name)
+			}
+		case MetricTypeInfo:
+		default:
+			return fmt.Errorf("custom resource %q: metric %q: unknown type %q", c.Kind, m.Name, m.Type)
+		}
+		if m.Path != "" {
+			if _, err := parsePath(m.Path); err != nil {
+				return fmt.Errorf("custom resource %q: metric %q: %v", c.Kind, m.Name, err)
+			}
+		}
+		for label, path := range m.LabelsFromPath {
+			if !metricNameRegexp.MatchString(label) {
+				return fmt.Errorf("custom resource %q: metric %q: invalid label name %q", c.Kind, m.Name, label)
+			}
+			if _, err := parsePath(path); err != nil {
+				return fmt.Errorf("custom resource %q: metric %q: label %q: %v", c.Kind, m.Name, label, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// NewCustomResourceStateFactory returns a new metric family generator factory for the given custom resource.
+func NewCustomResourceStateFactory(config CustomResourceConfig) (customresource.RegistryFactory, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	f := &customResourceStateFactory{
+		config:        config,
+		gvr:           config.GroupVersionResource(),
+		defaultLabels: config.LabelsToMatch(),
+		metrics:       make([]compiledMetric, 0, len(config.Metrics)),
+	}
+
+	for i := range config.Metrics {
+		m := &config.Metrics[i]
+		cm := compiledMetric{
+			name:         config.MetricName(m),
+			help:         m.Help,
+			isInfo:       m.Type == MetricTypeInfo,
+			valueMapping: m.ValueMapping,
+		}
+		if cm.help == "" {
+			cm.help = fmt.Sprintf("%s %s of the %s custom resource", m.Type, m.Name, config.Kind)
+		}
+		if m.Path != "" {
+			cm.path, _ = parsePath(m.Path)
+		}
+		for label, path := range m.LabelsFromPath {
+			p, _ := parsePath(path)
+			cm.labels = append(cm.labels, labelFromPath{key: label, path: p})
+		}
+		sort.Slice(cm.labels, func(i, j int) bool { return cm.labels[i].key < cm.labels[j].key })
+		f.metrics = append(f.metrics, cm)
+	}
+
+	return f, nil
+}
+
+type customResourceStateFactory struct {
+	config        CustomResourceConfig
+	gvr           schema.GroupVersionResource
+	defaultLabels []string
+	metrics       []compiledMetric
+}
+
+type compiledMetric struct {
+	name         string
+	help         string
+	isInfo       bool
+	path         fieldPath
+	valueMapping map[string]float64
+	labels       []labelFromPath
+}
+
+type labelFromPath struct {
+	key  string
+	path fieldPath
+}
+
+func (f *customResourceStateFactory) Name() string {
+	return f.config.ResourceName()
+}
+
+// CreateClient is not implemented
+func (f *customResourceStateFactory) CreateClient(cfg *rest.Config) (interface{}, error) {
+	panic("not implemented")
+}
+
+func (f *customResourceStateFactory) MetricFamilyGenerators(allowAnnotationsList, allowLabelsList []string) []generator.FamilyGenerator {
+	families := []generator.FamilyGenerator{
+		*generator.NewFamilyGenerator(
+			f.config.LabelsMetricName(),
+			"Kubernetes labels converted to Prometheus labels.",
+			metric.Gauge,
+			"",
+			f.wrapFunc(func(obj *unstructured.Unstructured) *metric.Family {
+				labelKeys, labelValues := createPrometheusLabelKeysValues("label", obj.GetLabels(), allowLabelsList)
+				return &metric.Family{
+					Metrics: []*metric.Metric{
+						{
+							LabelKeys:   labelKeys,
+							LabelValues: labelValues,
+							Value:       1,
+						},
+					},
+				}
+			}),
+		),
+	}
+
+	for i := range f.metrics {
+		m := f.metrics[i]
+		families = append(families, *generator.NewFamilyGenerator(
+			m.name,
+			m.help,
+			metric.Gauge,
+			"",
+			f.wrapFunc(func(obj *unstructured.Unstructured) *metric.Family {
+				return &metric.Family{
+					Metrics: m.generate(obj.Object),
+				}
+			}),
+		))
+	}
+
+	return families
+}
+
+func (f *customResourceStateFactory) ExpectedType() interface{} {
+	u := &unstructured.Unstructured{}
+	u.SetGroupVersionKind(schema.GroupVersionKind{Group: f.config.Group, Version: f.config.Version, Kind: f.config.Kind})
+	return u
+}
+
+func (f *customResourceStateFactory) ListWatch(customResourceClient interface{}, ns string, fieldSelector string) cache.ListerWatcher {
+	client := customResourceClient.(dynamic.Interface)
+
+	var resourceClient dynamic.ResourceInterface = client.Resource(f.gvr)
+	if !f.config.ClusterScoped {
+		resourceClient = client.Resource(f.gvr).Namespace(ns)
+	}
+
+	return &cache.ListWatch{
+		ListFunc: func(opts metav1.ListOptions) (runtime.Object, error) {
+			opts.FieldSelector = fieldSelector
+			return resourceClient.List(context.TODO(), opts)
+		},
+		WatchFunc: func(opts metav1.ListOptions) (watch.Interface, error) {
+			opts.FieldSelector = fieldSelector
+			return resourceClient.Watch(context.TODO(), opts)
+		},
+	}
+}
+
+func (f *customResourceStateFactory) wrapFunc(fn func(*unstructured.Unstructured) *metric.Family) func(interface{}) *metric.Family {
+	return func(obj interface{}) *metric.Family {
+		u := obj.(*unstructured.Unstructured)
+
+		metricFamily := fn(u)
+
+		defaultValues := []string{u.GetName()}
+		if !f.config.ClusterScoped {
+			defaultValues = append(defaultValues, u.GetNamespace())
+		}
+
+		for _, m := range metricFamily.Metrics {
+			m.LabelKeys, m.LabelValues = mergeKeyValues(f.defaultLabels, defaultValues, m.LabelKeys, m.LabelValues)
+		}
+
+		return metricFamily
+	}
+}
+
+// generate returns the metric generated from the given object, if any
+func (m *compiledMetric) generate(obj map[string]interface{}) []*metric.Metric {
+	value := float64(1)
+	if len(m.path) > 0 {
+		field, found := m.path.get(obj)
+		if !found {
+			return []*metric.Metric{}
+		}
+		if m.isInfo {
+			if field == nil {
+				return []*metric.Metric{}
+			}
+		} else {
+			v, ok := m.toFloat64(field)
+			if !ok {
+				return []*metric.Metric{}
+			}
+			value = v
+		}
+	}
+
+	keys := make([]string, 0, len(m.labels))
+	values := make([]string, 0, len(m.labels))
+	for _, l := range m.labels {
+		field, found := l.path.get(obj)
+		if !found {
+			continue
+		}
+		s, ok := scalarToString(field)
+		if !ok {
+			continue
+		}
+		keys = append(keys, l.key)
+		values = append(values, s)
+	}
+
+	return []*metric.Metric{
+		{
+			LabelKeys:   keys,
+			LabelValues: values,
+			Value:       value,
+		},
+	}
+}
+
+// toFloat64 converts the value of a field to a metric value.
+// Strings are converted using the value mapping if set, and are otherwise
+// parsed as numbers or RFC 3339 timestamps (converted to a Unix timestamp).
+func (m *compiledMetric) toFloat64(field interface{}) (float64, bool) {
+	if len(m.valueMapping) > 0 {
+		s, ok := scalarToString(field)
+		if !ok {
+			return 0, false
+		}
+		v, found := m.valueMapping[s]
+		return v, found
+	}
+
+	switch v := field.(type) {
+	case int64:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case float64:
+		return v, true
+	case bool:
+		return boolFloat64(v), true
+	case string:
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			return f, true
+		}
+		if t, err := time.Parse(time.RFC3339, v); err == nil {
+			return float64(t.Unix()), true
+		}
+	}
+
+	return 0, false
+}
+
+func scalarToString(field interface{}) (string, bool) {
+	switch v := field.(type) {
+	case string:
+		return v, true
+	case int64, int, float64, bool:
+		return fmt.Sprint(v), true
+	}
+	return "", false
+}
+
+// fieldPath is a parsed path to a field of an unstructured object
+type fieldPath []pathSegment
+
+type pathSegment struct {
+	// field is the name of the map key to get, empty when the segment
+	// selects a list item
+	field string
+
+	// index selects a list item by position when >= 0
+	index int
+
+	// selectorKey and selectorValue select the first list item whose
+	// selectorKey field equals selectorValue
+	selectorKey   string
+	selectorValue string
+}
+
+// parsePath parses paths like `status.conditions[type=Ready].status`,
+// `spec.containers[0].image` or `metadata.annotations["example.com/key"]`.
+// A leading `$.` or `.` is ignored.
+func parsePath(path string) (fieldPath, error) {
+	s := strings.TrimPrefix(strings.TrimPrefix(path, "$"), ".")
+	if s == "" {
+		return nil, fmt.Errorf("empty path %q", path)
+	}
+
+	var fp fieldPath
+	for len(s) > 0 {
+		switch s[0] {
+		case '.':
+			s = s[1:]
+			if s == "" || s[0] == '.' || s[0] == '[' {
+				return nil, fmt.Errorf("invalid path %q: empty field name", path)
+			}
+		case '[':
+			end := strings.IndexByte(s, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("invalid path %q: missing ']'", path)
+			}
+			segment, err := parseBracket(s[1:end])
+			if err != nil {
+				return nil, fmt.Errorf("invalid path %q: %v", path, err)
+			}
+			fp = append(fp, segment)
+			s = s[end+1:]
+		default:
+			end := strings.IndexAny(s, ".[")
+			if end < 0 {
+				end = len(s)
+			}
+			fp = append(fp, pathSegment{field: s[:end], index: -1})
+			s = s[end:]
+		}
+	}
+
+	return fp, nil
+}
+
+func parseBracket(content string) (pathSegment, error) {
+	if len(content) >= 2 && content[0] == '"' && content[len(content)-1] == '"' {
+		return pathSegment{field: content[1 : len(content)-1], index: -1}, nil
+	}
+	if sep := strings.IndexByte(content, '='); sep >= 0 {
+		if sep == 0 {
+			return pathSegment{}, fmt.Errorf("empty selector key in [%s]", content)
+		}
+		return pathSegment{index: -1, selectorKey: content[:sep], selectorValue: strings.Trim(content[sep+1:], `"`)}, nil
+	}
+	index, err := strconv.Atoi(content)
+	if err != nil || index < 0 {
+		return pathSegment{}, fmt.Errorf("invalid index [%s]", content)
+	}
+	return pathSegment{index: index}, nil
+}
+
+// get returns the value of the field pointed by the path in obj
+func (p fieldPath) get(obj map[string]interface{}) (interface{}, bool) {
+	var current interface{} = obj
+	for _, segment := range p {
+		switch {
+		case segment.field != "":
+			m, ok := current.(map[string]interface{})
+			if !ok {
+				return nil, false
+			}
+			if current, ok = m[segment.field]; !ok {
+				return nil, false
+			}
+		case segment.selectorKey != "":
+			items, ok := current.([]interface{})
+			if !ok {
+				return nil, false
+			}
+			current = nil
+			for _, item := range items {
+				m, ok := item.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				if s, ok := scalarToString(m[segment.selectorKey]); ok && s == segment.selectorValue {
+					current = m
+					break
+				}
+			}
+			if current == nil {
+				return nil, false
+			}
+		default:
+			items, ok := current.([]interface{})
+			if !ok || segment.index < 0 || segment.index >= len(items) {
+				return nil, false
+			}
+			current = items[segment.index]
+		}
+	}
+	return current, true
+}
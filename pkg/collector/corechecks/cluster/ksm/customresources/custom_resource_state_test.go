@@ -0,0 +1,269 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package customresources
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/kube-state-metrics/v2/pkg/metric"
+)
+
+func TestParsePath(t *testing.T) {
+	tests := []struct {
+		path     string
+		expected fieldPath
+		wantErr  bool
+	}{
+		{
+			path:     "status.availableReplicas",
+			expected: fieldPath{{field: "status", index: -1}, {field: "availableReplicas", index: -1}},
+		},
+		{
+			path:     "$.status.phase",
+			expected: fieldPath{{field: "status", index: -1}, {field: "phase", index: -1}},
+		},
+		{
+			path: "status.conditions[type=Ready].status",
+			expected: fieldPath{
+				{field: "status", index: -1},
+				{field: "conditions", index: -1},
+				{index: -1, selectorKey: "type", selectorValue: "Ready"},
+				{field: "status", index: -1},
+			},
+		},
+		{
+			path:     "spec.containers[1]",
+			expected: fieldPath{{field: "spec", index: -1}, {field: "containers", index: -1}, {index: 1}},
+		},
+		{
+			path:     `metadata.annotations["example.com/key"]`,
+			expected: fieldPath{{field: "metadata", index: -1}, {field: "annotations", index: -1}, {field: "example.com/key", index: -1}},
+		},
+		{path: "", wantErr: true},
+		{path: "status..phase", wantErr: true},
+		{path: "status.conditions[type=Ready", wantErr: true},
+		{path: "status.conditions[-1]", wantErr: true},
+		{path: "status.conditions[=Ready]", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got, err := parsePath(tt.path)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+}
+
+func TestCustomResourceConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  CustomResourceConfig
+		wantErr bool
+	}{
+		{
+			name: "valid",
+			config: CustomResourceConfig{
+				Group:   "argoproj.io",
+				Version: "v1alpha1",
+				Kind:    "Rollout",
+				Metrics: []CustomResourceMetricConfig{{Name: "replicas", Path: "status.replicas"}},
+			},
+		},
+		{
+			name: "missing kind",
+			config: CustomResourceConfig{
+				Version: "v1",
+				Metrics: []CustomResourceMetricConfig{{Name: "replicas", Path: "status.replicas"}},
+			},
+			wantErr: true,
+		},
+		{
+			name:    "no metrics",
+			config:  CustomResourceConfig{Version: "v1", Kind: "Rollout"},
+			wantErr: true,
+		},
+		{
+			name: "gauge without path",
+			config: CustomResourceConfig{
+				Version: "v1",
+				Kind:    "Rollout",
+				Metrics: []CustomResourceMetricConfig{{Name: "replicas"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "unknown type",
+			config: CustomResourceConfig{
+				Version: "v1",
+				Kind:    "Rollout",
+				Metrics: []CustomResourceMetricConfig{{Name: "replicas", Type: "histogram", Path: "status.replicas"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid label",
+			config: CustomResourceConfig{
+				Version: "v1",
+				Kind:    "Rollout",
+				Metrics: []CustomResourceMetricConfig{{Name: "status", Type: "info", LabelsFromPath: map[string]string{"Phase-Name": "status.phase"}}},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.Validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestCustomResourceConfigNames(t *testing.T) {
+	config := CustomResourceConfig{
+		Group:   "cert-manager.io",
+		Version: "v1",
+		Kind:    "CertificateRequest",
+	}
+
+	assert.Equal(t, "certificaterequests.cert-manager.io", config.ResourceName())
+	assert.Equal(t, "certificate_request", config.MetricPrefix())
+	assert.Equal(t, "kube_certificate_request_ready", config.MetricName(&CustomResourceMetricConfig{Name: "ready"}))
+	assert.Equal(t, "kube_certificate_request_spec_info", config.MetricName(&CustomResourceMetricConfig{Name: "spec", Type: MetricTypeInfo}))
+	assert.Equal(t, []string{"certificate_request", "namespace"}, config.LabelsToMatch())
+
+	config.Resource = "certreqs"
+	config.MetricNamePrefix = "certreq"
+	config.ClusterScoped = true
+	assert.Equal(t, "certreqs.cert-manager.io", config.ResourceName())
+	assert.Equal(t, "kube_certreq_labels", config.LabelsMetricName())
+	assert.Equal(t, []string{"certreq"}, config.LabelsToMatch())
+}
+
+func TestCustomResourceStateMetricFamilies(t *testing.T) {
+	f, err := NewCustomResourceStateFactory(CustomResourceConfig{
+		Group:   "cert-manager.io",
+		Version: "v1",
+		Kind:    "Certificate",
+		Metrics: []CustomResourceMetricConfig{
+			{
+				Name:         "ready",
+				Path:         "status.conditions[type=Ready].status",
+				ValueMapping: map[string]float64{"True": 1, "False": 0},
+			},
+			{Name: "expiration_timestamp", Path: "status.notAfter"},
+			{Name: "revision", Path: "status.revision"},
+			{Name: "renewal_timestamp", Path: "status.renewalTime"},
+			{
+				Name: "spec",
+				Type: MetricTypeInfo,
+				LabelsFromPath: map[string]string{
+					"issuer_name": "spec.issuerRef.name",
+					"secret_name": "spec.secretName",
+					"missing":     "spec.missing",
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "certificates.cert-manager.io", f.Name())
+
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "cert-manager.io/v1",
+		"kind":       "Certificate",
+		"metadata": map[string]interface{}{
+			"name":      "my-cert",
+			"namespace": "default",
+			"labels":    map[string]interface{}{"team": "platform"},
+		},
+		"spec": map[string]interface{}{
+			"issuerRef":  map[string]interface{}{"name": "letsencrypt"},
+			"secretName": "my-cert-tls",
+		},
+		"status": map[string]interface{}{
+			"conditions": []interface{}{
+				map[string]interface{}{"type": "Issuing", "status": "True"},
+				map[string]interface{}{"type": "Ready", "status": "False"},
+			},
+			"notAfter": "2022-09-01T00:00:00Z",
+			"revision": int64(3),
+		},
+	}}
+
+	defaultKeys := []string{"certificate", "namespace"}
+	defaultValues := []string{"my-cert", "default"}
+
+	expected := map[string][]*metric.Metric{
+		"kube_certificate_labels": {
+			{LabelKeys: append(defaultKeys, "label_team"), LabelValues: append(defaultValues, "platform"), Value: 1},
+		},
+		"kube_certificate_ready": {
+			{LabelKeys: defaultKeys, LabelValues: defaultValues, Value: 0},
+		},
+		"kube_certificate_expiration_timestamp": {
+			{LabelKeys: defaultKeys, LabelValues: defaultValues, Value: 1661990400},
+		},
+		"kube_certificate_revision": {
+			{LabelKeys: defaultKeys, LabelValues: defaultValues, Value: 3},
+		},
+		"kube_certificate_renewal_timestamp": {},
+		"kube_certificate_spec_info": {
+			{
+				LabelKeys:   append(defaultKeys, "issuer_name", "secret_name"),
+				LabelValues: append(defaultValues, "letsencrypt", "my-cert-tls"),
+				Value:       1,
+			},
+		},
+	}
+
+	generators := f.MetricFamilyGenerators(nil, []string{"*"})
+	require.Len(t, generators, len(expected))
+	for _, g := range generators {
+		family := g.GenerateFunc(obj)
+		want, found := expected[g.Name]
+		require.True(t, found, "unexpected metric family %s", g.Name)
+		assert.Equal(t, want, family.Metrics, g.Name)
+	}
+}
+
+func TestCustomResourceStateClusterScoped(t *testing.T) {
+	f, err := NewCustomResourceStateFactory(CustomResourceConfig{
+		Group:         "example.org",
+		Version:       "v1",
+		Kind:          "Database",
+		ClusterScoped: true,
+		Metrics: []CustomResourceMetricConfig{
+			{Name: "ready", Path: "status.ready"},
+		},
+	})
+	require.NoError(t, err)
+
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"metadata": map[string]interface{}{"name": "orders"},
+		"status":   map[string]interface{}{"ready": true},
+	}}
+
+	for _, g := range f.MetricFamilyGenerators(nil, nil) {
+		if g.Name != "kube_database_ready" {
+			continue
+		}
+		assert.Equal(t, []*metric.Metric{
+			{LabelKeys: []string{"database"}, LabelValues: []string{"orders"}, Value: 1},
+		}, g.GenerateFunc(obj).Metrics)
+		return
+	}
+	t.Fatal("kube_database_ready not generated")
+}
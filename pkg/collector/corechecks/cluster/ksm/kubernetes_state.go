@@ -121,6 +121,22 @@ type KSMConfig struct {
 	// LeaderSkip forces ignoring the leader election when running the check
 	// Can be useful when running the check as cluster check
 	LeaderSkip bool `yaml:"skip_leader_election"`
+
+	// CustomResources defines metrics generated from the fields of custom resources.
+	// Gauges are submitted as kubernetes_state.<prefix>.<name>, info metrics are only used for label joins.
+	// Example: Report the phase of Argo Rollouts.
+	// custom_resources:
+	//   - group: argoproj.io
+	//     version: v1alpha1
+	//     kind: Rollout
+	//     metrics:
+	//       - name: replicas_available
+	//         path: status.availableReplicas
+	//       - name: status
+	//         type: info
+	//         labels_from_path:
+	//           phase: status.phase
+	CustomResources []customresources.CustomResourceConfig `yaml:"custom_resources"`
 }
 
 // KSMCheck wraps the config and the metric stores needed to run the check
@@ -196,6 +212,8 @@ func (k *KSMCheck) Configure(config, initConfig integration.Data, source string)
 	labelJoins := defaultLabelJoins()
 	k.mergeLabelJoins(labelJoins)
 
+	k.processCustomResources()
+
 	k.processLabelsAsTags()
 
 	// Prepare labels mapper
@@ -217,6 +235,17 @@ func (k *KSMCheck) Configure(config, initConfig integration.Data, source string)
 		collectors = options.DefaultResources.AsSlice()
 	}
 
+	// Custom resources are always collected when configured
+	customResourceFactories := make([]customresource.RegistryFactory, 0, len(k.instance.CustomResources))
+	for _, cr := range k.instance.CustomResources {
+		f, err := customresources.NewCustomResourceStateFactory(cr)
+		if err != nil {
+			return err
+		}
+		customResourceFactories = append(customResourceFactories, f)
+		collectors = append(collectors, f.Name())
+	}
+
 	// Enable exposing resource labels explicitly for kube_<resource>_labels metadata metrics.
 	// Equivalent to configuring --metric-labels-allowlist.
 	allowedLabels := map[string][]string{}
@@ -281,11 +310,16 @@ func (k *KSMCheck) Configure(config, initConfig integration.Data, source string)
 		customresources.NewPodDisruptionBudgetFactory(),
 	}
 
-	clients := make(map[string]interface{}, len(factories))
+	clients := make(map[string]interface{}, len(factories)+len(customResourceFactories))
 	for _, f := range factories {
 		clients[f.Name()] = c.Cl
 	}
 
+	for _, f := range customResourceFactories {
+		clients[f.Name()] = c.DynamicCl
+	}
+	factories = append(factories, customResourceFactories...)
+
 	builder.WithCustomResourceStoreFactories(factories...)
 	builder.WithCustomResourceClients(clients)
 	builder.WithGenerateCustomResourceStoresFunc(builder.GenerateCustomResourceStoresFunc)
@@ -573,6 +607,42 @@ func (k *KSMCheck) processLabelsAsTags() {
 	}
 }
 
+// processCustomResources maps the custom resource gauges to datadog metric names
+// and prepares the label joins of their info and labels metrics.
+// User-defined label joins and metric names are prioritized.
+func (k *KSMCheck) processCustomResources() {
+	for i := range k.instance.CustomResources {
+		cr := &k.instance.CustomResources[i]
+		labelsToMatch := cr.LabelsToMatch()
+
+		for j := range cr.Metrics {
+			m := &cr.Metrics[j]
+			name := cr.MetricName(m)
+			if m.Type == customresources.MetricTypeInfo {
+				if _, found := k.instance.LabelJoins[name]; !found {
+					k.instance.LabelJoins[name] = &JoinsConfig{
+						LabelsToMatch: labelsToMatch,
+						GetAllLabels:  true,
+					}
+				}
+				continue
+			}
+
+			if _, found := k.metricNamesMapper[name]; !found {
+				k.metricNamesMapper[name] = cr.MetricPrefix() + "." + m.Name
+			}
+		}
+
+		// labels_as_tags relies on the default labels to match of the kind,
+		// which don't apply to cluster-scoped custom resources
+		if _, found := k.instance.LabelsAsTags[cr.MetricPrefix()]; found {
+			if _, found := k.instance.LabelJoins[cr.LabelsMetricName()]; !found {
+				k.instance.LabelJoins[cr.LabelsMetricName()] = &JoinsConfig{LabelsToMatch: labelsToMatch}
+			}
+		}
+	}
+}
+
 // getClusterName retrieves the name of the cluster, if found
 func (k *KSMCheck) getClusterName() {
 	hostname, _ := hostnameUtil.Get(context.TODO())
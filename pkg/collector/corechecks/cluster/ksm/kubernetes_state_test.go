@@ -20,6 +20,7 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/aggregator"
 	"github.com/DataDog/datadog-agent/pkg/aggregator/mocksender"
 	core "github.com/DataDog/datadog-agent/pkg/collector/corechecks"
+	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/cluster/ksm/customresources"
 	"github.com/DataDog/datadog-agent/pkg/config"
 	ksmstore "github.com/DataDog/datadog-agent/pkg/kubestatemetrics/store"
 )
@@ -1179,6 +1180,62 @@ func TestKSMCheck_processLabelsAsTags(t *testing.T) {
 	}
 }
 
+func TestKSMCheck_processCustomResources(t *testing.T) {
+	config := &KSMConfig{
+		LabelJoins: map[string]*JoinsConfig{
+			"kube_certificate_spec_info": {
+				LabelsToMatch: []string{"certificate", "namespace"},
+				LabelsToGet:   []string{"issuer_name"},
+			},
+		},
+		LabelsMapper: map[string]string{},
+		LabelsAsTags: map[string]map[string]string{
+			"composite_database": {"team": "team"},
+		},
+		CustomResources: []customresources.CustomResourceConfig{
+			{
+				Group:   "cert-manager.io",
+				Version: "v1",
+				Kind:    "Certificate",
+				Metrics: []customresources.CustomResourceMetricConfig{
+					{Name: "ready", Path: "status.conditions[type=Ready].status"},
+					{Name: "spec", Type: "info", LabelsFromPath: map[string]string{"issuer_name": "spec.issuerRef.name"}},
+				},
+			},
+			{
+				Group:         "example.org",
+				Version:       "v1alpha1",
+				Kind:          "CompositeDatabase",
+				ClusterScoped: true,
+				Metrics: []customresources.CustomResourceMetricConfig{
+					{Name: "status", Type: "info", LabelsFromPath: map[string]string{"engine": "spec.engine"}},
+				},
+			},
+		},
+	}
+
+	k := &KSMCheck{instance: config, metricNamesMapper: map[string]string{}}
+	k.processCustomResources()
+	k.processLabelsAsTags()
+
+	assert.Equal(t, map[string]string{"kube_certificate_ready": "certificate.ready"}, k.metricNamesMapper)
+	assert.Equal(t, map[string]*JoinsConfig{
+		// user-defined joins are kept as is
+		"kube_certificate_spec_info": {
+			LabelsToMatch: []string{"certificate", "namespace"},
+			LabelsToGet:   []string{"issuer_name"},
+		},
+		"kube_composite_database_status_info": {
+			LabelsToMatch: []string{"composite_database"},
+			GetAllLabels:  true,
+		},
+		"kube_composite_database_labels": {
+			LabelsToMatch: []string{"composite_database"},
+			LabelsToGet:   []string{"label_team"},
+		},
+	}, k.instance.LabelJoins)
+}
+
 func TestKSMCheck_mergeLabelsMapper(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -18,6 +18,7 @@ import (
 	cache "github.com/patrickmn/go-cache"
 	"gopkg.in/yaml.v2"
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 
 	"github.com/DataDog/datadog-agent/pkg/aggregator"
 	"github.com/DataDog/datadog-agent/pkg/autodiscovery/integration"
@@ -62,18 +63,27 @@ var (
 		"Number of events emitted by the check.",
 		telemetry.Options{NoDoubleUnderscoreSep: true},
 	)
+
+	droppedEvents = telemetry.NewCounterWithOpts(
+		kubernetesAPIServerCheckName,
+		"dropped_events",
+		[]string{"kind", "type"},
+		"Number of events dropped by the event rules of the check.",
+		telemetry.Options{NoDoubleUnderscoreSep: true},
+	)
 )
 
 // KubeASConfig is the config of the API server.
 type KubeASConfig struct {
-	CollectEvent             bool     `yaml:"collect_events"`
-	CollectOShiftQuotas      bool     `yaml:"collect_openshift_clusterquotas"`
-	FilteredEventTypes       []string `yaml:"filtered_event_types"`
-	EventCollectionTimeoutMs int      `yaml:"kubernetes_event_read_timeout_ms"`
-	MaxEventCollection       int      `yaml:"max_events_per_run"`
-	LeaderSkip               bool     `yaml:"skip_leader_election"`
-	ResyncPeriodEvents       int      `yaml:"kubernetes_event_resync_period_s"`
-	UseComponentStatus       bool     `yaml:"use_component_status"`
+	CollectEvent             bool        `yaml:"collect_events"`
+	CollectOShiftQuotas      bool        `yaml:"collect_openshift_clusterquotas"`
+	FilteredEventTypes       []string    `yaml:"filtered_event_types"`
+	EventCollectionTimeoutMs int         `yaml:"kubernetes_event_read_timeout_ms"`
+	MaxEventCollection       int         `yaml:"max_events_per_run"`
+	LeaderSkip               bool        `yaml:"skip_leader_election"`
+	ResyncPeriodEvents       int         `yaml:"kubernetes_event_resync_period_s"`
+	UseComponentStatus       bool        `yaml:"use_component_status"`
+	EventRules               []EventRule `yaml:"event_rules"`
 }
 
 // EventC holds the information pertaining to which event we collected last and when we last re-synced.
@@ -91,6 +101,9 @@ type KubeASCheck struct {
 	ac              *apiserver.APIClient
 	oshiftAPILevel  apiserver.OpenShiftAPILevel
 	providerIDCache *cache.Cache
+	labelsCache     *cache.Cache
+	restMapper      meta.RESTMapper
+	getObjectLabels func(ref v1.ObjectReference) (map[string]string, error)
 }
 
 func (c *KubeASConfig) parse(data []byte) error {
@@ -105,11 +118,14 @@ func (c *KubeASConfig) parse(data []byte) error {
 
 // NewKubeASCheck returns a new KubeASCheck
 func NewKubeASCheck(base core.CheckBase, instance *KubeASConfig) *KubeASCheck {
-	return &KubeASCheck{
+	k := &KubeASCheck{
 		CheckBase:       base,
 		instance:        instance,
 		providerIDCache: cache.New(defaultCacheExpire, defaultCachePurge),
+		labelsCache:     cache.New(defaultCacheExpire, defaultCachePurge),
 	}
+	k.getObjectLabels = k.fetchObjectLabels
+	return k
 }
 
 // KubernetesASFactory is exported for integration testing.
@@ -139,6 +155,10 @@ func (k *KubeASCheck) Configure(config, initConfig integration.Data, source stri
 	}
 	k.ignoredEvents = convertFilter(k.instance.FilteredEventTypes)
 
+	if err = compileEventRules(k.instance.EventRules); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -329,11 +349,26 @@ func (k *KubeASCheck) processEvents(sender aggregator.Sender, events []*v1.Event
 			continue
 		}
 
-		id := buildBundleID(event)
+		ruleIndex := k.matchEventRule(event)
+		if ruleIndex != noEventRule {
+			rule := &k.instance.EventRules[ruleIndex]
+			if rule.Metric {
+				submitEventMetric(sender, event)
+			}
+			if rule.Action == eventRuleActionDrop {
+				droppedEvents.Inc(event.InvolvedObject.Kind, event.Type)
+				continue
+			}
+		}
+
+		id := buildBundleID(event, ruleIndex)
 
 		bundle, found := bundlesByObject[id]
 		if !found {
 			bundle = newKubernetesEventBundler(event)
+			if ruleIndex != noEventRule {
+				bundle.applyRule(&k.instance.EventRules[ruleIndex])
+			}
 			bundlesByObject[id] = bundle
 		}
 
@@ -408,15 +443,17 @@ type bundleID struct {
 	kind   string
 	uid    string
 	evType string
+	rule   int
 }
 
 // buildBundleID generates a unique ID to separate k8s events
-// based on their InvolvedObject UIDs and event Types
-func buildBundleID(e *v1.Event) bundleID {
+// based on their InvolvedObject UIDs, event Types and the event rule they matched
+func buildBundleID(e *v1.Event, rule int) bundleID {
 	return bundleID{
 		kind:   e.InvolvedObject.Kind,
 		uid:    string(e.InvolvedObject.UID),
 		evType: e.Type,
+		rule:   rule,
 	}
 }
 
@@ -37,6 +37,7 @@ type kubernetesEventBundle struct {
 	countByAction map[string]int         // Map of count per action to aggregate several events from the same ObjUid in one event
 	nodename      string                 // Stores the nodename that should be used to submit the events
 	alertType     metrics.EventAlertType // The Datadog event type
+	priority      metrics.EventPriority  // The Datadog event priority
 }
 
 func newKubernetesEventBundler(event *v1.Event) *kubernetesEventBundle {
@@ -45,6 +46,17 @@ func newKubernetesEventBundler(event *v1.Event) *kubernetesEventBundle {
 		component:     event.Source.Component,
 		countByAction: make(map[string]int),
 		alertType:     getDDAlertType(event.Type),
+		priority:      metrics.EventPriorityNormal,
+	}
+}
+
+// applyRule overrides the priority and alert type of the bundle with the ones of the event rule
+func (b *kubernetesEventBundle) applyRule(rule *EventRule) {
+	if rule.priority != "" {
+		b.priority = rule.priority
+	}
+	if rule.alertType != "" {
+		b.alertType = rule.alertType
 	}
 }
 
@@ -125,7 +137,7 @@ func (b *kubernetesEventBundle) formatEvents(clusterName string, providerIDCache
 	// If hostname was not defined, the aggregator will then set the local hostname
 	output := metrics.Event{
 		Title:          fmt.Sprintf("Events from the %s", b.readableKey),
-		Priority:       metrics.EventPriorityNormal,
+		Priority:       b.priority,
 		Host:           hostname,
 		SourceTypeName: "kubernetes",
 		EventType:      kubernetesAPIServerCheckName,
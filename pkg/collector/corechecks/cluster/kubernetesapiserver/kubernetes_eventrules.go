@@ -0,0 +1,240 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build kubeapiserver
+// +build kubeapiserver
+
+package kubernetesapiserver
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/restmapper"
+
+	"github.com/DataDog/datadog-agent/pkg/aggregator"
+	"github.com/DataDog/datadog-agent/pkg/metrics"
+)
+
+const (
+	// eventRuleActionDrop doesn't forward the matched events as Datadog events
+	eventRuleActionDrop = "drop"
+	// eventRuleActionEvent forwards the matched events as Datadog events
+	eventRuleActionEvent = "event"
+
+	kubeEventsMetricName = "kubernetes.events"
+
+	noEventRule = -1
+)
+
+// EventRule routes the Kubernetes events it matches. An empty matcher matches
+// every event, the rules are evaluated in order and the first match applies.
+type EventRule struct {
+	Reasons    []string          `yaml:"reasons"`
+	Kinds      []string          `yaml:"kinds"`
+	Namespaces []string          `yaml:"namespaces"`
+	Message    string            `yaml:"message"`
+	Labels     map[string]string `yaml:"involved_object_labels"`
+
+	Action    string `yaml:"action"`
+	Priority  string `yaml:"priority"`
+	AlertType string `yaml:"alert_type"`
+	Metric    bool   `yaml:"metric"`
+
+	message   *regexp.Regexp
+	priority  metrics.EventPriority
+	alertType metrics.EventAlertType
+}
+
+// compile validates the rule and prepares it to match events
+func (r *EventRule) compile() error {
+	switch r.Action {
+	case "":
+		r.Action = eventRuleActionEvent
+	case eventRuleActionDrop, eventRuleActionEvent:
+	default:
+		return fmt.Errorf("unknown action %q, expected %s or %s", r.Action, eventRuleActionDrop, eventRuleActionEvent)
+	}
+
+	if r.Message != "" {
+		message, err := regexp.Compile(r.Message)
+		if err != nil {
+			return fmt.Errorf("invalid message pattern: %w", err)
+		}
+		r.message = message
+	}
+
+	if r.Priority != "" {
+		priority, err := metrics.GetEventPriorityFromString(r.Priority)
+		if err != nil {
+			return err
+		}
+		r.priority = priority
+	}
+
+	if r.AlertType != "" {
+		alertType, err := metrics.GetAlertTypeFromString(r.AlertType)
+		if err != nil {
+			return err
+		}
+		r.alertType = alertType
+	}
+
+	return nil
+}
+
+// match returns whether the rule matches the event, the labels of the
+// involved object are only retrieved when the rule matches on labels.
+func (r *EventRule) match(event *v1.Event, objectLabels func() map[string]string) bool {
+	if !matchAny(r.Reasons, event.Reason) ||
+		!matchAny(r.Kinds, event.InvolvedObject.Kind) ||
+		!matchAny(r.Namespaces, event.InvolvedObject.Namespace) {
+		return false
+	}
+
+	if r.message != nil && !r.message.MatchString(event.Message) {
+		return false
+	}
+
+	if len(r.Labels) == 0 {
+		return true
+	}
+
+	labels := objectLabels()
+	for k, v := range r.Labels {
+		if value, found := labels[k]; !found || value != v {
+			return false
+		}
+	}
+
+	return true
+}
+
+// matchAny returns whether the value is part of the candidates, an empty list
+// of candidates matches any value
+func matchAny(candidates []string, value string) bool {
+	if len(candidates) == 0 {
+		return true
+	}
+
+	for _, c := range candidates {
+		if c == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// compileEventRules validates the event rules of the configuration
+func compileEventRules(rules []EventRule) error {
+	for i := range rules {
+		if err := rules[i].compile(); err != nil {
+			return fmt.Errorf("invalid event rule %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// matchEventRule returns the index of the first rule matching the event,
+// noEventRule if none matches
+func (k *KubeASCheck) matchEventRule(event *v1.Event) int {
+	var labels map[string]string
+	var labelsFetched bool
+	objectLabels := func() map[string]string {
+		if !labelsFetched {
+			labels = k.involvedObjectLabels(event.InvolvedObject)
+			labelsFetched = true
+		}
+		return labels
+	}
+
+	for i := range k.instance.EventRules {
+		if k.instance.EventRules[i].match(event, objectLabels) {
+			return i
+		}
+	}
+
+	return noEventRule
+}
+
+// involvedObjectLabels returns the labels of the object involved in an event,
+// the labels are cached as many events target the same objects.
+func (k *KubeASCheck) involvedObjectLabels(ref v1.ObjectReference) map[string]string {
+	key := string(ref.UID)
+	if key == "" {
+		key = strings.Join([]string{ref.APIVersion, ref.Kind, ref.Namespace, ref.Name}, "/")
+	}
+
+	if labels, found := k.labelsCache.Get(key); found {
+		return labels.(map[string]string)
+	}
+
+	labels, err := k.getObjectLabels(ref)
+	if err != nil {
+		k.Warnf("Cannot get the labels of %s %s/%s, event rules matching on labels won't apply: %v", ref.Kind, ref.Namespace, ref.Name, err) //nolint:errcheck
+	}
+
+	// Failures are cached as well to avoid querying the API server for every event
+	k.labelsCache.SetDefault(key, labels)
+
+	return labels
+}
+
+// fetchObjectLabels gets the labels of an object from the API server
+func (k *KubeASCheck) fetchObjectLabels(ref v1.ObjectReference) (map[string]string, error) {
+	if k.ac == nil {
+		return nil, fmt.Errorf("the API server client is not initialized")
+	}
+
+	if k.restMapper == nil {
+		k.restMapper = restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(k.ac.DiscoveryCl))
+	}
+
+	gv, err := schema.ParseGroupVersion(ref.APIVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	mapping, err := k.restMapper.RESTMapping(gv.WithKind(ref.Kind).GroupKind(), gv.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	obj, err := k.ac.DynamicCl.Resource(mapping.Resource).Namespace(ref.Namespace).Get(context.TODO(), ref.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	return obj.GetLabels(), nil
+}
+
+// submitEventMetric counts the event in the kubernetes.events metric
+func submitEventMetric(sender aggregator.Sender, event *v1.Event) {
+	tags := []string{
+		fmt.Sprintf("reason:%s", event.Reason),
+		fmt.Sprintf("kubernetes_kind:%s", event.InvolvedObject.Kind),
+		fmt.Sprintf("event_type:%s", event.Type),
+		fmt.Sprintf("source_component:%s", event.Source.Component),
+	}
+
+	if event.InvolvedObject.Namespace != "" {
+		tags = append(tags, fmt.Sprintf("kube_namespace:%s", event.InvolvedObject.Namespace))
+	}
+
+	// the count of an event is the number of occurrences it aggregates
+	count := event.Count
+	if count < 1 {
+		count = 1
+	}
+
+	sender.Count(kubeEventsMetricName, float64(count), "", tags)
+}
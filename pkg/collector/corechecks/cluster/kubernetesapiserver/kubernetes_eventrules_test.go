@@ -0,0 +1,207 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+//go:build kubeapiserver
+// +build kubeapiserver
+
+package kubernetesapiserver
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+
+	"github.com/DataDog/datadog-agent/pkg/aggregator/mocksender"
+	core "github.com/DataDog/datadog-agent/pkg/collector/corechecks"
+	"github.com/DataDog/datadog-agent/pkg/metrics"
+)
+
+func TestCompileEventRules(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    EventRule
+		wantErr bool
+	}{
+		{
+			name: "default action",
+			rule: EventRule{Reasons: []string{"BackOff"}},
+		},
+		{
+			name: "valid overrides",
+			rule: EventRule{Action: "event", Priority: "low", AlertType: "error", Message: "^Back-off"},
+		},
+		{
+			name:    "unknown action",
+			rule:    EventRule{Action: "forward"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid message pattern",
+			rule:    EventRule{Message: "(Back-off"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid priority",
+			rule:    EventRule{Priority: "urgent"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid alert type",
+			rule:    EventRule{AlertType: "critical"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rules := []EventRule{tt.rule}
+			err := compileEventRules(rules)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.NotEmpty(t, rules[0].Action)
+		})
+	}
+}
+
+func TestEventRuleMatch(t *testing.T) {
+	event := createEvent(4, "default", "dca-789976f5d7-2ljx6", "Pod", "e6417a7f-f566-11e7-9749-0e4863e1cbf4", "kubelet", "machine-blue", "BackOff", "Back-off restarting failed container", "Warning", 709662600)
+	labels := func() map[string]string { return map[string]string{"app": "dca", "team": "containers"} }
+
+	tests := []struct {
+		name  string
+		rule  EventRule
+		match bool
+	}{
+		{
+			name:  "empty rule",
+			rule:  EventRule{},
+			match: true,
+		},
+		{
+			name:  "reason, kind and namespace",
+			rule:  EventRule{Reasons: []string{"FailedScheduling", "BackOff"}, Kinds: []string{"Pod"}, Namespaces: []string{"default"}},
+			match: true,
+		},
+		{
+			name:  "other reason",
+			rule:  EventRule{Reasons: []string{"FailedScheduling"}},
+			match: false,
+		},
+		{
+			name:  "other kind",
+			rule:  EventRule{Kinds: []string{"Node"}},
+			match: false,
+		},
+		{
+			name:  "other namespace",
+			rule:  EventRule{Namespaces: []string{"kube-system"}},
+			match: false,
+		},
+		{
+			name:  "message",
+			rule:  EventRule{Message: "^Back-off .* container$"},
+			match: true,
+		},
+		{
+			name:  "other message",
+			rule:  EventRule{Message: "image"},
+			match: false,
+		},
+		{
+			name:  "labels",
+			rule:  EventRule{Labels: map[string]string{"app": "dca"}},
+			match: true,
+		},
+		{
+			name:  "other label value",
+			rule:  EventRule{Labels: map[string]string{"app": "nginx"}},
+			match: false,
+		},
+		{
+			name:  "missing label",
+			rule:  EventRule{Labels: map[string]string{"tier": "web"}},
+			match: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.NoError(t, tt.rule.compile())
+			assert.Equal(t, tt.match, tt.rule.match(event, labels))
+		})
+	}
+}
+
+func TestProcessEventsWithRules(t *testing.T) {
+	backOff := createEvent(4, "default", "dca-789976f5d7-2ljx6", "Pod", "e6417a7f-f566-11e7-9749-0e4863e1cbf4", "kubelet", "machine-blue", "BackOff", "Back-off restarting failed container", "Warning", 709662600)
+	started := createEvent(3, "default", "dca-789976f5d7-2ljx6", "Pod", "e6417a7f-f566-11e7-9749-0e4863e1cbf4", "kubelet", "machine-blue", "Started", "Started container", "Normal", 709662600)
+	failedScheduling := createEvent(1, "batch", "job-7g5mx", "Pod", "0a1c56de-2b1e-4a43-9b68-a34f8b2a1c7d", "default-scheduler", "", "FailedScheduling", "0/3 nodes are available", "Warning", 709662600)
+
+	kubeASCheck := NewKubeASCheck(core.NewCheckBase(kubernetesAPIServerCheckName), &KubeASConfig{
+		EventRules: []EventRule{
+			{
+				Reasons:   []string{"BackOff"},
+				Labels:    map[string]string{"app": "dca"},
+				Priority:  "normal",
+				AlertType: "error",
+				Metric:    true,
+			},
+			{
+				Reasons: []string{"FailedScheduling"},
+				Action:  "drop",
+				Metric:  true,
+			},
+			{
+				Kinds:    []string{"Pod"},
+				Priority: "low",
+			},
+		},
+	})
+	require.NoError(t, compileEventRules(kubeASCheck.instance.EventRules))
+
+	var labelsCalls int
+	kubeASCheck.getObjectLabels = func(ref v1.ObjectReference) (map[string]string, error) {
+		labelsCalls++
+		if ref.Name == "dca-789976f5d7-2ljx6" {
+			return map[string]string{"app": "dca"}, nil
+		}
+		return nil, errors.New("not found")
+	}
+
+	mocked := mocksender.NewMockSender(kubeASCheck.ID())
+	mocked.On("Event", mock.AnythingOfType("metrics.Event"))
+	mocked.On("Count", kubeEventsMetricName, mock.AnythingOfType("float64"), "", mock.Anything)
+
+	kubeASCheck.processEvents(mocked, []*v1.Event{backOff, started, failedScheduling})
+
+	// The BackOff and Started events match different rules, they're not bundled together
+	mocked.AssertNumberOfCalls(t, "Event", 2)
+	for _, call := range mocked.Calls {
+		if call.Method != "Event" {
+			continue
+		}
+		ev := call.Arguments.Get(0).(metrics.Event)
+		if ev.AlertType == metrics.EventAlertTypeError {
+			assert.Contains(t, ev.Text, "4 **BackOff**")
+			assert.Equal(t, metrics.EventPriorityNormal, ev.Priority)
+		} else {
+			assert.Contains(t, ev.Text, "3 **Started**")
+			assert.Equal(t, metrics.EventPriorityLow, ev.Priority)
+			assert.Equal(t, metrics.EventAlertTypeInfo, ev.AlertType)
+		}
+	}
+
+	// The dropped FailedScheduling event is still counted
+	mocked.AssertNumberOfCalls(t, "Count", 2)
+	mocked.AssertCalled(t, "Count", kubeEventsMetricName, 4.0, "", []string{"reason:BackOff", "kubernetes_kind:Pod", "event_type:Warning", "source_component:kubelet", "kube_namespace:default"})
+	mocked.AssertCalled(t, "Count", kubeEventsMetricName, 1.0, "", []string{"reason:FailedScheduling", "kubernetes_kind:Pod", "event_type:Warning", "source_component:default-scheduler", "kube_namespace:batch"})
+
+	// The labels are only fetched for rules matching on labels, and once per involved object
+	assert.Equal(t, 1, labelsCalls)
+}
@@ -31,8 +31,12 @@ func NewCollectorInventory() *CollectorInventory {
 			k8sCollectors.NewCronJobCollector(),
 			k8sCollectors.NewDaemonSetCollector(),
 			k8sCollectors.NewDeploymentCollector(),
+			k8sCollectors.NewHorizontalPodAutoscalerCollector(),
 			k8sCollectors.NewIngressCollector(),
 			k8sCollectors.NewJobCollector(),
+			k8sCollectors.NewLimitRangeCollector(),
+			k8sCollectors.NewNamespaceCollector(),
+			k8sCollectors.NewNetworkPolicyCollector(),
 			k8sCollectors.NewNodeCollector(),
 			k8sCollectors.NewPersistentVolumeCollector(),
 			k8sCollectors.NewPersistentVolumeClaimCollector(),
@@ -42,19 +46,28 @@ func NewCollectorInventory() *CollectorInventory {
 			k8sCollectors.NewServiceCollector(),
 			k8sCollectors.NewServiceAccountCollector(),
 			k8sCollectors.NewStatefulSetCollector(),
+			k8sCollectors.NewStorageClassCollector(),
 			k8sCollectors.NewUnassignedPodCollector(),
 		},
 	}
 }
 
-// CollectorByName gets a collector given its name. It returns an error if the
-// name is not known.
+// CollectorByName gets a collector given its name. Names formatted as
+// <group>/<version>/<resource> designate custom resource collectors. It returns
+// an error if the name is not known.
 func (ci *CollectorInventory) CollectorByName(collectorName string) (collectors.Collector, error) {
 	for _, c := range ci.collectors {
 		if c.Metadata().Name == collectorName {
 			return c, nil
 		}
 	}
+	if k8sCollectors.IsCRCollectorName(collectorName) {
+		c, err := k8sCollectors.NewCRCollector(collectorName)
+		if err != nil {
+			return nil, err
+		}
+		return c, nil
+	}
 	return nil, fmt.Errorf("no collector found for name %s", collectorName)
 }
 
@@ -0,0 +1,150 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build kubeapiserver && orchestrator
+// +build kubeapiserver,orchestrator
+
+package k8s
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/cluster/orchestrator/collectors"
+	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/cluster/orchestrator/processors"
+	k8sProcessors "github.com/DataDog/datadog-agent/pkg/collector/corechecks/cluster/orchestrator/processors/k8s"
+	"github.com/DataDog/datadog-agent/pkg/config"
+	"github.com/DataDog/datadog-agent/pkg/orchestrator"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/tools/cache"
+)
+
+// CRCollector is a collector for the instances of a Kubernetes custom
+// resource. Custom resources are sent as manifests.
+type CRCollector struct {
+	discoveryCl discovery.DiscoveryInterface
+	gvr         schema.GroupVersionResource
+	informer    informers.GenericInformer
+	lister      cache.GenericLister
+	metadata    *collectors.CollectorMetadata
+	processor   *processors.Processor
+}
+
+// NewCRCollector creates a new collector for the Kubernetes custom resource
+// given as <group>/<version>/<resource>, e.g. datadoghq.com/v1alpha1/datadogmetrics.
+func NewCRCollector(name string) (*CRCollector, error) {
+	gvr, err := parseGroupVersionResource(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CRCollector{
+		gvr: gvr,
+		metadata: &collectors.CollectorMetadata{
+			IsStable: false,
+			Name:     name,
+			NodeType: orchestrator.K8sCR,
+		},
+		processor: processors.NewProcessor(new(k8sProcessors.CRHandlers)),
+	}, nil
+}
+
+// IsCRCollectorName returns whether a collector name designates a custom
+// resource collector.
+func IsCRCollectorName(name string) bool {
+	return strings.Count(name, "/") == 2
+}
+
+func parseGroupVersionResource(name string) (schema.GroupVersionResource, error) {
+	parts := strings.Split(name, "/")
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+		return schema.GroupVersionResource{}, fmt.Errorf("invalid custom resource %q, expected <group>/<version>/<resource>", name)
+	}
+
+	return schema.GroupVersionResource{
+		Group:    parts[0],
+		Version:  parts[1],
+		Resource: parts[2],
+	}, nil
+}
+
+// Informer returns the shared informer.
+func (c *CRCollector) Informer() cache.SharedInformer {
+	return c.informer.Informer()
+}
+
+// Init is used to initialize the collector.
+func (c *CRCollector) Init(rcfg *collectors.CollectorRunConfig) {
+	resyncPeriod := time.Duration(config.Datadog.GetInt64("kubernetes_informers_resync_period")) * time.Second
+	factory := dynamicinformer.NewDynamicSharedInformerFactory(rcfg.APIClient.DynamicCl, resyncPeriod)
+
+	c.discoveryCl = rcfg.APIClient.DiscoveryCl
+	c.informer = factory.ForResource(c.gvr)
+	c.lister = c.informer.Lister()
+}
+
+// IsAvailable returns whether the collector is available.
+// Returns false if the custom resource isn't served by the API server.
+func (c *CRCollector) IsAvailable() bool {
+	resources, err := c.discoveryCl.ServerResourcesForGroupVersion(c.gvr.GroupVersion().String())
+	if err != nil {
+		log.Infof("Couldn't query %s successfully: %s", c.gvr.GroupVersion(), err.Error())
+		return false
+	}
+
+	for _, r := range resources.APIResources {
+		if r.Name == c.gvr.Resource {
+			return true
+		}
+	}
+
+	log.Infof("Resource %s is not served by %s", c.gvr.Resource, c.gvr.GroupVersion())
+	return false
+}
+
+// Metadata is used to access information about the collector.
+func (c *CRCollector) Metadata() *collectors.CollectorMetadata {
+	return c.metadata
+}
+
+// Run triggers the collection process.
+func (c *CRCollector) Run(rcfg *collectors.CollectorRunConfig) (*collectors.CollectorRunResult, error) {
+	list, err := c.lister.List(labels.Everything())
+	if err != nil {
+		return nil, collectors.NewListingError(err)
+	}
+
+	ctx := &processors.ProcessorContext{
+		APIClient:  rcfg.APIClient,
+		Cfg:        rcfg.Config,
+		ClusterID:  rcfg.ClusterID,
+		MsgGroupID: rcfg.MsgGroupRef.Inc(),
+		NodeType:   c.metadata.NodeType,
+	}
+
+	processResult, processed := c.processor.Process(ctx, list)
+
+	if processed == -1 {
+		return nil, collectors.ErrProcessingPanic
+	}
+
+	// Custom resources are already sent as manifests.
+	processResult.ManifestMessages = nil
+
+	result := &collectors.CollectorRunResult{
+		Result:             processResult,
+		ResourcesListed:    len(list),
+		ResourcesProcessed: processed,
+	}
+
+	return result, nil
+}
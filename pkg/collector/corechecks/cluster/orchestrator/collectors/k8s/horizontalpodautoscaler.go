@@ -0,0 +1,124 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build kubeapiserver && orchestrator
+// +build kubeapiserver,orchestrator
+
+package k8s
+
+import (
+	"context"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/cluster/orchestrator/collectors"
+	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/cluster/orchestrator/processors"
+	k8sProcessors "github.com/DataDog/datadog-agent/pkg/collector/corechecks/cluster/orchestrator/processors/k8s"
+	"github.com/DataDog/datadog-agent/pkg/orchestrator"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+	"github.com/DataDog/datadog-agent/pkg/util/retry"
+
+	v2 "k8s.io/api/autoscaling/v2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	v2Informers "k8s.io/client-go/informers/autoscaling/v2"
+	v2Listers "k8s.io/client-go/listers/autoscaling/v2"
+	"k8s.io/client-go/tools/cache"
+)
+
+// HorizontalPodAutoscalerCollector is a collector for Kubernetes
+// HorizontalPodAutoscalers.
+type HorizontalPodAutoscalerCollector struct {
+	informer    v2Informers.HorizontalPodAutoscalerInformer
+	lister      v2Listers.HorizontalPodAutoscalerLister
+	metadata    *collectors.CollectorMetadata
+	processor   *processors.Processor
+	retryLister func(ctx context.Context, opts metav1.ListOptions) (*v2.HorizontalPodAutoscalerList, error)
+}
+
+// NewHorizontalPodAutoscalerCollector creates a new collector for the
+// Kubernetes HorizontalPodAutoscaler resource.
+func NewHorizontalPodAutoscalerCollector() *HorizontalPodAutoscalerCollector {
+	return &HorizontalPodAutoscalerCollector{
+		metadata: &collectors.CollectorMetadata{
+			IsStable: false,
+			Name:     "horizontalpodautoscalers",
+			NodeType: orchestrator.K8sHorizontalPodAutoscaler,
+		},
+		processor: processors.NewProcessor(new(k8sProcessors.HorizontalPodAutoscalerHandlers)),
+	}
+}
+
+// Informer returns the shared informer.
+func (c *HorizontalPodAutoscalerCollector) Informer() cache.SharedInformer {
+	return c.informer.Informer()
+}
+
+// Init is used to initialize the collector.
+func (c *HorizontalPodAutoscalerCollector) Init(rcfg *collectors.CollectorRunConfig) {
+	c.informer = rcfg.APIClient.InformerFactory.Autoscaling().V2().HorizontalPodAutoscalers()
+	c.lister = c.informer.Lister()
+	c.retryLister = rcfg.APIClient.Cl.AutoscalingV2().HorizontalPodAutoscalers("").List
+}
+
+// IsAvailable returns whether the collector is available.
+// Returns false if the autoscaling/v2 API version is not available (kubernetes < 1.23).
+func (c *HorizontalPodAutoscalerCollector) IsAvailable() bool {
+	var retrier retry.Retrier
+	if err := retrier.SetupRetrier(&retry.Config{
+		Name:          "AutoscalingV2Discovery",
+		AttemptMethod: c.list,
+		Strategy:      retry.RetryCount,
+		RetryCount:    3,               // try 3 times
+		RetryDelay:    1 * time.Second, // with 1 sec interval
+	}); err != nil {
+		log.Errorf("Couldn't setup api retrier: %v", err)
+		return false
+	}
+
+	return try(&retrier, "autoscaling/v2") == nil
+}
+
+// Metadata is used to access information about the collector.
+func (c *HorizontalPodAutoscalerCollector) Metadata() *collectors.CollectorMetadata {
+	return c.metadata
+}
+
+// Run triggers the collection process.
+func (c *HorizontalPodAutoscalerCollector) Run(rcfg *collectors.CollectorRunConfig) (*collectors.CollectorRunResult, error) {
+	list, err := c.lister.List(labels.Everything())
+	if err != nil {
+		return nil, collectors.NewListingError(err)
+	}
+
+	ctx := &processors.ProcessorContext{
+		APIClient:  rcfg.APIClient,
+		Cfg:        rcfg.Config,
+		ClusterID:  rcfg.ClusterID,
+		MsgGroupID: rcfg.MsgGroupRef.Inc(),
+		NodeType:   c.metadata.NodeType,
+	}
+
+	processResult, processed := c.processor.Process(ctx, list)
+
+	if processed == -1 {
+		return nil, collectors.ErrProcessingPanic
+	}
+
+	result := &collectors.CollectorRunResult{
+		Result:             processResult,
+		ResourcesListed:    len(list),
+		ResourcesProcessed: processed,
+	}
+
+	return result, nil
+}
+
+func (c *HorizontalPodAutoscalerCollector) list() error {
+	_, err := c.retryLister(context.TODO(), metav1.ListOptions{})
+	return err
+}
+
+// try triggers the retrier until the API group version given is successfully
+// queried or the retries are exhausted.
@@ -76,7 +76,7 @@ func (c *IngressCollector) IsAvailable() bool {
 		return false
 	}
 
-	return try(&retrier) == nil
+	return try(&retrier, "networking.k8s.io/v1") == nil
 }
 
 // Metadata is used to access information about the collector.
@@ -119,16 +119,18 @@ func (c *IngressCollector) list() error {
 	return err
 }
 
-func try(r *retry.Retrier) error {
+// try triggers the retrier until the API group version given is successfully
+// queried or the retries are exhausted.
+func try(r *retry.Retrier, groupVersion string) error {
 	for {
 		_ = r.TriggerRetry()
 		switch r.RetryStatus() {
 		case retry.OK:
-			log.Debug("Queried networking.k8s.io/v1 successfully")
+			log.Debugf("Queried %s successfully", groupVersion)
 			return nil
 		case retry.PermaFail:
 			err := r.LastError()
-			log.Infof("Couldn't query networking.k8s.io/v1 successfully: %s", err.Error())
+			log.Infof("Couldn't query %s successfully: %s", groupVersion, err.Error())
 			return err
 		}
 	}
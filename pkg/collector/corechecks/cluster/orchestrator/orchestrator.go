@@ -47,6 +47,8 @@ type OrchestratorInstance struct {
 	// collectors:
 	//   - nodes
 	//   - services
+	// Custom resources are collected with <group>/<version>/<resource>
+	// collectors, e.g. datadoghq.com/v1alpha1/datadogmetrics.
 	Collectors              []string `yaml:"collectors"`
 	ExtraSyncTimeoutSeconds int      `yaml:"extra_sync_timeout_seconds"`
 }
@@ -0,0 +1,78 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build orchestrator
+// +build orchestrator
+
+package k8s
+
+import (
+	"testing"
+
+	model "github.com/DataDog/agent-payload/v5/process"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/cluster/orchestrator/processors"
+	"github.com/DataDog/datadog-agent/pkg/orchestrator"
+	"github.com/DataDog/datadog-agent/pkg/orchestrator/config"
+	"github.com/DataDog/datadog-agent/pkg/orchestrator/redact"
+)
+
+func TestCRProcess(t *testing.T) {
+	cr := &unstructured.Unstructured{}
+	cr.SetAPIVersion("datadoghq.com/v1alpha1")
+	cr.SetKind("DatadogMetric")
+	cr.SetName("requests")
+	cr.SetNamespace("default")
+	cr.SetUID("0ff96226-578d-4679-b3c8-72e8a485c0ef")
+	cr.SetResourceVersion("1234")
+	cr.SetAnnotations(map[string]string{
+		"kubectl.kubernetes.io/last-applied-configuration": `{"spec":{"query":"secret"}}`,
+		"team": "web",
+	})
+	require.NoError(t, unstructured.SetNestedSlice(cr.Object, []interface{}{
+		map[string]interface{}{
+			"name": "web",
+			"env": []interface{}{
+				map[string]interface{}{"name": "password", "value": "hunter2"},
+			},
+		},
+	}, "spec", "template", "spec", "containers"))
+
+	ctx := &processors.ProcessorContext{
+		Cfg: &config.OrchestratorConfig{
+			KubeClusterName:    "cluster",
+			MaxPerMessage:      100,
+			ExtraTags:          []string{"env:test"},
+			IsScrubbingEnabled: true,
+			Scrubber:           redact.NewDefaultDataScrubber(),
+		},
+		ClusterID:  "cluster-id",
+		MsgGroupID: 1,
+		NodeType:   orchestrator.K8sCR,
+	}
+
+	processResult, processed := processors.NewProcessor(new(CRHandlers)).Process(ctx, []runtime.Object{cr})
+	require.Equal(t, 1, processed)
+	require.Len(t, processResult.MetadataMessages, 1)
+
+	message, ok := processResult.MetadataMessages[0].(*model.CollectorManifestCR)
+	require.True(t, ok)
+	assert.Equal(t, []string{"env:test"}, message.Tags)
+	assert.Equal(t, "cluster", message.Manifest.ClusterName)
+	assert.Equal(t, "cluster-id", message.Manifest.ClusterId)
+	require.Len(t, message.Manifest.Manifests, 1)
+
+	manifest := message.Manifest.Manifests[0]
+	assert.Equal(t, int32(orchestrator.K8sCR), manifest.Type)
+	assert.Equal(t, "0ff96226-578d-4679-b3c8-72e8a485c0ef", manifest.Uid)
+	assert.Equal(t, "1234", manifest.ResourceVersion)
+	assert.Contains(t, string(manifest.Content), `"team":"web"`)
+	assert.NotContains(t, string(manifest.Content), "secret")
+	assert.NotContains(t, string(manifest.Content), "hunter2")
+}
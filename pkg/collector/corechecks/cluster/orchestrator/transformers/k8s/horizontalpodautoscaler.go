@@ -0,0 +1,234 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build orchestrator
+// +build orchestrator
+
+package k8s
+
+import (
+	model "github.com/DataDog/agent-payload/v5/process"
+
+	v2 "k8s.io/api/autoscaling/v2"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// ExtractHorizontalPodAutoscaler returns the protobuf model corresponding to a
+// Kubernetes HorizontalPodAutoscaler resource.
+func ExtractHorizontalPodAutoscaler(hpa *v2.HorizontalPodAutoscaler) *model.HorizontalPodAutoscaler {
+	message := &model.HorizontalPodAutoscaler{
+		Metadata: extractMetadata(&hpa.ObjectMeta),
+		Spec: &model.HorizontalPodAutoscalerSpec{
+			Target: &model.HorizontalPodAutoscalerTarget{
+				Kind: hpa.Spec.ScaleTargetRef.Kind,
+				Name: hpa.Spec.ScaleTargetRef.Name,
+			},
+			MaxReplicas: hpa.Spec.MaxReplicas,
+		},
+		Status: &model.HorizontalPodAutoscalerStatus{
+			CurrentReplicas: hpa.Status.CurrentReplicas,
+			DesiredReplicas: hpa.Status.DesiredReplicas,
+		},
+	}
+
+	if hpa.Spec.MinReplicas != nil {
+		message.Spec.MinReplicas = *hpa.Spec.MinReplicas
+	}
+
+	for _, metric := range hpa.Spec.Metrics {
+		message.Spec.Metrics = append(message.Spec.Metrics, extractHPAMetricSpec(metric))
+	}
+
+	if hpa.Spec.Behavior != nil {
+		message.Spec.Behavior = &model.HorizontalPodAutoscalerBehavior{
+			ScaleUp:   extractHPAScalingRules(hpa.Spec.Behavior.ScaleUp),
+			ScaleDown: extractHPAScalingRules(hpa.Spec.Behavior.ScaleDown),
+		}
+	}
+
+	if hpa.Status.ObservedGeneration != nil {
+		message.Status.ObservedGeneration = *hpa.Status.ObservedGeneration
+	}
+	if hpa.Status.LastScaleTime != nil && !hpa.Status.LastScaleTime.IsZero() {
+		message.Status.LastScaleTime = hpa.Status.LastScaleTime.Unix()
+	}
+
+	for _, metric := range hpa.Status.CurrentMetrics {
+		message.Status.CurrentMetrics = append(message.Status.CurrentMetrics, extractHPAMetricStatus(metric))
+	}
+
+	for _, condition := range hpa.Status.Conditions {
+		c := &model.HorizontalPodAutoscalerCondition{
+			ConditionType:   string(condition.Type),
+			ConditionStatus: string(condition.Status),
+			Reason:          condition.Reason,
+			Message:         condition.Message,
+		}
+		if !condition.LastTransitionTime.IsZero() {
+			c.LastTransitionTime = condition.LastTransitionTime.Unix()
+		}
+		message.Conditions = append(message.Conditions, c)
+	}
+
+	return message
+}
+
+func extractHPAMetricSpec(metric v2.MetricSpec) *model.HorizontalPodAutoscalerMetricSpec {
+	m := &model.HorizontalPodAutoscalerMetricSpec{
+		Type: string(metric.Type),
+	}
+
+	switch {
+	case metric.Object != nil:
+		m.Object = &model.ObjectMetricSource{
+			DescribedObject: &model.ObjectReference{
+				Kind:       metric.Object.DescribedObject.Kind,
+				Name:       metric.Object.DescribedObject.Name,
+				ApiVersion: metric.Object.DescribedObject.APIVersion,
+			},
+			Target: extractHPAMetricTarget(metric.Object.Target),
+			Metric: extractHPAMetricIdentifier(metric.Object.Metric),
+		}
+	case metric.Pods != nil:
+		m.Pods = &model.PodsMetricSource{
+			Metric: extractHPAMetricIdentifier(metric.Pods.Metric),
+			Target: extractHPAMetricTarget(metric.Pods.Target),
+		}
+	case metric.Resource != nil:
+		m.Resource = &model.ResourceMetricSource{
+			ResourceName: metric.Resource.Name.String(),
+			Target:       extractHPAMetricTarget(metric.Resource.Target),
+		}
+	case metric.ContainerResource != nil:
+		m.ContainerResource = &model.ContainerResourceMetricSource{
+			ResourceName: metric.ContainerResource.Name.String(),
+			Target:       extractHPAMetricTarget(metric.ContainerResource.Target),
+			Container:    metric.ContainerResource.Container,
+		}
+	case metric.External != nil:
+		m.External = &model.ExternalMetricSource{
+			Metric: extractHPAMetricIdentifier(metric.External.Metric),
+			Target: extractHPAMetricTarget(metric.External.Target),
+		}
+	}
+
+	return m
+}
+
+func extractHPAMetricStatus(metric v2.MetricStatus) *model.HorizontalPodAutoscalerMetricStatus {
+	m := &model.HorizontalPodAutoscalerMetricStatus{
+		Type: string(metric.Type),
+	}
+
+	switch {
+	case metric.Object != nil:
+		m.Object = &model.ObjectMetricStatus{
+			DescribedObject: &model.ObjectReference{
+				Kind:       metric.Object.DescribedObject.Kind,
+				Name:       metric.Object.DescribedObject.Name,
+				ApiVersion: metric.Object.DescribedObject.APIVersion,
+			},
+			Current: extractHPAMetricValue(metric.Object.Current),
+			Metric:  extractHPAMetricIdentifier(metric.Object.Metric),
+		}
+	case metric.Pods != nil:
+		m.Pods = &model.PodsMetricStatus{
+			Metric:  extractHPAMetricIdentifier(metric.Pods.Metric),
+			Current: extractHPAMetricValue(metric.Pods.Current),
+		}
+	case metric.Resource != nil:
+		m.Resource = &model.ResourceMetricStatus{
+			ResourceName: metric.Resource.Name.String(),
+			Current:      extractHPAMetricValue(metric.Resource.Current),
+		}
+	case metric.ContainerResource != nil:
+		m.ContainerResource = &model.ContainerResourceMetricStatus{
+			ResourceName: metric.ContainerResource.Name.String(),
+			Current:      extractHPAMetricValue(metric.ContainerResource.Current),
+			Container:    metric.ContainerResource.Container,
+		}
+	case metric.External != nil:
+		m.External = &model.ExternalMetricStatus{
+			Metric:  extractHPAMetricIdentifier(metric.External.Metric),
+			Current: extractHPAMetricValue(metric.External.Current),
+		}
+	}
+
+	return m
+}
+
+func extractHPAMetricIdentifier(metric v2.MetricIdentifier) *model.MetricIdentifier {
+	m := &model.MetricIdentifier{
+		Name: metric.Name,
+	}
+	if metric.Selector != nil {
+		m.LabelSelector = extractLabelSelector(metric.Selector)
+	}
+	return m
+}
+
+// extractHPAMetricTarget returns the target set on the metric, quantities are
+// expressed in milli-units to keep their precision.
+func extractHPAMetricTarget(target v2.MetricTarget) *model.MetricTarget {
+	t := &model.MetricTarget{
+		Type: string(target.Type),
+	}
+
+	switch target.Type {
+	case v2.UtilizationMetricType:
+		if target.AverageUtilization != nil {
+			t.Value = int64(*target.AverageUtilization)
+		}
+	case v2.AverageValueMetricType:
+		t.Value = milliValue(target.AverageValue)
+	case v2.ValueMetricType:
+		t.Value = milliValue(target.Value)
+	}
+
+	return t
+}
+
+// extractHPAMetricValue returns the current value of the metric, quantities
+// are expressed in milli-units to keep their precision.
+func extractHPAMetricValue(value v2.MetricValueStatus) int64 {
+	switch {
+	case value.AverageUtilization != nil:
+		return int64(*value.AverageUtilization)
+	case value.AverageValue != nil:
+		return milliValue(value.AverageValue)
+	default:
+		return milliValue(value.Value)
+	}
+}
+
+func extractHPAScalingRules(rules *v2.HPAScalingRules) *model.HPAScalingRules {
+	if rules == nil {
+		return nil
+	}
+
+	r := &model.HPAScalingRules{}
+	if rules.StabilizationWindowSeconds != nil {
+		r.StabilizationWindowSeconds = *rules.StabilizationWindowSeconds
+	}
+	if rules.SelectPolicy != nil {
+		r.SelectPolicy = string(*rules.SelectPolicy)
+	}
+	for _, policy := range rules.Policies {
+		r.Policies = append(r.Policies, &model.HPAScalingPolicy{
+			Type:          string(policy.Type),
+			Value:         policy.Value,
+			PeriodSeconds: policy.PeriodSeconds,
+		})
+	}
+
+	return r
+}
+
+func milliValue(q *resource.Quantity) int64 {
+	if q == nil {
+		return 0
+	}
+	return q.MilliValue()
+}
@@ -0,0 +1,258 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build orchestrator
+// +build orchestrator
+
+package k8s
+
+import (
+	"testing"
+	"time"
+
+	model "github.com/DataDog/agent-payload/v5/process"
+
+	"github.com/stretchr/testify/assert"
+	v2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestExtractHorizontalPodAutoscaler(t *testing.T) {
+	creationTime := metav1.NewTime(time.Date(2021, time.April, 16, 14, 30, 0, 0, time.UTC))
+	scaleTime := metav1.NewTime(time.Date(2021, time.April, 16, 15, 30, 0, 0, time.UTC))
+	maxPolicy := v2.MaxChangePolicySelect
+	averageValue := resource.MustParse("1500m")
+	externalValue := resource.MustParse("100")
+	currentValue := resource.MustParse("1200m")
+
+	tests := map[string]struct {
+		input    v2.HorizontalPodAutoscaler
+		expected model.HorizontalPodAutoscaler
+	}{
+		"resource and external metrics": {
+			input: v2.HorizontalPodAutoscaler{
+				ObjectMeta: metav1.ObjectMeta{
+					CreationTimestamp: creationTime,
+					Name:              "web",
+					Namespace:         "default",
+					ResourceVersion:   "1234",
+					UID:               types.UID("e42e5adc-0749-11e8-a2b8-000c29dea4f6"),
+				},
+				Spec: v2.HorizontalPodAutoscalerSpec{
+					ScaleTargetRef: v2.CrossVersionObjectReference{
+						Kind:       "Deployment",
+						Name:       "web",
+						APIVersion: "apps/v1",
+					},
+					MinReplicas: int32Ptr(2),
+					MaxReplicas: 10,
+					Metrics: []v2.MetricSpec{
+						{
+							Type: v2.ResourceMetricSourceType,
+							Resource: &v2.ResourceMetricSource{
+								Name: corev1.ResourceCPU,
+								Target: v2.MetricTarget{
+									Type:               v2.UtilizationMetricType,
+									AverageUtilization: int32Ptr(80),
+								},
+							},
+						},
+						{
+							Type: v2.PodsMetricSourceType,
+							Pods: &v2.PodsMetricSource{
+								Metric: v2.MetricIdentifier{
+									Name: "requests_per_second",
+								},
+								Target: v2.MetricTarget{
+									Type:         v2.AverageValueMetricType,
+									AverageValue: &averageValue,
+								},
+							},
+						},
+						{
+							Type: v2.ExternalMetricSourceType,
+							External: &v2.ExternalMetricSource{
+								Metric: v2.MetricIdentifier{
+									Name: "queue_depth",
+									Selector: &metav1.LabelSelector{
+										MatchLabels: map[string]string{"queue": "jobs"},
+									},
+								},
+								Target: v2.MetricTarget{
+									Type:  v2.ValueMetricType,
+									Value: &externalValue,
+								},
+							},
+						},
+					},
+					Behavior: &v2.HorizontalPodAutoscalerBehavior{
+						ScaleDown: &v2.HPAScalingRules{
+							StabilizationWindowSeconds: int32Ptr(300),
+							SelectPolicy:               &maxPolicy,
+							Policies: []v2.HPAScalingPolicy{
+								{
+									Type:          v2.PercentScalingPolicy,
+									Value:         10,
+									PeriodSeconds: 60,
+								},
+							},
+						},
+					},
+				},
+				Status: v2.HorizontalPodAutoscalerStatus{
+					ObservedGeneration: int64Ptr(3),
+					LastScaleTime:      &scaleTime,
+					CurrentReplicas:    3,
+					DesiredReplicas:    4,
+					CurrentMetrics: []v2.MetricStatus{
+						{
+							Type: v2.ResourceMetricSourceType,
+							Resource: &v2.ResourceMetricStatus{
+								Name: corev1.ResourceCPU,
+								Current: v2.MetricValueStatus{
+									AverageUtilization: int32Ptr(92),
+								},
+							},
+						},
+						{
+							Type: v2.PodsMetricSourceType,
+							Pods: &v2.PodsMetricStatus{
+								Metric: v2.MetricIdentifier{
+									Name: "requests_per_second",
+								},
+								Current: v2.MetricValueStatus{
+									AverageValue: &currentValue,
+								},
+							},
+						},
+					},
+					Conditions: []v2.HorizontalPodAutoscalerCondition{
+						{
+							Type:               v2.AbleToScale,
+							Status:             corev1.ConditionTrue,
+							LastTransitionTime: scaleTime,
+							Reason:             "ReadyForNewScale",
+							Message:            "recommended size matches current size",
+						},
+					},
+				},
+			},
+			expected: model.HorizontalPodAutoscaler{
+				Metadata: &model.Metadata{
+					CreationTimestamp: creationTime.Unix(),
+					Name:              "web",
+					Namespace:         "default",
+					ResourceVersion:   "1234",
+					Uid:               "e42e5adc-0749-11e8-a2b8-000c29dea4f6",
+				},
+				Spec: &model.HorizontalPodAutoscalerSpec{
+					Target: &model.HorizontalPodAutoscalerTarget{
+						Kind: "Deployment",
+						Name: "web",
+					},
+					MinReplicas: 2,
+					MaxReplicas: 10,
+					Metrics: []*model.HorizontalPodAutoscalerMetricSpec{
+						{
+							Type: "Resource",
+							Resource: &model.ResourceMetricSource{
+								ResourceName: "cpu",
+								Target: &model.MetricTarget{
+									Type:  "Utilization",
+									Value: 80,
+								},
+							},
+						},
+						{
+							Type: "Pods",
+							Pods: &model.PodsMetricSource{
+								Metric: &model.MetricIdentifier{
+									Name: "requests_per_second",
+								},
+								Target: &model.MetricTarget{
+									Type:  "AverageValue",
+									Value: 1500,
+								},
+							},
+						},
+						{
+							Type: "External",
+							External: &model.ExternalMetricSource{
+								Metric: &model.MetricIdentifier{
+									Name: "queue_depth",
+									LabelSelector: []*model.LabelSelectorRequirement{
+										{
+											Key:      "queue",
+											Operator: "In",
+											Values:   []string{"jobs"},
+										},
+									},
+								},
+								Target: &model.MetricTarget{
+									Type:  "Value",
+									Value: 100000,
+								},
+							},
+						},
+					},
+					Behavior: &model.HorizontalPodAutoscalerBehavior{
+						ScaleDown: &model.HPAScalingRules{
+							StabilizationWindowSeconds: 300,
+							SelectPolicy:               "Max",
+							Policies: []*model.HPAScalingPolicy{
+								{
+									Type:          "Percent",
+									Value:         10,
+									PeriodSeconds: 60,
+								},
+							},
+						},
+					},
+				},
+				Status: &model.HorizontalPodAutoscalerStatus{
+					ObservedGeneration: 3,
+					LastScaleTime:      scaleTime.Unix(),
+					CurrentReplicas:    3,
+					DesiredReplicas:    4,
+					CurrentMetrics: []*model.HorizontalPodAutoscalerMetricStatus{
+						{
+							Type: "Resource",
+							Resource: &model.ResourceMetricStatus{
+								ResourceName: "cpu",
+								Current:      92,
+							},
+						},
+						{
+							Type: "Pods",
+							Pods: &model.PodsMetricStatus{
+								Metric: &model.MetricIdentifier{
+									Name: "requests_per_second",
+								},
+								Current: 1200,
+							},
+						},
+					},
+				},
+				Conditions: []*model.HorizontalPodAutoscalerCondition{
+					{
+						ConditionType:      "AbleToScale",
+						ConditionStatus:    "True",
+						LastTransitionTime: scaleTime.Unix(),
+						Reason:             "ReadyForNewScale",
+						Message:            "recommended size matches current size",
+					},
+				},
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, &tc.expected, ExtractHorizontalPodAutoscaler(&tc.input))
+		})
+	}
+}
@@ -0,0 +1,62 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build orchestrator
+// +build orchestrator
+
+package k8s
+
+import (
+	model "github.com/DataDog/agent-payload/v5/process"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// ExtractLimitRange returns the protobuf model corresponding to a Kubernetes
+// LimitRange resource.
+func ExtractLimitRange(lr *corev1.LimitRange) *model.LimitRange {
+	message := &model.LimitRange{
+		Metadata: extractMetadata(&lr.ObjectMeta),
+		Spec:     &model.LimitRangeSpec{},
+	}
+
+	limitTypes := make(map[string]struct{})
+	for _, item := range lr.Spec.Limits {
+		message.Spec.Limits = append(message.Spec.Limits, &model.LimitRangeItem{
+			Type:                 string(item.Type),
+			Default:              extractResourceList(item.Default),
+			DefaultRequest:       extractResourceList(item.DefaultRequest),
+			Max:                  extractResourceList(item.Max),
+			Min:                  extractResourceList(item.Min),
+			MaxLimitRequestRatio: extractResourceList(item.MaxLimitRequestRatio),
+		})
+
+		if _, found := limitTypes[string(item.Type)]; !found {
+			limitTypes[string(item.Type)] = struct{}{}
+			message.LimitTypes = append(message.LimitTypes, string(item.Type))
+		}
+	}
+
+	return message
+}
+
+// extractResourceList converts a resource list to a map of quantities. CPU
+// quantities are expressed in millicores, other quantities in their base unit.
+func extractResourceList(rl corev1.ResourceList) map[string]int64 {
+	if len(rl) == 0 {
+		return nil
+	}
+
+	resources := make(map[string]int64, len(rl))
+	for name, quantity := range rl {
+		if name == corev1.ResourceCPU {
+			resources[name.String()] = quantity.MilliValue()
+		} else {
+			resources[name.String()] = quantity.Value()
+		}
+	}
+
+	return resources
+}
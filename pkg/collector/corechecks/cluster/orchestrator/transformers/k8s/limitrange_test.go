@@ -0,0 +1,122 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build orchestrator
+// +build orchestrator
+
+package k8s
+
+import (
+	"testing"
+	"time"
+
+	model "github.com/DataDog/agent-payload/v5/process"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestExtractLimitRange(t *testing.T) {
+	creationTime := metav1.NewTime(time.Date(2021, time.April, 16, 14, 30, 0, 0, time.UTC))
+
+	tests := map[string]struct {
+		input    corev1.LimitRange
+		expected model.LimitRange
+	}{
+		"container and pod limits": {
+			input: corev1.LimitRange{
+				ObjectMeta: metav1.ObjectMeta{
+					CreationTimestamp: creationTime,
+					Name:              "limit-range",
+					Namespace:         "default",
+					ResourceVersion:   "1234",
+					UID:               types.UID("e42e5adc-0749-11e8-a2b8-000c29dea4f6"),
+				},
+				Spec: corev1.LimitRangeSpec{
+					Limits: []corev1.LimitRangeItem{
+						{
+							Type: corev1.LimitTypeContainer,
+							Default: corev1.ResourceList{
+								corev1.ResourceCPU:    resource.MustParse("500m"),
+								corev1.ResourceMemory: resource.MustParse("256Mi"),
+							},
+							DefaultRequest: corev1.ResourceList{
+								corev1.ResourceCPU: resource.MustParse("250m"),
+							},
+							Max: corev1.ResourceList{
+								corev1.ResourceCPU: resource.MustParse("2"),
+							},
+							MaxLimitRequestRatio: corev1.ResourceList{
+								corev1.ResourceMemory: resource.MustParse("2"),
+							},
+						},
+						{
+							Type: corev1.LimitTypePod,
+							Min: corev1.ResourceList{
+								corev1.ResourceMemory: resource.MustParse("64Mi"),
+							},
+						},
+						{
+							Type: corev1.LimitTypeContainer,
+							Min: corev1.ResourceList{
+								corev1.ResourceCPU: resource.MustParse("100m"),
+							},
+						},
+					},
+				},
+			},
+			expected: model.LimitRange{
+				Metadata: &model.Metadata{
+					CreationTimestamp: creationTime.Unix(),
+					Name:              "limit-range",
+					Namespace:         "default",
+					ResourceVersion:   "1234",
+					Uid:               "e42e5adc-0749-11e8-a2b8-000c29dea4f6",
+				},
+				Spec: &model.LimitRangeSpec{
+					Limits: []*model.LimitRangeItem{
+						{
+							Type: "Container",
+							Default: map[string]int64{
+								"cpu":    500,
+								"memory": 268435456,
+							},
+							DefaultRequest: map[string]int64{
+								"cpu": 250,
+							},
+							Max: map[string]int64{
+								"cpu": 2000,
+							},
+							MaxLimitRequestRatio: map[string]int64{
+								"memory": 2,
+							},
+						},
+						{
+							Type: "Pod",
+							Min: map[string]int64{
+								"memory": 67108864,
+							},
+						},
+						{
+							Type: "Container",
+							Min: map[string]int64{
+								"cpu": 100,
+							},
+						},
+					},
+				},
+				LimitTypes: []string{"Container", "Pod"},
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, &tc.expected, ExtractLimitRange(&tc.input))
+		})
+	}
+}
@@ -0,0 +1,72 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build orchestrator
+// +build orchestrator
+
+package k8s
+
+import (
+	"strings"
+
+	model "github.com/DataDog/agent-payload/v5/process"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// ExtractNamespace returns the protobuf model corresponding to a Kubernetes
+// Namespace resource.
+func ExtractNamespace(ns *corev1.Namespace) *model.Namespace {
+	message := &model.Namespace{
+		Metadata:         extractMetadata(&ns.ObjectMeta),
+		Status:           string(ns.Status.Phase),
+		ConditionMessage: getNamespaceConditionMessage(ns),
+	}
+
+	for _, condition := range ns.Status.Conditions {
+		c := &model.NamespaceCondition{
+			Type:    string(condition.Type),
+			Status:  string(condition.Status),
+			Reason:  condition.Reason,
+			Message: condition.Message,
+		}
+		if !condition.LastTransitionTime.IsZero() {
+			c.LastTransitionTime = condition.LastTransitionTime.Unix()
+		}
+		message.Conditions = append(message.Conditions, c)
+	}
+
+	addAdditionalNamespaceTags(message)
+
+	return message
+}
+
+func addAdditionalNamespaceTags(nsModel *model.Namespace) {
+	additionalTags := []string{"namespace_status:" + strings.ToLower(nsModel.Status)}
+	nsModel.Tags = append(nsModel.Tags, additionalTags...)
+}
+
+// getNamespaceConditionMessage returns the message of the first namespace
+// condition reporting a deletion failure, ordered by the deletion steps.
+func getNamespaceConditionMessage(ns *corev1.Namespace) string {
+	conditions := make(map[corev1.NamespaceConditionType]corev1.NamespaceCondition, len(ns.Status.Conditions))
+	for _, condition := range ns.Status.Conditions {
+		conditions[condition.Type] = condition
+	}
+
+	for _, conditionType := range []corev1.NamespaceConditionType{
+		corev1.NamespaceFinalizersRemaining,
+		corev1.NamespaceContentRemaining,
+		corev1.NamespaceDeletionContentFailure,
+		corev1.NamespaceDeletionDiscoveryFailure,
+		corev1.NamespaceDeletionGVParsingFailure,
+	} {
+		if condition, found := conditions[conditionType]; found && condition.Status == corev1.ConditionTrue {
+			return condition.Message
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,124 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build orchestrator
+// +build orchestrator
+
+package k8s
+
+import (
+	"testing"
+	"time"
+
+	model "github.com/DataDog/agent-payload/v5/process"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestExtractNamespace(t *testing.T) {
+	creationTime := metav1.NewTime(time.Date(2021, time.April, 16, 14, 30, 0, 0, time.UTC))
+	transitionTime := metav1.NewTime(time.Date(2021, time.April, 16, 15, 30, 0, 0, time.UTC))
+
+	tests := map[string]struct {
+		input    corev1.Namespace
+		expected model.Namespace
+	}{
+		"active": {
+			input: corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						"annotation": "my-annotation",
+					},
+					CreationTimestamp: creationTime,
+					Labels: map[string]string{
+						"app": "my-app",
+					},
+					Name:            "my-namespace",
+					ResourceVersion: "1234",
+					UID:             types.UID("e42e5adc-0749-11e8-a2b8-000c29dea4f6"),
+				},
+				Status: corev1.NamespaceStatus{
+					Phase: corev1.NamespaceActive,
+				},
+			},
+			expected: model.Namespace{
+				Metadata: &model.Metadata{
+					Annotations:       []string{"annotation:my-annotation"},
+					CreationTimestamp: creationTime.Unix(),
+					Labels:            []string{"app:my-app"},
+					Name:              "my-namespace",
+					ResourceVersion:   "1234",
+					Uid:               "e42e5adc-0749-11e8-a2b8-000c29dea4f6",
+				},
+				Status: "Active",
+				Tags:   []string{"namespace_status:active"},
+			},
+		},
+		"terminating": {
+			input: corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					CreationTimestamp: creationTime,
+					Name:              "my-namespace",
+					ResourceVersion:   "1234",
+					UID:               types.UID("e42e5adc-0749-11e8-a2b8-000c29dea4f6"),
+				},
+				Status: corev1.NamespaceStatus{
+					Phase: corev1.NamespaceTerminating,
+					Conditions: []corev1.NamespaceCondition{
+						{
+							Type:               corev1.NamespaceDeletionDiscoveryFailure,
+							Status:             corev1.ConditionFalse,
+							LastTransitionTime: transitionTime,
+							Reason:             "ResourcesDiscovered",
+							Message:            "All resources successfully discovered",
+						},
+						{
+							Type:               corev1.NamespaceFinalizersRemaining,
+							Status:             corev1.ConditionTrue,
+							LastTransitionTime: transitionTime,
+							Reason:             "SomeFinalizersRemain",
+							Message:            "Some content in the namespace has finalizers remaining",
+						},
+					},
+				},
+			},
+			expected: model.Namespace{
+				Metadata: &model.Metadata{
+					CreationTimestamp: creationTime.Unix(),
+					Name:              "my-namespace",
+					ResourceVersion:   "1234",
+					Uid:               "e42e5adc-0749-11e8-a2b8-000c29dea4f6",
+				},
+				Status:           "Terminating",
+				ConditionMessage: "Some content in the namespace has finalizers remaining",
+				Conditions: []*model.NamespaceCondition{
+					{
+						Type:               "NamespaceDeletionDiscoveryFailure",
+						Status:             "False",
+						LastTransitionTime: transitionTime.Unix(),
+						Reason:             "ResourcesDiscovered",
+						Message:            "All resources successfully discovered",
+					},
+					{
+						Type:               "NamespaceFinalizersRemaining",
+						Status:             "True",
+						LastTransitionTime: transitionTime.Unix(),
+						Reason:             "SomeFinalizersRemain",
+						Message:            "Some content in the namespace has finalizers remaining",
+					},
+				},
+				Tags: []string{"namespace_status:terminating"},
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, &tc.expected, ExtractNamespace(&tc.input))
+		})
+	}
+}
@@ -0,0 +1,96 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build orchestrator
+// +build orchestrator
+
+package k8s
+
+import (
+	model "github.com/DataDog/agent-payload/v5/process"
+
+	netv1 "k8s.io/api/networking/v1"
+)
+
+// ExtractNetworkPolicy returns the protobuf model corresponding to a Kubernetes
+// NetworkPolicy resource.
+func ExtractNetworkPolicy(np *netv1.NetworkPolicy) *model.NetworkPolicy {
+	message := &model.NetworkPolicy{
+		Metadata: extractMetadata(&np.ObjectMeta),
+		Spec: &model.NetworkPolicySpec{
+			Selectors: extractLabelSelector(&np.Spec.PodSelector),
+		},
+	}
+
+	for _, rule := range np.Spec.Ingress {
+		message.Spec.Ingress = append(message.Spec.Ingress, &model.NetworkPolicyIngressRule{
+			Ports: extractNetworkPolicyPorts(rule.Ports),
+			From:  extractNetworkPolicyPeers(rule.From),
+		})
+	}
+
+	for _, rule := range np.Spec.Egress {
+		message.Spec.Egress = append(message.Spec.Egress, &model.NetworkPolicyEgressRule{
+			Ports: extractNetworkPolicyPorts(rule.Ports),
+			To:    extractNetworkPolicyPeers(rule.To),
+		})
+	}
+
+	for _, policyType := range np.Spec.PolicyTypes {
+		message.Spec.PolicyTypes = append(message.Spec.PolicyTypes, string(policyType))
+	}
+
+	return message
+}
+
+func extractNetworkPolicyPorts(ports []netv1.NetworkPolicyPort) []*model.NetworkPolicyPort {
+	if len(ports) == 0 {
+		return nil
+	}
+
+	policyPorts := make([]*model.NetworkPolicyPort, 0, len(ports))
+	for _, p := range ports {
+		port := &model.NetworkPolicyPort{}
+		if p.Protocol != nil {
+			port.Protocol = string(*p.Protocol)
+		}
+		// Named ports can't be represented in the model, only numbered ports are kept.
+		if p.Port != nil {
+			port.Port = p.Port.IntVal
+		}
+		if p.EndPort != nil {
+			port.EndPort = *p.EndPort
+		}
+		policyPorts = append(policyPorts, port)
+	}
+
+	return policyPorts
+}
+
+func extractNetworkPolicyPeers(peers []netv1.NetworkPolicyPeer) []*model.NetworkPolicyPeer {
+	if len(peers) == 0 {
+		return nil
+	}
+
+	policyPeers := make([]*model.NetworkPolicyPeer, 0, len(peers))
+	for _, p := range peers {
+		peer := &model.NetworkPolicyPeer{}
+		if p.PodSelector != nil {
+			peer.PodSelector = extractLabelSelector(p.PodSelector)
+		}
+		if p.NamespaceSelector != nil {
+			peer.NamespaceSelector = extractLabelSelector(p.NamespaceSelector)
+		}
+		if p.IPBlock != nil {
+			peer.IpBlock = &model.NetworkPolicyIPBlock{
+				Cidr:   p.IPBlock.CIDR,
+				Except: p.IPBlock.Except,
+			}
+		}
+		policyPeers = append(policyPeers, peer)
+	}
+
+	return policyPeers
+}
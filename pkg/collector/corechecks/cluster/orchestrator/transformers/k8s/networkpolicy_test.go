@@ -0,0 +1,206 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build orchestrator
+// +build orchestrator
+
+package k8s
+
+import (
+	"testing"
+	"time"
+
+	model "github.com/DataDog/agent-payload/v5/process"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	netv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestExtractNetworkPolicy(t *testing.T) {
+	creationTime := metav1.NewTime(time.Date(2021, time.April, 16, 14, 30, 0, 0, time.UTC))
+	protocol := corev1.ProtocolTCP
+	port := intstr.FromInt(6379)
+	namedPort := intstr.FromString("http")
+
+	tests := map[string]struct {
+		input    netv1.NetworkPolicy
+		expected model.NetworkPolicy
+	}{
+		"ingress and egress": {
+			input: netv1.NetworkPolicy{
+				ObjectMeta: metav1.ObjectMeta{
+					CreationTimestamp: creationTime,
+					Labels: map[string]string{
+						"app": "my-app",
+					},
+					Name:            "test-network-policy",
+					Namespace:       "default",
+					ResourceVersion: "1234",
+					UID:             types.UID("e42e5adc-0749-11e8-a2b8-000c29dea4f6"),
+				},
+				Spec: netv1.NetworkPolicySpec{
+					PodSelector: metav1.LabelSelector{
+						MatchLabels: map[string]string{"role": "db"},
+					},
+					PolicyTypes: []netv1.PolicyType{netv1.PolicyTypeIngress, netv1.PolicyTypeEgress},
+					Ingress: []netv1.NetworkPolicyIngressRule{
+						{
+							From: []netv1.NetworkPolicyPeer{
+								{
+									IPBlock: &netv1.IPBlock{
+										CIDR:   "172.17.0.0/16",
+										Except: []string{"172.17.1.0/24"},
+									},
+								},
+								{
+									NamespaceSelector: &metav1.LabelSelector{
+										MatchLabels: map[string]string{"project": "myproject"},
+									},
+								},
+							},
+							Ports: []netv1.NetworkPolicyPort{
+								{
+									Protocol: &protocol,
+									Port:     &port,
+								},
+							},
+						},
+					},
+					Egress: []netv1.NetworkPolicyEgressRule{
+						{
+							To: []netv1.NetworkPolicyPeer{
+								{
+									PodSelector: &metav1.LabelSelector{
+										MatchExpressions: []metav1.LabelSelectorRequirement{
+											{
+												Key:      "role",
+												Operator: metav1.LabelSelectorOpIn,
+												Values:   []string{"api"},
+											},
+										},
+									},
+								},
+							},
+							Ports: []netv1.NetworkPolicyPort{
+								{
+									Protocol: &protocol,
+									Port:     &port,
+									EndPort:  int32Ptr(6380),
+								},
+								{
+									Port: &namedPort,
+								},
+							},
+						},
+					},
+				},
+			},
+			expected: model.NetworkPolicy{
+				Metadata: &model.Metadata{
+					CreationTimestamp: creationTime.Unix(),
+					Labels:            []string{"app:my-app"},
+					Name:              "test-network-policy",
+					Namespace:         "default",
+					ResourceVersion:   "1234",
+					Uid:               "e42e5adc-0749-11e8-a2b8-000c29dea4f6",
+				},
+				Spec: &model.NetworkPolicySpec{
+					Selectors: []*model.LabelSelectorRequirement{
+						{
+							Key:      "role",
+							Operator: "In",
+							Values:   []string{"db"},
+						},
+					},
+					PolicyTypes: []string{"Ingress", "Egress"},
+					Ingress: []*model.NetworkPolicyIngressRule{
+						{
+							From: []*model.NetworkPolicyPeer{
+								{
+									IpBlock: &model.NetworkPolicyIPBlock{
+										Cidr:   "172.17.0.0/16",
+										Except: []string{"172.17.1.0/24"},
+									},
+								},
+								{
+									NamespaceSelector: []*model.LabelSelectorRequirement{
+										{
+											Key:      "project",
+											Operator: "In",
+											Values:   []string{"myproject"},
+										},
+									},
+								},
+							},
+							Ports: []*model.NetworkPolicyPort{
+								{
+									Protocol: "TCP",
+									Port:     6379,
+								},
+							},
+						},
+					},
+					Egress: []*model.NetworkPolicyEgressRule{
+						{
+							To: []*model.NetworkPolicyPeer{
+								{
+									PodSelector: []*model.LabelSelectorRequirement{
+										{
+											Key:      "role",
+											Operator: "In",
+											Values:   []string{"api"},
+										},
+									},
+								},
+							},
+							Ports: []*model.NetworkPolicyPort{
+								{
+									Protocol: "TCP",
+									Port:     6379,
+									EndPort:  6380,
+								},
+								{},
+							},
+						},
+					},
+				},
+			},
+		},
+		"deny all": {
+			input: netv1.NetworkPolicy{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:            "default-deny",
+					Namespace:       "default",
+					ResourceVersion: "1234",
+					UID:             types.UID("e42e5adc-0749-11e8-a2b8-000c29dea4f6"),
+				},
+				Spec: netv1.NetworkPolicySpec{
+					PolicyTypes: []netv1.PolicyType{netv1.PolicyTypeIngress},
+				},
+			},
+			expected: model.NetworkPolicy{
+				Metadata: &model.Metadata{
+					Name:            "default-deny",
+					Namespace:       "default",
+					ResourceVersion: "1234",
+					Uid:             "e42e5adc-0749-11e8-a2b8-000c29dea4f6",
+				},
+				Spec: &model.NetworkPolicySpec{
+					Selectors:   []*model.LabelSelectorRequirement{},
+					PolicyTypes: []string{"Ingress"},
+				},
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, &tc.expected, ExtractNetworkPolicy(&tc.input))
+		})
+	}
+}
@@ -0,0 +1,50 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build orchestrator
+// +build orchestrator
+
+package k8s
+
+import (
+	model "github.com/DataDog/agent-payload/v5/process"
+
+	storagev1 "k8s.io/api/storage/v1"
+)
+
+// ExtractStorageClass returns the protobuf model corresponding to a Kubernetes
+// StorageClass resource.
+func ExtractStorageClass(sc *storagev1.StorageClass) *model.StorageClass {
+	message := &model.StorageClass{
+		Metadata:     extractMetadata(&sc.ObjectMeta),
+		Provisioner:  sc.Provisioner,
+		Parameters:   sc.Parameters,
+		MountOptions: sc.MountOptions,
+	}
+
+	if sc.ReclaimPolicy != nil {
+		message.ReclaimPolicy = string(*sc.ReclaimPolicy)
+	}
+	if sc.AllowVolumeExpansion != nil {
+		message.AllowVolumeExpansion = *sc.AllowVolumeExpansion
+	}
+	if sc.VolumeBindingMode != nil {
+		message.VolumeBindingMode = string(*sc.VolumeBindingMode)
+	}
+
+	if len(sc.AllowedTopologies) > 0 {
+		message.AllowedTopologies = &model.StorageClassTopologies{}
+		for _, term := range sc.AllowedTopologies {
+			for _, expression := range term.MatchLabelExpressions {
+				message.AllowedTopologies.LabelSelectors = append(message.AllowedTopologies.LabelSelectors, &model.TopologyLabelSelector{
+					Key:    expression.Key,
+					Values: expression.Values,
+				})
+			}
+		}
+	}
+
+	return message
+}
@@ -0,0 +1,109 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build orchestrator
+// +build orchestrator
+
+package k8s
+
+import (
+	"testing"
+	"time"
+
+	model "github.com/DataDog/agent-payload/v5/process"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestExtractStorageClass(t *testing.T) {
+	creationTime := metav1.NewTime(time.Date(2021, time.April, 16, 14, 30, 0, 0, time.UTC))
+	reclaimPolicy := corev1.PersistentVolumeReclaimRetain
+	bindingMode := storagev1.VolumeBindingWaitForFirstConsumer
+
+	tests := map[string]struct {
+		input    storagev1.StorageClass
+		expected model.StorageClass
+	}{
+		"full storage class": {
+			input: storagev1.StorageClass{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						"storageclass.kubernetes.io/is-default-class": "true",
+					},
+					CreationTimestamp: creationTime,
+					Name:              "standard",
+					ResourceVersion:   "1234",
+					UID:               types.UID("e42e5adc-0749-11e8-a2b8-000c29dea4f6"),
+				},
+				Provisioner:          "kubernetes.io/gce-pd",
+				Parameters:           map[string]string{"type": "pd-standard"},
+				ReclaimPolicy:        &reclaimPolicy,
+				MountOptions:         []string{"debug"},
+				AllowVolumeExpansion: boolPtr(true),
+				VolumeBindingMode:    &bindingMode,
+				AllowedTopologies: []corev1.TopologySelectorTerm{
+					{
+						MatchLabelExpressions: []corev1.TopologySelectorLabelRequirement{
+							{
+								Key:    "topology.kubernetes.io/zone",
+								Values: []string{"us-central-1a", "us-central-1b"},
+							},
+						},
+					},
+				},
+			},
+			expected: model.StorageClass{
+				Metadata: &model.Metadata{
+					Annotations:       []string{"storageclass.kubernetes.io/is-default-class:true"},
+					CreationTimestamp: creationTime.Unix(),
+					Name:              "standard",
+					ResourceVersion:   "1234",
+					Uid:               "e42e5adc-0749-11e8-a2b8-000c29dea4f6",
+				},
+				Provisioner:          "kubernetes.io/gce-pd",
+				Parameters:           map[string]string{"type": "pd-standard"},
+				ReclaimPolicy:        "Retain",
+				MountOptions:         []string{"debug"},
+				AllowVolumeExpansion: true,
+				VolumeBindingMode:    "WaitForFirstConsumer",
+				AllowedTopologies: &model.StorageClassTopologies{
+					LabelSelectors: []*model.TopologyLabelSelector{
+						{
+							Key:    "topology.kubernetes.io/zone",
+							Values: []string{"us-central-1a", "us-central-1b"},
+						},
+					},
+				},
+			},
+		},
+		"minimal storage class": {
+			input: storagev1.StorageClass{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:            "local",
+					ResourceVersion: "1234",
+					UID:             types.UID("e42e5adc-0749-11e8-a2b8-000c29dea4f6"),
+				},
+				Provisioner: "kubernetes.io/no-provisioner",
+			},
+			expected: model.StorageClass{
+				Metadata: &model.Metadata{
+					Name:            "local",
+					ResourceVersion: "1234",
+					Uid:             "e42e5adc-0749-11e8-a2b8-000c29dea4f6",
+				},
+				Provisioner: "kubernetes.io/no-provisioner",
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, &tc.expected, ExtractStorageClass(&tc.input))
+		})
+	}
+}
@@ -20,12 +20,11 @@ type event interface {
 	withSource(string)
 	withContainerExitCode(*int32)
 	withContainerExitTimestamp(*int64)
-	toPayloadModel() (model.EventsPayload, error)
+	toPayloadModel(*model.EventsPayload) error
 	toEventModel() (*model.Event, error)
 }
 
 type eventTransformer struct {
-	tpl          model.EventsPayload
 	objectKind   string
 	objectID     string
 	eventType    string
@@ -35,9 +34,7 @@ type eventTransformer struct {
 }
 
 func newEvent() event {
-	return &eventTransformer{
-		tpl: model.EventsPayload{Version: types.PayloadV1},
-	}
+	return &eventTransformer{}
 }
 
 func (e *eventTransformer) withObjectKind(kind string) {
@@ -64,23 +61,24 @@ func (e *eventTransformer) withContainerExitTimestamp(exitTS *int64) {
 	e.contExitTS = exitTS
 }
 
-func (e *eventTransformer) toPayloadModel() (model.EventsPayload, error) {
-	payload := e.tpl
+// toPayloadModel fills a new payload with the event.
+// The payload is filled in place as protobuf messages must not be copied.
+func (e *eventTransformer) toPayloadModel(payload *model.EventsPayload) error {
 	kind, err := e.kind()
 	if err != nil {
-		return model.EventsPayload{}, err
+		return err
 	}
 
-	payload.ObjectKind = kind
-
 	event, err := e.toEventModel()
 	if err != nil {
-		return model.EventsPayload{}, err
+		return err
 	}
 
+	payload.Version = types.PayloadV1
+	payload.ObjectKind = kind
 	payload.Events = []*model.Event{event}
 
-	return payload, nil
+	return nil
 }
 
 func (e *eventTransformer) toEventModel() (*model.Event, error) {
@@ -132,12 +130,12 @@ func (e *eventTransformer) evType() (model.Event_EventType, error) {
 	}
 }
 
-func (e *eventTransformer) kind() (model.EventsPayload_ObjectKind, error) {
+func (e *eventTransformer) kind() (model.ObjectKind, error) {
 	switch e.objectKind {
 	case types.ObjectKindContainer:
-		return model.EventsPayload_Container, nil
+		return model.ObjectKind_Container, nil
 	case types.ObjectKindPod:
-		return model.EventsPayload_Pod, nil
+		return model.ObjectKind_Pod, nil
 	default:
 		return -1, fmt.Errorf("unknown object kind %q", e.objectKind)
 	}
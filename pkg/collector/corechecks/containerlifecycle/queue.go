@@ -61,13 +61,12 @@ func (q *queue) add(ev event) error {
 // To be used if the queue is empty or if the last payload entry in the queue is full.
 // addPayload is not thread-safe, the caller must lock the queue.
 func (q *queue) addPayload(ev event) error {
-	payload, err := ev.toPayloadModel()
-	if err != nil {
+	q.data = append(q.data, model.EventsPayload{})
+	if err := ev.toPayloadModel(&q.data[len(q.data)-1]); err != nil {
+		q.data = q.data[:len(q.data)-1]
 		return err
 	}
 
-	q.data = append(q.data, payload)
-
 	return nil
 }
 
@@ -98,12 +97,12 @@ func (q *queue) isEmpty() bool {
 
 // lastPayload returns the last payload entry in the queue.
 // lastPayload is not thread-safe, the caller must lock the queue.
-func (q *queue) lastPayload() (model.EventsPayload, error) {
+func (q *queue) lastPayload() (*model.EventsPayload, error) {
 	if q.isEmpty() {
-		return model.EventsPayload{}, errors.New("empty queue")
+		return nil, errors.New("empty queue")
 	}
 
-	return q.data[len(q.data)-1], nil
+	return &q.data[len(q.data)-1], nil
 }
 
 // isLastPayloadFull returns whether the last payload entry
@@ -49,6 +49,7 @@ type PythonCheck struct {
 	telemetry      bool // whether or not the telemetry is enabled for this check
 	initConfig     string
 	instanceConfig string
+	lastCPUTime    time.Duration
 }
 
 // NewPythonCheck conveniently creates a PythonCheck instance
@@ -83,8 +84,19 @@ func (c *PythonCheck) runCheck(commitMetrics bool) error {
 
 	log.Debugf("Running python check %s (version: '%s', id: '%s')", c.ModuleName, c.version, c.id)
 
+	setRunningCheck(c.id)
+	cpuStart, cpuTracked := threadCPUTime()
 	cResult := C.run_check(rtloader, c.instance)
+	if cpuEnd, ok := threadCPUTime(); ok && cpuTracked {
+		c.lastCPUTime = cpuEnd - cpuStart
+	}
+	setRunningCheck("")
+
+	limitWarnings, limitErr := c.checkResourceLimits()
 	if cResult == nil {
+		if limitErr != nil {
+			return limitErr
+		}
 		if err := getRtLoaderError(); err != nil {
 			return err
 		}
@@ -92,6 +104,10 @@ func (c *PythonCheck) runCheck(commitMetrics bool) error {
 	}
 	defer C.rtloader_free(rtloader, unsafe.Pointer(cResult))
 
+	if limitErr != nil {
+		return limitErr
+	}
+
 	if commitMetrics {
 		s, err := aggregator.GetSender(c.ID())
 		if err != nil {
@@ -101,7 +117,7 @@ func (c *PythonCheck) runCheck(commitMetrics bool) error {
 	}
 
 	// grab the warnings and add them to the struct
-	c.lastWarnings = c.getPythonWarnings(gstate)
+	c.lastWarnings = append(c.getPythonWarnings(gstate), limitWarnings...)
 
 	checkErrStr := C.GoString(cResult)
 	if checkErrStr == "" {
@@ -110,6 +126,43 @@ func (c *PythonCheck) runCheck(commitMetrics bool) error {
 	return errors.New(checkErrStr)
 }
 
+// checkResourceLimits compares the resources used by the check to the
+// configured limits, returning a warning for each soft limit exceeded and an
+// error if a hard limit is exceeded.
+func (c *PythonCheck) checkResourceLimits() ([]error, error) {
+	var warnings []error
+
+	memoryInuse := getCheckInuseBytes(c.id)
+	if limit := config.Datadog.GetInt64("python_check_memory_hard_limit"); limit > 0 && memoryInuse > limit {
+		return nil, &check.ResourceLimitError{
+			Resource: "memory",
+			Usage:    fmt.Sprintf("%d bytes", memoryInuse),
+			Limit:    fmt.Sprintf("%d bytes", limit),
+		}
+	}
+	if limit := config.Datadog.GetInt64("python_check_memory_soft_limit"); limit > 0 && memoryInuse > limit {
+		warnings = append(warnings, fmt.Errorf("memory usage (%d bytes) exceeded the soft limit (%d bytes)", memoryInuse, limit))
+	}
+
+	if limit := configSeconds("python_check_cpu_time_hard_limit"); limit > 0 && c.lastCPUTime > limit {
+		return nil, &check.ResourceLimitError{
+			Resource: "CPU time",
+			Usage:    c.lastCPUTime.String(),
+			Limit:    limit.String(),
+		}
+	}
+	if limit := configSeconds("python_check_cpu_time_soft_limit"); limit > 0 && c.lastCPUTime > limit {
+		warnings = append(warnings, fmt.Errorf("CPU time usage (%s) exceeded the soft limit (%s)", c.lastCPUTime, limit))
+	}
+
+	return warnings, nil
+}
+
+// configSeconds reads a duration setting expressed in seconds
+func configSeconds(key string) time.Duration {
+	return time.Duration(config.Datadog.GetFloat64(key) * float64(time.Second))
+}
+
 // Run a Python check
 func (c *PythonCheck) Run() error {
 	return c.runCheck(true)
@@ -138,6 +191,7 @@ func (c *PythonCheck) Cancel() {
 		log.Warnf("failed to cancel check %s: %s", c.id, err)
 	}
 	aggregator.DestroySender(c.id)
+	removeCheckInuseBytes(c.id)
 }
 
 // String representation (for debug and logging)
@@ -321,6 +375,15 @@ func (c *PythonCheck) GetSenderStats() (check.SenderStats, error) {
 	return sender.GetSenderStats(), nil
 }
 
+// GetResourceStats returns the memory allocated through the rtloader by the
+// check and the CPU time used by its last run
+func (c *PythonCheck) GetResourceStats() check.ResourceStats {
+	return check.ResourceStats{
+		MemoryInuseBytes: getCheckInuseBytes(c.id),
+		LastCPUTime:      c.lastCPUTime,
+	}
+}
+
 // Interval returns the scheduling time for the check
 func (c *PythonCheck) Interval() time.Duration {
 	return c.interval
@@ -63,3 +63,15 @@ func TestConfigure(t *testing.T) {
 func TestConfigureDeprecated(t *testing.T) {
 	testConfigureDeprecated(t)
 }
+
+func TestRunResourceLimits(t *testing.T) {
+	testRunResourceLimits(t)
+}
+
+func TestCPUTimeLimits(t *testing.T) {
+	testCPUTimeLimits(t)
+}
+
+func TestRunningCheckPerThread(t *testing.T) {
+	testRunningCheckPerThread(t)
+}
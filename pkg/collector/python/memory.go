@@ -17,6 +17,7 @@ import (
 
 	"github.com/cihub/seelog"
 
+	"github.com/DataDog/datadog-agent/pkg/collector/check"
 	"github.com/DataDog/datadog-agent/pkg/config"
 	"github.com/DataDog/datadog-agent/pkg/telemetry"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
@@ -33,9 +34,25 @@ import (
 	"C"
 )
 
+// trackedAllocation is an allocation made through the rtloader, along with
+// the check that was running when it was made, if any
+type trackedAllocation struct {
+	size    C.size_t
+	checkID check.ID
+}
+
 var (
 	pointerCache = sync.Map{}
 
+	// runningChecks are the checks running on each OS thread, the allocations
+	// made through the rtloader by a thread are accounted to its check. Checks
+	// release the GIL while waiting, so several of them can be running at once.
+	runningChecks   = map[int]check.ID{}
+	runningChecksMu sync.RWMutex
+
+	checksInuseBytes   = map[check.ID]int64{}
+	checksInuseBytesMu sync.Mutex
+
 	// TODO(remy): if they're not exposed in the status page we may
 	// remove all these expvars
 	rtLoaderExpvars = expvar.NewMap("rtloader")
@@ -76,7 +93,9 @@ func MemoryTracker(ptr unsafe.Pointer, sz C.size_t, op C.rtloader_mem_ops_t) {
 	log.Tracef("Memory Tracker - ptr: %v, sz: %v, op: %v", ptr, sz, op)
 	switch op {
 	case C.DATADOG_AGENT_RTLOADER_ALLOCATION:
-		pointerCache.Store(ptr, sz)
+		checkID := getRunningCheck()
+		pointerCache.Store(ptr, trackedAllocation{size: sz, checkID: checkID})
+		addCheckInuseBytes(checkID, int64(sz))
 		allocations.Add(1)
 		tlmAllocations.Inc()
 		allocatedBytes.Add(int64(sz))
@@ -85,7 +104,7 @@ func MemoryTracker(ptr unsafe.Pointer, sz C.size_t, op C.rtloader_mem_ops_t) {
 		tlmInuseBytes.Set(float64(inuseBytes.Value()))
 
 	case C.DATADOG_AGENT_RTLOADER_FREE:
-		bytes, ok := pointerCache.Load(ptr)
+		value, ok := pointerCache.Load(ptr)
 		if !ok {
 			log.Debugf("untracked memory was attempted to be freed - set trace level for details")
 			lvl, err := log.GetLogLevel()
@@ -99,15 +118,77 @@ func MemoryTracker(ptr unsafe.Pointer, sz C.size_t, op C.rtloader_mem_ops_t) {
 		}
 		defer pointerCache.Delete(ptr)
 
+		allocation := value.(trackedAllocation)
+		addCheckInuseBytes(allocation.checkID, -1*int64(allocation.size))
 		frees.Add(1)
 		tlmFrees.Inc()
-		freedBytes.Add(int64(bytes.(C.size_t)))
-		tlmFreedBytes.Add(float64(bytes.(C.size_t)))
-		inuseBytes.Add(-1 * int64(bytes.(C.size_t)))
+		freedBytes.Add(int64(allocation.size))
+		tlmFreedBytes.Add(float64(allocation.size))
+		inuseBytes.Add(-1 * int64(allocation.size))
 		tlmInuseBytes.Set(float64(inuseBytes.Value()))
 	}
 }
 
+// setRunningCheck accounts the allocations made through the rtloader by the
+// current OS thread to the given check until it is reset with an empty ID. The
+// goroutine must be locked to its thread, as done by the stickyLock.
+func setRunningCheck(id check.ID) {
+	tid, ok := currentThreadID()
+	if !ok {
+		return
+	}
+
+	runningChecksMu.Lock()
+	defer runningChecksMu.Unlock()
+	if id == "" {
+		delete(runningChecks, tid)
+	} else {
+		runningChecks[tid] = id
+	}
+}
+
+// getRunningCheck returns the check running on the current OS thread, the
+// rtloader calling the memory tracker from the thread making the allocation
+func getRunningCheck() check.ID {
+	tid, ok := currentThreadID()
+	if !ok {
+		return ""
+	}
+
+	runningChecksMu.RLock()
+	defer runningChecksMu.RUnlock()
+	return runningChecks[tid]
+}
+
+func addCheckInuseBytes(id check.ID, sz int64) {
+	if id == "" {
+		return
+	}
+
+	checksInuseBytesMu.Lock()
+	defer checksInuseBytesMu.Unlock()
+	// the allocations of a cancelled check may be freed later on
+	if _, found := checksInuseBytes[id]; !found && sz < 0 {
+		return
+	}
+	checksInuseBytes[id] += sz
+}
+
+// removeCheckInuseBytes stops accounting the memory of a check, once it is unscheduled
+func removeCheckInuseBytes(id check.ID) {
+	checksInuseBytesMu.Lock()
+	defer checksInuseBytesMu.Unlock()
+	delete(checksInuseBytes, id)
+}
+
+// getCheckInuseBytes returns the memory allocated through the rtloader while
+// the given check was running and not freed yet
+func getCheckInuseBytes(id check.ID) int64 {
+	checksInuseBytesMu.Lock()
+	defer checksInuseBytesMu.Unlock()
+	return checksInuseBytes[id]
+}
+
 func TrackedCString(str string) *C.char {
 	cstr := C.CString(str)
 
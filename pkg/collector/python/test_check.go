@@ -20,6 +20,7 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/aggregator/mocksender"
 	"github.com/DataDog/datadog-agent/pkg/autodiscovery/integration"
 	"github.com/DataDog/datadog-agent/pkg/collector/check"
+	"github.com/DataDog/datadog-agent/pkg/config"
 )
 
 /*
@@ -627,3 +628,99 @@ func NewPythonFakeCheck() (*PythonCheck, error) {
 
 	return c, err
 }
+
+func testRunResourceLimits(t *testing.T) {
+	rtloader = newMockRtLoaderPtr()
+	defer func() { rtloader = nil }()
+
+	c, err := NewPythonFakeCheck()
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	c.instance = newMockPyObjectPtr()
+	c.id = check.ID("testLimitsID")
+
+	addCheckInuseBytes(c.id, 2048)
+	defer addCheckInuseBytes(c.id, -2048)
+	assert.Equal(t, int64(2048), c.GetResourceStats().MemoryInuseBytes)
+
+	config.Datadog.Set("python_check_memory_soft_limit", 1024)
+	defer config.Datadog.Set("python_check_memory_soft_limit", 0)
+
+	C.reset_check_mock()
+	C.run_check_return = C.CString("")
+
+	err = c.runCheck(false)
+	assert.Nil(t, err)
+	assert.Equal(t, []error{fmt.Errorf("memory usage (2048 bytes) exceeded the soft limit (1024 bytes)")}, c.lastWarnings)
+
+	config.Datadog.Set("python_check_memory_hard_limit", 1024)
+	defer config.Datadog.Set("python_check_memory_hard_limit", 0)
+
+	C.reset_check_mock()
+	C.run_check_return = C.CString("")
+
+	err = c.runCheck(false)
+	require.NotNil(t, err)
+	assert.True(t, check.IsResourceLimitError(err))
+	assert.Equal(t, "check unscheduled: memory usage (2048 bytes) exceeded the hard limit (1024 bytes)", err.Error())
+
+	// the memory of an unscheduled check is no longer accounted
+	c.Cancel()
+	assert.Equal(t, int64(0), getCheckInuseBytes(c.id))
+	addCheckInuseBytes(c.id, -1024)
+	checksInuseBytesMu.Lock()
+	_, found := checksInuseBytes[c.id]
+	checksInuseBytesMu.Unlock()
+	assert.False(t, found)
+}
+
+func testCPUTimeLimits(t *testing.T) {
+	c, err := NewPythonFakeCheck()
+	if !assert.Nil(t, err) {
+		return
+	}
+	c.lastCPUTime = 1500 * time.Millisecond
+
+	// the limits are in seconds
+	config.Datadog.Set("python_check_cpu_time_soft_limit", 1)
+	defer config.Datadog.Set("python_check_cpu_time_soft_limit", 0)
+	config.Datadog.Set("python_check_cpu_time_hard_limit", 2)
+	defer config.Datadog.Set("python_check_cpu_time_hard_limit", 0)
+
+	warnings, err := c.checkResourceLimits()
+	assert.Nil(t, err)
+	assert.Equal(t, []error{fmt.Errorf("CPU time usage (1.5s) exceeded the soft limit (1s)")}, warnings)
+
+	config.Datadog.Set("python_check_cpu_time_hard_limit", 1.2)
+	_, err = c.checkResourceLimits()
+	require.NotNil(t, err)
+	assert.True(t, check.IsResourceLimitError(err))
+}
+
+func testRunningCheckPerThread(t *testing.T) {
+	if _, ok := currentThreadID(); !ok {
+		t.Skip("threads are not identified on this platform")
+	}
+
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	setRunningCheck("first")
+	defer setRunningCheck("")
+
+	// a check running on another thread doesn't take over the allocations of this one
+	other := make(chan check.ID)
+	go func() {
+		runtime.LockOSThread()
+		defer runtime.UnlockOSThread()
+
+		setRunningCheck("second")
+		defer setRunningCheck("")
+		other <- getRunningCheck()
+	}()
+
+	assert.Equal(t, check.ID("second"), <-other)
+	assert.Equal(t, check.ID("first"), getRunningCheck())
+}
@@ -0,0 +1,21 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build python && !linux
+// +build python,!linux
+
+package python
+
+import "time"
+
+// threadCPUTime is not supported on this platform
+func threadCPUTime() (time.Duration, bool) {
+	return 0, false
+}
+
+// currentThreadID is not supported on this platform
+func currentThreadID() (int, bool) {
+	return 0, false
+}
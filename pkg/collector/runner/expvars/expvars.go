@@ -140,6 +140,9 @@ func AddCheckStats(
 	}
 
 	s.Add(execTime, err, warnings, mStats)
+	if reporter, ok := c.(check.ResourceReporter); ok {
+		s.SetResourceStats(reporter.GetResourceStats())
+	}
 }
 
 // RemoveCheckStats removes a check from the check stats map
@@ -45,6 +45,7 @@ type Runner struct {
 	checksTracker       *tracker.RunningChecksTracker // Tracker in charge of maintaining the running check list
 	scheduler           *scheduler.Scheduler          // Scheduler runner operates on
 	schedulerLock       sync.RWMutex                  // Lock around operations on the scheduler
+	unscheduleCheckFunc func(id check.ID) error       // Function halting the checks that exceeded their resource limits
 }
 
 // NewRunner takes the number of desired goroutines processing incoming checks.
@@ -116,6 +117,7 @@ func (r *Runner) newWorker() (*worker.Worker, error) {
 		r.pendingChecksChan,
 		r.checksTracker,
 		r.ShouldAddCheckStats,
+		r.UnscheduleCheck,
 	)
 	if err != nil {
 		log.Errorf("Runner %d was unable to instantiate a worker: %s", r.id, err)
@@ -249,6 +251,39 @@ func (r *Runner) ShouldAddCheckStats(id check.ID) bool {
 	return false
 }
 
+// SetUnscheduleCheckFunc sets the function used to halt the checks that
+// exceeded their resource limits
+func (r *Runner) SetUnscheduleCheckFunc(f func(id check.ID) error) {
+	r.schedulerLock.Lock()
+	r.unscheduleCheckFunc = f
+	r.schedulerLock.Unlock()
+}
+
+// UnscheduleCheck halts a check that exceeded its resource limits with the
+// function set by SetUnscheduleCheckFunc. If none is set, the check is only
+// removed from the scheduler.
+func (r *Runner) UnscheduleCheck(id check.ID) error {
+	if !r.isRunning.Load() {
+		// the runner is shutting down and stops all its checks
+		return nil
+	}
+
+	r.schedulerLock.RLock()
+	unscheduleCheckFunc := r.unscheduleCheckFunc
+	r.schedulerLock.RUnlock()
+
+	if unscheduleCheckFunc != nil {
+		return unscheduleCheckFunc(id)
+	}
+
+	sc := r.getScheduler()
+	if sc == nil {
+		return fmt.Errorf("no scheduler is set, check %s can't be unscheduled", id)
+	}
+
+	return sc.Cancel(id)
+}
+
 // StopCheck invokes the `Stop` method on a check if it's running. If the check
 // is not running, this is a noop
 func (r *Runner) StopCheck(id check.ID) error {
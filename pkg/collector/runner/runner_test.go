@@ -450,3 +450,38 @@ func TestRunnerShouldAddCheckStats(t *testing.T) {
 	// If there's a scheduler with scheduled check, add the stats
 	require.True(t, r.ShouldAddCheckStats(testCheck.ID()))
 }
+
+func TestRunnerUnscheduleCheck(t *testing.T) {
+	testSetUp(t)
+	config.Datadog.Set("check_runners", "3")
+
+	testCheck := newCheck(t, "test", false, nil)
+	sched := newScheduler()
+
+	r := NewRunner()
+	require.NotNil(t, r)
+
+	// Without a scheduler, the check can't be unscheduled
+	require.NotNil(t, r.UnscheduleCheck(testCheck.ID()))
+
+	r.SetScheduler(sched)
+	sched.Enter(testCheck)
+
+	// Without an unschedule function, the check is only removed from the scheduler
+	require.Nil(t, r.UnscheduleCheck(testCheck.ID()))
+	require.False(t, sched.IsCheckScheduled(testCheck.ID()))
+
+	var unscheduled []check.ID
+	r.SetUnscheduleCheckFunc(func(id check.ID) error {
+		unscheduled = append(unscheduled, id)
+		return nil
+	})
+
+	require.Nil(t, r.UnscheduleCheck(testCheck.ID()))
+	require.Equal(t, []check.ID{testCheck.ID()}, unscheduled)
+
+	// Once the runner is stopped, its checks are already being stopped
+	r.Stop()
+	require.Nil(t, r.UnscheduleCheck(testCheck.ID()))
+	require.Len(t, unscheduled, 1)
+}
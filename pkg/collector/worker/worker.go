@@ -38,6 +38,7 @@ type Worker struct {
 	pendingChecksChan       chan check.Check
 	runnerID                int
 	shouldAddCheckStatsFunc func(id check.ID) bool
+	unscheduleCheckFunc     func(id check.ID) error
 	utilizationTracker      UtilizationTracker
 }
 
@@ -48,6 +49,7 @@ func NewWorker(
 	pendingChecksChan chan check.Check,
 	checksTracker *tracker.RunningChecksTracker,
 	shouldAddCheckStatsFunc func(id check.ID) bool,
+	unscheduleCheckFunc func(id check.ID) error,
 ) (*Worker, error) {
 
 	if checksTracker == nil {
@@ -62,12 +64,17 @@ func NewWorker(
 		return nil, fmt.Errorf("worker cannot initialize using a nil shouldAddCheckStatsFunc")
 	}
 
+	if unscheduleCheckFunc == nil {
+		return nil, fmt.Errorf("worker cannot initialize using a nil unscheduleCheckFunc")
+	}
+
 	return newWorkerWithOptions(
 		runnerID,
 		ID,
 		pendingChecksChan,
 		checksTracker,
 		shouldAddCheckStatsFunc,
+		unscheduleCheckFunc,
 		aggregator.GetDefaultSender,
 		windowSize,
 		pollingInterval,
@@ -83,6 +90,7 @@ func newWorkerWithOptions(
 	pendingChecksChan chan check.Check,
 	checksTracker *tracker.RunningChecksTracker,
 	shouldAddCheckStatsFunc func(id check.ID) bool,
+	unscheduleCheckFunc func(id check.ID) error,
 	getDefaultSenderFunc func() (aggregator.Sender, error),
 	windowSize time.Duration,
 	pollingInterval time.Duration,
@@ -105,6 +113,7 @@ func newWorkerWithOptions(
 		pendingChecksChan:       pendingChecksChan,
 		runnerID:                runnerID,
 		shouldAddCheckStatsFunc: shouldAddCheckStatsFunc,
+		unscheduleCheckFunc:     unscheduleCheckFunc,
 		getDefaultSenderFunc:    getDefaultSenderFunc,
 		utilizationTracker:      utilizationTracker,
 	}, nil
@@ -194,8 +203,23 @@ func (w *Worker) Run() {
 			}
 		}
 
+		w.unscheduleIfLimitExceeded(check, checkErr)
+
 		checkLogger.CheckFinished()
 	}
 
 	log.Debugf("Runner %d, worker %d: Finished processing checks.", w.runnerID, w.ID)
 }
+
+// unscheduleIfLimitExceeded unschedules a check that exceeded its hard
+// resource limits so that its resources are released.
+func (w *Worker) unscheduleIfLimitExceeded(c check.Check, checkErr error) {
+	if checkErr == nil || !check.IsResourceLimitError(checkErr) {
+		return
+	}
+
+	log.Warnf("Runner %d, worker %d: unscheduling check %s: %v", w.runnerID, w.ID, c.ID(), checkErr)
+	if err := w.unscheduleCheckFunc(c.ID()); err != nil {
+		log.Errorf("Runner %d, worker %d: unable to unschedule check %s: %v", w.runnerID, w.ID, c.ID(), err)
+	}
+}
@@ -24,11 +24,14 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/metrics"
 )
 
+var mockUnscheduleCheckFunc = func(id check.ID) error { return nil }
+
 type testCheck struct {
 	check.StubCheck
 	sync.Mutex
 	doErr       bool
 	doWarn      bool
+	runErr      error
 	id          string
 	longRunning bool
 	t           *testing.T
@@ -66,6 +69,10 @@ func (c *testCheck) Run() error {
 	c.Lock()
 	defer c.Unlock()
 
+	if c.runErr != nil {
+		return c.runErr
+	}
+
 	if c.doErr {
 		return fmt.Errorf("myerror")
 	}
@@ -117,16 +124,19 @@ func TestWorkerInit(t *testing.T) {
 	pendingChecksChan := make(chan check.Check, 1)
 	mockShouldAddStatsFunc := func(id check.ID) bool { return true }
 
-	_, err := NewWorker(1, 2, nil, checksTracker, mockShouldAddStatsFunc)
+	_, err := NewWorker(1, 2, nil, checksTracker, mockShouldAddStatsFunc, mockUnscheduleCheckFunc)
 	require.NotNil(t, err)
 
-	_, err = NewWorker(1, 2, pendingChecksChan, nil, mockShouldAddStatsFunc)
+	_, err = NewWorker(1, 2, pendingChecksChan, nil, mockShouldAddStatsFunc, mockUnscheduleCheckFunc)
 	require.NotNil(t, err)
 
-	_, err = NewWorker(1, 2, pendingChecksChan, checksTracker, nil)
+	_, err = NewWorker(1, 2, pendingChecksChan, checksTracker, nil, mockUnscheduleCheckFunc)
 	require.NotNil(t, err)
 
-	worker, err := NewWorker(1, 2, pendingChecksChan, checksTracker, mockShouldAddStatsFunc)
+	_, err = NewWorker(1, 2, pendingChecksChan, checksTracker, mockShouldAddStatsFunc, nil)
+	require.NotNil(t, err)
+
+	worker, err := NewWorker(1, 2, pendingChecksChan, checksTracker, mockShouldAddStatsFunc, mockUnscheduleCheckFunc)
 	assert.Nil(t, err)
 	assert.NotNil(t, worker)
 }
@@ -145,7 +155,7 @@ func TestWorkerInitExpvarStats(t *testing.T) {
 		go func(idx int) {
 			defer wg.Done()
 
-			worker, err := NewWorker(1, idx, pendingChecksChan, checksTracker, mockShouldAddStatsFunc)
+			worker, err := NewWorker(1, idx, pendingChecksChan, checksTracker, mockShouldAddStatsFunc, mockUnscheduleCheckFunc)
 			assert.Nil(t, err)
 
 			worker.Run()
@@ -167,7 +177,7 @@ func TestWorkerName(t *testing.T) {
 
 	for _, id := range []int{1, 100, 500} {
 		expectedName := fmt.Sprintf("worker_%d", id)
-		worker, err := NewWorker(1, id, pendingChecksChan, checksTracker, mockShouldAddStatsFunc)
+		worker, err := NewWorker(1, id, pendingChecksChan, checksTracker, mockShouldAddStatsFunc, mockUnscheduleCheckFunc)
 		assert.Nil(t, err)
 		assert.NotNil(t, worker)
 
@@ -219,7 +229,7 @@ func TestWorker(t *testing.T) {
 	pendingChecksChan <- testCheck1
 	close(pendingChecksChan)
 
-	worker, err := NewWorker(100, 200, pendingChecksChan, checksTracker, mockShouldAddStatsFunc)
+	worker, err := NewWorker(100, 200, pendingChecksChan, checksTracker, mockShouldAddStatsFunc, mockUnscheduleCheckFunc)
 	require.Nil(t, err)
 
 	wg.Add(1)
@@ -278,6 +288,7 @@ func TestWorkerUtilizationExpvars(t *testing.T) {
 		pendingChecksChan,
 		checksTracker,
 		mockShouldAddStatsFunc,
+		mockUnscheduleCheckFunc,
 		func() (aggregator.Sender, error) { return nil, nil },
 		1000*time.Millisecond,
 		100*time.Millisecond,
@@ -350,7 +361,7 @@ func TestWorkerErrorAndWarningHandling(t *testing.T) {
 	}
 	close(pendingChecksChan)
 
-	worker, err := NewWorker(100, 200, pendingChecksChan, checksTracker, mockShouldAddStatsFunc)
+	worker, err := NewWorker(100, 200, pendingChecksChan, checksTracker, mockShouldAddStatsFunc, mockUnscheduleCheckFunc)
 	require.Nil(t, err)
 	AssertAsyncWorkerCount(t, 0)
 
@@ -377,6 +388,44 @@ func TestWorkerErrorAndWarningHandling(t *testing.T) {
 	AssertAsyncWorkerCount(t, 0)
 }
 
+func TestWorkerResourceLimitUnscheduling(t *testing.T) {
+	expvars.Reset()
+	config.Datadog.Set("hostname", "myhost")
+
+	checksTracker := tracker.NewRunningChecksTracker()
+	pendingChecksChan := make(chan check.Check, 10)
+	mockShouldAddStatsFunc := func(id check.ID) bool { return true }
+
+	var unscheduled []check.ID
+	unscheduleCheckFunc := func(id check.ID) error {
+		unscheduled = append(unscheduled, id)
+		return nil
+	}
+
+	limitedCheck := newCheck(t, "limited:123", false, nil)
+	limitedCheck.runErr = &check.ResourceLimitError{Resource: "memory", Usage: "12 MiB", Limit: "10 MiB"}
+	failingCheck := newCheck(t, "failing:234", true, nil)
+	okCheck := newCheck(t, "ok:345", false, nil)
+
+	for _, c := range []check.Check{limitedCheck, failingCheck, okCheck} {
+		pendingChecksChan <- c
+	}
+	close(pendingChecksChan)
+
+	worker, err := NewWorker(100, 200, pendingChecksChan, checksTracker, mockShouldAddStatsFunc, unscheduleCheckFunc)
+	require.Nil(t, err)
+
+	worker.Run()
+
+	assert.Equal(t, []check.ID{limitedCheck.ID()}, unscheduled)
+
+	// The error is kept in the stats so that it shows in the status page
+	stats, found := expvars.CheckStats(limitedCheck.ID())
+	require.True(t, found)
+	assert.Equal(t, 1, int(stats.TotalErrors))
+	assert.Contains(t, stats.LastError, "exceeded the hard limit")
+}
+
 func TestWorkerConcurrentCheckScheduling(t *testing.T) {
 	expvars.Reset()
 	config.Datadog.Set("hostname", "myhost")
@@ -395,7 +444,7 @@ func TestWorkerConcurrentCheckScheduling(t *testing.T) {
 	pendingChecksChan <- testCheck
 	close(pendingChecksChan)
 
-	worker, err := NewWorker(100, 200, pendingChecksChan, checksTracker, mockShouldAddStatsFunc)
+	worker, err := NewWorker(100, 200, pendingChecksChan, checksTracker, mockShouldAddStatsFunc, mockUnscheduleCheckFunc)
 	require.Nil(t, err)
 
 	worker.Run()
@@ -452,7 +501,7 @@ func TestWorkerStatsAddition(t *testing.T) {
 	pendingChecksChan <- squelchedStatsCheck
 	close(pendingChecksChan)
 
-	worker, err := NewWorker(100, 200, pendingChecksChan, checksTracker, shouldAddStatsFunc)
+	worker, err := NewWorker(100, 200, pendingChecksChan, checksTracker, shouldAddStatsFunc, mockUnscheduleCheckFunc)
 	require.Nil(t, err)
 
 	worker.Run()
@@ -500,6 +549,7 @@ func TestWorkerServiceCheckSending(t *testing.T) {
 		pendingChecksChan,
 		checksTracker,
 		mockShouldAddStatsFunc,
+		mockUnscheduleCheckFunc,
 		func() (aggregator.Sender, error) {
 			return mockSender, nil
 		},
@@ -571,6 +621,7 @@ func TestWorkerSenderNil(t *testing.T) {
 		pendingChecksChan,
 		checksTracker,
 		mockShouldAddStatsFunc,
+		mockUnscheduleCheckFunc,
 		func() (aggregator.Sender, error) {
 			return nil, fmt.Errorf("testerr")
 		},
@@ -612,6 +663,7 @@ func TestWorkerServiceCheckSendingLongRunningTasks(t *testing.T) {
 		pendingChecksChan,
 		checksTracker,
 		mockShouldAddStatsFunc,
+		mockUnscheduleCheckFunc,
 		func() (aggregator.Sender, error) {
 			return mockSender, nil
 		},
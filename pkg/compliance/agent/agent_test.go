@@ -300,7 +300,7 @@ func TestRunChecks(t *testing.T) {
 	dockerClient.On("Close").Return(nil).Once()
 	defer dockerClient.AssertExpectations(t)
 
-	err := RunChecks(
+	statuses, err := RunChecks(
 		reporter,
 		e.dir,
 		checks.WithMatchSuite(checks.IsFramework("cis-docker")),
@@ -310,6 +310,8 @@ func TestRunChecks(t *testing.T) {
 		checks.WithDockerClient(dockerClient),
 	)
 	assert.NoError(err)
+	assert.Len(statuses, 1)
+	assert.Equal("cis-docker-1", statuses[0].RuleID)
 }
 
 func TestRunChecksFromFile(t *testing.T) {
@@ -348,7 +350,7 @@ func TestRunChecksFromFile(t *testing.T) {
 		"node-role.kubernetes.io/worker": "",
 	}
 
-	err := RunChecksFromFile(
+	_, err := RunChecksFromFile(
 		reporter,
 		filepath.Join(e.dir, "cis-kubernetes.yaml"),
 		checks.WithHostname("the-host"),
@@ -18,6 +18,7 @@ type CheckStatus struct {
 	RuleID      string
 	Name        string
 	Description string
+	Remediation string
 	Version     string
 	Framework   string
 	Source      string
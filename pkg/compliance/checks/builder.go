@@ -64,6 +64,18 @@ func WithInterval(interval time.Duration) BuilderOption {
 	}
 }
 
+// WithChangeOnlyReporting configures checks to report the results that changed
+// since their previous run, and all their results once per snapshot interval
+func WithChangeOnlyReporting(snapshotInterval time.Duration) BuilderOption {
+	return func(b *builder) error {
+		if snapshotInterval <= 0 {
+			return fmt.Errorf("invalid snapshot interval %s", snapshotInterval)
+		}
+		b.resultTracker = newResultTracker(snapshotInterval)
+		return nil
+	}
+}
+
 // WithMaxEvents configures default max events per run
 func WithMaxEvents(max int) BuilderOption {
 	return func(b *builder) error {
@@ -309,6 +321,8 @@ type builder struct {
 	regoInputDumpPath string
 	regoEvalSkip      bool
 
+	resultTracker *resultTracker
+
 	status *status
 }
 
@@ -351,6 +365,7 @@ func (b *builder) addCheckAndRun(suite *compliance.Suite, r *compliance.RuleComm
 		b.status.addCheck(&compliance.CheckStatus{
 			RuleID:      r.ID,
 			Description: r.Description,
+			Remediation: r.Remediation,
 			Name:        compliance.CheckName(r.ID, r.Description),
 			Framework:   suite.Meta.Framework,
 			Source:      suite.Meta.Source,
@@ -698,7 +713,8 @@ func (b *builder) newCheck(meta *compliance.SuiteMeta, ruleScope compliance.Rule
 		scope:           ruleScope,
 		checkable:       checkable,
 
-		eventNotify: notify,
+		resultTracker: b.resultTracker,
+		eventNotify:   notify,
 	}, nil
 }
 
@@ -731,7 +747,8 @@ func (b *builder) newRegoCheck(meta *compliance.SuiteMeta, ruleScope compliance.
 		scope:           ruleScope,
 		checkable:       regoCheck,
 
-		eventNotify: notify,
+		resultTracker: b.resultTracker,
+		eventNotify:   notify,
 	}, nil
 }
 
@@ -37,7 +37,8 @@ type complianceCheck struct {
 
 	checkable checkable
 
-	eventNotify eventNotify
+	resultTracker *resultTracker
+	eventNotify   eventNotify
 }
 
 func (c *complianceCheck) Stop() {
@@ -117,6 +118,7 @@ func (c *complianceCheck) Run() error {
 
 	reports := c.checkable.check(c)
 	resourceQuadIDs := make(map[resourceQuadID]bool)
+	events := make([]*event.Event, 0, len(reports))
 
 	for _, report := range reports {
 		if report.Error != nil {
@@ -160,14 +162,23 @@ func (c *complianceCheck) Run() error {
 			ExpireAt:         c.computeExpireAt(),
 		}
 
-		log.Debugf("%s: reporting [%s] [%s] [%s]", c.ruleID, e.Result, e.ResourceID, e.ResourceType)
-
-		c.Reporter().Report(e)
+		events = append(events, e)
 		if c.eventNotify != nil {
 			c.eventNotify(c.ruleID, e)
 		}
 	}
 
+	// a failed run is reported as is, leaving the previous results untouched
+	if c.resultTracker != nil && err == nil {
+		events = c.resultTracker.filter(c.suiteMeta.Framework, c.ruleID, events, time.Now())
+	}
+
+	for _, e := range events {
+		log.Debugf("%s: reporting [%s] [%s] [%s]", c.ruleID, e.Result, e.ResourceID, e.ResourceType)
+
+		c.Reporter().Report(e)
+	}
+
 	return err
 }
 
@@ -175,7 +186,13 @@ func (c *complianceCheck) Run() error {
 const ExpireAtIntervalFactor = 3
 
 func (c *complianceCheck) computeExpireAt() time.Time {
-	base := time.Now().Add(c.interval * ExpireAtIntervalFactor).UTC()
+	interval := c.interval
+	// unchanged results are only reported once per snapshot interval
+	if c.resultTracker != nil && c.resultTracker.snapshotInterval > interval {
+		interval = c.resultTracker.snapshotInterval
+	}
+
+	base := time.Now().Add(interval * ExpireAtIntervalFactor).UTC()
 	// remove sub-second precision
 	truncated := base.Truncate(1 * time.Second)
 	return truncated
@@ -174,3 +174,54 @@ func TestCheckRunNoLeader(t *testing.T) {
 	err := check.Run()
 	assert.Nil(err)
 }
+
+func TestCheckRunErrorKeepsResultState(t *testing.T) {
+	const (
+		ruleID       = "rule-id"
+		frameworkID  = "cis"
+		resourceType = "resource-type"
+		resourceID   = "resource-id"
+	)
+
+	assert := assert.New(t)
+
+	env := &mocks.Env{}
+	reporter := &mocks.Reporter{}
+	checkable := &mockCheckable{}
+
+	check := &complianceCheck{
+		Env: env,
+
+		ruleID:    ruleID,
+		checkable: checkable,
+		scope:     resourceType,
+
+		suiteMeta:     &compliance.SuiteMeta{Framework: frameworkID},
+		resultTracker: newTestResultTracker(time.Hour),
+	}
+
+	env.On("Hostname").Return(resourceID)
+	env.On("IsLeader").Return(true)
+	env.On("Reporter").Return(reporter)
+
+	var reported []*event.Event
+	reporter.On("Report", mock.Anything).Run(func(args mock.Arguments) {
+		reported = append(reported, args.Get(0).(*event.Event))
+	})
+
+	checkable.On("check", check).Return([]*compliance.Report{{Passed: true}}).Once()
+	assert.NoError(check.Run())
+	assert.Len(reported, 1)
+
+	// the failed run is reported without transition
+	checkable.On("check", check).Return([]*compliance.Report{{Error: errors.New("check error")}}).Once()
+	assert.Error(check.Run())
+	assert.Len(reported, 2)
+	assert.Equal(event.Error, reported[1].Result)
+	assert.Empty(reported[1].Transition)
+
+	// the result before the error is still the reference
+	checkable.On("check", check).Return([]*compliance.Report{{Passed: true}}).Once()
+	assert.NoError(check.Run())
+	assert.Len(reported, 2)
+}
@@ -0,0 +1,362 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package checks
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/compliance"
+	"github.com/DataDog/datadog-agent/pkg/compliance/checks/env"
+	"github.com/DataDog/datadog-agent/pkg/compliance/eval"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+const (
+	dpkgStatusPath   = "/var/lib/dpkg/status"
+	apkInstalledPath = "/lib/apk/db/installed"
+	rpmDBPath        = "/var/lib/rpm"
+
+	rpmQueryTimeout = 30 * time.Second
+	rpmQueryFormat  = "%|EPOCH?{%{EPOCH}:}:{}|%{VERSION}-%{RELEASE}\\n"
+)
+
+var packageReportedFields = []string{
+	compliance.PackageFieldName,
+	compliance.PackageFieldVersion,
+	compliance.PackageFieldManager,
+	compliance.PackageFieldInstalled,
+}
+
+// ErrNoPackageManager is returned when no supported package database is found on the host
+var ErrNoPackageManager = errors.New("no package database found")
+
+func resolvePackage(_ context.Context, e env.Env, id string, res compliance.ResourceCommon, rego bool) (resolved, error) {
+	if res.Package == nil {
+		return nil, fmt.Errorf("%s: expecting package resource in package check", id)
+	}
+
+	pkg := res.Package
+
+	log.Debugf("%s: running package check: %s", id, pkg.Name)
+
+	manager := pkg.Manager
+	if manager == "" {
+		manager = detectPackageManager(e)
+		if manager == "" {
+			return nil, ErrNoPackageManager
+		}
+	}
+
+	var (
+		versions []string
+		err      error
+	)
+
+	switch manager {
+	case compliance.PackageManagerDpkg:
+		versions, err = findPackageInFile(e.NormalizeToHostRoot(dpkgStatusPath), pkg.Name, findDpkgPackage)
+	case compliance.PackageManagerApk:
+		versions, err = findPackageInFile(e.NormalizeToHostRoot(apkInstalledPath), pkg.Name, findApkPackage)
+	case compliance.PackageManagerRpm:
+		versions, err = findRpmPackage(e.NormalizeToHostRoot("/"), pkg.Name)
+	default:
+		return nil, fmt.Errorf("%s: unsupported package manager `%s`", id, manager)
+	}
+
+	if err != nil {
+		return nil, wrapErrorWithID(id, err)
+	}
+
+	// a package which is not installed is reported as such, as benchmarks
+	// commonly require some packages to be absent
+	if len(versions) == 0 {
+		return newResolvedInstance(newPackageInstance(pkg.Name, "", manager, false), pkg.Name, "package"), nil
+	}
+
+	var instances []resolvedInstance
+	for _, version := range versions {
+		instances = append(instances, newResolvedInstance(newPackageInstance(pkg.Name, version, manager, true), pkg.Name, "package"))
+	}
+
+	if len(instances) == 1 {
+		return instances[0].(*_resolvedInstance), nil
+	}
+
+	return newResolvedInstances(instances), nil
+}
+
+func newPackageInstance(name, version, manager string, installed bool) eval.Instance {
+	return eval.NewInstance(
+		eval.VarMap{
+			compliance.PackageFieldName:      name,
+			compliance.PackageFieldVersion:   version,
+			compliance.PackageFieldManager:   manager,
+			compliance.PackageFieldInstalled: installed,
+		},
+		eval.FunctionMap{
+			compliance.PackageFuncVersionCompare: packageVersionCompare(version),
+		},
+		eval.RegoInputMap{
+			"name":      name,
+			"version":   version,
+			"manager":   manager,
+			"installed": installed,
+		},
+	)
+}
+
+// detectPackageManager returns the first package manager whose database exists on the host
+func detectPackageManager(e env.Env) string {
+	candidates := []struct {
+		manager string
+		path    string
+	}{
+		{compliance.PackageManagerDpkg, dpkgStatusPath},
+		{compliance.PackageManagerApk, apkInstalledPath},
+		{compliance.PackageManagerRpm, rpmDBPath},
+	}
+
+	for _, candidate := range candidates {
+		if _, err := os.Stat(e.NormalizeToHostRoot(candidate.path)); err == nil {
+			return candidate.manager
+		}
+	}
+	return ""
+}
+
+type packageFinderFunc func(r io.Reader, name string) ([]string, error)
+
+func findPackageInFile(path string, name string, finder packageFinderFunc) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return finder(f, name)
+}
+
+// readStanzas calls fn with the fields of each block of `Key: value` lines
+// separated by empty lines, continuation lines being ignored
+func readStanzas(r io.Reader, separator string, fn func(fields map[string]string)) error {
+	fields := make(map[string]string)
+
+	bs := bufio.NewScanner(r)
+	bs.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for bs.Scan() {
+		line := bs.Bytes()
+		if len(bytes.TrimSpace(line)) == 0 {
+			if len(fields) > 0 {
+				fn(fields)
+				fields = make(map[string]string)
+			}
+			continue
+		}
+
+		if line[0] == ' ' || line[0] == '\t' {
+			continue
+		}
+
+		parts := strings.SplitN(string(line), separator, 2)
+		if len(parts) != 2 {
+			continue
+		}
+		fields[parts[0]] = strings.TrimSpace(parts[1])
+	}
+
+	if len(fields) > 0 {
+		fn(fields)
+	}
+	return bs.Err()
+}
+
+// findDpkgPackage returns the versions of a package installed according to a dpkg status file
+func findDpkgPackage(r io.Reader, name string) ([]string, error) {
+	var versions []string
+	err := readStanzas(r, ":", func(fields map[string]string) {
+		if fields["Package"] != name {
+			return
+		}
+
+		// Status is made of the wanted, error and current states of the package
+		status := strings.Fields(fields["Status"])
+		if len(status) != 3 || status[2] != "installed" {
+			return
+		}
+		versions = append(versions, fields["Version"])
+	})
+	return versions, err
+}
+
+// findApkPackage returns the versions of a package installed according to an apk installed database
+func findApkPackage(r io.Reader, name string) ([]string, error) {
+	var versions []string
+	err := readStanzas(r, ":", func(fields map[string]string) {
+		if fields["P"] == name {
+			versions = append(versions, fields["V"])
+		}
+	})
+	return versions, err
+}
+
+// findRpmPackage queries the rpm database of the host, which can't be read
+// without the rpm library
+func findRpmPackage(root string, name string) ([]string, error) {
+	execCommand := &compliance.BinaryCmd{
+		Name: "rpm",
+		Args: []string{"--root", root, "-q", "--queryformat", rpmQueryFormat, name},
+	}
+
+	exitCode, stdout, err := runBinaryCmd(execCommand, rpmQueryTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	// rpm exits with the number of packages not found, and reports them on
+	// stdout. Any other failure, such as an unreadable database, is an error.
+	if exitCode != 0 {
+		if strings.Contains(stdout, "is not installed") {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("rpm query for package '%s' failed with exit code %d: %s", name, exitCode, strings.TrimSpace(stdout))
+	}
+
+	var versions []string
+	for _, line := range strings.Split(stdout, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			versions = append(versions, line)
+		}
+	}
+	return versions, nil
+}
+
+func packageVersionCompare(version string) eval.Function {
+	return func(_ eval.Instance, args ...interface{}) (interface{}, error) {
+		if len(args) != 1 {
+			return nil, fmt.Errorf(`invalid number of arguments, expecting 1 got %d`, len(args))
+		}
+		other, ok := args[0].(string)
+		if !ok {
+			return nil, errors.New(`expecting string value for version argument`)
+		}
+		if version == "" {
+			return nil, errors.New("package is not installed")
+		}
+		return compareVersions(version, other), nil
+	}
+}
+
+// compareVersions compares two [epoch:]upstream[-revision] versions following
+// the dpkg ordering, which is also a close approximation of the rpm and apk ones.
+// It returns -1, 0 or 1.
+func compareVersions(a, b string) int {
+	aEpoch, aUpstream, aRevision := splitVersion(a)
+	bEpoch, bUpstream, bRevision := splitVersion(b)
+
+	if aEpoch != bEpoch {
+		if aEpoch < bEpoch {
+			return -1
+		}
+		return 1
+	}
+
+	if c := compareVersionParts(aUpstream, bUpstream); c != 0 {
+		return c
+	}
+	return compareVersionParts(aRevision, bRevision)
+}
+
+func splitVersion(version string) (epoch int, upstream string, revision string) {
+	if i := strings.IndexByte(version, ':'); i >= 0 {
+		epoch, _ = strconv.Atoi(version[:i])
+		version = version[i+1:]
+	}
+
+	if i := strings.LastIndexByte(version, '-'); i >= 0 {
+		return epoch, version[:i], version[i+1:]
+	}
+	return epoch, version, ""
+}
+
+func isVersionDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// versionCharOrder sorts letters before non-letters, and `~` before anything,
+// even the end of the version
+func versionCharOrder(s string, i int) int {
+	if i >= len(s) {
+		return 0
+	}
+
+	c := s[i]
+	switch {
+	case isVersionDigit(c):
+		return 0
+	case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z':
+		return int(c)
+	case c == '~':
+		return -1
+	default:
+		return int(c) + 256
+	}
+}
+
+func compareVersionParts(a, b string) int {
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		for (i < len(a) && !isVersionDigit(a[i])) || (j < len(b) && !isVersionDigit(b[j])) {
+			ac, bc := versionCharOrder(a, i), versionCharOrder(b, j)
+			if ac != bc {
+				if ac < bc {
+					return -1
+				}
+				return 1
+			}
+			i++
+			j++
+		}
+
+		for i < len(a) && a[i] == '0' {
+			i++
+		}
+		for j < len(b) && b[j] == '0' {
+			j++
+		}
+
+		firstDiff := 0
+		for i < len(a) && isVersionDigit(a[i]) && j < len(b) && isVersionDigit(b[j]) {
+			if firstDiff == 0 {
+				firstDiff = int(a[i]) - int(b[j])
+			}
+			i++
+			j++
+		}
+
+		if i < len(a) && isVersionDigit(a[i]) {
+			return 1
+		}
+		if j < len(b) && isVersionDigit(b[j]) {
+			return -1
+		}
+		if firstDiff < 0 {
+			return -1
+		}
+		if firstDiff > 0 {
+			return 1
+		}
+	}
+	return 0
+}
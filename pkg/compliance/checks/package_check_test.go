@@ -0,0 +1,322 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build !windows
+// +build !windows
+
+package checks
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/DataDog/datadog-agent/pkg/compliance"
+	"github.com/DataDog/datadog-agent/pkg/compliance/event"
+	"github.com/DataDog/datadog-agent/pkg/compliance/mocks"
+
+	"github.com/stretchr/testify/mock"
+	assert "github.com/stretchr/testify/require"
+)
+
+func hostRootEnv(hostRoot string) *mocks.Env {
+	env := &mocks.Env{}
+	env.On("NormalizeToHostRoot", mock.Anything).Return(func(path string) string {
+		return filepath.Join(hostRoot, path)
+	})
+	return env
+}
+
+func TestPackageCheck(t *testing.T) {
+	tests := []struct {
+		name     string
+		hostRoot string
+		resource compliance.Resource
+
+		expectReport *compliance.Report
+	}{
+		{
+			name:     "dpkg package installed",
+			hostRoot: "./testdata/package/dpkg",
+			resource: compliance.Resource{
+				ResourceCommon: compliance.ResourceCommon{
+					Package: &compliance.Package{
+						Name: "openssh-server",
+					},
+				},
+				Condition: `package.installed && package.versionCompare("1:8.2p1-4ubuntu0.3") >= 0`,
+			},
+			expectReport: &compliance.Report{
+				Passed: true,
+				Data: event.Data{
+					"package.name":      "openssh-server",
+					"package.version":   "1:8.2p1-4ubuntu0.5",
+					"package.manager":   "dpkg",
+					"package.installed": true,
+				},
+				Resource: compliance.ReportResource{
+					ID:   "openssh-server",
+					Type: "package",
+				},
+			},
+		},
+		{
+			name:     "dpkg package removed",
+			hostRoot: "./testdata/package/dpkg",
+			resource: compliance.Resource{
+				ResourceCommon: compliance.ResourceCommon{
+					Package: &compliance.Package{
+						Name:    "telnetd",
+						Manager: "dpkg",
+					},
+				},
+				Condition: `!package.installed`,
+			},
+			expectReport: &compliance.Report{
+				Passed: true,
+				Data: event.Data{
+					"package.name":      "telnetd",
+					"package.version":   "",
+					"package.manager":   "dpkg",
+					"package.installed": false,
+				},
+				Resource: compliance.ReportResource{
+					ID:   "telnetd",
+					Type: "package",
+				},
+			},
+		},
+		{
+			name:     "apk package outdated",
+			hostRoot: "./testdata/package/apk",
+			resource: compliance.Resource{
+				ResourceCommon: compliance.ResourceCommon{
+					Package: &compliance.Package{
+						Name: "openssl",
+					},
+				},
+				Condition: `package.versionCompare("1.1.1n-r0") >= 0`,
+			},
+			expectReport: &compliance.Report{
+				Passed: false,
+				Data: event.Data{
+					"package.name":      "openssl",
+					"package.version":   "1.1.1l-r0",
+					"package.manager":   "apk",
+					"package.installed": true,
+				},
+				Resource: compliance.ReportResource{
+					ID:   "openssl",
+					Type: "package",
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			env := hostRootEnv(test.hostRoot)
+
+			packageCheck, err := newResourceCheck(env, "rule-id", test.resource)
+			assert.NoError(err)
+
+			reports := packageCheck.check(env)
+			assert.Equal(test.expectReport, reports[0])
+		})
+	}
+}
+
+func TestPackageCheckRpm(t *testing.T) {
+	assert := assert.New(t)
+
+	commandRunner = func(ctx context.Context, name string, args []string, captureStdout bool) (int, []byte, error) {
+		assert.Equal("rpm", name)
+		assert.Equal([]string{"--root", "/host", "-q", "--queryformat", rpmQueryFormat, "kernel"}, args)
+		return 0, []byte("4.18.0-305.el8\n4.18.0-348.el8\n"), nil
+	}
+	defer func() { commandRunner = runCommand }()
+
+	resource := compliance.Resource{
+		ResourceCommon: compliance.ResourceCommon{
+			Package: &compliance.Package{
+				Name:    "kernel",
+				Manager: "rpm",
+			},
+		},
+		Condition: `package.versionCompare("4.18.0-348") >= 0`,
+	}
+
+	env := hostRootEnv("/host")
+	env.On("MaxEventsPerRun").Return(30)
+
+	packageCheck, err := newResourceCheck(env, "rule-id", resource)
+	assert.NoError(err)
+
+	reports := packageCheck.check(env)
+	assert.Len(reports, 2)
+	assert.False(reports[0].Passed)
+	assert.True(reports[1].Passed)
+}
+
+func TestFindRpmPackage(t *testing.T) {
+	tests := []struct {
+		name           string
+		exitCode       int
+		stdout         string
+		expectVersions []string
+		expectError    bool
+	}{
+		{
+			name:           "installed",
+			stdout:         "4.18.0-348.el8\n",
+			expectVersions: []string{"4.18.0-348.el8"},
+		},
+		{
+			name:     "not installed",
+			exitCode: 1,
+			stdout:   "package kernel is not installed\n",
+		},
+		{
+			name:        "database error",
+			exitCode:    1,
+			stdout:      "error: cannot open Packages database in /host/var/lib/rpm\n",
+			expectError: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			commandRunner = func(ctx context.Context, name string, args []string, captureStdout bool) (int, []byte, error) {
+				return test.exitCode, []byte(test.stdout), nil
+			}
+			defer func() { commandRunner = runCommand }()
+
+			versions, err := findRpmPackage("/host", "kernel")
+			assert.Equal(t, test.expectVersions, versions)
+			if test.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestPackageCheckNoManager(t *testing.T) {
+	assert := assert.New(t)
+
+	resource := compliance.Resource{
+		ResourceCommon: compliance.ResourceCommon{
+			Package: &compliance.Package{
+				Name: "openssh-server",
+			},
+		},
+		Condition: `package.installed`,
+	}
+
+	env := hostRootEnv(t.TempDir())
+
+	packageCheck, err := newResourceCheck(env, "rule-id", resource)
+	assert.NoError(err)
+
+	reports := packageCheck.check(env)
+	assert.Equal(ErrNoPackageManager, reports[0].Error)
+}
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b   string
+		expect int
+	}{
+		{"1.0", "1.0", 0},
+		{"1.0", "1.1", -1},
+		{"1.10", "1.9", 1},
+		{"1.0~rc1", "1.0", -1},
+		{"1.0a", "1.0", 1},
+		{"1:1.0", "2.0", 1},
+		{"8.2p1-4ubuntu0.5", "8.2p1-4ubuntu0.3", 1},
+		{"1.1.1l-r0", "1.1.1n-r0", -1},
+		{"1.2.2-r7", "1.2.2-r10", -1},
+		{"1.01", "1.1", 0},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expect, compareVersions(test.a, test.b), "%s <=> %s", test.a, test.b)
+		assert.Equal(t, -test.expect, compareVersions(test.b, test.a), "%s <=> %s", test.b, test.a)
+	}
+}
+
+func TestPackageAndSysctlRegoInput(t *testing.T) {
+	assert := assert.New(t)
+
+	rule := &compliance.RegoRule{
+		RuleCommon: compliance.RuleCommon{
+			ID: "rule-id",
+		},
+		Module: `
+			package test
+
+			import data.datadog as dd
+
+			# package being a keyword, the input can't be referenced as input.package
+			findings[f] {
+				input["package"].installed
+				input.sysctl.value == "0"
+				f := dd.passed_finding("package", input["package"].name, {"package.version": input["package"].version})
+			}
+		`,
+		Findings: "data.test.findings",
+	}
+
+	regoCheck := &regoCheck{
+		ruleID: "rule-id",
+		inputs: []compliance.RegoInput{
+			{
+				ResourceCommon: compliance.ResourceCommon{
+					Package: &compliance.Package{
+						Name: "rsync",
+					},
+				},
+			},
+			{
+				ResourceCommon: compliance.ResourceCommon{
+					Sysctl: &compliance.Sysctl{
+						Name: "net.ipv4.ip_forward",
+					},
+				},
+			},
+		},
+	}
+	assert.NoError(regoCheck.compileRule(rule, "", &compliance.SuiteMeta{}))
+
+	env := &mocks.Env{}
+	env.On("NormalizeToHostRoot", mock.Anything).Return(func(path string) string {
+		if filepath.Dir(path) == procSysPath+"/net/ipv4" {
+			return filepath.Join("./testdata/sysctl", path)
+		}
+		return filepath.Join("./testdata/package/dpkg", path)
+	})
+	env.On("ProvidedInput", mock.Anything).Return(nil).Once()
+	env.On("Hostname").Return("hostname_test").Once()
+	env.On("DumpInputPath").Return("").Once()
+	env.On("ShouldSkipRegoEval").Return(false).Once()
+
+	reports := regoCheck.check(env)
+	assert.Equal([]*compliance.Report{
+		{
+			Passed: true,
+			Data: event.Data{
+				"package.version": "3.1.3-8ubuntu0.4",
+			},
+			Resource: compliance.ReportResource{
+				ID:   "rsync",
+				Type: "package",
+			},
+			Evaluator: "rego",
+		},
+	}, reports)
+}
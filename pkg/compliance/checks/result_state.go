@@ -0,0 +1,168 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package checks
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/compliance/event"
+	"github.com/DataDog/datadog-agent/pkg/persistentcache"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+	"github.com/DataDog/datadog-agent/pkg/version"
+)
+
+const resultStateCacheKeyPrefix = "compliance_results:"
+
+// resourceKey identifies a resource evaluated by a rule
+type resourceKey struct {
+	ResourceType string
+	ResourceID   string
+}
+
+// resourceResultState is the last result reported for a resource
+type resourceResultState struct {
+	ResourceType string `json:"resource_type"`
+	ResourceID   string `json:"resource_id"`
+	Result       string `json:"result"`
+}
+
+// ruleResultState is the persisted state of a rule
+type ruleResultState struct {
+	LastSnapshot time.Time             `json:"last_snapshot"`
+	Resources    []resourceResultState `json:"resources"`
+}
+
+// resultTracker keeps the last result of each resource of the rules across
+// runs, so that only the changes are reported between two full snapshots
+type resultTracker struct {
+	snapshotInterval time.Duration
+
+	read  func(key string) (string, error)
+	write func(key, value string) error
+}
+
+func newResultTracker(snapshotInterval time.Duration) *resultTracker {
+	return &resultTracker{
+		snapshotInterval: snapshotInterval,
+		read:             persistentcache.Read,
+		write:            persistentcache.Write,
+	}
+}
+
+// resultStateCacheKey hashes the framework and rule IDs as the persistent
+// cache drops the characters, such as dots, it does not allow in file names
+func resultStateCacheKey(frameworkID, ruleID string) string {
+	sum := sha256.Sum256([]byte(frameworkID + "/" + ruleID))
+	return resultStateCacheKeyPrefix + hex.EncodeToString(sum[:])
+}
+
+func (t *resultTracker) load(key string) ruleResultState {
+	var state ruleResultState
+
+	content, err := t.read(key)
+	if err != nil {
+		log.Warnf("Failed to read compliance result state %s: %v", key, err)
+		return state
+	}
+	if content == "" {
+		return state
+	}
+
+	if err := json.Unmarshal([]byte(content), &state); err != nil {
+		log.Warnf("Failed to parse compliance result state %s: %v", key, err)
+		return ruleResultState{}
+	}
+	return state
+}
+
+func (t *resultTracker) save(key string, state ruleResultState) {
+	content, err := json.Marshal(state)
+	if err != nil {
+		log.Errorf("Failed to serialize compliance result state %s: %v", key, err)
+		return
+	}
+
+	if err := t.write(key, string(content)); err != nil {
+		log.Errorf("Failed to write compliance result state %s: %v", key, err)
+	}
+}
+
+// filter returns the events of a run of a rule to report. Events of new
+// resources and of resources whose result changed are annotated with their
+// transition and always reported, along with an event for each resource that
+// is gone since the previous run. Unchanged results are only reported with
+// the full snapshot taken every snapshot interval.
+func (t *resultTracker) filter(frameworkID, ruleID string, events []*event.Event, now time.Time) []*event.Event {
+	cacheKey := resultStateCacheKey(frameworkID, ruleID)
+	previous := t.load(cacheKey)
+
+	previousResults := make(map[resourceKey]string, len(previous.Resources))
+	for _, resource := range previous.Resources {
+		previousResults[resourceKey{ResourceType: resource.ResourceType, ResourceID: resource.ResourceID}] = resource.Result
+	}
+
+	snapshot := previous.LastSnapshot.IsZero() || now.Sub(previous.LastSnapshot) >= t.snapshotInterval
+
+	next := ruleResultState{
+		LastSnapshot: previous.LastSnapshot,
+	}
+	if snapshot {
+		next.LastSnapshot = now
+	}
+
+	var reported []*event.Event
+	for _, e := range events {
+		key := resourceKey{ResourceType: e.ResourceType, ResourceID: e.ResourceID}
+
+		previousResult, found := previousResults[key]
+		delete(previousResults, key)
+
+		switch {
+		case !found:
+			e.Transition = event.TransitionNew
+		case previousResult != e.Result:
+			e.Transition = event.TransitionChanged
+			e.PreviousResult = previousResult
+		}
+
+		if snapshot || e.Transition != "" {
+			reported = append(reported, e)
+		}
+
+		next.Resources = append(next.Resources, resourceResultState{
+			ResourceType: e.ResourceType,
+			ResourceID:   e.ResourceID,
+			Result:       e.Result,
+		})
+	}
+
+	// the remaining resources were not evaluated by this run
+	for _, resource := range previous.Resources {
+		key := resourceKey{ResourceType: resource.ResourceType, ResourceID: resource.ResourceID}
+		if _, gone := previousResults[key]; !gone {
+			continue
+		}
+
+		reported = append(reported, &event.Event{
+			AgentRuleID:      ruleID,
+			AgentFrameworkID: frameworkID,
+			AgentVersion:     version.AgentVersion,
+			ResourceType:     resource.ResourceType,
+			ResourceID:       resource.ResourceID,
+			Result:           resource.Result,
+			PreviousResult:   resource.Result,
+			Transition:       event.TransitionGone,
+			ExpireAt:         now.UTC().Truncate(time.Second),
+		})
+	}
+
+	t.save(cacheKey, next)
+
+	return reported
+}
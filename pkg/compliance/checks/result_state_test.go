@@ -0,0 +1,143 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package checks
+
+import (
+	"testing"
+	"time"
+
+	assert "github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/pkg/compliance/event"
+	"github.com/DataDog/datadog-agent/pkg/config"
+)
+
+func newTestResultTracker(snapshotInterval time.Duration) *resultTracker {
+	store := make(map[string]string)
+	return &resultTracker{
+		snapshotInterval: snapshotInterval,
+		read: func(key string) (string, error) {
+			return store[key], nil
+		},
+		write: func(key, value string) error {
+			store[key] = value
+			return nil
+		},
+	}
+}
+
+func newResultEvent(resourceID, result string) *event.Event {
+	return &event.Event{
+		AgentRuleID:      "rule-id",
+		AgentFrameworkID: "cis",
+		ResourceType:     "file",
+		ResourceID:       resourceID,
+		Result:           result,
+	}
+}
+
+func TestResultTrackerTransitions(t *testing.T) {
+	assert := assert.New(t)
+
+	tracker := newTestResultTracker(time.Hour)
+	now := time.Now()
+
+	// the first run is a snapshot where every resource is new
+	reported := tracker.filter("cis", "rule-id", []*event.Event{
+		newResultEvent("a", event.Passed),
+		newResultEvent("b", event.Passed),
+		newResultEvent("c", event.Failed),
+	}, now)
+	assert.Len(reported, 3)
+	for _, e := range reported {
+		assert.Equal(event.TransitionNew, e.Transition)
+	}
+
+	// unchanged results are not reported
+	reported = tracker.filter("cis", "rule-id", []*event.Event{
+		newResultEvent("a", event.Passed),
+		newResultEvent("b", event.Passed),
+		newResultEvent("c", event.Failed),
+	}, now.Add(20*time.Minute))
+	assert.Empty(reported)
+
+	// transitions are reported right away
+	reported = tracker.filter("cis", "rule-id", []*event.Event{
+		newResultEvent("a", event.Failed),
+		newResultEvent("c", event.Passed),
+		newResultEvent("d", event.Passed),
+	}, now.Add(40*time.Minute))
+	assert.Len(reported, 4)
+
+	assert.Equal("a", reported[0].ResourceID)
+	assert.Equal(event.TransitionChanged, reported[0].Transition)
+	assert.Equal(event.Passed, reported[0].PreviousResult)
+
+	assert.Equal("c", reported[1].ResourceID)
+	assert.Equal(event.TransitionChanged, reported[1].Transition)
+	assert.Equal(event.Failed, reported[1].PreviousResult)
+
+	assert.Equal("d", reported[2].ResourceID)
+	assert.Equal(event.TransitionNew, reported[2].Transition)
+
+	assert.Equal("b", reported[3].ResourceID)
+	assert.Equal(event.TransitionGone, reported[3].Transition)
+	assert.Equal("rule-id", reported[3].AgentRuleID)
+	assert.False(reported[3].ExpireAt.After(now.Add(40 * time.Minute)))
+
+	// the full result set is reported once the snapshot interval is over
+	reported = tracker.filter("cis", "rule-id", []*event.Event{
+		newResultEvent("a", event.Failed),
+		newResultEvent("c", event.Passed),
+		newResultEvent("d", event.Passed),
+	}, now.Add(time.Hour))
+	assert.Len(reported, 3)
+	for _, e := range reported {
+		assert.Empty(e.Transition)
+	}
+}
+
+func TestResultTrackerRulesAreIsolated(t *testing.T) {
+	assert := assert.New(t)
+
+	tracker := newTestResultTracker(time.Hour)
+	now := time.Now()
+
+	tracker.filter("cis", "rule-1", []*event.Event{newResultEvent("a", event.Passed)}, now)
+
+	reported := tracker.filter("cis", "rule-2", []*event.Event{newResultEvent("a", event.Failed)}, now)
+	assert.Len(reported, 1)
+	assert.Equal(event.TransitionNew, reported[0].Transition)
+}
+
+func TestResultTrackerPersistentCache(t *testing.T) {
+	assert := assert.New(t)
+
+	mockConfig := config.Mock(t)
+	mockConfig.Set("run_path", t.TempDir())
+
+	now := time.Now()
+
+	// the state survives a restart of the agent
+	newResultTracker(time.Hour).filter("cis", "rule-id", []*event.Event{newResultEvent("a", event.Passed)}, now)
+	reported := newResultTracker(time.Hour).filter("cis", "rule-id", []*event.Event{newResultEvent("a", event.Passed)}, now.Add(time.Minute))
+	assert.Empty(reported)
+}
+
+func TestResultTrackerPersistentCacheRuleIDs(t *testing.T) {
+	assert := assert.New(t)
+
+	mockConfig := config.Mock(t)
+	mockConfig.Set("run_path", t.TempDir())
+
+	now := time.Now()
+
+	// rule IDs only differing by characters not allowed in file names are kept apart
+	newResultTracker(time.Hour).filter("cis", "cis-docker-1.1", []*event.Event{newResultEvent("a", event.Passed)}, now)
+	reported := newResultTracker(time.Hour).filter("cis", "cis-docker-11", []*event.Event{newResultEvent("a", event.Passed)}, now)
+	assert.Len(reported, 1)
+	assert.Equal(event.TransitionNew, reported[0].Transition)
+}
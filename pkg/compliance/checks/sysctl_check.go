@@ -0,0 +1,86 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package checks
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/DataDog/datadog-agent/pkg/compliance"
+	"github.com/DataDog/datadog-agent/pkg/compliance/checks/env"
+	"github.com/DataDog/datadog-agent/pkg/compliance/eval"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+const procSysPath = "/proc/sys"
+
+var sysctlReportedFields = []string{
+	compliance.SysctlFieldName,
+	compliance.SysctlFieldValue,
+}
+
+func resolveSysctl(_ context.Context, e env.Env, id string, res compliance.ResourceCommon, rego bool) (resolved, error) {
+	if res.Sysctl == nil {
+		return nil, fmt.Errorf("%s: expecting sysctl resource in sysctl check", id)
+	}
+
+	sysctl := res.Sysctl
+
+	log.Debugf("%s: running sysctl check: %s", id, sysctl.Name)
+
+	path, err := sysctlPath(sysctl.Name)
+	if err != nil {
+		return nil, wrapErrorWithID(id, err)
+	}
+
+	content, err := ioutil.ReadFile(e.NormalizeToHostRoot(path))
+	if err != nil {
+		if os.IsNotExist(err) && rego {
+			return nil, nil
+		}
+		return nil, wrapErrorWithID(id, err)
+	}
+
+	// values made of several fields, such as net.ipv4.ip_local_port_range,
+	// are separated by tabulations
+	value := strings.Join(strings.Fields(string(content)), " ")
+
+	instance := eval.NewInstance(
+		eval.VarMap{
+			compliance.SysctlFieldName:  sysctl.Name,
+			compliance.SysctlFieldValue: value,
+		},
+		nil,
+		eval.RegoInputMap{
+			"name":  sysctl.Name,
+			"value": value,
+		},
+	)
+
+	return newResolvedInstance(instance, sysctl.Name, "sysctl"), nil
+}
+
+// sysctlPath returns the path under /proc/sys of a kernel parameter, given
+// either with dots or slashes as sysctl accepts
+func sysctlPath(name string) (string, error) {
+	if name == "" {
+		return "", fmt.Errorf("sysctl resource is missing name")
+	}
+
+	if !strings.Contains(name, "/") {
+		name = strings.ReplaceAll(name, ".", "/")
+	}
+
+	path := filepath.Join(procSysPath, name)
+	if !strings.HasPrefix(path, procSysPath+"/") {
+		return "", fmt.Errorf("invalid sysctl name `%s`", name)
+	}
+	return path, nil
+}
@@ -16,6 +16,15 @@ const (
 	Error = "error"
 )
 
+const (
+	// TransitionNew is used when a resource is reported for the first time by a rule
+	TransitionNew = "new"
+	// TransitionChanged is used when the result of a resource changed since the previous run of a rule
+	TransitionChanged = "changed"
+	// TransitionGone is used when a resource is no longer evaluated by a rule
+	TransitionGone = "gone"
+)
+
 // Data defines a key value map for storing attributes of a reported rule event
 type Data map[string]interface{}
 
@@ -32,4 +41,6 @@ type Event struct {
 	Data             interface{} `json:"data,omitempty"`
 	ExpireAt         time.Time   `json:"expire_at,omitempty"`
 	Evaluator        string      `json:"evaluator,omitempty"`
+	Transition       string      `json:"transition,omitempty"`
+	PreviousResult   string      `json:"previous_result,omitempty"`
 }
@@ -0,0 +1,214 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// Package export writes the results of a compliance run in machine-readable formats
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/compliance"
+	"github.com/DataDog/datadog-agent/pkg/compliance/event"
+)
+
+// Format is the format of an exported report
+type Format string
+
+const (
+	// FormatJSON exports the run as a JSON document
+	FormatJSON Format = "json"
+	// FormatJUnit exports the run as a JUnit XML document, one test case per rule and resource
+	FormatJUnit Format = "junit"
+	// FormatSARIF exports the run as a SARIF 2.1.0 log
+	FormatSARIF Format = "sarif"
+)
+
+// ResultSkipped is the result of a rule that didn't report any event, usually
+// because it doesn't apply to the host
+const ResultSkipped = "skipped"
+
+// ParseFormat returns the format matching its name
+func ParseFormat(name string) (Format, error) {
+	switch format := Format(name); format {
+	case FormatJSON, FormatJUnit, FormatSARIF:
+		return format, nil
+	default:
+		return "", fmt.Errorf("invalid report format `%s`, expecting one of json, junit or sarif", name)
+	}
+}
+
+// RuleResult holds the events reported for a rule during a run
+type RuleResult struct {
+	RuleID      string         `json:"rule_id"`
+	Name        string         `json:"name"`
+	Description string         `json:"description,omitempty"`
+	Remediation string         `json:"remediation,omitempty"`
+	Framework   string         `json:"framework,omitempty"`
+	Version     string         `json:"version,omitempty"`
+	Result      string         `json:"result"`
+	Error       string         `json:"error,omitempty"`
+	Events      []*event.Event `json:"events"`
+}
+
+// Summary counts the results of a run
+type Summary struct {
+	Rules   int `json:"rules"`
+	Passed  int `json:"passed"`
+	Failed  int `json:"failed"`
+	Errors  int `json:"errors"`
+	Skipped int `json:"skipped"`
+}
+
+// Run holds the results of all the rules of a run
+type Run struct {
+	Hostname     string        `json:"hostname"`
+	AgentVersion string        `json:"agent_version"`
+	Start        time.Time     `json:"start"`
+	End          time.Time     `json:"end"`
+	Summary      Summary       `json:"summary"`
+	Rules        []*RuleResult `json:"rules"`
+}
+
+// NewRun returns the run made of the given checks and the events they reported, indexed by rule ID
+func NewRun(hostname, agentVersion string, start, end time.Time, statuses compliance.CheckStatusList, events map[string][]*event.Event) *Run {
+	run := &Run{
+		Hostname:     hostname,
+		AgentVersion: agentVersion,
+		Start:        start,
+		End:          end,
+	}
+
+	seen := make(map[string]bool)
+	for _, status := range statuses {
+		seen[status.RuleID] = true
+
+		rule := &RuleResult{
+			RuleID:      status.RuleID,
+			Name:        status.Name,
+			Description: status.Description,
+			Remediation: status.Remediation,
+			Framework:   status.Framework,
+			Version:     status.Version,
+			Events:      events[status.RuleID],
+		}
+		if status.InitError != nil {
+			rule.Error = status.InitError.Error()
+		}
+		run.addRule(rule)
+	}
+
+	// events of rules without status, which shouldn't happen, are still exported
+	for ruleID, ruleEvents := range events {
+		if !seen[ruleID] && len(ruleEvents) > 0 {
+			run.addRule(&RuleResult{
+				RuleID:    ruleID,
+				Name:      ruleID,
+				Framework: ruleEvents[0].AgentFrameworkID,
+				Events:    ruleEvents,
+			})
+		}
+	}
+
+	return run
+}
+
+func (r *Run) addRule(rule *RuleResult) {
+	if rule.Events == nil {
+		rule.Events = []*event.Event{}
+	}
+	rule.Result = ruleResult(rule)
+
+	r.Summary.Rules++
+	switch rule.Result {
+	case event.Passed:
+		r.Summary.Passed++
+	case event.Failed:
+		r.Summary.Failed++
+	case event.Error:
+		r.Summary.Errors++
+	default:
+		r.Summary.Skipped++
+	}
+
+	r.Rules = append(r.Rules, rule)
+}
+
+// ruleResult returns the worst result of the events of a rule
+func ruleResult(rule *RuleResult) string {
+	if rule.Error != "" {
+		return event.Error
+	}
+
+	result := ResultSkipped
+	for _, e := range rule.Events {
+		switch e.Result {
+		case event.Error:
+			return event.Error
+		case event.Failed:
+			result = event.Failed
+		case event.Passed:
+			if result == ResultSkipped {
+				result = event.Passed
+			}
+		}
+	}
+	return result
+}
+
+// Write writes the run to w in the given format
+func (r *Run) Write(w io.Writer, format Format) error {
+	switch format {
+	case FormatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "\t")
+		return encoder.Encode(r)
+	case FormatJUnit:
+		return r.writeJUnit(w)
+	case FormatSARIF:
+		return r.writeSARIF(w)
+	default:
+		return fmt.Errorf("invalid report format `%s`", format)
+	}
+}
+
+// WriteFile writes the run to the file at path in the given format
+func (r *Run) WriteFile(path string, format Format) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+
+	if err := r.Write(f, format); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// eventError returns the evaluation error of an event, if any
+func eventError(e *event.Event) string {
+	if e.Result != event.Error {
+		return ""
+	}
+
+	if data, ok := e.Data.(event.Data); ok {
+		if err, ok := data["error"].(string); ok {
+			return err
+		}
+	}
+	return "evaluation error"
+}
+
+// resourceName returns a human readable identifier of the resource of an event
+func resourceName(e *event.Event) string {
+	if e.ResourceType == "" {
+		return e.ResourceID
+	}
+	return e.ResourceType + ":" + e.ResourceID
+}
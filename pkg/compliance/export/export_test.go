@@ -0,0 +1,183 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/pkg/compliance"
+	"github.com/DataDog/datadog-agent/pkg/compliance/event"
+)
+
+func newTestRun() *Run {
+	start := time.Date(2021, 12, 1, 10, 0, 0, 0, time.UTC)
+
+	statuses := compliance.CheckStatusList{
+		{
+			RuleID:      "cis-docker-1",
+			Name:        "cis-docker-1: docker daemon configuration",
+			Description: "docker daemon configuration",
+			Remediation: "Set tlsverify to true in /etc/docker/daemon.json",
+			Framework:   "cis-docker",
+			Version:     "1.2.0",
+		},
+		{
+			RuleID:    "cis-docker-2",
+			Name:      "cis-docker-2",
+			Framework: "cis-docker",
+			Version:   "1.2.0",
+		},
+		{
+			RuleID:    "cis-docker-3",
+			Name:      "cis-docker-3",
+			Framework: "cis-docker",
+			Version:   "1.2.0",
+			InitError: errors.New("docker client not initialized"),
+		},
+		{
+			RuleID:    "cis-docker-4",
+			Name:      "cis-docker-4",
+			Framework: "cis-docker",
+			Version:   "1.2.0",
+		},
+	}
+
+	events := map[string][]*event.Event{
+		"cis-docker-1": {
+			{
+				AgentRuleID:      "cis-docker-1",
+				AgentFrameworkID: "cis-docker",
+				Result:           event.Passed,
+				ResourceType:     "docker_daemon",
+				ResourceID:       "host-1",
+			},
+			{
+				AgentRuleID:      "cis-docker-1",
+				AgentFrameworkID: "cis-docker",
+				Result:           event.Failed,
+				ResourceType:     "docker_container",
+				ResourceID:       "3f8a61d2c94b",
+				Data:             event.Data{"container.name": "web"},
+			},
+		},
+		"cis-docker-2": {
+			{
+				AgentRuleID:      "cis-docker-2",
+				AgentFrameworkID: "cis-docker",
+				Result:           event.Error,
+				ResourceType:     "docker_daemon",
+				ResourceID:       "host-1",
+				Data:             event.Data{"error": "failed to parse"},
+			},
+		},
+	}
+
+	return NewRun("host-1", "7.34.0", start, start.Add(1500*time.Millisecond), statuses, events)
+}
+
+func TestParseFormat(t *testing.T) {
+	for _, name := range []string{"json", "junit", "sarif"} {
+		format, err := ParseFormat(name)
+		assert.NoError(t, err)
+		assert.Equal(t, Format(name), format)
+	}
+
+	_, err := ParseFormat("xml")
+	assert.Error(t, err)
+}
+
+func TestNewRun(t *testing.T) {
+	run := newTestRun()
+
+	require.Len(t, run.Rules, 4)
+	assert.Equal(t, event.Failed, run.Rules[0].Result)
+	assert.Equal(t, "Set tlsverify to true in /etc/docker/daemon.json", run.Rules[0].Remediation)
+	assert.Equal(t, event.Error, run.Rules[1].Result)
+	assert.Equal(t, event.Error, run.Rules[2].Result)
+	assert.Equal(t, "docker client not initialized", run.Rules[2].Error)
+	assert.Equal(t, ResultSkipped, run.Rules[3].Result)
+	assert.Equal(t, Summary{Rules: 4, Failed: 1, Errors: 2, Skipped: 1}, run.Summary)
+}
+
+func TestWriteJSON(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, newTestRun().Write(&buf, FormatJSON))
+
+	var run Run
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &run))
+	assert.Equal(t, "host-1", run.Hostname)
+	assert.Len(t, run.Rules, 4)
+	assert.Len(t, run.Rules[0].Events, 2)
+	assert.Equal(t, "3f8a61d2c94b", run.Rules[0].Events[1].ResourceID)
+	assert.Equal(t, 1, run.Summary.Failed)
+}
+
+func TestWriteJUnit(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, newTestRun().Write(&buf, FormatJUnit))
+
+	var suites junitTestSuites
+	require.NoError(t, xml.Unmarshal(buf.Bytes(), &suites))
+
+	assert.Equal(t, 5, suites.Tests)
+	assert.Equal(t, 1, suites.Failures)
+	assert.Equal(t, 2, suites.Errors)
+	assert.Equal(t, 1, suites.Skipped)
+	assert.Equal(t, "1.500", suites.Time)
+
+	require.Len(t, suites.Suites, 1)
+	suite := suites.Suites[0]
+	assert.Equal(t, "cis-docker 1.2.0", suite.Name)
+	assert.Equal(t, "host-1", suite.Hostname)
+
+	require.Len(t, suite.Cases, 5)
+	assert.Nil(t, suite.Cases[0].Failure)
+	require.NotNil(t, suite.Cases[1].Failure)
+	assert.Equal(t, "cis-docker-1: docker daemon configuration [docker_container:3f8a61d2c94b]", suite.Cases[1].Name)
+	assert.Contains(t, suite.Cases[1].Failure.Content, "Remediation: Set tlsverify to true")
+	require.NotNil(t, suite.Cases[2].Error)
+	assert.Equal(t, "failed to parse", suite.Cases[2].Error.Message)
+	require.NotNil(t, suite.Cases[3].Error)
+	assert.Equal(t, "docker client not initialized", suite.Cases[3].Error.Message)
+	assert.NotNil(t, suite.Cases[4].Skipped)
+}
+
+func TestWriteSARIF(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, newTestRun().Write(&buf, FormatSARIF))
+
+	var log sarifLog
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &log))
+
+	assert.Equal(t, "2.1.0", log.Version)
+	require.Len(t, log.Runs, 1)
+	run := log.Runs[0]
+
+	assert.Equal(t, "7.34.0", run.Tool.Driver.Version)
+	require.Len(t, run.Tool.Driver.Rules, 4)
+	assert.Equal(t, "Set tlsverify to true in /etc/docker/daemon.json", run.Tool.Driver.Rules[0].Help.Text)
+
+	require.Len(t, run.Results, 3)
+	assert.Equal(t, "pass", run.Results[0].Kind)
+	assert.Equal(t, "fail", run.Results[1].Kind)
+	assert.Equal(t, "error", run.Results[1].Level)
+	assert.Equal(t, "3f8a61d2c94b", run.Results[1].Locations[0].LogicalLocations[0].Name)
+	assert.Equal(t, "review", run.Results[2].Kind)
+	assert.Equal(t, 1, run.Results[2].RuleIndex)
+
+	require.Len(t, run.Invocations, 1)
+	assert.False(t, run.Invocations[0].ExecutionSuccessful)
+	require.Len(t, run.Invocations[0].ToolExecutionNotifications, 1)
+	assert.Equal(t, "cis-docker-3", run.Invocations[0].ToolExecutionNotifications[0].Descriptor.ID)
+}
@@ -0,0 +1,172 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package export
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/compliance/event"
+)
+
+type junitTestSuites struct {
+	XMLName  xml.Name          `xml:"testsuites"`
+	Name     string            `xml:"name,attr"`
+	Tests    int               `xml:"tests,attr"`
+	Failures int               `xml:"failures,attr"`
+	Errors   int               `xml:"errors,attr"`
+	Skipped  int               `xml:"skipped,attr"`
+	Time     string            `xml:"time,attr"`
+	Suites   []*junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string           `xml:"name,attr"`
+	Tests     int              `xml:"tests,attr"`
+	Failures  int              `xml:"failures,attr"`
+	Errors    int              `xml:"errors,attr"`
+	Skipped   int              `xml:"skipped,attr"`
+	Timestamp string           `xml:"timestamp,attr"`
+	Hostname  string           `xml:"hostname,attr"`
+	Cases     []*junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Error     *junitMessage `xml:"error,omitempty"`
+	Skipped   *junitMessage `xml:"skipped,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr,omitempty"`
+	Type    string `xml:"type,attr,omitempty"`
+	Content string `xml:",chardata"`
+}
+
+// writeJUnit writes the run with one test suite per framework, and one test
+// case per rule and resource
+func (r *Run) writeJUnit(w io.Writer) error {
+	suites := &junitTestSuites{
+		Name: "compliance",
+		Time: fmt.Sprintf("%.3f", r.End.Sub(r.Start).Seconds()),
+	}
+
+	byFramework := make(map[string]*junitTestSuite)
+	for _, rule := range r.Rules {
+		suite, found := byFramework[rule.Framework]
+		if !found {
+			name := rule.Framework
+			if rule.Version != "" {
+				name += " " + rule.Version
+			}
+			suite = &junitTestSuite{
+				Name:      name,
+				Timestamp: r.Start.UTC().Format(time.RFC3339),
+				Hostname:  r.Hostname,
+			}
+			byFramework[rule.Framework] = suite
+			suites.Suites = append(suites.Suites, suite)
+		}
+
+		for _, testCase := range junitRuleCases(rule) {
+			suite.Tests++
+			switch {
+			case testCase.Failure != nil:
+				suite.Failures++
+			case testCase.Error != nil:
+				suite.Errors++
+			case testCase.Skipped != nil:
+				suite.Skipped++
+			}
+			suite.Cases = append(suite.Cases, testCase)
+		}
+	}
+
+	for _, suite := range suites.Suites {
+		suites.Tests += suite.Tests
+		suites.Failures += suite.Failures
+		suites.Errors += suite.Errors
+		suites.Skipped += suite.Skipped
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "\t")
+	if err := encoder.Encode(suites); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func junitRuleCases(rule *RuleResult) []*junitTestCase {
+	className := rule.Framework
+	if className == "" {
+		className = "compliance"
+	}
+
+	if rule.Error != "" {
+		return []*junitTestCase{{
+			Name:      rule.Name,
+			ClassName: className,
+			Error:     &junitMessage{Message: rule.Error, Type: event.Error},
+		}}
+	}
+
+	if len(rule.Events) == 0 {
+		return []*junitTestCase{{
+			Name:      rule.Name,
+			ClassName: className,
+			Skipped:   &junitMessage{Message: "no resource evaluated"},
+		}}
+	}
+
+	var cases []*junitTestCase
+	for _, e := range rule.Events {
+		testCase := &junitTestCase{
+			Name:      rule.Name + " [" + resourceName(e) + "]",
+			ClassName: className,
+		}
+
+		switch e.Result {
+		case event.Failed:
+			testCase.Failure = &junitMessage{
+				Message: rule.Description,
+				Type:    event.Failed,
+				Content: junitFailureContent(rule, e),
+			}
+		case event.Error:
+			testCase.Error = &junitMessage{
+				Message: eventError(e),
+				Type:    event.Error,
+			}
+		}
+		cases = append(cases, testCase)
+	}
+	return cases
+}
+
+func junitFailureContent(rule *RuleResult, e *event.Event) string {
+	var lines []string
+	if rule.Remediation != "" {
+		lines = append(lines, "Remediation: "+rule.Remediation)
+	}
+	if e.Data != nil {
+		if data, err := json.Marshal(e.Data); err == nil {
+			lines = append(lines, "Data: "+string(data))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,198 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package export
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/compliance/event"
+)
+
+const (
+	sarifVersion  = "2.1.0"
+	sarifSchema   = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifToolName = "datadog-security-agent"
+	sarifToolURI  = "https://docs.datadoghq.com/security_platform/cspm/"
+)
+
+type sarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool        sarifTool         `json:"tool"`
+	Invocations []sarifInvocation `json:"invocations"`
+	Results     []sarifResult     `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version,omitempty"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string                 `json:"id"`
+	Name             string                 `json:"name,omitempty"`
+	ShortDescription *sarifMessage          `json:"shortDescription,omitempty"`
+	Help             *sarifMessage          `json:"help,omitempty"`
+	Properties       map[string]interface{} `json:"properties,omitempty"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifInvocation struct {
+	ExecutionSuccessful        bool                `json:"executionSuccessful"`
+	StartTimeUTC               string              `json:"startTimeUtc"`
+	EndTimeUTC                 string              `json:"endTimeUtc"`
+	Machine                    string              `json:"machine,omitempty"`
+	ToolExecutionNotifications []sarifNotification `json:"toolExecutionNotifications,omitempty"`
+}
+
+type sarifNotification struct {
+	Level      string              `json:"level"`
+	Message    sarifMessage        `json:"message"`
+	Descriptor *sarifDescriptorRef `json:"descriptor,omitempty"`
+}
+
+type sarifDescriptorRef struct {
+	ID string `json:"id"`
+}
+
+type sarifResult struct {
+	RuleID     string                 `json:"ruleId"`
+	RuleIndex  int                    `json:"ruleIndex"`
+	Kind       string                 `json:"kind"`
+	Level      string                 `json:"level"`
+	Message    sarifMessage           `json:"message"`
+	Locations  []sarifLocation        `json:"locations,omitempty"`
+	Properties map[string]interface{} `json:"properties,omitempty"`
+}
+
+type sarifLocation struct {
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations"`
+}
+
+type sarifLogicalLocation struct {
+	Name string `json:"name"`
+	Kind string `json:"kind,omitempty"`
+}
+
+// writeSARIF writes the run as a SARIF log. Passed checks are reported with
+// the pass kind, failed checks as errors and evaluation errors as results
+// to review. SARIF only allows a level on failures.
+func (r *Run) writeSARIF(w io.Writer) error {
+	run := sarifRun{
+		Tool: sarifTool{
+			Driver: sarifDriver{
+				Name:           sarifToolName,
+				Version:        r.AgentVersion,
+				InformationURI: sarifToolURI,
+				Rules:          []sarifRule{},
+			},
+		},
+		Results: []sarifResult{},
+	}
+
+	invocation := sarifInvocation{
+		ExecutionSuccessful: true,
+		StartTimeUTC:        r.Start.UTC().Format(time.RFC3339),
+		EndTimeUTC:          r.End.UTC().Format(time.RFC3339),
+		Machine:             r.Hostname,
+	}
+
+	for index, rule := range r.Rules {
+		descriptor := sarifRule{
+			ID:   rule.RuleID,
+			Name: rule.Name,
+			Properties: map[string]interface{}{
+				"framework": rule.Framework,
+				"version":   rule.Version,
+			},
+		}
+		if rule.Description != "" {
+			descriptor.ShortDescription = &sarifMessage{Text: rule.Description}
+		}
+		if rule.Remediation != "" {
+			descriptor.Help = &sarifMessage{Text: rule.Remediation}
+		}
+		run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, descriptor)
+
+		if rule.Error != "" {
+			invocation.ExecutionSuccessful = false
+			invocation.ToolExecutionNotifications = append(invocation.ToolExecutionNotifications, sarifNotification{
+				Level:      "error",
+				Message:    sarifMessage{Text: rule.Error},
+				Descriptor: &sarifDescriptorRef{ID: rule.RuleID},
+			})
+			continue
+		}
+
+		for _, e := range rule.Events {
+			run.Results = append(run.Results, sarifEventResult(rule, index, e))
+		}
+	}
+
+	run.Invocations = []sarifInvocation{invocation}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "\t")
+	return encoder.Encode(&sarifLog{
+		Version: sarifVersion,
+		Schema:  sarifSchema,
+		Runs:    []sarifRun{run},
+	})
+}
+
+func sarifEventResult(rule *RuleResult, index int, e *event.Event) sarifResult {
+	result := sarifResult{
+		RuleID:    rule.RuleID,
+		RuleIndex: index,
+		Locations: []sarifLocation{{
+			LogicalLocations: []sarifLogicalLocation{{
+				Name: e.ResourceID,
+				Kind: e.ResourceType,
+			}},
+		}},
+	}
+
+	if e.Data != nil {
+		result.Properties = map[string]interface{}{
+			"data": e.Data,
+		}
+	}
+
+	switch e.Result {
+	case event.Passed:
+		result.Kind = "pass"
+		result.Level = "none"
+		result.Message.Text = rule.Name + ": passed on " + resourceName(e)
+	case event.Failed:
+		result.Kind = "fail"
+		result.Level = "error"
+		result.Message.Text = rule.Name + ": failed on " + resourceName(e)
+		if rule.Remediation != "" {
+			result.Message.Text += ". " + rule.Remediation
+		}
+	default:
+		result.Kind = "review"
+		result.Level = "none"
+		result.Message.Text = rule.Name + ": " + eventError(e)
+	}
+
+	return result
+}
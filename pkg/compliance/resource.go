@@ -34,6 +34,10 @@ const (
 	KindConstants = ResourceKind("constants")
 	// KindCustom is used for a Custom check
 	KindCustom = ResourceKind("custom")
+	// KindPackage is used for a Package resource
+	KindPackage = ResourceKind("package")
+	// KindSysctl is used for a Sysctl resource
+	KindSysctl = ResourceKind("sysctl")
 )
 
 // ResourceCommon describes the base fields of resource types
@@ -47,6 +51,8 @@ type ResourceCommon struct {
 	KubeApiserver *KubernetesResource `yaml:"kubeApiserver,omitempty"`
 	Constants     *ConstantsResource  `yaml:"constants,omitempty"`
 	Custom        *Custom             `yaml:"custom,omitempty"`
+	Package       *Package            `yaml:"package,omitempty"`
+	Sysctl        *Sysctl             `yaml:"sysctl,omitempty"`
 }
 
 // Resource describes supported resource types observed by a Rule
@@ -96,6 +102,10 @@ func (r *ResourceCommon) Kind() ResourceKind {
 		return KindConstants
 	case r.Custom != nil:
 		return KindCustom
+	case r.Package != nil:
+		return KindPackage
+	case r.Sysctl != nil:
+		return KindSysctl
 	default:
 		return KindInvalid
 	}
@@ -301,3 +311,40 @@ type Custom struct {
 	Name      string            `yaml:"name"`
 	Variables map[string]string `yaml:"variables,omitempty"`
 }
+
+// Package managers supported by Package resources
+const (
+	PackageManagerDpkg = "dpkg"
+	PackageManagerRpm  = "rpm"
+	PackageManagerApk  = "apk"
+)
+
+// Fields & functions available for Package
+const (
+	PackageFieldName      = "package.name"
+	PackageFieldVersion   = "package.version"
+	PackageFieldManager   = "package.manager"
+	PackageFieldInstalled = "package.installed"
+
+	PackageFuncVersionCompare = "package.versionCompare"
+)
+
+// Package describes a package installed on the host, looked up in the
+// database of the package manager
+type Package struct {
+	Name string `yaml:"name"`
+	// Manager is one of dpkg, rpm or apk. Defaults to the first package
+	// manager whose database is found on the host.
+	Manager string `yaml:"manager,omitempty"`
+}
+
+// Fields available for Sysctl
+const (
+	SysctlFieldName  = "sysctl.name"
+	SysctlFieldValue = "sysctl.value"
+)
+
+// Sysctl describes a kernel parameter read from /proc/sys
+type Sysctl struct {
+	Name string `yaml:"name"`
+}
@@ -18,6 +18,7 @@ type Rule interface {
 type RuleCommon struct {
 	ID           string        `yaml:"id"`
 	Description  string        `yaml:"description,omitempty"`
+	Remediation  string        `yaml:"remediation,omitempty"`
 	Scope        RuleScopeList `yaml:"scope,omitempty"`
 	HostSelector string        `yaml:"hostSelector,omitempty"`
 	SkipOnK8s    bool          `yaml:"skipOnKubernetes,omitempty"`
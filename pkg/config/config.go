@@ -328,6 +328,14 @@ func InitConfig(config Config) {
 	config.BindEnvAndSetDefault("c_core_dump", false)
 	config.BindEnvAndSetDefault("go_core_dump", false)
 	config.BindEnvAndSetDefault("memtrack_enabled", true)
+	// Per-check resource limits for Python checks, 0 disables them. Exceeding a
+	// soft limit adds a warning to the check, exceeding a hard limit unschedules it.
+	// Memory limits are in bytes and rely on memtrack_enabled, CPU time limits are
+	// in seconds and apply to every run.
+	config.BindEnvAndSetDefault("python_check_memory_soft_limit", 0)
+	config.BindEnvAndSetDefault("python_check_memory_hard_limit", 0)
+	config.BindEnvAndSetDefault("python_check_cpu_time_soft_limit", 0)
+	config.BindEnvAndSetDefault("python_check_cpu_time_hard_limit", 0)
 	config.BindEnvAndSetDefault("tracemalloc_debug", false)
 	config.BindEnvAndSetDefault("tracemalloc_include", "")
 	config.BindEnvAndSetDefault("tracemalloc_exclude", "")
@@ -385,6 +393,12 @@ func InitConfig(config Config) {
 
 	// Yaml keys which values are stripped from flare
 	config.BindEnvAndSetDefault("flare_stripped_keys", []string{})
+	// Additional regular expressions whose matches are redacted from flare
+	config.BindEnvAndSetDefault("flare_additional_scrubber_patterns", []string{})
+	// Flare profile selecting the optional sections of the flare: "full",
+	// "minimal" or the name of one of the profiles defined in flare_profiles
+	config.BindEnvAndSetDefault("flare_profile", "full")
+	config.BindEnvAndSetDefault("flare_profiles", map[string][]string{})
 
 	// Agent GUI access port
 	config.BindEnvAndSetDefault("GUI_port", defaultGuiPort)
@@ -586,6 +600,7 @@ func InitConfig(config Config) {
 	config.BindEnvAndSetDefault("container_exclude_stopped_age", DefaultAuditorTTL-1) // in hours
 	config.BindEnvAndSetDefault("ad_config_poll_interval", int64(10))                 // in seconds
 	config.BindEnvAndSetDefault("extra_listeners", []string{})
+	config.BindEnvAndSetDefault("ad_process_listener_poll_interval", 30) // in seconds
 	config.BindEnvAndSetDefault("extra_config_providers", []string{})
 	config.BindEnvAndSetDefault("ignore_autoconf", []string{})
 	config.BindEnvAndSetDefault("autoconfig_from_environment", true)
@@ -835,6 +850,8 @@ func InitConfig(config Config) {
 	config.BindEnv("logs_config.processing_rules")
 	// enforce the agent to use files to collect container logs on kubernetes environment
 	config.BindEnvAndSetDefault("logs_config.k8s_container_use_file", false)
+	// collect the failures of the containers of the pods running on the node (terminations, image pull errors...) as logs
+	config.BindEnvAndSetDefault("logs_config.pod_failures_collect", false)
 	// Enable the agent to use files to collect container logs on standalone docker environment, containers
 	// with an existing registry offset will continue to be tailed from the docker socket unless
 	// logs_config.docker_container_force_use_file is set to true.
@@ -944,6 +961,8 @@ func InitConfig(config Config) {
 	config.BindEnvAndSetDefault("cluster_checks.cluster_tag_name", "cluster_name")
 	config.BindEnvAndSetDefault("cluster_checks.extra_tags", []string{})
 	config.BindEnvAndSetDefault("cluster_checks.advanced_dispatching_enabled", false)
+	config.BindEnvAndSetDefault("cluster_checks.zone_label", "topology.kubernetes.io/zone")
+	config.BindEnvAndSetDefault("cluster_checks.report_node_labels", true)
 	config.BindEnvAndSetDefault("cluster_checks.clc_runners_port", 5005)
 	// Cluster check runner
 	config.BindEnvAndSetDefault("clc_runner_enabled", false)
@@ -978,6 +997,13 @@ func InitConfig(config Config) {
 	config.BindEnvAndSetDefault("admission_controller.auto_instrumentation.enabled", true)
 	config.BindEnvAndSetDefault("admission_controller.auto_instrumentation.endpoint", "/injectlib")
 	config.BindEnvAndSetDefault("admission_controller.auto_instrumentation.container_registry", "gcr.io/datadoghq")
+	config.BindEnvAndSetDefault("admission_controller.auto_instrumentation.namespaces", map[string]string{})       // namespace -> language of the library to inject into all its pods
+	config.BindEnvAndSetDefault("admission_controller.auto_instrumentation.default_versions", map[string]string{}) // language -> version of the library injected through the namespace opt-in
+	config.BindEnvAndSetDefault("admission_controller.auto_instrumentation.dry_run", false)
+	config.BindEnvAndSetDefault("admission_controller.validate_annotations.enabled", false)
+	config.BindEnvAndSetDefault("admission_controller.validate_annotations.endpoint", "/validateannotations")
+	config.BindEnvAndSetDefault("admission_controller.validate_annotations.mode", "warn") // possible values: warn / reject
+	config.BindEnvAndSetDefault("admission_controller.validate_annotations.known_checks", []string{})
 
 	// Telemetry
 	// Enable telemetry metrics on the internals of the Agent.
@@ -1060,6 +1086,8 @@ func InitConfig(config Config) {
 	config.BindEnvAndSetDefault("compliance_config.enabled", false)
 	config.BindEnvAndSetDefault("compliance_config.check_interval", 20*time.Minute)
 	config.BindEnvAndSetDefault("compliance_config.check_max_events_per_run", 100)
+	config.BindEnvAndSetDefault("compliance_config.change_only_reporting.enabled", false)
+	config.BindEnvAndSetDefault("compliance_config.change_only_reporting.snapshot_interval", 4*time.Hour)
 	config.BindEnvAndSetDefault("compliance_config.dir", "/etc/datadog-agent/compliance.d")
 	config.BindEnvAndSetDefault("compliance_config.run_path", defaultRunPath)
 	config.BindEnv("compliance_config.run_commands_as")
@@ -1134,6 +1162,7 @@ func InitConfig(config Config) {
 	config.BindEnvAndSetDefault("capture_lambda_payload", false)
 	config.BindEnvAndSetDefault("serverless.trace_enabled", false, "DD_TRACE_ENABLED")
 	config.BindEnvAndSetDefault("serverless.trace_managed_services", false, "DD_TRACE_MANAGED_SERVICES")
+	config.BindEnvAndSetDefault("serverless.trace_propagation_style_extract", []string{"datadog", "tracecontext", "b3multi", "b3"}, "DD_TRACE_PROPAGATION_STYLE_EXTRACT")
 
 	// trace-agent's evp_proxy
 	config.BindEnv("evp_proxy_config.enabled")
@@ -1256,7 +1285,8 @@ func LoadWithoutSecret() (*Warnings, error) {
 	return load(Datadog, "datadog.yaml", false)
 }
 
-func findUnknownKeys(config Config) []string {
+// FindUnknownKeys returns the keys loaded in the config that are not known to the agent
+func FindUnknownKeys(config Config) []string {
 	var unknownKeys []string
 	knownKeys := config.GetKnownKeys()
 	loadedKeys := config.AllKeys()
@@ -1388,7 +1418,12 @@ func load(config Config, origin string, loadSecret bool) (*Warnings, error) {
 		return &warnings, err
 	}
 
-	for _, key := range findUnknownKeys(config) {
+	if err := loadIncludes(config); err != nil {
+		log.Warnf("Error loading config includes from %s: %v", IncludeDir(config.ConfigFileUsed()), err)
+		return &warnings, err
+	}
+
+	for _, key := range FindUnknownKeys(config) {
 		log.Warnf("Unknown key in config file: %v", key)
 	}
 
@@ -187,17 +187,17 @@ func TestUnknownKeysWarning(t *testing.T) {
 site: datadoghq.eu
 `
 	confBase := setupConfFromYAML(yamlBase)
-	assert.Len(t, findUnknownKeys(confBase), 0)
+	assert.Len(t, FindUnknownKeys(confBase), 0)
 
 	yamlWithUnknownKeys := `
 site: datadoghq.eu
 unknown_key.unknown_subkey: true
 `
 	confWithUnknownKeys := setupConfFromYAML(yamlWithUnknownKeys)
-	assert.Len(t, findUnknownKeys(confWithUnknownKeys), 1)
+	assert.Len(t, FindUnknownKeys(confWithUnknownKeys), 1)
 
 	confWithUnknownKeys.SetKnown("unknown_key.*")
-	assert.Len(t, findUnknownKeys(confWithUnknownKeys), 0)
+	assert.Len(t, FindUnknownKeys(confWithUnknownKeys), 0)
 }
 
 func TestUnknownVarsWarning(t *testing.T) {
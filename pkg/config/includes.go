@@ -0,0 +1,149 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	yaml "gopkg.in/yaml.v2"
+
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+const (
+	// OriginDefault is the origin of the keys that are not set
+	OriginDefault = "default"
+	// OriginRuntime is the origin of the keys changed at runtime
+	OriginRuntime = "runtime"
+	// OriginEnvVarPrefix prefixes the origin of the keys set by an environment variable
+	OriginEnvVarPrefix = "environment variable "
+)
+
+// IncludeDir returns the directory whose *.yaml files are merged into the
+// given configuration file, e.g. datadog.d for datadog.yaml.
+func IncludeDir(configFile string) string {
+	base := filepath.Base(configFile)
+	return filepath.Join(filepath.Dir(configFile), strings.TrimSuffix(base, filepath.Ext(base))+".d")
+}
+
+// loadIncludes merges the *.yaml files of the include directory of the
+// configuration file already read into the configuration, in lexical order.
+// Maps are merged recursively, lists are concatenated and any other value is
+// replaced. The origin of each key is recorded in the configuration.
+func loadIncludes(config Config) error {
+	configFile := config.ConfigFileUsed()
+	if configFile == "" {
+		return nil
+	}
+
+	merged, err := readYAMLFile(configFile)
+	if err != nil {
+		return err
+	}
+	origins := map[string][]string{}
+	recordOrigins(origins, "", merged, configFile)
+
+	// Glob returns the files in lexical order
+	includes, err := filepath.Glob(filepath.Join(IncludeDir(configFile), "*.yaml"))
+	if err != nil {
+		return err
+	}
+	for _, include := range includes {
+		overlay, err := readYAMLFile(include)
+		if err != nil {
+			return err
+		}
+		log.Debugf("Merging configuration file %s", include)
+		merged = deepMerge(merged, overlay, "", include, origins).(map[interface{}]interface{})
+	}
+
+	if len(includes) > 0 {
+		content, err := yaml.Marshal(merged)
+		if err != nil {
+			return fmt.Errorf("unable to marshal the merged configuration: %v", err)
+		}
+		if err := config.ReadConfig(bytes.NewReader(content)); err != nil {
+			return fmt.Errorf("unable to read the merged configuration: %v", err)
+		}
+	}
+
+	for key, files := range origins {
+		config.SetOrigin(key, strings.Join(files, ", "))
+	}
+	return nil
+}
+
+func readYAMLFile(path string) (map[interface{}]interface{}, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	raw := map[interface{}]interface{}{}
+	if err := yaml.Unmarshal(content, &raw); err != nil {
+		return nil, fmt.Errorf("unable to parse %s: %v", path, err)
+	}
+	return raw, nil
+}
+
+// deepMerge merges overlay into base and records the file each merged key
+// comes from.
+func deepMerge(base, overlay interface{}, prefix string, file string, origins map[string][]string) interface{} {
+	switch overlayValue := overlay.(type) {
+	case map[interface{}]interface{}:
+		baseMap, ok := base.(map[interface{}]interface{})
+		if !ok {
+			clearOrigins(origins, prefix)
+			recordOrigins(origins, prefix, overlayValue, file)
+			return overlayValue
+		}
+		for k, v := range overlayValue {
+			baseMap[k] = deepMerge(baseMap[k], v, joinKey(prefix, k), file, origins)
+		}
+		return baseMap
+	case []interface{}:
+		if baseList, ok := base.([]interface{}); ok {
+			origins[prefix] = append(origins[prefix], file)
+			return append(baseList, overlayValue...)
+		}
+	}
+	clearOrigins(origins, prefix)
+	origins[prefix] = []string{file}
+	return overlay
+}
+
+// recordOrigins records file as the origin of every leaf key of value
+func recordOrigins(origins map[string][]string, prefix string, value interface{}, file string) {
+	if m, ok := value.(map[interface{}]interface{}); ok {
+		for k, v := range m {
+			recordOrigins(origins, joinKey(prefix, k), v, file)
+		}
+		return
+	}
+	if prefix != "" {
+		origins[prefix] = []string{file}
+	}
+}
+
+// clearOrigins forgets the origins of key and of its sub-keys
+func clearOrigins(origins map[string][]string, key string) {
+	for k := range origins {
+		if k == key || strings.HasPrefix(k, key+".") {
+			delete(origins, k)
+		}
+	}
+}
+
+func joinKey(prefix string, key interface{}) string {
+	k := strings.ToLower(fmt.Sprintf("%v", key))
+	if prefix == "" {
+		return k
+	}
+	return prefix + "." + k
+}
@@ -0,0 +1,135 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeConfFiles(t *testing.T, files map[string]string) string {
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0700))
+		require.NoError(t, ioutil.WriteFile(path, []byte(content), 0600))
+	}
+	return dir
+}
+
+func TestIncludeDir(t *testing.T) {
+	assert.Equal(t, filepath.Join("etc", "datadog.d"), IncludeDir(filepath.Join("etc", "datadog.yaml")))
+	assert.Equal(t, filepath.Join("etc", "system-probe.d"), IncludeDir(filepath.Join("etc", "system-probe.yaml")))
+}
+
+func TestLoadIncludes(t *testing.T) {
+	dir := writeConfFiles(t, map[string]string{
+		"datadog.yaml": `
+env: prod
+hostname: main
+tags:
+  - team:core
+logs_config:
+  container_collect_all: true
+  processing_rules:
+    - type: exclude_at_match
+      name: exclude_healthchecks
+      pattern: healthcheck
+`,
+		"datadog.d/10-team-a.yaml": `
+hostname: team-a
+tags:
+  - team:a
+logs_config:
+  processing_rules:
+    - type: mask_sequences
+      name: mask_tokens
+      pattern: token=\w+
+      replace_placeholder: token=***
+`,
+		"datadog.d/20-team-b.yaml": `
+hostname: team-b
+site: datadoghq.eu
+`,
+		// only *.yaml files are merged
+		"datadog.d/30-ignored.yaml.example": `
+hostname: ignored
+`,
+	})
+	mainFile := filepath.Join(dir, "datadog.yaml")
+	teamA := filepath.Join(dir, "datadog.d", "10-team-a.yaml")
+	teamB := filepath.Join(dir, "datadog.d", "20-team-b.yaml")
+
+	conf := setupConf()
+	conf.SetConfigFile(mainFile)
+	_, err := load(conf, "datadog.yaml", false)
+	require.NoError(t, err)
+
+	// scalars are replaced in lexical order
+	assert.Equal(t, "team-b", conf.GetString("hostname"))
+	assert.Equal(t, "datadoghq.eu", conf.GetString("site"))
+	assert.Equal(t, "prod", conf.GetString("env"))
+	// lists are concatenated
+	assert.Equal(t, []string{"team:core", "team:a"}, conf.GetStringSlice("tags"))
+	rules, ok := conf.Get("logs_config.processing_rules").([]interface{})
+	require.True(t, ok)
+	assert.Len(t, rules, 2)
+	// maps are merged
+	assert.True(t, conf.GetBool("logs_config.container_collect_all"))
+
+	assert.Equal(t, teamB, conf.GetOrigin("hostname"))
+	assert.Equal(t, mainFile, conf.GetOrigin("env"))
+	assert.Equal(t, mainFile+", "+teamA, conf.GetOrigin("tags"))
+	assert.Equal(t, mainFile, conf.GetOrigin("logs_config.container_collect_all"))
+	assert.Equal(t, OriginDefault, conf.GetOrigin("dogstatsd_port"))
+
+	// environment variables take precedence over files
+	defer setEnvForTest("DD_HOSTNAME", "from-env")()
+	assert.Equal(t, OriginEnvVarPrefix+"DD_HOSTNAME", conf.GetOrigin("hostname"))
+
+	conf.SetOrigin("site", OriginRuntime)
+	assert.Equal(t, OriginRuntime, conf.GetOrigin("site"))
+}
+
+func TestLoadIncludesInvalidFile(t *testing.T) {
+	dir := writeConfFiles(t, map[string]string{
+		"datadog.yaml":             "api_key: deadbeef\n",
+		"datadog.d/10-broken.yaml": "hostname: [\n",
+	})
+
+	conf := setupConf()
+	conf.SetConfigFile(filepath.Join(dir, "datadog.yaml"))
+	_, err := load(conf, "datadog.yaml", false)
+	assert.Error(t, err)
+}
+
+func TestDeepMergeReplacesMismatchingTypes(t *testing.T) {
+	origins := map[string][]string{}
+	base := map[interface{}]interface{}{
+		"section": map[interface{}]interface{}{"a": 1, "b": 2},
+		"list":    []interface{}{1},
+	}
+	recordOrigins(origins, "", base, "main")
+
+	merged := deepMerge(base, map[interface{}]interface{}{
+		"section": "scalar",
+		"list":    map[interface{}]interface{}{"c": 3},
+	}, "", "overlay", origins)
+
+	assert.Equal(t, map[interface{}]interface{}{
+		"section": "scalar",
+		"list":    map[interface{}]interface{}{"c": 3},
+	}, merged)
+	assert.Equal(t, map[string][]string{
+		"section": {"overlay"},
+		"list.c":  {"overlay"},
+	}, origins)
+}
@@ -0,0 +1,293 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package lint
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/config"
+	"github.com/DataDog/datadog-agent/pkg/dogstatsd"
+	logsconfig "github.com/DataDog/datadog-agent/pkg/logs/config"
+
+	"gopkg.in/yaml.v2"
+)
+
+// deprecatedKeys maps deprecated settings to the setting replacing them
+var deprecatedKeys = map[string]string{
+	"tracemalloc_whitelist":                            "tracemalloc_include",
+	"tracemalloc_blacklist":                            "tracemalloc_exclude",
+	"force_tls_12":                                     "min_tls_version",
+	"forwarder_retry_queue_max_size":                   "forwarder_retry_queue_payloads_max_size",
+	"log_enabled":                                      "logs_enabled",
+	"logs_config.use_http":                             "logs_config.force_use_http",
+	"logs_config.use_tcp":                              "logs_config.force_use_tcp",
+	"process_config.enabled":                           "process_config.process_collection.enabled",
+	"process_config.orchestrator_dd_url":               "orchestrator_explorer.orchestrator_dd_url",
+	"process_config.orchestrator_additional_endpoints": "orchestrator_explorer.orchestrator_additional_endpoints",
+}
+
+// AgentConfig lints an agent configuration that has already been read.
+// Type mismatches and deprecated settings are looked up in the configuration
+// file and the files of its include directory only.
+func AgentConfig(cfg config.Config) []Issue {
+	source := cfg.ConfigFileUsed()
+	if source == "" {
+		source = "datadog.yaml"
+	}
+
+	var issues []Issue
+	for _, key := range config.FindUnknownKeys(cfg) {
+		issues = append(issues, Issue{
+			Source:   source,
+			Key:      key,
+			Category: CategoryUnknownKey,
+			Severity: SeverityWarning,
+			Message:  fmt.Sprintf("unknown key %s", key),
+		})
+	}
+
+	// values returned by the config are already cast to the type of their
+	// default, so the raw files are read to look for type mismatches.
+	if path := cfg.ConfigFileUsed(); path != "" {
+		includes, _ := filepath.Glob(filepath.Join(config.IncludeDir(path), "*.yaml"))
+		for _, file := range append([]string{path}, includes...) {
+			raw, err := readRawConfig(file)
+			if err != nil {
+				issues = append(issues, Issue{
+					Source:   file,
+					Category: CategoryTypeMismatch,
+					Severity: SeverityError,
+					Message:  err.Error(),
+				})
+				continue
+			}
+			userSettings := flattenUserSettings(raw)
+			issues = append(issues, typeMismatches(file, userSettings)...)
+			issues = append(issues, deprecatedSettings(file, userSettings)...)
+		}
+	}
+
+	if _, err := logsconfig.GlobalProcessingRulesWithConfig(cfg); err != nil {
+		issues = append(issues, Issue{
+			Source:   source,
+			Key:      "logs_config.processing_rules",
+			Category: CategoryProcessingRules,
+			Severity: SeverityError,
+			Message:  err.Error(),
+		})
+	}
+
+	var profiles []config.MappingProfile
+	if err := cfg.UnmarshalKey("dogstatsd_mapper_profiles", &profiles); err != nil {
+		issues = append(issues, Issue{
+			Source:   source,
+			Key:      "dogstatsd_mapper_profiles",
+			Category: CategoryMapperProfiles,
+			Severity: SeverityError,
+			Message:  fmt.Sprintf("could not parse dogstatsd_mapper_profiles: %v", err),
+		})
+	} else if err := dogstatsd.ValidateMappingProfiles(profiles); err != nil {
+		issues = append(issues, Issue{
+			Source:   source,
+			Key:      "dogstatsd_mapper_profiles",
+			Category: CategoryMapperProfiles,
+			Severity: SeverityError,
+			Message:  err.Error(),
+		})
+	}
+
+	return issues
+}
+
+func readRawConfig(path string) (map[string]interface{}, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	raw := make(map[string]interface{})
+	if err := yaml.Unmarshal(content, &raw); err != nil {
+		return nil, fmt.Errorf("unable to parse %s: %v", path, err)
+	}
+	return raw, nil
+}
+
+// flattenUserSettings returns the settings set in the raw configuration
+// indexed by their full key. Known settings holding a map are not flattened
+// further.
+func flattenUserSettings(raw map[string]interface{}) map[string]userSetting {
+	defaults := config.NewConfig("datadog", "DD", strings.NewReplacer(".", "_"))
+	config.InitConfig(defaults)
+
+	knownKeys := defaults.GetKnownKeys()
+
+	settings := make(map[string]userSetting)
+	var visit func(prefix string, value interface{})
+	visit = func(prefix string, value interface{}) {
+		if _, known := knownKeys[strings.ToLower(prefix)]; known {
+			settings[prefix] = userSetting{value: value, defaultValue: defaults.Get(strings.ToLower(prefix))}
+			return
+		}
+
+		children, isMap := toStringMap(value)
+		if !isMap {
+			if prefix != "" {
+				settings[prefix] = userSetting{value: value}
+			}
+			return
+		}
+		for k, v := range children {
+			key := k
+			if prefix != "" {
+				key = prefix + "." + k
+			}
+			visit(key, v)
+		}
+	}
+	visit("", raw)
+
+	return settings
+}
+
+type userSetting struct {
+	value        interface{}
+	defaultValue interface{}
+}
+
+func typeMismatches(source string, settings map[string]userSetting) []Issue {
+	var issues []Issue
+	for _, key := range sortedKeys(settings) {
+		setting := settings[key]
+		if setting.defaultValue == nil || setting.value == nil {
+			continue
+		}
+		if expected, ok := compatibleType(setting.defaultValue, setting.value); !ok {
+			issues = append(issues, Issue{
+				Source:   source,
+				Key:      key,
+				Category: CategoryTypeMismatch,
+				Severity: SeverityError,
+				Message:  fmt.Sprintf("expected a value of type %s, got %T (%v)", expected, setting.value, setting.value),
+			})
+		}
+	}
+	return issues
+}
+
+func deprecatedSettings(source string, settings map[string]userSetting) []Issue {
+	var issues []Issue
+	for _, key := range sortedKeys(settings) {
+		replacement, found := deprecatedKeys[key]
+		if !found {
+			continue
+		}
+		issues = append(issues, Issue{
+			Source:   source,
+			Key:      key,
+			Category: CategoryDeprecated,
+			Severity: SeverityWarning,
+			Message:  fmt.Sprintf("%s is deprecated, use %s instead", key, replacement),
+		})
+	}
+	return issues
+}
+
+// compatibleType returns whether value can be used where defaultValue is
+// expected, and the name of the expected type.
+func compatibleType(defaultValue, value interface{}) (string, bool) {
+	switch defaultValue.(type) {
+	case bool:
+		switch v := value.(type) {
+		case bool:
+			return "bool", true
+		case string:
+			_, err := strconv.ParseBool(v)
+			return "bool", err == nil
+		}
+		return "bool", false
+	case time.Duration:
+		switch v := value.(type) {
+		case string:
+			_, err := time.ParseDuration(v)
+			if err != nil {
+				_, err = strconv.ParseFloat(v, 64)
+			}
+			return "duration", err == nil
+		}
+		return "duration", isNumber(value)
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		if v, ok := value.(string); ok {
+			_, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+			return "number", err == nil
+		}
+		return "number", isNumber(value)
+	case string:
+		// scalars are converted to strings when read
+		switch value.(type) {
+		case string, bool:
+			return "string", true
+		}
+		return "string", isNumber(value)
+	}
+
+	switch reflect.ValueOf(defaultValue).Kind() {
+	case reflect.Slice, reflect.Array:
+		switch reflect.ValueOf(value).Kind() {
+		case reflect.Slice, reflect.Array:
+			return "list", true
+		case reflect.String:
+			// lists can be given as space separated strings or JSON strings
+			return "list", true
+		}
+		return "list", false
+	case reflect.Map:
+		if _, isMap := toStringMap(value); isMap {
+			return "map", true
+		}
+		// maps can be given as JSON strings
+		_, isString := value.(string)
+		return "map", isString
+	}
+
+	return fmt.Sprintf("%T", defaultValue), true
+}
+
+func isNumber(value interface{}) bool {
+	switch value.(type) {
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return true
+	}
+	return false
+}
+
+func toStringMap(value interface{}) (map[string]interface{}, bool) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return v, true
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for k, val := range v {
+			m[fmt.Sprintf("%v", k)] = val
+		}
+		return m, true
+	}
+	return nil, false
+}
+
+func sortedKeys(settings map[string]userSetting) []string {
+	keys := make([]string, 0, len(settings))
+	for k := range settings {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
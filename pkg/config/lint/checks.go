@@ -0,0 +1,135 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package lint
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/DataDog/datadog-agent/pkg/autodiscovery/configresolver"
+	"github.com/DataDog/datadog-agent/pkg/autodiscovery/integration"
+	"github.com/DataDog/datadog-agent/pkg/autodiscovery/providers"
+	logsconfig "github.com/DataDog/datadog-agent/pkg/logs/config"
+	"github.com/DataDog/datadog-agent/pkg/util/tmplvar"
+)
+
+// CheckConfigs lints the check configurations found by the file config provider
+// in the given search paths.
+func CheckConfigs(confSearchPaths []string) []Issue {
+	providers.InitConfigFilesReader(confSearchPaths)
+	configs, errors, err := providers.ReadConfigFiles(providers.GetAll)
+	if err != nil {
+		return []Issue{{
+			Source:   strings.Join(confSearchPaths, ","),
+			Category: CategoryCheckConfig,
+			Severity: SeverityError,
+			Message:  err.Error(),
+		}}
+	}
+
+	var issues []Issue
+
+	names := make([]string, 0, len(errors))
+	for name := range errors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		issues = append(issues, Issue{
+			Source:   name,
+			Category: CategoryCheckConfig,
+			Severity: SeverityError,
+			Message:  errors[name],
+		})
+	}
+
+	for _, c := range configs {
+		issues = append(issues, CheckConfig(c)...)
+	}
+
+	return issues
+}
+
+// CheckConfig lints a single check configuration
+func CheckConfig(c integration.Config) []Issue {
+	source := strings.TrimPrefix(c.Source, "file:")
+	if source == "" {
+		source = c.Name
+	}
+
+	var issues []Issue
+	isTemplate := c.IsTemplate()
+
+	data := []integration.Data{c.InitConfig, c.MetricConfig, c.LogsConfig}
+	data = append(data, c.Instances...)
+	seen := make(map[string]struct{})
+	for _, d := range data {
+		for _, v := range tmplvar.Parse(d) {
+			raw := string(v.Raw)
+			if _, found := seen[raw]; found {
+				continue
+			}
+			seen[raw] = struct{}{}
+
+			name, key := string(v.Name), string(v.Key)
+			switch {
+			case !configresolver.IsTemplateVariable(name):
+				issues = append(issues, Issue{
+					Source:   source,
+					Key:      raw,
+					Category: CategoryTemplateVariables,
+					Severity: SeverityError,
+					Message:  fmt.Sprintf("unknown template variable %s", raw),
+				})
+			case name == "env":
+				// environment variables are substituted when the file is read,
+				// any remaining one is not set in the environment.
+				issues = append(issues, Issue{
+					Source:   source,
+					Key:      raw,
+					Category: CategoryTemplateVariables,
+					Severity: SeverityWarning,
+					Message:  fmt.Sprintf("environment variable %s is not set", key),
+				})
+			case !isTemplate:
+				issues = append(issues, Issue{
+					Source:   source,
+					Key:      raw,
+					Category: CategoryTemplateVariables,
+					Severity: SeverityError,
+					Message:  fmt.Sprintf("template variable %s can't be resolved in a configuration without ad_identifiers", raw),
+				})
+			}
+		}
+	}
+
+	if len(c.LogsConfig) > 0 {
+		logsConfigs, err := logsconfig.ParseYAML(c.LogsConfig)
+		if err != nil {
+			issues = append(issues, Issue{
+				Source:   source,
+				Key:      "logs",
+				Category: CategoryCheckConfig,
+				Severity: SeverityError,
+				Message:  err.Error(),
+			})
+		}
+		for _, lc := range logsConfigs {
+			if err := logsconfig.ValidateProcessingRules(lc.ProcessingRules); err != nil {
+				issues = append(issues, Issue{
+					Source:   source,
+					Key:      "logs.log_processing_rules",
+					Category: CategoryProcessingRules,
+					Severity: SeverityError,
+					Message:  err.Error(),
+				})
+			}
+		}
+	}
+
+	return issues
+}
@@ -0,0 +1,96 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// Package lint validates the agent configuration and the check configurations
+// found on disk without starting any component.
+package lint
+
+import (
+	"encoding/json"
+	"sort"
+)
+
+// Severity is the severity of an issue found while linting
+type Severity string
+
+// Severities of the issues
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// Category is the kind of issue found while linting
+type Category string
+
+// Categories of the issues
+const (
+	CategoryUnknownKey        Category = "unknown_key"
+	CategoryTypeMismatch      Category = "type_mismatch"
+	CategoryDeprecated        Category = "deprecated"
+	CategoryProcessingRules   Category = "processing_rules"
+	CategoryMapperProfiles    Category = "mapper_profiles"
+	CategoryCheckConfig       Category = "check_config"
+	CategoryTemplateVariables Category = "template_variables"
+)
+
+// Issue is a single problem found in a configuration
+type Issue struct {
+	Source   string   `json:"source"`
+	Key      string   `json:"key,omitempty"`
+	Category Category `json:"category"`
+	Severity Severity `json:"severity"`
+	Message  string   `json:"message"`
+}
+
+// Report holds all the issues found while linting
+type Report struct {
+	Issues   []Issue `json:"issues"`
+	Errors   int     `json:"errors"`
+	Warnings int     `json:"warnings"`
+}
+
+// NewReport creates a new empty Report
+func NewReport() *Report {
+	return &Report{
+		Issues: []Issue{},
+	}
+}
+
+// Add adds issues to the report
+func (r *Report) Add(issues ...Issue) {
+	for _, issue := range issues {
+		switch issue.Severity {
+		case SeverityError:
+			r.Errors++
+		case SeverityWarning:
+			r.Warnings++
+		}
+		r.Issues = append(r.Issues, issue)
+	}
+}
+
+// HasErrors returns whether at least one issue of severity error was found
+func (r *Report) HasErrors() bool {
+	return r.Errors > 0
+}
+
+// MarshalJSON returns the report in JSON with issues sorted by source and key
+func (r *Report) MarshalJSON() ([]byte, error) {
+	issues := make([]Issue, len(r.Issues))
+	copy(issues, r.Issues)
+	sort.SliceStable(issues, func(i, j int) bool {
+		if issues[i].Source != issues[j].Source {
+			return issues[i].Source < issues[j].Source
+		}
+		return issues[i].Key < issues[j].Key
+	})
+
+	type report Report
+	return json.Marshal(&report{
+		Issues:   issues,
+		Errors:   r.Errors,
+		Warnings: r.Warnings,
+	})
+}
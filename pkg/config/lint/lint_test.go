@@ -0,0 +1,172 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package lint
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/pkg/autodiscovery/integration"
+	"github.com/DataDog/datadog-agent/pkg/config"
+)
+
+func setupConfFromYAML(t *testing.T, yamlConfig string) config.Config {
+	path := filepath.Join(t.TempDir(), "datadog.yaml")
+	require.NoError(t, ioutil.WriteFile(path, []byte(yamlConfig), 0600))
+
+	conf := config.NewConfig("datadog", "DD", strings.NewReplacer(".", "_"))
+	config.InitConfig(conf)
+	conf.SetConfigFile(path)
+	require.NoError(t, conf.ReadInConfig())
+	return conf
+}
+
+func issuesByCategory(issues []Issue) map[Category][]Issue {
+	res := make(map[Category][]Issue)
+	for _, issue := range issues {
+		res[issue.Category] = append(res[issue.Category], issue)
+	}
+	return res
+}
+
+func TestAgentConfigValid(t *testing.T) {
+	conf := setupConfFromYAML(t, `
+api_key: deadbeef
+logs_enabled: true
+dogstatsd_port: "8125"
+logs_config:
+  processing_rules:
+    - type: exclude_at_match
+      name: exclude_healthchecks
+      pattern: healthcheck
+dogstatsd_mapper_profiles:
+  - name: airflow
+    prefix: "airflow."
+    mappings:
+      - match: "airflow.job.duration_sec.*.*"
+        name: "airflow.job.duration"
+        tags:
+          job_type: "$1"
+`)
+	assert.Empty(t, AgentConfig(conf))
+}
+
+func TestAgentConfigIssues(t *testing.T) {
+	conf := setupConfFromYAML(t, `
+api_key: deadbeef
+unknown_setting: 1
+logs_enabled: maybe
+dogstatsd_port: [1, 2]
+log_enabled: true
+logs_config:
+  processing_rules:
+    - type: exclude_at_match
+      name: broken
+      pattern: "("
+dogstatsd_mapper_profiles:
+  - name: airflow
+    mappings:
+      - match: "airflow.*"
+        name: "airflow"
+`)
+	issues := issuesByCategory(AgentConfig(conf))
+
+	require.Len(t, issues[CategoryUnknownKey], 1)
+	assert.Equal(t, "unknown_setting", issues[CategoryUnknownKey][0].Key)
+
+	require.Len(t, issues[CategoryTypeMismatch], 2)
+	assert.Equal(t, "dogstatsd_port", issues[CategoryTypeMismatch][0].Key)
+	assert.Equal(t, "logs_enabled", issues[CategoryTypeMismatch][1].Key)
+
+	require.Len(t, issues[CategoryDeprecated], 1)
+	assert.Equal(t, "log_enabled", issues[CategoryDeprecated][0].Key)
+
+	require.Len(t, issues[CategoryProcessingRules], 1)
+	assert.Equal(t, SeverityError, issues[CategoryProcessingRules][0].Severity)
+
+	require.Len(t, issues[CategoryMapperProfiles], 1)
+	assert.Contains(t, issues[CategoryMapperProfiles][0].Message, "missing prefix")
+}
+
+func TestCheckConfigTemplateVariables(t *testing.T) {
+	c := integration.Config{
+		Name:          "redisdb",
+		Source:        "file:/etc/datadog-agent/conf.d/redisdb.d/conf.yaml",
+		ADIdentifiers: []string{"redis"},
+		Instances:     []integration.Data{integration.Data("host: '%%host%%'\nport: '%%port_6379%%'\npassword: '%%env_REDIS_PASSWORD%%'\nfoo: '%%unknown%%'")},
+	}
+	issues := issuesByCategory(CheckConfig(c))
+	require.Len(t, issues[CategoryTemplateVariables], 2)
+	for _, issue := range issues[CategoryTemplateVariables] {
+		assert.Equal(t, "/etc/datadog-agent/conf.d/redisdb.d/conf.yaml", issue.Source)
+		switch issue.Key {
+		case "%%env_REDIS_PASSWORD%%":
+			assert.Equal(t, SeverityWarning, issue.Severity)
+		case "%%unknown%%":
+			assert.Equal(t, SeverityError, issue.Severity)
+		default:
+			assert.Fail(t, "unexpected issue", issue.Key)
+		}
+	}
+
+	// non-template configs can't use service template variables
+	c.ADIdentifiers = nil
+	c.Instances = []integration.Data{integration.Data("host: '%%host%%'")}
+	issues = issuesByCategory(CheckConfig(c))
+	require.Len(t, issues[CategoryTemplateVariables], 1)
+	assert.Equal(t, SeverityError, issues[CategoryTemplateVariables][0].Severity)
+}
+
+func TestCheckConfigLogsProcessingRules(t *testing.T) {
+	c := integration.Config{
+		Name:       "nginx",
+		LogsConfig: integration.Data("logs:\n- type: file\n  path: /var/log/nginx.log\n  log_processing_rules:\n  - type: unknown\n    name: foo\n    pattern: bar\n"),
+	}
+	issues := issuesByCategory(CheckConfig(c))
+	require.Len(t, issues[CategoryProcessingRules], 1)
+	assert.Equal(t, "nginx", issues[CategoryProcessingRules][0].Source)
+}
+
+func TestReport(t *testing.T) {
+	report := NewReport()
+	assert.False(t, report.HasErrors())
+
+	report.Add(
+		Issue{Source: "b", Category: CategoryUnknownKey, Severity: SeverityWarning},
+		Issue{Source: "a", Category: CategoryTypeMismatch, Severity: SeverityError},
+	)
+	assert.True(t, report.HasErrors())
+	assert.Equal(t, 1, report.Errors)
+	assert.Equal(t, 1, report.Warnings)
+
+	out, err := json.Marshal(report)
+	require.NoError(t, err)
+
+	var decoded Report
+	require.NoError(t, json.Unmarshal(out, &decoded))
+	require.Len(t, decoded.Issues, 2)
+	assert.Equal(t, "a", decoded.Issues[0].Source)
+}
+
+func TestAgentConfigIncludes(t *testing.T) {
+	conf := setupConfFromYAML(t, "api_key: deadbeef\n")
+	includeDir := config.IncludeDir(conf.ConfigFileUsed())
+	require.NoError(t, os.Mkdir(includeDir, 0700))
+	include := filepath.Join(includeDir, "10-logs.yaml")
+	require.NoError(t, ioutil.WriteFile(include, []byte("logs_enabled: maybe\n"), 0600))
+
+	issues := issuesByCategory(AgentConfig(conf))
+	require.Len(t, issues[CategoryTypeMismatch], 1)
+	assert.Equal(t, include, issues[CategoryTypeMismatch][0].Source)
+	assert.Equal(t, "logs_enabled", issues[CategoryTypeMismatch][0].Key)
+}
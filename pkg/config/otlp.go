@@ -16,6 +16,9 @@ const (
 	OTLPMetricsSubSectionKey  = "metrics"
 	OTLPMetrics               = OTLPSection + "." + OTLPMetricsSubSectionKey
 	OTLPMetricsEnabled        = OTLPSection + "." + OTLPMetricsSubSectionKey + ".enabled"
+	OTLPLogsSubSectionKey     = "logs"
+	OTLPLogs                  = OTLPSection + "." + OTLPLogsSubSectionKey
+	OTLPLogsEnabled           = OTLPLogs + ".enabled"
 	OTLPTagCardinalityKey     = OTLPMetrics + ".tag_cardinality"
 	OTLPDebugKey              = "debug"
 	OTLPDebug                 = OTLPSection + "." + OTLPDebugKey
@@ -46,6 +49,7 @@ func SetupOTLP(config Config) {
 	config.BindEnvAndSetDefault(OTLPTracePort, 5003)
 	config.BindEnvAndSetDefault(OTLPMetricsEnabled, true)
 	config.BindEnvAndSetDefault(OTLPTracesEnabled, true)
+	config.BindEnvAndSetDefault(OTLPLogsEnabled, false)
 	config.BindEnvAndSetDefault(OTLPDebugLogLevel, "info")
 
 	// NOTE: This only partially works.
@@ -177,6 +177,13 @@ func setupProcesses(config Config) {
 
 	procBindEnvAndSetDefault(config, "process_config.drop_check_payloads", []string{})
 
+	// Unified service tagging from the environment of host processes
+	procBindEnvAndSetDefault(config, "process_config.env_tags.enabled", false)
+	procBindEnvAndSetDefault(config, "process_config.env_tags.allowlist", []string{"DD_SERVICE", "DD_ENV", "DD_VERSION"})
+
+	// Metrics of groups of processes
+	config.SetKnown("process_config.process_groups")
+
 	// Process Lifecycle Events
 	procBindEnvAndSetDefault(config, "process_config.event_collection.store.max_items", DefaultProcessEventStoreMaxItems)
 	procBindEnvAndSetDefault(config, "process_config.event_collection.store.max_pending_pushes", DefaultProcessEventStoreMaxPendingPushes)
@@ -50,6 +50,14 @@ func TestProcessDefaultConfig(t *testing.T) {
 			key:          "process_config.process_collection.enabled",
 			defaultValue: false,
 		},
+		{
+			key:          "process_config.env_tags.enabled",
+			defaultValue: false,
+		},
+		{
+			key:          "process_config.env_tags.allowlist",
+			defaultValue: []string{"DD_SERVICE", "DD_ENV", "DD_VERSION"},
+		},
 		{
 			key:          "process_config.container_collection.enabled",
 			defaultValue: true,
@@ -229,6 +237,12 @@ func TestEnvVarOverride(t *testing.T) {
 			value:    "1h",
 			expected: time.Hour,
 		},
+		{
+			key:      "process_config.env_tags.enabled",
+			env:      "DD_PROCESS_CONFIG_ENV_TAGS_ENABLED",
+			value:    "true",
+			expected: true,
+		},
 		{
 			key:      "process_config.disable_realtime_checks",
 			env:      "DD_PROCESS_CONFIG_DISABLE_REALTIME_CHECKS",
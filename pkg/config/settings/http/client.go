@@ -11,6 +11,7 @@ import (
 	"fmt"
 	"html"
 	"net/http"
+	"net/url"
 
 	"github.com/DataDog/datadog-agent/pkg/api/util"
 	"github.com/DataDog/datadog-agent/pkg/config/settings"
@@ -63,6 +64,26 @@ func (rc *runtimeSettingsHTTPClient) List() (map[string]settings.RuntimeSettingR
 	return settingsList, nil
 }
 
+func (rc *runtimeSettingsHTTPClient) Origins() (map[string]string, error) {
+	r, err := util.DoGet(rc.c, fmt.Sprintf("%s/%s", rc.baseURL, "origins"), util.LeaveConnectionOpen)
+	if err != nil {
+		var errMap = make(map[string]string)
+		_ = json.Unmarshal(r, &errMap)
+		// If the error has been marshalled into a json object, check it and return it properly
+		if e, found := errMap["error"]; found {
+			return nil, fmt.Errorf(e)
+		}
+		return nil, err
+	}
+	var origins = make(map[string]string)
+	err = json.Unmarshal(r, &origins)
+	if err != nil {
+		return nil, err
+	}
+
+	return origins, nil
+}
+
 func (rc *runtimeSettingsHTTPClient) Get(key string) (interface{}, error) {
 	r, err := util.DoGet(rc.c, fmt.Sprintf("%s/%s", rc.baseURL, key), util.LeaveConnectionOpen)
 	if err != nil {
@@ -92,7 +113,8 @@ func (rc *runtimeSettingsHTTPClient) Set(key string, value string) (bool, error)
 		return false, err
 	}
 
-	body := fmt.Sprintf("value=%s", html.EscapeString(value))
+	// values are URL encoded so that structured (JSON) values are sent untouched
+	body := url.Values{"value": {html.EscapeString(value)}}.Encode()
 	r, err := util.DoPost(rc.c, fmt.Sprintf("%s/%s", rc.baseURL, key), "application/x-www-form-urlencoded", bytes.NewBuffer([]byte(body)))
 	if err != nil {
 		var errMap = make(map[string]string)
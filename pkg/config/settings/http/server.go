@@ -9,6 +9,7 @@ import (
 	"encoding/json"
 	"html"
 	"net/http"
+	"strings"
 
 	"github.com/gorilla/mux"
 	"gopkg.in/yaml.v2"
@@ -25,11 +26,13 @@ var Server = struct {
 	GetValue         http.HandlerFunc
 	SetValue         http.HandlerFunc
 	ListConfigurable http.HandlerFunc
+	ListOrigins      func(...string) http.HandlerFunc
 }{
 	GetFull:          getFullConfig,
 	GetValue:         getConfigValue,
 	SetValue:         setConfigValue,
 	ListConfigurable: listConfigurableSettings,
+	ListOrigins:      listSettingOrigins,
 }
 
 func getFullConfig(namespaces ...string) http.HandlerFunc {
@@ -104,6 +107,49 @@ func listConfigurableSettings(w http.ResponseWriter, _ *http.Request) {
 	_, _ = w.Write(body)
 }
 
+// listSettingOrigins returns the origin of the settings of the given namespaces,
+// or of all the settings when no namespace or an empty one is given
+func listSettingOrigins(namespaces ...string) http.HandlerFunc {
+	var prefixes []string
+	for _, ns := range namespaces {
+		if ns == "" {
+			prefixes = nil
+			break
+		}
+		prefixes = append(prefixes, ns+".")
+	}
+
+	return func(w http.ResponseWriter, _ *http.Request) {
+		origins := make(map[string]string)
+		for _, key := range ddconfig.Datadog.AllKeys() {
+			if !inNamespaces(key, prefixes) {
+				continue
+			}
+			origins[key] = ddconfig.Datadog.GetOrigin(key)
+		}
+		body, err := json.Marshal(origins)
+		if err != nil {
+			log.Errorf("Unable to marshal setting origins response: %s", err)
+			body, _ := json.Marshal(map[string]string{"error": err.Error()})
+			http.Error(w, string(body), http.StatusInternalServerError)
+			return
+		}
+		_, _ = w.Write(body)
+	}
+}
+
+func inNamespaces(key string, prefixes []string) bool {
+	if len(prefixes) == 0 {
+		return true
+	}
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
 func getConfigValue(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	setting := vars["setting"]
@@ -0,0 +1,38 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package http
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	ddconfig "github.com/DataDog/datadog-agent/pkg/config"
+)
+
+func TestListSettingOrigins(t *testing.T) {
+	cfg := ddconfig.Mock(t)
+	cfg.Set("system_probe_config.enabled", true)
+	cfg.SetOrigin("system_probe_config.enabled", ddconfig.OriginRuntime)
+
+	listOrigins := func(namespaces ...string) map[string]string {
+		rec := httptest.NewRecorder()
+		listSettingOrigins(namespaces...)(rec, httptest.NewRequest("GET", "/config/origins", nil))
+		origins := map[string]string{}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &origins))
+		return origins
+	}
+
+	origins := listOrigins("system_probe_config", "network_config")
+	assert.Equal(t, map[string]string{"system_probe_config.enabled": ddconfig.OriginRuntime}, origins)
+
+	origins = listOrigins("")
+	assert.Contains(t, origins, "api_key")
+	assert.Contains(t, origins, "system_probe_config.enabled")
+}
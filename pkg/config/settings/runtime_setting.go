@@ -6,9 +6,14 @@
 package settings
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/DataDog/datadog-agent/pkg/config"
 )
 
 var runtimeSettings = make(map[string]RuntimeSetting)
@@ -56,7 +61,11 @@ func SetRuntimeSetting(setting string, value interface{}) error {
 	if _, ok := runtimeSettings[setting]; !ok {
 		return &SettingNotFoundError{name: setting}
 	}
-	return runtimeSettings[setting].Set(value)
+	if err := runtimeSettings[setting].Set(value); err != nil {
+		return err
+	}
+	config.Datadog.SetOrigin(setting, config.OriginRuntime)
+	return nil
 }
 
 // GetRuntimeSetting returns the value of a runtime configurable setting
@@ -115,3 +124,52 @@ func GetInt(v interface{}) (int, error) {
 		return 0, fmt.Errorf("GetInt: bad parameter value provided: %v", v)
 	}
 }
+
+// GetStringSlice returns the string slice contained in value.
+// If value is a slice, each of its elements is converted to a string.
+// If value is a string, it is parsed as a JSON array, or else split on commas
+// and whitespaces.
+// Else, returns an error.
+func GetStringSlice(v interface{}) ([]string, error) {
+	switch v := v.(type) {
+	case []string:
+		return v, nil
+	case []interface{}:
+		res := make([]string, 0, len(v))
+		for _, elem := range v {
+			res = append(res, fmt.Sprintf("%v", elem))
+		}
+		return res, nil
+	case string:
+		if strings.HasPrefix(strings.TrimSpace(v), "[") {
+			var res []string
+			if err := json.Unmarshal([]byte(v), &res); err != nil {
+				return nil, fmt.Errorf("GetStringSlice: %s", err)
+			}
+			return res, nil
+		}
+		return strings.FieldsFunc(v, func(r rune) bool {
+			return r == ',' || unicode.IsSpace(r)
+		}), nil
+	default:
+		return nil, fmt.Errorf("GetStringSlice: bad parameter value provided: %v", v)
+	}
+}
+
+// UnmarshalValue decodes the structured value contained in v into out.
+// If value is a string, it is decoded as JSON.
+// Else, it is converted to JSON before being decoded.
+func UnmarshalValue(v interface{}, out interface{}) error {
+	data, ok := v.(string)
+	if !ok {
+		raw, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Errorf("UnmarshalValue: bad parameter value provided: %v", err)
+		}
+		data = string(raw)
+	}
+	if err := json.Unmarshal([]byte(data), out); err != nil {
+		return fmt.Errorf("UnmarshalValue: %s", err)
+	}
+	return nil
+}
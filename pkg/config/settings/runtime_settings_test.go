@@ -151,3 +151,42 @@ func TestGetInt(t *testing.T) {
 		}
 	}
 }
+
+func TestGetStringSlice(t *testing.T) {
+	cases := []struct {
+		v   interface{}
+		exp []string
+		err bool
+	}{
+		{[]string{"a", "b"}, []string{"a", "b"}, false},
+		{[]interface{}{"a", 1}, []string{"a", "1"}, false},
+		{`["a", "b"]`, []string{"a", "b"}, false},
+		{"a, b c", []string{"a", "b", "c"}, false},
+		{"", []string{}, false},
+		{`["a"`, nil, true},
+		{1, nil, true},
+	}
+
+	for _, c := range cases {
+		v, err := GetStringSlice(c.v)
+		if c.err {
+			assert.NotNil(t, err)
+		} else {
+			assert.Nil(t, err)
+			assert.Equal(t, c.exp, v)
+		}
+	}
+}
+
+func TestUnmarshalValue(t *testing.T) {
+	var out map[string][]string
+
+	assert.Nil(t, UnmarshalValue(`{"https://foo": ["key"]}`, &out))
+	assert.Equal(t, map[string][]string{"https://foo": {"key"}}, out)
+
+	out = nil
+	assert.Nil(t, UnmarshalValue(map[string]interface{}{"https://bar": []interface{}{"key"}}, &out))
+	assert.Equal(t, map[string][]string{"https://bar": {"key"}}, out)
+
+	assert.NotNil(t, UnmarshalValue(`{"https://foo": "key"}`, &out))
+}
@@ -94,4 +94,10 @@ type Config interface {
 	// IsSectionSet checks if a given section is set by checking if any of
 	// its subkeys is set.
 	IsSectionSet(section string) bool
+
+	// SetOrigin records where the value of a key comes from: a file or OriginRuntime.
+	SetOrigin(key string, origin string)
+	// GetOrigin returns where the effective value of a key comes from: OriginRuntime,
+	// an environment variable, the files it was set in or OriginDefault.
+	GetOrigin(key string) string
 }
@@ -8,6 +8,8 @@ package config
 import (
 	"fmt"
 	"io"
+	"os"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -32,6 +34,12 @@ type safeConfig struct {
 	// configEnvVars is the set of env vars that are consulted for
 	// configuration values.
 	configEnvVars map[string]struct{}
+
+	// envVarsPerKey maps each key to the env vars bound to it
+	envVarsPerKey map[string][]string
+
+	// origins maps the keys set from a file or at runtime to their origin
+	origins map[string]string
 }
 
 // Set wraps Viper for concurrent access
@@ -316,12 +324,14 @@ func (c *safeConfig) BindEnv(input ...string) {
 		envKeys = input[1:]
 	}
 
+	configKey := strings.ToLower(input[0])
 	for _, key := range envKeys {
 		// apply EnvKeyReplacer to each key
 		if c.envKeyReplacer != nil {
 			key = c.envKeyReplacer.Replace(key)
 		}
 		c.configEnvVars[key] = struct{}{}
+		c.envVarsPerKey[configKey] = append(c.envVarsPerKey[configKey], key)
 	}
 
 	_ = c.Viper.BindEnv(input...)
@@ -456,6 +466,49 @@ func (c *safeConfig) GetEnvVars() []string {
 	return vars
 }
 
+// SetOrigin implements the Config interface
+func (c *safeConfig) SetOrigin(key string, origin string) {
+	c.Lock()
+	defer c.Unlock()
+	c.origins[strings.ToLower(key)] = origin
+}
+
+// GetOrigin implements the Config interface
+func (c *safeConfig) GetOrigin(key string) string {
+	key = strings.ToLower(key)
+
+	c.RLock()
+	defer c.RUnlock()
+
+	// same precedence as viper: runtime, then environment, then files
+	if origin := c.origins[key]; origin == OriginRuntime {
+		return origin
+	}
+	for _, envVar := range c.envVarsPerKey[key] {
+		if os.Getenv(envVar) != "" {
+			return OriginEnvVarPrefix + envVar
+		}
+	}
+	if origin, found := c.origins[key]; found {
+		return origin
+	}
+
+	// a section gets the origins of its sub-keys
+	var origins []string
+	seen := map[string]struct{}{}
+	for k, origin := range c.origins {
+		if _, found := seen[origin]; !found && strings.HasPrefix(k, key+".") {
+			seen[origin] = struct{}{}
+			origins = append(origins, origin)
+		}
+	}
+	if len(origins) > 0 {
+		sort.Strings(origins)
+		return strings.Join(origins, ", ")
+	}
+	return OriginDefault
+}
+
 // BindEnvAndSetDefault implements the Config interface
 func (c *safeConfig) BindEnvAndSetDefault(key string, val interface{}, env ...string) {
 	c.SetDefault(key, val)
@@ -467,6 +520,8 @@ func NewConfig(name string, envPrefix string, envKeyReplacer *strings.Replacer)
 	config := safeConfig{
 		Viper:         viper.New(),
 		configEnvVars: map[string]struct{}{},
+		envVarsPerKey: map[string][]string{},
+		origins:       map[string]string{},
 	}
 	config.SetConfigName(name)
 	config.SetEnvPrefix(envPrefix)
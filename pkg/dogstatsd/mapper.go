@@ -0,0 +1,18 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package dogstatsd
+
+import (
+	"github.com/DataDog/datadog-agent/pkg/config"
+	"github.com/DataDog/datadog-agent/pkg/dogstatsd/internal/mapper"
+)
+
+// ValidateMappingProfiles returns an error if the given mapping profiles
+// can't be used to build a metric mapper.
+func ValidateMappingProfiles(profiles []config.MappingProfile) error {
+	_, err := mapper.NewMetricMapper(profiles, 1)
+	return err
+}
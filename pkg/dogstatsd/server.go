@@ -141,7 +141,7 @@ type Server struct {
 	health                    *health.Handle
 	metricPrefix              string
 	metricPrefixBlacklist     []string
-	metricBlocklist           []string
+	metricBlocklist           atomic.Value // []string, can be updated at runtime
 	defaultHostname           string
 	histToDist                bool
 	histToDistPrefix          string
@@ -149,7 +149,7 @@ type Server struct {
 	Debug                     *dsdServerDebug
 	debugTagsAccumulator      *tagset.HashingTagsAccumulator
 	TCapture                  *replay.TrafficCapture
-	mapper                    *mapper.MetricMapper
+	mapper                    atomic.Value // *mapper.MetricMapper, can be updated at runtime
 	eolTerminationUDP         bool
 	eolTerminationUDS         bool
 	eolTerminationNamedPipe   bool
@@ -352,7 +352,6 @@ func NewServer(demultiplexer aggregator.Demultiplexer, serverless bool) (*Server
 		health:                    health.RegisterLiveness("dogstatsd-main"),
 		metricPrefix:              metricPrefix,
 		metricPrefixBlacklist:     metricPrefixBlacklist,
-		metricBlocklist:           metricBlocklist,
 		defaultHostname:           defaultHostname,
 		histToDist:                histToDist,
 		histToDistPrefix:          histToDistPrefix,
@@ -400,18 +399,13 @@ func NewServer(demultiplexer aggregator.Demultiplexer, serverless bool) (*Server
 	// map some metric name
 	// ----------------------
 
-	cacheSize := config.Datadog.GetInt("dogstatsd_mapper_cache_size")
+	s.SetMetricBlocklist(metricBlocklist)
 
 	mappings, err := config.GetDogstatsdMappingProfiles()
 	if err != nil {
 		log.Warnf("Could not parse mapping profiles: %v", err)
-	} else if len(mappings) != 0 {
-		mapperInstance, err := mapper.NewMetricMapper(mappings, cacheSize)
-		if err != nil {
-			log.Warnf("Could not create metric mapper: %v", err)
-		} else {
-			s.mapper = mapperInstance
-		}
+	} else if err := s.SetMappingProfiles(mappings); err != nil {
+		log.Warnf("Could not create metric mapper: %v", err)
 	}
 	return s, nil
 }
@@ -673,8 +667,8 @@ func (s *Server) parseMetricMessage(metricSamples []metrics.MetricSample, parser
 		return metricSamples, err
 	}
 
-	if s.mapper != nil {
-		mapResult := s.mapper.Map(sample.name)
+	if metricMapper := s.getMapper(); metricMapper != nil {
+		mapResult := metricMapper.Map(sample.name)
 		if mapResult != nil {
 			log.Tracef("Dogstatsd mapper: metric mapped from %q to %q with tags %v", sample.name, mapResult.Name, mapResult.Tags)
 			sample.name = mapResult.Name
@@ -682,7 +676,7 @@ func (s *Server) parseMetricMessage(metricSamples []metrics.MetricSample, parser
 		}
 	}
 
-	metricSamples = enrichMetricSample(metricSamples, sample, s.metricPrefix, s.metricPrefixBlacklist, s.metricBlocklist, s.defaultHostname, origin, s.entityIDPrecedenceEnabled, s.ServerlessMode)
+	metricSamples = enrichMetricSample(metricSamples, sample, s.metricPrefix, s.metricPrefixBlacklist, s.getMetricBlocklist(), s.defaultHostname, origin, s.entityIDPrecedenceEnabled, s.ServerlessMode)
 
 	if len(sample.values) > 0 {
 		s.sharedFloat64List.put(sample.values)
@@ -900,3 +894,33 @@ func FormatDebugStats(stats []byte) (string, error) {
 func (s *Server) SetExtraTags(tags []string) {
 	s.extraTags = tags
 }
+
+// SetMetricBlocklist replaces the list of metric names dropped by the server.
+func (s *Server) SetMetricBlocklist(blocklist []string) {
+	s.metricBlocklist.Store(blocklist)
+}
+
+func (s *Server) getMetricBlocklist() []string {
+	blocklist, _ := s.metricBlocklist.Load().([]string)
+	return blocklist
+}
+
+// SetMappingProfiles replaces the profiles used to map metric names. The
+// current profiles are kept if the new ones are invalid.
+func (s *Server) SetMappingProfiles(profiles []config.MappingProfile) error {
+	if len(profiles) == 0 {
+		s.mapper.Store((*mapper.MetricMapper)(nil))
+		return nil
+	}
+	metricMapper, err := mapper.NewMetricMapper(profiles, config.Datadog.GetInt("dogstatsd_mapper_cache_size"))
+	if err != nil {
+		return err
+	}
+	s.mapper.Store(metricMapper)
+	return nil
+}
+
+func (s *Server) getMapper() *mapper.MetricMapper {
+	metricMapper, _ := s.mapper.Load().(*mapper.MetricMapper)
+	return metricMapper
+}
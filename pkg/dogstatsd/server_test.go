@@ -753,7 +753,7 @@ func TestNoMappingsConfig(t *testing.T) {
 	s, err := NewServer(demux, false)
 	require.NoError(t, err, "cannot start DSD")
 
-	assert.Nil(t, s.mapper)
+	assert.Nil(t, s.getMapper())
 
 	parser := newParser(newFloat64ListPool())
 	samples, err = s.parseMetricMessage(samples, parser, []byte("test.metric:666|g"), "", false)
@@ -761,6 +761,47 @@ func TestNoMappingsConfig(t *testing.T) {
 	assert.Len(t, samples, 1)
 }
 
+func TestRuntimeUpdates(t *testing.T) {
+	port, err := getAvailableUDPPort()
+	require.NoError(t, err)
+	config.Datadog.SetDefault("dogstatsd_port", port)
+
+	demux := mockDemultiplexer()
+	defer demux.Stop(false)
+	s, err := NewServer(demux, false)
+	require.NoError(t, err, "cannot start DSD")
+	defer s.Stop()
+
+	profiles := []config.MappingProfile{{
+		Name:     "test",
+		Prefix:   "test.",
+		Mappings: []config.MetricMapping{{Match: "test.job.*", Name: "test.job", Tags: map[string]string{"job": "$1"}}},
+	}}
+	require.NoError(t, s.SetMappingProfiles(profiles))
+	require.NotNil(t, s.getMapper())
+
+	// invalid profiles are rejected and the current mapper is kept
+	mapper := s.getMapper()
+	assert.Error(t, s.SetMappingProfiles([]config.MappingProfile{{Name: "invalid", Mappings: profiles[0].Mappings}}))
+	assert.Equal(t, mapper, s.getMapper())
+
+	parser := newParser(newFloat64ListPool())
+	samples, err := s.parseMetricMessage(nil, parser, []byte("test.job.foo:666|g"), "", false)
+	require.NoError(t, err)
+	require.Len(t, samples, 1)
+	assert.Equal(t, "test.job", samples[0].Name)
+	assert.Equal(t, []string{"job:foo"}, samples[0].Tags)
+
+	// the blocklist applies to the mapped names
+	s.SetMetricBlocklist([]string{"test.job"})
+	samples, err = s.parseMetricMessage(nil, parser, []byte("test.job.foo:666|g"), "", false)
+	require.NoError(t, err)
+	assert.Len(t, samples, 0)
+
+	require.NoError(t, s.SetMappingProfiles(nil))
+	assert.Nil(t, s.getMapper())
+}
+
 type MetricSample struct {
 	Name  string
 	Value float64
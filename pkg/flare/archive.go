@@ -22,6 +22,7 @@ import (
 	"regexp"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/DataDog/datadog-agent/pkg/api/security"
@@ -64,6 +65,10 @@ var (
 
 	// specialized scrubber for flare content
 	flareScrubber *scrubber.Scrubber
+
+	// archiveMu serializes the creation of flares, which share flareScrubber
+	// and flareRedactions
+	archiveMu sync.Mutex
 )
 
 // SearchPaths is just an alias for a map of strings
@@ -84,8 +89,14 @@ type filePermsInfo struct {
 type ProfileData map[string][]byte
 
 func init() {
-	flareScrubber = scrubber.New()
-	scrubber.AddDefaultReplacers(flareScrubber)
+	flareScrubber = newFlareScrubber()
+}
+
+// newFlareScrubber returns a scrubber with the default replacers and the
+// flare specific ones
+func newFlareScrubber() *scrubber.Scrubber {
+	s := scrubber.New()
+	scrubber.AddDefaultReplacers(s)
 
 	// The default scrubber doesn't deal with api keys of other services, for
 	// example powerDNS which has an "api_key" field in its YAML configuration.
@@ -98,12 +109,15 @@ func init() {
 	// We want the value to be at least 2 characters which will avoid matching the first '"' from the regular
 	// replacer for api_key.
 	otherAPIKeysRx := regexp.MustCompile(`api_key\s*:\s*[a-zA-Z0-9\\\/\^\]\[\(\){}!|%:;"~><=#@$_\-\+]{2,}`)
-	flareScrubber.AddReplacer(scrubber.SingleLine, scrubber.Replacer{
+	s.AddReplacer(scrubber.SingleLine, scrubber.Replacer{
 		Regex: otherAPIKeysRx,
 		ReplFunc: func(b []byte) []byte {
 			return []byte("api_key: \"********\"")
 		},
+		Name: "api_key",
 	})
+
+	return s
 }
 
 // CreatePerformanceProfile adds a set of heap and CPU profiles into target, using cpusec as the CPU
@@ -152,18 +166,19 @@ func CreatePerformanceProfile(prefix, debugURL string, cpusec int, target *Profi
 	return nil
 }
 
-// CreateArchive packages up the files
-func CreateArchive(local bool, distPath, pyChecksPath string, logFilePaths []string, pdata ProfileData, ipcError error) (string, error) {
+// CreateArchive packages up the files, the optional sections of the flare
+// being selected by the given profile
+func CreateArchive(local bool, distPath, pyChecksPath string, logFilePaths []string, pdata ProfileData, ipcError error, profile Profile) (string, error) {
 	zipFilePath := getArchivePath()
 	confSearchPaths := SearchPaths{
 		"":        config.Datadog.GetString("confd_path"),
 		"dist":    filepath.Join(distPath, "conf.d"),
 		"checksd": pyChecksPath,
 	}
-	return createArchive(confSearchPaths, local, zipFilePath, logFilePaths, pdata, ipcError)
+	return createArchive(confSearchPaths, local, zipFilePath, logFilePaths, pdata, ipcError, profile)
 }
 
-func createArchive(confSearchPaths SearchPaths, local bool, zipFilePath string, logFilePaths []string, pdata ProfileData, ipcError error) (string, error) {
+func createArchive(confSearchPaths SearchPaths, local bool, zipFilePath string, logFilePaths []string, pdata ProfileData, ipcError error, profile Profile) (string, error) {
 	/** WARNING
 	 *
 	 * When adding data to flares, carefully analyze the what is being added
@@ -173,6 +188,14 @@ func createArchive(confSearchPaths SearchPaths, local bool, zipFilePath string,
 	 * not capture data containing secrets, than to scrub that data.
 	 */
 
+	archiveMu.Lock()
+	defer archiveMu.Unlock()
+
+	if err := configureFlareScrubber(); err != nil {
+		return "", err
+	}
+	flareRedactions.reset()
+
 	tempDir, err := createTempDir()
 	if err != nil {
 		return "", err
@@ -203,9 +226,11 @@ func createArchive(confSearchPaths SearchPaths, local bool, zipFilePath string,
 			if err != nil {
 				return "", err
 			}
-			err = writeConfigCheck(tempDir, hostname, msg)
-			if err != nil {
-				return "", err
+			if profile.Includes(SectionConfigs) {
+				err = writeConfigCheck(tempDir, hostname, msg)
+				if err != nil {
+					return "", err
+				}
 			}
 		} else {
 			// Can't reach the agent, mention it in those two files
@@ -213,9 +238,11 @@ func createArchive(confSearchPaths SearchPaths, local bool, zipFilePath string,
 			if err != nil {
 				return "", err
 			}
-			err = writeConfigCheck(tempDir, hostname, []byte("unable to get loaded checks config, is the agent running?"))
-			if err != nil {
-				return "", err
+			if profile.Includes(SectionConfigs) {
+				err = writeConfigCheck(tempDir, hostname, []byte("unable to get loaded checks config, is the agent running?"))
+				if err != nil {
+					return "", err
+				}
 			}
 		}
 	} else {
@@ -225,24 +252,30 @@ func createArchive(confSearchPaths SearchPaths, local bool, zipFilePath string,
 			log.Errorf("Could not zip status: %s", err)
 		}
 
-		err = zipConfigCheck(tempDir, hostname)
-		if err != nil {
-			log.Errorf("Could not zip config check: %s", err)
+		if profile.Includes(SectionConfigs) {
+			err = zipConfigCheck(tempDir, hostname)
+			if err != nil {
+				log.Errorf("Could not zip config check: %s", err)
+			}
 		}
 
-		err = zipAgentTaggerList(tempDir, hostname)
-		if err != nil {
-			log.Errorf("Could not zip tagger list: %s", err)
-		}
+		if profile.Includes(SectionTaggerList) {
+			err = zipAgentTaggerList(tempDir, hostname)
+			if err != nil {
+				log.Errorf("Could not zip tagger list: %s", err)
+			}
 
-		err = zipWorkloadList(tempDir, hostname)
-		if err != nil {
-			log.Errorf("Could not zip workload list: %s", err)
+			err = zipProcessAgentTaggerList(tempDir, hostname)
+			if err != nil {
+				log.Errorf("Could not zip process-agent tagger list: %s", err)
+			}
 		}
 
-		err = zipProcessAgentTaggerList(tempDir, hostname)
-		if err != nil {
-			log.Errorf("Could not zip process-agent tagger list: %s", err)
+		if profile.Includes(SectionWorkloadList) {
+			err = zipWorkloadList(tempDir, hostname)
+			if err != nil {
+				log.Errorf("Could not zip workload list: %s", err)
+			}
 		}
 
 		err = zipProcessChecks(tempDir, hostname, config.GetProcessAPIAddressPort)
@@ -256,20 +289,24 @@ func createArchive(confSearchPaths SearchPaths, local bool, zipFilePath string,
 		permsInfos.add(security.GetAuthTokenFilepath())
 	}
 
-	err = zipConfigFiles(tempDir, hostname, confSearchPaths, permsInfos)
-	if err != nil {
-		log.Errorf("Could not zip config: %s", err)
-	}
-
-	err = zipExpVar(tempDir, hostname)
-	if err != nil {
-		log.Errorf("Could not zip exp var: %s", err)
+	if profile.Includes(SectionConfigs) {
+		err = zipConfigFiles(tempDir, hostname, confSearchPaths, permsInfos)
+		if err != nil {
+			log.Errorf("Could not zip config: %s", err)
+		}
 	}
 
-	if config.Datadog.GetBool("system_probe_config.enabled") {
-		err = zipSystemProbeStats(tempDir, hostname)
+	if profile.Includes(SectionExpvars) {
+		err = zipExpVar(tempDir, hostname)
 		if err != nil {
-			log.Errorf("Could not zip system probe exp var stats: %s", err)
+			log.Errorf("Could not zip exp var: %s", err)
+		}
+
+		if config.Datadog.GetBool("system_probe_config.enabled") {
+			err = zipSystemProbeStats(tempDir, hostname)
+			if err != nil {
+				log.Errorf("Could not zip system probe exp var stats: %s", err)
+			}
 		}
 	}
 
@@ -369,12 +406,14 @@ func createArchive(confSearchPaths SearchPaths, local bool, zipFilePath string,
 		log.Errorf("Could not export Windows Datadog Registry: %s", err)
 	}
 
-	// force a log flush before zipping them
-	log.Flush()
-	for _, logFilePath := range logFilePaths {
-		err = zipLogFiles(tempDir, hostname, logFilePath, permsInfos)
-		if err != nil {
-			log.Errorf("Could not zip logs: %s", err)
+	if profile.Includes(SectionLogs) {
+		// force a log flush before zipping them
+		log.Flush()
+		for _, logFilePath := range logFilePaths {
+			err = zipLogFiles(tempDir, hostname, logFilePath, permsInfos)
+			if err != nil {
+				log.Errorf("Could not zip logs: %s", err)
+			}
 		}
 	}
 
@@ -383,7 +422,7 @@ func createArchive(confSearchPaths SearchPaths, local bool, zipFilePath string,
 		log.Errorf("Could not zip install_info: %s", err)
 	}
 
-	if pdata != nil {
+	if pdata != nil && profile.Includes(SectionProfiles) {
 		err = zipPerformanceProfile(tempDir, hostname, pdata)
 		if err != nil {
 			log.Errorf("Could not zip performance profile: %s", err)
@@ -395,6 +434,10 @@ func createArchive(confSearchPaths SearchPaths, local bool, zipFilePath string,
 		log.Errorf("Could not write permissions.log file: %s", err)
 	}
 
+	if err := writeManifest(tempDir, hostname, profile); err != nil {
+		log.Errorf("Could not write the flare manifest: %s", err)
+	}
+
 	// File format is determined based on `zipFilePath` extension
 	err = archiver.Archive([]string{filepath.Join(tempDir, hostname)}, zipFilePath)
 	if err != nil {
@@ -960,10 +1003,11 @@ func walkConfigFilePaths(tempDir, hostname string, confSearchPaths SearchPaths,
 // or proprietary information. For example, do not include arbitrary
 // environment variables.
 func writeScrubbedFile(filename string, data []byte) error {
-	scrubbed, err := flareScrubber.ScrubBytes(data)
+	scrubbed, redactions, err := flareScrubber.ScrubBytesWithRedactions(data)
 	if err != nil {
 		return err
 	}
+	flareRedactions.record(filename, redactions)
 	return ioutil.WriteFile(filename, scrubbed, os.ModePerm)
 }
 
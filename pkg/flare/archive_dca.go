@@ -37,6 +37,13 @@ func CreateDCAArchive(local bool, distPath, logFilePath string) (string, error)
 }
 
 func createDCAArchive(zipFilePath string, local bool, confSearchPaths SearchPaths, logFilePath string) (string, error) {
+	archiveMu.Lock()
+	defer archiveMu.Unlock()
+
+	if err := configureFlareScrubber(); err != nil {
+		return "", err
+	}
+
 	tempDir, err := createTempDir()
 	if err != nil {
 		return "", err
@@ -24,6 +24,13 @@ import (
 func CreateSecurityAgentArchive(local bool, logFilePath string, runtimeStatus, complianceStatus map[string]interface{}) (string, error) {
 	zipFilePath := getArchivePath()
 
+	archiveMu.Lock()
+	defer archiveMu.Unlock()
+
+	if err := configureFlareScrubber(); err != nil {
+		return "", err
+	}
+
 	tempDir, err := createTempDir()
 	if err != nil {
 		return "", err
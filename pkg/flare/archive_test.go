@@ -31,6 +31,7 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/config"
 	"github.com/DataDog/datadog-agent/pkg/config/settings"
 	tagger_api "github.com/DataDog/datadog-agent/pkg/tagger/api"
+	"github.com/DataDog/datadog-agent/pkg/util/scrubber"
 	"github.com/DataDog/datadog-agent/pkg/workloadmeta"
 )
 
@@ -60,6 +61,12 @@ func createTestDirStructure(
 	return srcDir, dstDir, nil
 }
 
+func fullProfile(t *testing.T) Profile {
+	profile, err := GetProfile(ProfileFull)
+	require.NoError(t, err)
+	return profile
+}
+
 func TestArchiveName(t *testing.T) {
 	// test with No log level set
 	zipFilePath := getArchivePath()
@@ -91,7 +98,7 @@ func TestCreateArchive(t *testing.T) {
 	mockConfig.Set("confd_path", "./test/confd")
 	mockConfig.Set("log_file", "./test/logs/agent.log")
 	zipFilePath := getArchivePath()
-	filePath, err := createArchive(SearchPaths{}, true, zipFilePath, []string{""}, nil, nil)
+	filePath, err := createArchive(SearchPaths{}, true, zipFilePath, []string{""}, nil, nil, fullProfile(t))
 
 	require.Nil(t, err)
 	require.Equal(t, zipFilePath, filePath)
@@ -114,7 +121,7 @@ func TestCreateArchiveAndGoRoutines(t *testing.T) {
 	pprofURL = ts.URL
 
 	zipFilePath := getArchivePath()
-	filePath, err := createArchive(SearchPaths{}, true, zipFilePath, []string{""}, nil, nil)
+	filePath, err := createArchive(SearchPaths{}, true, zipFilePath, []string{""}, nil, nil, fullProfile(t))
 
 	require.Nil(t, err)
 	require.Equal(t, zipFilePath, filePath)
@@ -157,7 +164,7 @@ func TestCreateArchiveAndGoRoutines(t *testing.T) {
 func TestCreateArchiveBadConfig(t *testing.T) {
 	common.SetupConfig("")
 	zipFilePath := getArchivePath()
-	filePath, err := createArchive(SearchPaths{}, true, zipFilePath, []string{""}, nil, nil)
+	filePath, err := createArchive(SearchPaths{}, true, zipFilePath, []string{""}, nil, nil, fullProfile(t))
 
 	require.Nil(t, err)
 	require.Equal(t, zipFilePath, filePath)
@@ -207,7 +214,7 @@ func TestIncludeSystemProbeConfig(t *testing.T) {
 	defer os.Remove("./test/system-probe.yaml")
 
 	zipFilePath := getArchivePath()
-	filePath, err := createArchive(SearchPaths{"": "./test/confd"}, true, zipFilePath, []string{""}, nil, nil)
+	filePath, err := createArchive(SearchPaths{"": "./test/confd"}, true, zipFilePath, []string{""}, nil, nil, fullProfile(t))
 	assert.NoError(err)
 	assert.Equal(zipFilePath, filePath)
 
@@ -235,7 +242,7 @@ func TestIncludeConfigFiles(t *testing.T) {
 
 	common.SetupConfig("./test")
 	zipFilePath := getArchivePath()
-	filePath, err := createArchive(SearchPaths{"": "./test/confd"}, true, zipFilePath, []string{""}, nil, nil)
+	filePath, err := createArchive(SearchPaths{"": "./test/confd"}, true, zipFilePath, []string{""}, nil, nil, fullProfile(t))
 
 	assert.NoError(err)
 	assert.Equal(zipFilePath, filePath)
@@ -424,7 +431,7 @@ func TestPerformanceProfile(t *testing.T) {
 		"third":  []byte{},
 	}
 	zipFilePath := getArchivePath()
-	filePath, err := createArchive(SearchPaths{}, true, zipFilePath, []string{""}, testProfile, nil)
+	filePath, err := createArchive(SearchPaths{}, true, zipFilePath, []string{""}, testProfile, nil, fullProfile(t))
 
 	require.NoError(t, err)
 	require.Equal(t, zipFilePath, filePath)
@@ -454,6 +461,67 @@ func TestPerformanceProfile(t *testing.T) {
 	assert.True(t, cpu, "cpu.profile should've been included")
 }
 
+func TestCreateArchiveMinimalProfile(t *testing.T) {
+	common.SetupConfig("./test")
+	profile, err := GetProfile(ProfileMinimal)
+	require.NoError(t, err)
+
+	testProfile := ProfileData{"first": []byte{}}
+	zipFilePath := getArchivePath()
+	filePath, err := createArchive(SearchPaths{"": "./test/confd"}, true, zipFilePath, []string{""}, testProfile, nil, profile)
+	require.NoError(t, err)
+	defer os.Remove(filePath)
+
+	manifest, err := ReadManifest(filePath)
+	require.NoError(t, err)
+	assert.Equal(t, ProfileMinimal, manifest.Profile)
+	assert.Empty(t, manifest.Sections)
+
+	z, err := zip.OpenReader(filePath)
+	require.NoError(t, err)
+	defer z.Close()
+
+	var files []string
+	for _, f := range z.File {
+		if f.FileInfo().IsDir() || path.Base(f.Name) == ManifestFilename {
+			continue
+		}
+		files = append(files, f.Name)
+		assert.NotContains(t, f.Name, "/etc/", "configuration files shouldn't be included")
+		assert.NotEqual(t, "config-check.log", path.Base(f.Name), "check configurations shouldn't be included")
+		assert.NotEqual(t, "first", path.Base(f.Name), "performance profiles shouldn't be included")
+		assert.NotEqual(t, "expvar", path.Base(path.Dir(f.Name)), "expvars shouldn't be included")
+	}
+
+	// every file of the archive is listed in the manifest
+	listed := make([]string, 0, len(manifest.Files))
+	for _, entry := range manifest.Files {
+		listed = append(listed, entry.Path)
+	}
+	assert.ElementsMatch(t, files, listed)
+}
+
+func TestAdditionalScrubberPatterns(t *testing.T) {
+	config.Datadog.Set("flare_additional_scrubber_patterns", []string{`customer-[0-9]+`})
+	defer config.Datadog.Set("flare_additional_scrubber_patterns", []string{})
+	defer configureFlareScrubber()
+	require.NoError(t, configureFlareScrubber())
+	flareRedactions.reset()
+
+	dir := t.TempDir()
+	filename := path.Join(dir, "test.log")
+	err := writeScrubbedFile(filename, []byte("serving customer-1234 and customer-42\npassword: secret"))
+	require.NoError(t, err)
+
+	got, err := ioutil.ReadFile(filename)
+	require.NoError(t, err)
+	assert.Equal(t, "serving ******** and ********\npassword: \"********\"", string(got))
+	assert.Equal(t, scrubber.Redactions{"customer-[0-9]+": 2, "password": 1}, flareRedactions.get(filename))
+
+	config.Datadog.Set("flare_additional_scrubber_patterns", []string{`customer-[0-9`})
+	assert.Error(t, configureFlareScrubber())
+}
+
 // Test that writeScrubbedFile actually scrubs third-party API keys.
 func TestRedactingOtherServicesApiKey(t *testing.T) {
 	dir := t.TempDir()
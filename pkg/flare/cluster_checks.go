@@ -17,6 +17,7 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/api/util"
 	"github.com/DataDog/datadog-agent/pkg/autodiscovery/providers/names"
 	"github.com/DataDog/datadog-agent/pkg/clusteragent/clusterchecks/types"
+	"github.com/DataDog/datadog-agent/pkg/collector/check"
 	"github.com/DataDog/datadog-agent/pkg/config"
 )
 
@@ -75,6 +76,16 @@ func GetClusterChecks(w io.Writer, checkName string) error {
 		fmt.Fprintln(w, fmt.Sprintf("=== %s configurations ===", color.RedString("Unassigned")))
 		for _, c := range cr.Dangling {
 			PrintConfig(w, c, checkName)
+			if checkName != "" && c.Name != checkName {
+				continue
+			}
+			for _, inst := range c.Instances {
+				ID := string(check.BuildID(c.Name, inst, c.InitConfig))
+				if reason, found := cr.DanglingReasons[ID]; found {
+					fmt.Fprintln(w, fmt.Sprintf("%s: %s", color.BlueString("Not dispatched"), color.RedString(reason)))
+					break
+				}
+			}
 		}
 		fmt.Fprintln(w, "")
 	}
@@ -0,0 +1,196 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package flare
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/DataDog/datadog-agent/pkg/config"
+	"github.com/DataDog/datadog-agent/pkg/util/scrubber"
+)
+
+// ManifestFilename is the name of the file listing the content of a flare
+// archive, at the root of the archive
+const ManifestFilename = "flare_manifest.json"
+
+// Manifest lists the files of a flare archive along with the redactions
+// applied to each of them
+type Manifest struct {
+	Profile  string          `json:"profile"`
+	Sections []string        `json:"sections"`
+	Files    []ManifestEntry `json:"files"`
+}
+
+// ManifestEntry describes a file of a flare archive
+type ManifestEntry struct {
+	Path       string              `json:"path"`
+	Size       int64               `json:"size"`
+	Redactions scrubber.Redactions `json:"redactions,omitempty"`
+}
+
+// redactionsRecorder keeps track of the redactions applied to the files
+// written by writeScrubbedFile, per absolute file path
+type redactionsRecorder struct {
+	sync.Mutex
+	files map[string]scrubber.Redactions
+}
+
+func (r *redactionsRecorder) reset() {
+	r.Lock()
+	defer r.Unlock()
+	r.files = make(map[string]scrubber.Redactions)
+}
+
+func (r *redactionsRecorder) record(filename string, redactions scrubber.Redactions) {
+	if len(redactions) == 0 {
+		return
+	}
+
+	r.Lock()
+	defer r.Unlock()
+	if r.files == nil {
+		r.files = make(map[string]scrubber.Redactions)
+	}
+	r.files[filename] = redactions
+}
+
+func (r *redactionsRecorder) get(filename string) scrubber.Redactions {
+	r.Lock()
+	defer r.Unlock()
+	return r.files[filename]
+}
+
+var flareRedactions redactionsRecorder
+
+// configureFlareScrubber adds the patterns configured with
+// `flare_additional_scrubber_patterns` to the replacers of the flare scrubber.
+// The keys of `flare_stripped_keys` are already part of its default replacers.
+func configureFlareScrubber() error {
+	s := newFlareScrubber()
+
+	for _, pattern := range config.Datadog.GetStringSlice("flare_additional_scrubber_patterns") {
+		rx, err := regexp.Compile(pattern)
+		if err != nil {
+			return fmt.Errorf("invalid pattern %q in flare_additional_scrubber_patterns: %v", pattern, err)
+		}
+		s.AddReplacer(scrubber.SingleLine, scrubber.Replacer{
+			Regex: rx,
+			Repl:  []byte("********"),
+		})
+	}
+
+	flareScrubber = s
+	return nil
+}
+
+// writeManifest lists the files of the flare directory, along with their
+// recorded redactions, in the manifest of the flare
+func writeManifest(tempDir, hostname string, profile Profile) error {
+	root := filepath.Join(tempDir, hostname)
+	manifest := Manifest{
+		Profile:  profile.Name,
+		Sections: profile.Sections(),
+		Files:    []ManifestEntry{},
+	}
+
+	err := filepath.Walk(root, func(src string, f os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if f.IsDir() {
+			return nil
+		}
+
+		relPath, err := filepath.Rel(tempDir, src)
+		if err != nil {
+			return err
+		}
+		manifest.Files = append(manifest.Files, ManifestEntry{
+			Path:       filepath.ToSlash(relPath),
+			Size:       f.Size(),
+			Redactions: flareRedactions.get(src),
+		})
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	sort.Slice(manifest.Files, func(i, j int) bool { return manifest.Files[i].Path < manifest.Files[j].Path })
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(root, ManifestFilename), data, os.ModePerm)
+}
+
+// ReadManifest reads the manifest of the given flare archive
+func ReadManifest(archivePath string) (*Manifest, error) {
+	r, err := zip.OpenReader(archivePath)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	for _, f := range r.File {
+		if filepath.Base(f.Name) != ManifestFilename {
+			continue
+		}
+
+		rc, err := f.Open()
+		if err != nil {
+			return nil, err
+		}
+		defer rc.Close()
+
+		data, err := ioutil.ReadAll(rc)
+		if err != nil {
+			return nil, err
+		}
+
+		manifest := &Manifest{}
+		if err := json.Unmarshal(data, manifest); err != nil {
+			return nil, fmt.Errorf("invalid flare manifest: %v", err)
+		}
+		return manifest, nil
+	}
+
+	return nil, fmt.Errorf("no %s found in %s", ManifestFilename, archivePath)
+}
+
+// PrintManifest writes a human readable version of the manifest to w
+func PrintManifest(w io.Writer, manifest *Manifest) {
+	fmt.Fprintf(w, "Flare profile: %s\n", manifest.Profile)
+	sections := strings.Join(manifest.Sections, ", ")
+	if sections == "" {
+		sections = "none"
+	}
+	fmt.Fprintf(w, "Optional sections included: %s\n", sections)
+	fmt.Fprintf(w, "Files (%d):\n", len(manifest.Files))
+	for _, entry := range manifest.Files {
+		fmt.Fprintf(w, "  %s (%d bytes)\n", entry.Path, entry.Size)
+
+		names := make([]string, 0, len(entry.Redactions))
+		for name := range entry.Redactions {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			fmt.Fprintf(w, "    redacted %d x %s\n", entry.Redactions[name], name)
+		}
+	}
+}
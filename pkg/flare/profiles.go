@@ -0,0 +1,112 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package flare
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/DataDog/datadog-agent/pkg/config"
+)
+
+// Optional sections of a flare, a flare profile selects which of them are
+// included in the archive.
+const (
+	SectionLogs         = "logs"
+	SectionConfigs      = "configs"
+	SectionExpvars      = "expvars"
+	SectionWorkloadList = "workload_list"
+	SectionTaggerList   = "tagger_list"
+	SectionProfiles     = "profiles"
+)
+
+// Built-in flare profiles
+const (
+	ProfileFull    = "full"
+	ProfileMinimal = "minimal"
+)
+
+var allSections = []string{
+	SectionLogs,
+	SectionConfigs,
+	SectionExpvars,
+	SectionWorkloadList,
+	SectionTaggerList,
+	SectionProfiles,
+}
+
+var builtinProfiles = map[string][]string{
+	ProfileFull:    allSections,
+	ProfileMinimal: {},
+}
+
+// Profile is a named selection of the optional flare sections
+type Profile struct {
+	Name     string
+	sections map[string]struct{}
+}
+
+// Includes returns whether the given section is part of the profile
+func (p Profile) Includes(section string) bool {
+	_, found := p.sections[section]
+	return found
+}
+
+// Sections returns the sorted list of the sections of the profile
+func (p Profile) Sections() []string {
+	sections := make([]string, 0, len(p.sections))
+	for section := range p.sections {
+		sections = append(sections, section)
+	}
+	sort.Strings(sections)
+	return sections
+}
+
+// GetProfile returns the flare profile with the given name, either a built-in
+// one or one defined in `flare_profiles`. An empty name selects the profile
+// configured by `flare_profile`.
+func GetProfile(name string) (Profile, error) {
+	if name == "" {
+		name = config.Datadog.GetString("flare_profile")
+	}
+	if name == "" {
+		name = ProfileFull
+	}
+
+	sections, found := builtinProfiles[name]
+	if !found {
+		sections, found = config.Datadog.GetStringMapStringSlice("flare_profiles")[name]
+	}
+	if !found {
+		return Profile{}, fmt.Errorf("unknown flare profile %q", name)
+	}
+
+	return newProfile(name, sections)
+}
+
+func newProfile(name string, sections []string) (Profile, error) {
+	p := Profile{
+		Name:     name,
+		sections: make(map[string]struct{}, len(sections)),
+	}
+	for _, section := range sections {
+		if !isSection(section) {
+			return Profile{}, fmt.Errorf("flare profile %q: unknown section %q, valid sections are: %s", name, section, strings.Join(allSections, ", "))
+		}
+		p.sections[section] = struct{}{}
+	}
+	return p, nil
+}
+
+func isSection(section string) bool {
+	for _, s := range allSections {
+		if s == section {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,50 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package flare
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/pkg/config"
+)
+
+func TestGetProfile(t *testing.T) {
+	profile, err := GetProfile("")
+	require.NoError(t, err)
+	assert.Equal(t, ProfileFull, profile.Name)
+	for _, section := range allSections {
+		assert.True(t, profile.Includes(section))
+	}
+
+	profile, err = GetProfile(ProfileMinimal)
+	require.NoError(t, err)
+	assert.Empty(t, profile.Sections())
+
+	_, err = GetProfile("unknown")
+	assert.Error(t, err)
+}
+
+func TestGetConfiguredProfile(t *testing.T) {
+	config.Datadog.Set("flare_profiles", map[string][]string{
+		"regulated": {SectionExpvars, SectionConfigs},
+		"invalid":   {"core_dumps"},
+	})
+	config.Datadog.Set("flare_profile", "regulated")
+	defer config.Datadog.Set("flare_profiles", map[string][]string{})
+	defer config.Datadog.Set("flare_profile", ProfileFull)
+
+	profile, err := GetProfile("")
+	require.NoError(t, err)
+	assert.Equal(t, "regulated", profile.Name)
+	assert.Equal(t, []string{SectionConfigs, SectionExpvars}, profile.Sections())
+	assert.False(t, profile.Includes(SectionLogs))
+
+	_, err = GetProfile("invalid")
+	assert.Error(t, err)
+}
@@ -201,10 +201,15 @@ type DefaultForwarder struct {
 
 	domainForwarders map[string]*domainForwarder
 	domainResolvers  map[string]resolver.DomainResolver
+	domainsMutex     sync.RWMutex // To protect domainForwarders and domainResolvers updated at runtime
 	healthChecker    *forwarderHealth
 	internalState    *atomic.Uint32
 	m                sync.Mutex // To control Start/Stop races
 
+	retryQueuePayloadsTotalMaxSize int
+	connectionResetInterval        time.Duration
+	flushToDiskMemRatio            float64
+
 	completionHandler transaction.HTTPCompletionHandler
 
 	agentName                       string
@@ -227,6 +232,9 @@ func NewDefaultForwarder(options *Options) *DefaultForwarder {
 		},
 		completionHandler: options.CompletionHandler,
 		agentName:         agentName,
+
+		retryQueuePayloadsTotalMaxSize: options.RetryQueuePayloadsTotalMaxSize,
+		connectionResetInterval:        options.ConnectionResetInterval,
 	}
 	var optionalRemovalPolicy *retry.FileRemovalPolicy
 	storageMaxSize := config.Datadog.GetInt64("forwarder_storage_max_size_in_bytes")
@@ -263,6 +271,7 @@ func NewDefaultForwarder(options *Options) *DefaultForwarder {
 	}
 
 	flushToDiskMemRatio := config.Datadog.GetFloat64("forwarder_flush_to_disk_mem_ratio")
+	f.flushToDiskMemRatio = flushToDiskMemRatio
 	domainForwarderSort := transaction.SortByCreatedTimeAndPriority{HighPriorityFirst: true}
 	transactionContainerSort := transaction.SortByCreatedTimeAndPriority{HighPriorityFirst: false}
 
@@ -343,6 +352,9 @@ func (f *DefaultForwarder) Start() error {
 		return fmt.Errorf("the forwarder is already started")
 	}
 
+	f.domainsMutex.RLock()
+	defer f.domainsMutex.RUnlock()
+
 	for _, df := range f.domainForwarders {
 		_ = df.Start()
 	}
@@ -375,6 +387,9 @@ func (f *DefaultForwarder) Stop() {
 
 	f.internalState.Store(Stopped)
 
+	f.domainsMutex.Lock()
+	defer f.domainsMutex.Unlock()
+
 	purgeTimeout := config.Datadog.GetDuration("forwarder_stop_timeout") * time.Second
 	if purgeTimeout > 0 {
 		var wg sync.WaitGroup
@@ -419,11 +434,144 @@ func (f *DefaultForwarder) State() uint32 {
 
 	return f.internalState.Load()
 }
+
+// SetKeysPerDomain updates, at runtime, the domains and the API keys the
+// forwarder sends payloads to. Domain forwarders are created for new domains
+// and stopped for the domains that are no longer present. The retry queue of
+// a domain added at runtime is never persisted on disk.
+func (f *DefaultForwarder) SetKeysPerDomain(keysPerDomain map[string][]string) error {
+	f.m.Lock()
+	defer f.m.Unlock()
+
+	newResolvers := make(map[string]resolver.DomainResolver, len(keysPerDomain))
+	for domain, apiKeys := range keysPerDomain {
+		versionedDomain, err := config.AddAgentVersionToDomain(domain, "app")
+		if err != nil {
+			return fmt.Errorf("invalid domain '%s': %v", domain, err)
+		}
+		if len(apiKeys) == 0 {
+			return fmt.Errorf("no API keys for domain '%s'", domain)
+		}
+		newResolvers[versionedDomain] = resolver.NewSingleDomainResolver(versionedDomain, apiKeys)
+	}
+
+	f.domainsMutex.Lock()
+
+	// validate everything before updating anything
+	for domain, newResolver := range newResolvers {
+		currentResolver, found := f.domainResolvers[domain]
+		if !found {
+			continue
+		}
+		if _, isSingle := currentResolver.(*resolver.SingleDomainResolver); !isSingle && !equalAPIKeys(currentResolver.GetAPIKeys(), newResolver.GetAPIKeys()) {
+			f.domainsMutex.Unlock()
+			return fmt.Errorf("the API keys of domain '%s' can't be changed at runtime", domain)
+		}
+	}
+
+	// build and start the forwarders of the new domains before updating anything,
+	// so that a forwarder failing to start leaves the current domains untouched
+	started := f.internalState.Load() == Started
+	added := make(map[string]*domainForwarder)
+	domainForwarderSort := transaction.SortByCreatedTimeAndPriority{HighPriorityFirst: true}
+	transactionContainerSort := transaction.SortByCreatedTimeAndPriority{HighPriorityFirst: false}
+	for domain, newResolver := range newResolvers {
+		if _, found := f.domainResolvers[domain]; found {
+			continue
+		}
+
+		transactionContainer := retry.BuildTransactionRetryQueue(
+			f.retryQueuePayloadsTotalMaxSize,
+			f.flushToDiskMemRatio,
+			"",
+			nil,
+			transactionContainerSort,
+			newResolver)
+		fwd := newDomainForwarder(
+			domain,
+			transactionContainer,
+			f.NumberOfWorkers,
+			f.connectionResetInterval,
+			domainForwarderSort)
+		if started {
+			if err := fwd.Start(); err != nil {
+				f.domainsMutex.Unlock()
+				for _, addedFwd := range added {
+					addedFwd.Stop(true)
+				}
+				return fmt.Errorf("could not start the forwarder for domain '%s': %v", domain, err)
+			}
+		}
+		added[domain] = fwd
+	}
+
+	// swap the domains in
+	domainResolvers := make(map[string]resolver.DomainResolver, len(newResolvers))
+	domainForwarders := make(map[string]*domainForwarder, len(newResolvers))
+	for domain, newResolver := range newResolvers {
+		if fwd, found := added[domain]; found {
+			domainResolvers[domain] = newResolver
+			domainForwarders[domain] = fwd
+			log.Infof("Forwarder started sending to domain \"%s\" (%v api key(s))", domain, len(newResolver.GetAPIKeys()))
+			continue
+		}
+
+		currentResolver := f.domainResolvers[domain]
+		if _, isSingle := currentResolver.(*resolver.SingleDomainResolver); isSingle {
+			domainResolvers[domain] = newResolver
+		} else {
+			domainResolvers[domain] = currentResolver
+		}
+		domainForwarders[domain] = f.domainForwarders[domain]
+		for _, alternateDomain := range currentResolver.GetAlternateDomains() {
+			domainForwarders[alternateDomain] = f.domainForwarders[domain]
+		}
+	}
+
+	toStop := []*domainForwarder{}
+	for domain := range f.domainResolvers {
+		if _, found := domainResolvers[domain]; !found {
+			toStop = append(toStop, f.domainForwarders[domain])
+			log.Infof("Forwarder stopped sending to domain \"%s\"", domain)
+		}
+	}
+
+	f.domainResolvers = domainResolvers
+	f.domainForwarders = domainForwarders
+
+	f.domainsMutex.Unlock()
+
+	// stopping a domain forwarder purges its transactions, so it's done once
+	// the lock is released.
+	if started {
+		for _, fwd := range toStop {
+			fwd.Stop(true)
+		}
+	}
+
+	return nil
+}
+
+func equalAPIKeys(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
 func (f *DefaultForwarder) createHTTPTransactions(endpoint transaction.Endpoint, payloads Payloads, apiKeyInQueryString bool, extra http.Header) []*transaction.HTTPTransaction {
 	return f.createAdvancedHTTPTransactions(endpoint, payloads, apiKeyInQueryString, extra, transaction.TransactionPriorityNormal, true)
 }
 
 func (f *DefaultForwarder) createAdvancedHTTPTransactions(endpoint transaction.Endpoint, payloads Payloads, apiKeyInQueryString bool, extra http.Header, priority transaction.Priority, storableOnDisk bool) []*transaction.HTTPTransaction {
+	f.domainsMutex.RLock()
+	defer f.domainsMutex.RUnlock()
+
 	transactions := make([]*transaction.HTTPTransaction, 0, len(payloads)*len(f.domainForwarders))
 	allowArbitraryTags := config.Datadog.GetBool("allow_arbitrary_tags")
 
@@ -469,13 +617,21 @@ func (f *DefaultForwarder) sendHTTPTransactions(transactions []*transaction.HTTP
 	if f.internalState.Load() == Stopped {
 		return fmt.Errorf("the forwarder is not started")
 	}
+
+	f.domainsMutex.RLock()
+	defer f.domainsMutex.RUnlock()
+
 	if config.Datadog.GetBool("telemetry.enabled") {
 		f.retryQueueDurationCapacityMutex.Lock()
 		defer f.retryQueueDurationCapacityMutex.Unlock()
 
 		now := time.Now()
 		for _, t := range transactions {
-			forwarder := f.domainForwarders[t.Domain]
+			forwarder, found := f.domainForwarders[t.Domain]
+			if !found {
+				log.Debugf("Dropping transaction to domain %s: the domain was removed", t.Domain)
+				continue
+			}
 			forwarder.sendHTTPTransactions(t)
 
 			if f.queueDurationCapacity != nil {
@@ -504,7 +660,11 @@ func (f *DefaultForwarder) sendHTTPTransactions(transactions []*transaction.HTTP
 		}
 	} else {
 		for _, t := range transactions {
-			forwarder := f.domainForwarders[t.Domain]
+			forwarder, found := f.domainForwarders[t.Domain]
+			if !found {
+				log.Debugf("Dropping transaction to domain %s: the domain was removed", t.Domain)
+				continue
+			}
 			forwarder.sendHTTPTransactions(t)
 		}
 	}
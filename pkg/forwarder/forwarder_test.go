@@ -87,6 +87,47 @@ func TestStart(t *testing.T) {
 	assert.NotNil(t, forwarder.Start())
 }
 
+func TestSetKeysPerDomain(t *testing.T) {
+	forwarder := NewDefaultForwarder(NewOptionsWithResolvers(resolver.NewSingleDomainResolvers(monoKeysDomains)))
+	require.NoError(t, forwarder.Start())
+	defer forwarder.Stop()
+
+	otherDomain := "http://app.datadoghq.eu"
+	otherVersionDomain, _ := config.AddAgentVersionToDomain(otherDomain, "app")
+
+	// add a domain and update the keys of the existing one
+	err := forwarder.SetKeysPerDomain(map[string][]string{
+		testDomain:  {"api-key-1"},
+		otherDomain: {"api-key-2"},
+	})
+	require.NoError(t, err)
+	require.Len(t, forwarder.domainForwarders, 2)
+	assert.Equal(t, Started, forwarder.domainForwarders[otherVersionDomain].State())
+	assert.Equal(t, []string{"api-key-1"}, forwarder.domainResolvers[testVersionDomain].GetAPIKeys())
+	assert.Equal(t, []string{"api-key-2"}, forwarder.domainResolvers[otherVersionDomain].GetAPIKeys())
+
+	// invalid updates are rejected without any change
+	err = forwarder.SetKeysPerDomain(map[string][]string{
+		testDomain:    {"api-key-1"},
+		"datadog.bar": nil,
+	})
+	assert.Error(t, err)
+	require.Len(t, forwarder.domainForwarders, 2)
+
+	// remove a domain
+	err = forwarder.SetKeysPerDomain(map[string][]string{
+		testDomain: {"api-key-1"},
+	})
+	require.NoError(t, err)
+	require.Len(t, forwarder.domainForwarders, 1)
+	assert.NotContains(t, forwarder.domainForwarders, otherVersionDomain)
+
+	p := []byte("A payload")
+	transactions := forwarder.createHTTPTransactions(endpoints.SeriesEndpoint, Payloads{&p}, false, nil)
+	require.Len(t, transactions, 1)
+	assert.Equal(t, testVersionDomain, transactions[0].Domain)
+}
+
 func TestStopWithoutPurgingTransaction(t *testing.T) {
 	forwarderTimeout := config.Datadog.GetDuration("forwarder_stop_timeout")
 	defer func() { config.Datadog.Set("forwarder_stop_timeout", forwarderTimeout) }()
@@ -34,15 +34,16 @@ import (
 type Builder struct {
 	ksmBuilder ksmtypes.BuilderInterface
 
-	kubeClient      clientset.Interface
-	vpaClient       vpaclientset.Interface
-	namespaces      options.NamespaceList
-	namespaceFilter string
-	ctx             context.Context
-	allowDenyList   generator.FamilyGeneratorFilter
-	metrics         *watch.ListWatchMetrics
-	shard           int32
-	totalShards     int
+	kubeClient            clientset.Interface
+	customResourceClients map[string]interface{}
+	vpaClient             vpaclientset.Interface
+	namespaces            options.NamespaceList
+	namespaceFilter       string
+	ctx                   context.Context
+	allowDenyList         generator.FamilyGeneratorFilter
+	metrics               *watch.ListWatchMetrics
+	shard                 int32
+	totalShards           int
 
 	resync time.Duration
 }
@@ -83,6 +84,7 @@ func (b *Builder) WithKubeClient(c clientset.Interface) {
 
 // WithCustomResourceClients sets the customResourceClients property of a Builder.
 func (b *Builder) WithCustomResourceClients(clients map[string]interface{}) {
+	b.customResourceClients = clients
 	b.ksmBuilder.WithCustomResourceClients(clients)
 }
 
@@ -188,8 +190,15 @@ func (b *Builder) GenerateCustomResourceStoresFunc(
 	listWatchFunc func(kubeClient interface{}, ns string, fieldSelector string) cache.ListerWatcher,
 	useAPIServerCache bool,
 ) []cache.Store {
-	return b.GenerateStores(metricFamilies, expectedType, func(kubeClient clientset.Interface, ns string, fieldSelector string) cache.ListerWatcher {
-		return listWatchFunc(kubeClient, ns, fieldSelector)
+	// custom resources can rely on a dedicated client (e.g. a dynamic
+	// client for CRDs), fallback to the kubernetes client otherwise
+	var client interface{} = b.kubeClient
+	if customResourceClient, found := b.customResourceClients[resourceName]; found {
+		client = customResourceClient
+	}
+
+	return b.GenerateStores(metricFamilies, expectedType, func(_ clientset.Interface, ns string, fieldSelector string) cache.ListerWatcher {
+		return listWatchFunc(client, ns, fieldSelector)
 	}, useAPIServerCache)
 }
 
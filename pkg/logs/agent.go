@@ -22,6 +22,7 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/logs/internal/launchers/journald"
 	"github.com/DataDog/datadog-agent/pkg/logs/internal/launchers/kubernetes"
 	"github.com/DataDog/datadog-agent/pkg/logs/internal/launchers/listener"
+	"github.com/DataDog/datadog-agent/pkg/logs/internal/launchers/podfailures"
 	"github.com/DataDog/datadog-agent/pkg/logs/internal/launchers/windowsevent"
 	"github.com/DataDog/datadog-agent/pkg/logs/internal/util/containersorpods"
 	"github.com/DataDog/datadog-agent/pkg/logs/pipeline"
@@ -79,6 +80,7 @@ func NewAgent(sources *sources.LogSources, services *service.Services, processin
 	lnchrs.AddLauncher(listener.NewLauncher(coreConfig.Datadog.GetInt("logs_config.frame_size")))
 	lnchrs.AddLauncher(journald.NewLauncher())
 	lnchrs.AddLauncher(windowsevent.NewLauncher())
+	lnchrs.AddLauncher(podfailures.NewLauncher(coreConfig.Datadog.GetBool("logs_config.pod_failures_collect")))
 	if !util.CcaInAD() {
 		lnchrs.AddLauncher(docker.NewLauncher(
 			time.Duration(coreConfig.Datadog.GetInt("logs_config.docker_client_read_timeout"))*time.Second,
@@ -165,6 +167,16 @@ func (a *Agent) Flush(ctx context.Context) {
 	a.pipelineProvider.Flush(ctx)
 }
 
+// GetPipelineProvider returns the provider of the pipelines managed by the Logs Agent.
+func (a *Agent) GetPipelineProvider() pipeline.Provider {
+	return a.pipelineProvider
+}
+
+// SetProcessingRules replaces the global processing rules of the pipelines managed by the Logs Agent.
+func (a *Agent) SetProcessingRules(processingRules []*config.ProcessingRule) {
+	a.pipelineProvider.SetProcessingRules(processingRules)
+}
+
 // Stop stops all the elements of the data pipeline
 // in the right order to prevent data loss
 func (a *Agent) Stop() {
@@ -57,16 +57,21 @@ var (
 
 // GlobalProcessingRules returns the global processing rules to apply to all logs.
 func GlobalProcessingRules() ([]*ProcessingRule, error) {
+	return GlobalProcessingRulesWithConfig(coreConfig.Datadog)
+}
+
+// GlobalProcessingRulesWithConfig returns the global processing rules defined in the given config.
+func GlobalProcessingRulesWithConfig(config coreConfig.Config) ([]*ProcessingRule, error) {
 	var rules []*ProcessingRule
 	var err error
-	raw := coreConfig.Datadog.Get("logs_config.processing_rules")
+	raw := config.Get("logs_config.processing_rules")
 	if raw == nil {
 		return rules, nil
 	}
 	if s, ok := raw.(string); ok && s != "" {
 		err = json.Unmarshal([]byte(s), &rules)
 	} else {
-		err = coreConfig.Datadog.UnmarshalKey("logs_config.processing_rules", &rules)
+		err = config.UnmarshalKey("logs_config.processing_rules", &rules)
 	}
 	if err != nil {
 		return nil, err
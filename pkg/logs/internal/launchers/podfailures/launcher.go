@@ -0,0 +1,284 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package podfailures
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/logs/auditor"
+	"github.com/DataDog/datadog-agent/pkg/logs/config"
+	"github.com/DataDog/datadog-agent/pkg/logs/internal/launchers"
+	"github.com/DataDog/datadog-agent/pkg/logs/internal/util"
+	"github.com/DataDog/datadog-agent/pkg/logs/message"
+	"github.com/DataDog/datadog-agent/pkg/logs/pipeline"
+	"github.com/DataDog/datadog-agent/pkg/logs/sources"
+	"github.com/DataDog/datadog-agent/pkg/tagger"
+	"github.com/DataDog/datadog-agent/pkg/tagger/collectors"
+	"github.com/DataDog/datadog-agent/pkg/util/containers"
+	"github.com/DataDog/datadog-agent/pkg/util/kubernetes/kubelet"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+	"github.com/DataDog/datadog-agent/pkg/workloadmeta"
+)
+
+const (
+	sourceName = "pod_failures"
+	logSource  = "kubernetes"
+
+	oomKilledReason = "OOMKilled"
+	notReadyReason  = "NotReady"
+)
+
+// waitingFailureReasons are the reasons of waiting containers reported as failures,
+// CrashLoopBackOff is not part of it as the terminations are already reported.
+var waitingFailureReasons = map[string]struct{}{
+	"ErrImagePull":               {},
+	"ImagePullBackOff":           {},
+	"InvalidImageName":           {},
+	"ErrImageNeverPull":          {},
+	"CreateContainerConfigError": {},
+	"CreateContainerError":       {},
+	"RunContainerError":          {},
+}
+
+// Launcher turns the failures of the containers of the pods running on the
+// node into logs, so that they're available next to the logs of the containers.
+// The failures are detected from the changes of the pods in workloadmeta:
+// terminations with a non-zero exit code or OOM kills, image pull and
+// configuration errors, and running containers becoming not ready because of
+// their readiness probe.
+//
+// The pods present when the launcher starts are only used as a baseline.
+type Launcher struct {
+	enabled           bool
+	workloadmetaStore workloadmeta.Store
+	tagsFunc          func(entity string) ([]string, error) // tagsFunc gets the tags of an entity from the tagger, it is in a separate field for testing purpose
+	serviceNameFunc   func(string, string) string           // serviceNameFunc gets the service name from the tagger, it is in a separate field for testing purpose
+
+	source     *sources.LogSource
+	outputChan chan *message.Message
+
+	// containers holds the last known state of the containers, by pod UID and container name
+	containers map[string]map[string]containerState
+	ranOnce    bool
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// containerState is the part of a container status used to detect its failures
+type containerState struct {
+	ready          bool
+	waitingReason  string
+	lastFinishedAt time.Time
+}
+
+// failure is the structured content of the logs sent by the launcher
+type failure struct {
+	Message      string `json:"message"`
+	Reason       string `json:"reason"`
+	ExitCode     *int32 `json:"exit_code,omitempty"`
+	PodName      string `json:"pod_name"`
+	Namespace    string `json:"kube_namespace"`
+	Container    string `json:"container_name"`
+	RestartCount int    `json:"restart_count"`
+
+	status      string
+	containerID string
+}
+
+// NewLauncher returns a new launcher.
+func NewLauncher(enabled bool) *Launcher {
+	return &Launcher{
+		enabled:           enabled,
+		workloadmetaStore: workloadmeta.GetGlobalStore(),
+		tagsFunc: func(entity string) ([]string, error) {
+			return tagger.Tag(entity, collectors.HighCardinality)
+		},
+		serviceNameFunc: util.ServiceNameFromTags,
+		containers:      make(map[string]map[string]containerState),
+	}
+}
+
+// Start starts the launcher.
+func (l *Launcher) Start(sourceProvider launchers.SourceProvider, pipelineProvider pipeline.Provider, registry auditor.Registry) {
+	if !l.enabled || l.workloadmetaStore == nil {
+		return
+	}
+
+	l.source = sources.NewLogSource(sourceName, &config.LogsConfig{
+		Source: logSource,
+	})
+	l.outputChan = pipelineProvider.NextPipelineChan()
+	l.stop = make(chan struct{})
+	l.done = make(chan struct{})
+
+	ch := l.workloadmetaStore.Subscribe(sourceName, workloadmeta.NormalPriority, workloadmeta.NewFilter(
+		[]workloadmeta.Kind{workloadmeta.KindKubernetesPod},
+		workloadmeta.SourceNodeOrchestrator,
+		workloadmeta.EventTypeAll,
+	))
+
+	go l.run(ch)
+}
+
+// Stop stops the launcher.
+func (l *Launcher) Stop() {
+	if l.stop == nil {
+		return
+	}
+
+	close(l.stop)
+	<-l.done
+}
+
+func (l *Launcher) run(ch chan workloadmeta.EventBundle) {
+	defer close(l.done)
+	defer l.workloadmetaStore.Unsubscribe(ch)
+
+	for {
+		select {
+		case evBundle, ok := <-ch:
+			if !ok {
+				return
+			}
+			l.processEvents(evBundle)
+		case <-l.stop:
+			return
+		}
+	}
+}
+
+func (l *Launcher) processEvents(evBundle workloadmeta.EventBundle) {
+	close(evBundle.Ch)
+
+	for _, event := range evBundle.Events {
+		switch event.Type {
+		case workloadmeta.EventTypeSet:
+			pod := event.Entity.(*workloadmeta.KubernetesPod)
+			for _, f := range l.processPod(pod) {
+				l.send(pod, f)
+			}
+		case workloadmeta.EventTypeUnset:
+			delete(l.containers, event.Entity.GetID().ID)
+		default:
+			log.Errorf("cannot handle event of type %d", event.Type)
+		}
+	}
+
+	l.ranOnce = true
+}
+
+// processPod updates the state of the containers of the pod and returns their new failures
+func (l *Launcher) processPod(pod *workloadmeta.KubernetesPod) []failure {
+	var failures []failure
+
+	previous := l.containers[pod.ID]
+	current := make(map[string]containerState, len(pod.ContainerStatuses))
+
+	for _, status := range pod.ContainerStatuses {
+		containerFailures := len(failures)
+		prev, found := previous[status.Name]
+		state := containerState{
+			ready:          status.Ready,
+			waitingReason:  status.WaitingReason,
+			lastFinishedAt: prev.lastFinishedAt,
+		}
+
+		for _, termination := range []*workloadmeta.KubernetesContainerTermination{status.LastTermination, status.Termination} {
+			if termination == nil || !termination.FinishedAt.After(state.lastFinishedAt) {
+				continue
+			}
+			state.lastFinishedAt = termination.FinishedAt
+
+			if termination.ExitCode != 0 || termination.Reason == oomKilledReason {
+				failures = append(failures, terminationFailure(pod, status, termination))
+			}
+		}
+
+		if _, isFailure := waitingFailureReasons[status.WaitingReason]; isFailure && status.WaitingReason != prev.waitingReason {
+			failures = append(failures, failure{
+				Message: fmt.Sprintf("Container %s of pod %s/%s is waiting: %s %s", status.Name, pod.Namespace, pod.Name, status.WaitingReason, status.WaitingMessage),
+				Reason:  status.WaitingReason,
+				status:  message.StatusError,
+			})
+		}
+
+		if found && prev.ready && !status.Ready && status.Running() {
+			failures = append(failures, failure{
+				Message: fmt.Sprintf("Container %s of pod %s/%s is not ready anymore, its readiness probe is failing", status.Name, pod.Namespace, pod.Name),
+				Reason:  notReadyReason,
+				status:  message.StatusWarning,
+			})
+		}
+
+		for i := containerFailures; i < len(failures); i++ {
+			failures[i].Container = status.Name
+			failures[i].RestartCount = status.RestartCount
+			failures[i].containerID = status.ID
+		}
+
+		current[status.Name] = state
+	}
+
+	l.containers[pod.ID] = current
+
+	// The pods present on startup are a baseline, their past failures are not reported
+	if !l.ranOnce {
+		return nil
+	}
+
+	for i := range failures {
+		failures[i].PodName = pod.Name
+		failures[i].Namespace = pod.Namespace
+	}
+
+	return failures
+}
+
+func terminationFailure(pod *workloadmeta.KubernetesPod, status workloadmeta.KubernetesContainerStatus, termination *workloadmeta.KubernetesContainerTermination) failure {
+	exitCode := termination.ExitCode
+	msg := fmt.Sprintf("Container %s of pod %s/%s terminated with exit code %d", status.Name, pod.Namespace, pod.Name, exitCode)
+	if termination.Reason != "" {
+		msg += fmt.Sprintf(" (%s)", termination.Reason)
+	}
+	if termination.Message != "" {
+		msg += ": " + termination.Message
+	}
+
+	return failure{
+		Message:  msg,
+		Reason:   termination.Reason,
+		ExitCode: &exitCode,
+		status:   message.StatusError,
+	}
+}
+
+// send sends the failure to the pipeline with the tags of the container,
+// or of the pod if the container hasn't been created yet
+func (l *Launcher) send(pod *workloadmeta.KubernetesPod, f failure) {
+	entity := kubelet.PodUIDToTaggerEntityName(pod.ID)
+	if f.containerID != "" {
+		entity = containers.BuildTaggerEntityName(f.containerID)
+	}
+
+	content, err := json.Marshal(f)
+	if err != nil {
+		log.Warnf("Cannot marshal the failure of container %s of pod %s/%s: %v", f.Container, pod.Namespace, pod.Name, err)
+		return
+	}
+
+	origin := message.NewOrigin(l.source)
+	tags, err := l.tagsFunc(entity)
+	if err != nil {
+		log.Debugf("Cannot get the tags of %s: %v", entity, err)
+	}
+	origin.SetTags(tags)
+	origin.SetService(l.serviceNameFunc(f.Container, entity))
+
+	l.outputChan <- message.NewMessage(content, origin, f.status, time.Now().UnixNano())
+}
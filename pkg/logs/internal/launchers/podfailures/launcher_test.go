@@ -0,0 +1,198 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package podfailures
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/pkg/logs/config"
+	"github.com/DataDog/datadog-agent/pkg/logs/message"
+	"github.com/DataDog/datadog-agent/pkg/logs/sources"
+	"github.com/DataDog/datadog-agent/pkg/workloadmeta"
+)
+
+func newTestLauncher() *Launcher {
+	l := NewLauncher(true)
+	l.tagsFunc = func(entity string) ([]string, error) {
+		return []string{"entity:" + entity}, nil
+	}
+	l.serviceNameFunc = func(string, string) string { return "redis" }
+	l.source = sources.NewLogSource(sourceName, &config.LogsConfig{Source: logSource})
+	l.outputChan = make(chan *message.Message, 10)
+	return l
+}
+
+func newPod(statuses ...workloadmeta.KubernetesContainerStatus) *workloadmeta.KubernetesPod {
+	return &workloadmeta.KubernetesPod{
+		EntityID: workloadmeta.EntityID{
+			Kind: workloadmeta.KindKubernetesPod,
+			ID:   "pod-uid",
+		},
+		EntityMeta: workloadmeta.EntityMeta{
+			Name:      "redis-0",
+			Namespace: "default",
+		},
+		ContainerStatuses: statuses,
+	}
+}
+
+func processPod(l *Launcher, pod *workloadmeta.KubernetesPod) {
+	l.processEvents(workloadmeta.EventBundle{
+		Events: []workloadmeta.Event{{Type: workloadmeta.EventTypeSet, Entity: pod}},
+		Ch:     make(chan struct{}),
+	})
+}
+
+func receive(t *testing.T, l *Launcher) (*message.Message, failure) {
+	t.Helper()
+
+	var msg *message.Message
+	select {
+	case msg = <-l.outputChan:
+	default:
+		require.FailNow(t, "no message sent")
+	}
+
+	var f failure
+	require.NoError(t, json.Unmarshal(msg.Content, &f))
+	return msg, f
+}
+
+func TestBaselineIsNotReported(t *testing.T) {
+	l := newTestLauncher()
+
+	processPod(l, newPod(workloadmeta.KubernetesContainerStatus{
+		Name:          "redis",
+		ID:            "abc",
+		WaitingReason: "CrashLoopBackOff",
+		LastTermination: &workloadmeta.KubernetesContainerTermination{
+			ExitCode:   137,
+			Reason:     "OOMKilled",
+			FinishedAt: time.Now(),
+		},
+	}))
+
+	assert.Empty(t, l.outputChan)
+	assert.Contains(t, l.containers, "pod-uid")
+}
+
+func TestTerminations(t *testing.T) {
+	l := newTestLauncher()
+	processPod(l, newPod(workloadmeta.KubernetesContainerStatus{Name: "redis", ID: "abc", Ready: true}))
+
+	finishedAt := time.Now()
+	oomKilled := newPod(workloadmeta.KubernetesContainerStatus{
+		Name:         "redis",
+		ID:           "abc",
+		RestartCount: 1,
+		Termination: &workloadmeta.KubernetesContainerTermination{
+			ExitCode:   137,
+			Reason:     "OOMKilled",
+			FinishedAt: finishedAt,
+		},
+	})
+	processPod(l, oomKilled)
+
+	msg, f := receive(t, l)
+	assert.Equal(t, message.StatusError, msg.GetStatus())
+	assert.Equal(t, []string{"entity:container_id://abc"}, msg.Origin.Tags())
+	assert.Equal(t, "redis", msg.Origin.Service())
+	assert.Equal(t, "kubernetes", msg.Origin.Source())
+	assert.Equal(t, "OOMKilled", f.Reason)
+	require.NotNil(t, f.ExitCode)
+	assert.Equal(t, int32(137), *f.ExitCode)
+	assert.Equal(t, "redis", f.Container)
+	assert.Equal(t, "redis-0", f.PodName)
+	assert.Equal(t, "default", f.Namespace)
+	assert.Equal(t, 1, f.RestartCount)
+	assert.Equal(t, "Container redis of pod default/redis-0 terminated with exit code 137 (OOMKilled)", f.Message)
+
+	// The same termination, moved to the last state after a restart, is not reported again
+	processPod(l, newPod(workloadmeta.KubernetesContainerStatus{
+		Name:            "redis",
+		ID:              "def",
+		RestartCount:    1,
+		LastTermination: oomKilled.ContainerStatuses[0].Termination,
+	}))
+	assert.Empty(t, l.outputChan)
+
+	// Successful terminations are not reported
+	processPod(l, newPod(workloadmeta.KubernetesContainerStatus{
+		Name: "redis",
+		ID:   "def",
+		Termination: &workloadmeta.KubernetesContainerTermination{
+			ExitCode:   0,
+			Reason:     "Completed",
+			FinishedAt: finishedAt.Add(time.Minute),
+		},
+	}))
+	assert.Empty(t, l.outputChan)
+}
+
+func TestImagePullFailure(t *testing.T) {
+	l := newTestLauncher()
+	processPod(l, newPod())
+
+	pending := newPod(workloadmeta.KubernetesContainerStatus{
+		Name:           "redis",
+		WaitingReason:  "ErrImagePull",
+		WaitingMessage: "manifest unknown",
+	})
+	processPod(l, pending)
+
+	msg, f := receive(t, l)
+	assert.Equal(t, message.StatusError, msg.GetStatus())
+	// The container has no ID yet, the tags of the pod are used
+	assert.Equal(t, []string{"entity:kubernetes_pod_uid://pod-uid"}, msg.Origin.Tags())
+	assert.Equal(t, "ErrImagePull", f.Reason)
+	assert.Nil(t, f.ExitCode)
+	assert.Equal(t, "redis", f.Container)
+
+	// Same reason, not reported again
+	processPod(l, pending)
+	assert.Empty(t, l.outputChan)
+
+	// Back-off is a new reason
+	pending.ContainerStatuses[0].WaitingReason = "ImagePullBackOff"
+	processPod(l, pending)
+	_, f = receive(t, l)
+	assert.Equal(t, "ImagePullBackOff", f.Reason)
+}
+
+func TestReadinessLoss(t *testing.T) {
+	l := newTestLauncher()
+	processPod(l, newPod())
+
+	// Not ready while starting up is not reported
+	processPod(l, newPod(workloadmeta.KubernetesContainerStatus{Name: "redis", ID: "abc", Ready: false}))
+	assert.Empty(t, l.outputChan)
+
+	processPod(l, newPod(workloadmeta.KubernetesContainerStatus{Name: "redis", ID: "abc", Ready: true}))
+	assert.Empty(t, l.outputChan)
+
+	processPod(l, newPod(workloadmeta.KubernetesContainerStatus{Name: "redis", ID: "abc", Ready: false}))
+	msg, f := receive(t, l)
+	assert.Equal(t, message.StatusWarning, msg.GetStatus())
+	assert.Equal(t, notReadyReason, f.Reason)
+}
+
+func TestUnsetPod(t *testing.T) {
+	l := newTestLauncher()
+	pod := newPod(workloadmeta.KubernetesContainerStatus{Name: "redis", ID: "abc", Ready: true})
+	processPod(l, pod)
+	require.Contains(t, l.containers, "pod-uid")
+
+	l.processEvents(workloadmeta.EventBundle{
+		Events: []workloadmeta.Event{{Type: workloadmeta.EventTypeUnset, Entity: pod}},
+		Ch:     make(chan struct{}),
+	})
+	assert.NotContains(t, l.containers, "pod-uid")
+}
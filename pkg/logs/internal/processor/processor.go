@@ -23,6 +23,7 @@ type Processor struct {
 	inputChan                 chan *message.Message
 	outputChan                chan *message.Message
 	processingRules           []*config.ProcessingRule
+	processingRulesMu         sync.RWMutex
 	encoder                   Encoder
 	done                      chan struct{}
 	diagnosticMessageReceiver diagnostic.MessageReceiver
@@ -104,11 +105,27 @@ func (p *Processor) processMessage(msg *message.Message) {
 	}
 }
 
+// SetProcessingRules replaces the global processing rules applied to every message.
+func (p *Processor) SetProcessingRules(processingRules []*config.ProcessingRule) {
+	p.processingRulesMu.Lock()
+	defer p.processingRulesMu.Unlock()
+	p.processingRules = processingRules
+}
+
+func (p *Processor) getProcessingRules() []*config.ProcessingRule {
+	p.processingRulesMu.RLock()
+	defer p.processingRulesMu.RUnlock()
+	return p.processingRules
+}
+
 // applyRedactingRules returns given a message if we should process it or not,
 // and a copy of the message with some fields redacted, depending on config
 func (p *Processor) applyRedactingRules(msg *message.Message) (bool, []byte) {
 	content := msg.Content
-	rules := append(p.processingRules, msg.Origin.LogSource.Config.ProcessingRules...)
+	globalRules := p.getProcessingRules()
+	rules := make([]*config.ProcessingRule, 0, len(globalRules)+len(msg.Origin.LogSource.Config.ProcessingRules))
+	rules = append(rules, globalRules...)
+	rules = append(rules, msg.Origin.LogSource.Config.ProcessingRules...)
 	for _, rule := range rules {
 		switch rule.Type {
 		case config.ExcludeAtMatch:
@@ -91,6 +91,18 @@ func TestExclusionWithInclusion(t *testing.T) {
 	assert.Nil(t, redactedMessage)
 }
 
+func TestSetProcessingRules(t *testing.T) {
+	p := &Processor{}
+	source := sources.LogSource{Config: &config.LogsConfig{}}
+
+	shouldProcess, _ := p.applyRedactingRules(newMessage([]byte("hello world"), &source, ""))
+	assert.Equal(t, true, shouldProcess)
+
+	p.SetProcessingRules([]*config.ProcessingRule{newProcessingRule("exclude_at_match", "", "world")})
+	shouldProcess, _ = p.applyRedactingRules(newMessage([]byte("hello world"), &source, ""))
+	assert.Equal(t, false, shouldProcess)
+}
+
 func TestMask(t *testing.T) {
 	p := &Processor{}
 
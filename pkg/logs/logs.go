@@ -170,6 +170,25 @@ func Flush(ctx context.Context) {
 	log.Debug("Flush in the logs-agent done.")
 }
 
+// SetGlobalProcessingRules validates and applies new global processing rules to
+// the running instance of the Logs Agent. Multi-line rules are only applied when
+// a source is started, so they are rejected here.
+func SetGlobalProcessingRules(processingRules []*config.ProcessingRule) error {
+	if err := config.ValidateProcessingRules(processingRules); err != nil {
+		return err
+	}
+	if err := config.CompileProcessingRules(processingRules); err != nil {
+		return err
+	}
+	if config.HasMultiLineRule(processingRules) {
+		return errors.New("multi_line rules can't be set globally")
+	}
+	if IsAgentRunning() && agent != nil {
+		agent.SetProcessingRules(processingRules)
+	}
+	return nil
+}
+
 // IsAgentRunning returns true if the logs-agent is running.
 func IsAgentRunning() bool {
 	return status.Get().IsRunning
@@ -8,6 +8,7 @@ package mock
 import (
 	"context"
 
+	"github.com/DataDog/datadog-agent/pkg/logs/config"
 	"github.com/DataDog/datadog-agent/pkg/logs/message"
 	"github.com/DataDog/datadog-agent/pkg/logs/pipeline"
 )
@@ -33,6 +34,8 @@ func (p *mockProvider) Stop() {}
 // Flush does nothing
 func (p *mockProvider) Flush(ctx context.Context) {}
 
+func (p *mockProvider) SetProcessingRules(processingRules []*config.ProcessingRule) {}
+
 // NextPipelineChan returns the next pipeline
 func (p *mockProvider) NextPipelineChan() chan *message.Message {
 	return p.msgChan
@@ -7,6 +7,7 @@ package pipeline
 
 import (
 	"context"
+	"sync"
 
 	"go.uber.org/atomic"
 
@@ -26,6 +27,8 @@ type Provider interface {
 	NextPipelineChan() chan *message.Message
 	// Flush flushes all pipeline contained in this Provider
 	Flush(ctx context.Context)
+	// SetProcessingRules replaces the global processing rules of all the pipelines
+	SetProcessingRules(processingRules []*config.ProcessingRule)
 }
 
 // provider implements providing logic
@@ -38,6 +41,7 @@ type provider struct {
 	endpoints                 *config.Endpoints
 
 	pipelines            []*Pipeline
+	mu                   sync.Mutex
 	currentPipelineIndex *atomic.Uint32
 	destinationsContext  *client.DestinationsContext
 
@@ -78,6 +82,8 @@ func (p *provider) Start() {
 	// This requires the auditor to be started before.
 	p.outputChan = p.auditor.Channel()
 
+	p.mu.Lock()
+	defer p.mu.Unlock()
 	for i := 0; i < p.numberOfPipelines; i++ {
 		pipeline := NewPipeline(p.outputChan, p.processingRules, p.endpoints, p.destinationsContext, p.diagnosticMessageReceiver, p.serverless, i)
 		pipeline.Start()
@@ -93,7 +99,9 @@ func (p *provider) Stop() {
 		stopper.Add(pipeline)
 	}
 	stopper.Stop()
+	p.mu.Lock()
 	p.pipelines = p.pipelines[:0]
+	p.mu.Unlock()
 	p.outputChan = nil
 }
 
@@ -108,6 +116,17 @@ func (p *provider) NextPipelineChan() chan *message.Message {
 	return nextPipeline.InputChan
 }
 
+// SetProcessingRules replaces the global processing rules of all the pipelines,
+// including the ones started later on.
+func (p *provider) SetProcessingRules(processingRules []*config.ProcessingRule) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.processingRules = processingRules
+	for _, pipeline := range p.pipelines {
+		pipeline.SetProcessingRules(processingRules)
+	}
+}
+
 // Flush flushes synchronously all the contained pipeline of this provider.
 func (p *provider) Flush(ctx context.Context) {
 	for _, p := range p.pipelines {
@@ -9,6 +9,8 @@ import (
 	"fmt"
 	"sort"
 	"strconv"
+	"strings"
+	"sync"
 
 	"github.com/DataDog/datadog-agent/pkg/config"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
@@ -48,8 +50,57 @@ const (
 var (
 	defaultAggregates  = []string(nil)
 	defaultPercentiles = []int(nil)
+	// defaultAggregatesMutex protects defaultAggregates, which can be updated at runtime
+	defaultAggregatesMutex sync.RWMutex
+
+	validAggregates = []string{maxAgg, minAgg, medianAgg, avgAgg, sumAgg, countAgg}
 )
 
+// ValidateHistogramAggregates returns an error if one of the given aggregates isn't supported
+func ValidateHistogramAggregates(aggregates []string) error {
+	for _, aggregate := range aggregates {
+		valid := false
+		for _, validAggregate := range validAggregates {
+			if aggregate == validAggregate {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			return fmt.Errorf("unsupported histogram aggregate %q, valid values are: %s", aggregate, strings.Join(validAggregates, ", "))
+		}
+	}
+	return nil
+}
+
+// SetDefaultHistogramAggregates changes the aggregates computed by the histograms
+// created from now on.
+func SetDefaultHistogramAggregates(aggregates []string) error {
+	if err := ValidateHistogramAggregates(aggregates); err != nil {
+		return err
+	}
+	defaultAggregatesMutex.Lock()
+	defer defaultAggregatesMutex.Unlock()
+	defaultAggregates = aggregates
+	return nil
+}
+
+func getDefaultAggregates() []string {
+	defaultAggregatesMutex.RLock()
+	aggregates := defaultAggregates
+	defaultAggregatesMutex.RUnlock()
+	if aggregates != nil {
+		return aggregates
+	}
+
+	defaultAggregatesMutex.Lock()
+	defer defaultAggregatesMutex.Unlock()
+	if defaultAggregates == nil {
+		defaultAggregates = config.Datadog.GetStringSlice("histogram_aggregates")
+	}
+	return defaultAggregates
+}
+
 type histogramPercentilesConfig struct {
 	Percentiles []string `mapstructure:"histogram_percentiles"`
 }
@@ -77,9 +128,7 @@ func (h *histogramPercentilesConfig) percentiles() []int {
 // NewHistogram returns a newly initialized histogram
 func NewHistogram(interval int64) *Histogram {
 	// we initialize default value on the first histogram creation
-	if defaultAggregates == nil {
-		defaultAggregates = config.Datadog.GetStringSlice("histogram_aggregates")
-	}
+	aggregates := getDefaultAggregates()
 	if defaultPercentiles == nil {
 		c := histogramPercentilesConfig{}
 		err := config.Datadog.Unmarshal(&c)
@@ -93,7 +142,7 @@ func NewHistogram(interval int64) *Histogram {
 
 	return &Histogram{
 		interval:    interval,
-		aggregates:  defaultAggregates,
+		aggregates:  aggregates,
 		percentiles: defaultPercentiles,
 	}
 }
@@ -63,7 +63,7 @@ func modelConnections(conns *network.Connections) *model.Connections {
 	cfgOnce.Do(func() {
 		agentCfg = &model.AgentConfiguration{
 			NpmEnabled: config.Datadog.GetBool("network_config.enabled"),
-			TsmEnabled: config.Datadog.GetBool("service_monitoring_config.enabled"),
+			UsmEnabled: config.Datadog.GetBool("service_monitoring_config.enabled"),
 		}
 	})
 
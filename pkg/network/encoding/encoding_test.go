@@ -127,7 +127,7 @@ func getExpectedConnections(encodedWithQueryType bool, httpOutBlob []byte) *mode
 		},
 		AgentConfiguration: &model.AgentConfiguration{
 			NpmEnabled: false,
-			TsmEnabled: false,
+			UsmEnabled: false,
 		},
 		Tags: network.GetStaticTags(1),
 	}
@@ -280,6 +280,8 @@ func TestSerialization(t *testing.T) {
 
 		// fixup: json marshaler encode nil slice as empty
 		result.Conns[0].Tags = nil
+		result.CORETelemetryByAsset = nil
+		result.PrebuiltEBPFAssets = nil
 		if runtime.GOOS != "linux" {
 			result.Conns[1].Tags = nil
 			result.Tags = nil
@@ -305,6 +307,8 @@ func TestSerialization(t *testing.T) {
 
 		// fixup: json marshaler encode nil slice as empty
 		result.Conns[0].Tags = nil
+		result.CORETelemetryByAsset = nil
+		result.PrebuiltEBPFAssets = nil
 		if runtime.GOOS != "linux" {
 			result.Conns[1].Tags = nil
 			result.Tags = nil
@@ -331,6 +335,8 @@ func TestSerialization(t *testing.T) {
 
 		// fixup: json marshaler encode nil slice as empty
 		result.Conns[0].Tags = nil
+		result.CORETelemetryByAsset = nil
+		result.PrebuiltEBPFAssets = nil
 		if runtime.GOOS != "linux" {
 			result.Conns[1].Tags = nil
 			result.Tags = nil
@@ -359,6 +365,8 @@ func TestSerialization(t *testing.T) {
 
 		// fixup: json marshaler encode nil slice as empty
 		result.Conns[0].Tags = nil
+		result.CORETelemetryByAsset = nil
+		result.PrebuiltEBPFAssets = nil
 		if runtime.GOOS != "linux" {
 			result.Conns[1].Tags = nil
 			result.Tags = nil
@@ -510,7 +518,7 @@ func TestHTTPSerializationWithLocalhostTraffic(t *testing.T) {
 		},
 		AgentConfiguration: &model.AgentConfiguration{
 			NpmEnabled: false,
-			TsmEnabled: false,
+			UsmEnabled: false,
 		},
 	}
 
@@ -0,0 +1,73 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package redact
+
+import (
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// ScrubUnstructured calls ScrubContainer for every container embedded in an
+// unstructured resource, e.g. the pod templates of custom resources.
+func ScrubUnstructured(r *unstructured.Unstructured, scrubber *DataScrubber) {
+	scrubUnstructuredValue(r.Object, scrubber)
+}
+
+func scrubUnstructuredValue(value interface{}, scrubber *DataScrubber) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			if key == "containers" || key == "initContainers" {
+				if containers, ok := field.([]interface{}); ok {
+					scrubUnstructuredContainers(containers, scrubber)
+					continue
+				}
+			}
+			scrubUnstructuredValue(field, scrubber)
+		}
+	case []interface{}:
+		for _, item := range v {
+			scrubUnstructuredValue(item, scrubber)
+		}
+	}
+}
+
+// scrubUnstructuredContainers scrubs the env vars, commands and args of the
+// containers, the other fields are left as is.
+func scrubUnstructuredContainers(containers []interface{}, scrubber *DataScrubber) {
+	for _, item := range containers {
+		obj, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		container := v1.Container{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj, &container); err != nil {
+			log.Debugf("Cannot parse container of unstructured resource, obscuring its env vars and command: %v", err)
+			delete(obj, "env")
+			delete(obj, "args")
+			if _, found := obj["command"]; found {
+				obj["command"] = []interface{}{redactedValue}
+			}
+			continue
+		}
+
+		ScrubContainer(&container, scrubber)
+
+		scrubbed, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&container)
+		if err != nil {
+			continue
+		}
+		for _, key := range []string{"env", "command", "args"} {
+			if _, found := obj[key]; found {
+				obj[key] = scrubbed[key]
+			}
+		}
+	}
+}
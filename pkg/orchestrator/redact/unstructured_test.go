@@ -0,0 +1,76 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package redact
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestScrubUnstructured(t *testing.T) {
+	r := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "argoproj.io/v1alpha1",
+		"kind":       "Rollout",
+		"spec": map[string]interface{}{
+			"password": "not a container field",
+			"template": map[string]interface{}{
+				"spec": map[string]interface{}{
+					"initContainers": []interface{}{
+						map[string]interface{}{
+							"name":    "init",
+							"command": []interface{}{"mysql", "--password", "afztyerbzio1234"},
+						},
+					},
+					"containers": []interface{}{
+						map[string]interface{}{
+							"name":  "web",
+							"image": "web:1.0",
+							"env": []interface{}{
+								map[string]interface{}{"name": "password", "value": "kqhkiG9w0BAQEFAASCAl8wggJbAgEAAoGBAOLJ"},
+								map[string]interface{}{"name": "PORT", "value": "8080"},
+							},
+							"args": []interface{}{"--debug", "afztyerbzio1234"},
+						},
+					},
+				},
+			},
+		},
+	}}
+
+	ScrubUnstructured(r, NewDefaultDataScrubber())
+
+	expected := map[string]interface{}{
+		"apiVersion": "argoproj.io/v1alpha1",
+		"kind":       "Rollout",
+		"spec": map[string]interface{}{
+			"password": "not a container field",
+			"template": map[string]interface{}{
+				"spec": map[string]interface{}{
+					"initContainers": []interface{}{
+						map[string]interface{}{
+							"name":    "init",
+							"command": []interface{}{"mysql", "--password", "********"},
+						},
+					},
+					"containers": []interface{}{
+						map[string]interface{}{
+							"name":  "web",
+							"image": "web:1.0",
+							"env": []interface{}{
+								map[string]interface{}{"name": "password", "value": "********"},
+								map[string]interface{}{"name": "PORT", "value": "8080"},
+							},
+							"args": []interface{}{"--debug", "afztyerbzio1234"},
+						},
+					},
+				},
+			},
+		},
+	}
+	assert.Equal(t, expected, r.Object)
+}
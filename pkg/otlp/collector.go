@@ -24,6 +24,8 @@ import (
 	"go.uber.org/zap/zapcore"
 
 	"github.com/DataDog/datadog-agent/pkg/config"
+	"github.com/DataDog/datadog-agent/pkg/logs/message"
+	"github.com/DataDog/datadog-agent/pkg/otlp/internal/logsagentexporter"
 	"github.com/DataDog/datadog-agent/pkg/otlp/internal/serializerexporter"
 	"github.com/DataDog/datadog-agent/pkg/serializer"
 	"github.com/DataDog/datadog-agent/pkg/util/flavor"
@@ -36,7 +38,7 @@ var (
 	pipelineError = atomic.NewError(nil)
 )
 
-func getComponents(s serializer.MetricSerializer) (
+func getComponents(s serializer.MetricSerializer, logsAgentChannel chan *message.Message) (
 	component.Factories,
 	error,
 ) {
@@ -54,11 +56,16 @@ func getComponents(s serializer.MetricSerializer) (
 		errs = append(errs, err)
 	}
 
-	exporters, err := component.MakeExporterFactoryMap(
+	exporterFactories := []component.ExporterFactory{
 		otlpexporter.NewFactory(),
 		serializerexporter.NewFactory(s),
 		loggingexporter.NewFactory(),
-	)
+	}
+	if logsAgentChannel != nil {
+		exporterFactories = append(exporterFactories, logsagentexporter.NewFactory(logsAgentChannel))
+	}
+
+	exporters, err := component.MakeExporterFactoryMap(exporterFactories...)
 	if err != nil {
 		errs = append(errs, err)
 	}
@@ -98,6 +105,8 @@ type PipelineConfig struct {
 	MetricsEnabled bool
 	// TracesEnabled states whether OTLP traces support is enabled.
 	TracesEnabled bool
+	// LogsEnabled states whether OTLP logs support is enabled.
+	LogsEnabled bool
 	// Debug contains debug configurations.
 	Debug map[string]interface{}
 
@@ -131,13 +140,14 @@ type CollectorStatus struct {
 }
 
 // NewPipeline defines a new OTLP pipeline.
-func NewPipeline(cfg PipelineConfig, s serializer.MetricSerializer) (*Pipeline, error) {
+// The logs are sent to logsAgentChannel, which may be nil when OTLP logs support is disabled.
+func NewPipeline(cfg PipelineConfig, s serializer.MetricSerializer, logsAgentChannel chan *message.Message) (*Pipeline, error) {
 	buildInfo, err := getBuildInfo()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get build info: %w", err)
 	}
 
-	factories, err := getComponents(s)
+	factories, err := getComponents(s, logsAgentChannel)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get components: %w", err)
 	}
@@ -178,15 +188,21 @@ func (p *Pipeline) Stop() {
 	p.col.Shutdown()
 }
 
-// BuildAndStart builds and starts an OTLP pipeline
-func BuildAndStart(ctx context.Context, cfg config.Config, s serializer.MetricSerializer) (*Pipeline, error) {
+// BuildAndStart builds and starts an OTLP pipeline.
+// The logs are sent to logsAgentChannel, the input of a logs agent pipeline; it is nil when the logs agent is not running.
+func BuildAndStart(ctx context.Context, cfg config.Config, s serializer.MetricSerializer, logsAgentChannel chan *message.Message) (*Pipeline, error) {
 	pcfg, err := FromAgentConfig(cfg)
 	if err != nil {
 		pipelineError.Store(fmt.Errorf("config error: %w", err))
 		return nil, pipelineError.Load()
 	}
 
-	p, err := NewPipeline(pcfg, s)
+	if pcfg.LogsEnabled && logsAgentChannel == nil {
+		log.Warn("OTLP logs support is enabled but the logs agent is not running, OTLP logs will not be collected")
+		pcfg.LogsEnabled = false
+	}
+
+	p, err := NewPipeline(pcfg, s, logsAgentChannel)
 	if err != nil {
 		pipelineError.Store(fmt.Errorf("failed to build pipeline: %w", err))
 		return nil, pipelineError.Load()
@@ -18,18 +18,19 @@ import (
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/service"
 
+	"github.com/DataDog/datadog-agent/pkg/logs/message"
 	"github.com/DataDog/datadog-agent/pkg/otlp/internal/testutil"
 	"github.com/DataDog/datadog-agent/pkg/serializer"
 )
 
 func TestGetComponents(t *testing.T) {
-	_, err := getComponents(&serializer.MockSerializer{})
+	_, err := getComponents(&serializer.MockSerializer{}, make(chan *message.Message))
 	// No duplicate component
 	require.NoError(t, err)
 }
 
 func AssertSucessfulRun(t *testing.T, pcfg PipelineConfig) {
-	p, err := NewPipeline(pcfg, &serializer.MockSerializer{})
+	p, err := NewPipeline(pcfg, &serializer.MockSerializer{}, make(chan *message.Message))
 	require.NoError(t, err)
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
@@ -54,7 +55,7 @@ func AssertSucessfulRun(t *testing.T, pcfg PipelineConfig) {
 }
 
 func AssertFailedRun(t *testing.T, pcfg PipelineConfig, expected string) {
-	p, err := NewPipeline(pcfg, &serializer.MockSerializer{})
+	p, err := NewPipeline(pcfg, &serializer.MockSerializer{}, make(chan *message.Message))
 	require.NoError(t, err)
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
@@ -72,6 +73,18 @@ func TestStartPipeline(t *testing.T) {
 	AssertSucessfulRun(t, pcfg)
 }
 
+func TestStartPipelineWithLogs(t *testing.T) {
+	pcfg := PipelineConfig{
+		OTLPReceiverConfig: testutil.OTLPConfigFromPorts("localhost", 4317, 4318),
+		TracePort:          5003,
+		MetricsEnabled:     true,
+		TracesEnabled:      true,
+		LogsEnabled:        true,
+		Metrics:            map[string]interface{}{},
+	}
+	AssertSucessfulRun(t, pcfg)
+}
+
 func TestStartPipelineFromConfig(t *testing.T) {
 	// TODO (AP-1723): Disable changing the gRPC logger before re-enabling.
 	if runtime.GOOS == "windows" {
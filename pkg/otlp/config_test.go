@@ -222,6 +222,33 @@ func TestFromEnvironmentVariables(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "only gRPC, logs enabled",
+			env: map[string]string{
+				"DD_OTLP_CONFIG_RECEIVER_PROTOCOLS_GRPC_ENDPOINT": "0.0.0.0:9999",
+				"DD_OTLP_CONFIG_LOGS_ENABLED":                     "true",
+			},
+			cfg: PipelineConfig{
+				OTLPReceiverConfig: map[string]interface{}{
+					"protocols": map[string]interface{}{
+						"grpc": map[string]interface{}{
+							"endpoint": "0.0.0.0:9999",
+						},
+					},
+				},
+				MetricsEnabled: true,
+				TracesEnabled:  true,
+				LogsEnabled:    true,
+				TracePort:      5003,
+				Metrics: map[string]interface{}{
+					"enabled":         true,
+					"tag_cardinality": "low",
+				},
+				Debug: map[string]interface{}{
+					"loglevel": "info",
+				},
+			},
+		},
 		{
 			name: "HTTP + gRPC, metrics config",
 			env: map[string]string{
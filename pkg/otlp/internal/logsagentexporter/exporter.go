@@ -0,0 +1,217 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2022-present Datadog, Inc.
+
+package logsagentexporter
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	conventions "go.opentelemetry.io/collector/semconv/v1.6.1"
+
+	"github.com/DataDog/datadog-agent/pkg/logs/config"
+	"github.com/DataDog/datadog-agent/pkg/logs/message"
+	"github.com/DataDog/datadog-agent/pkg/logs/sources"
+	"github.com/DataDog/datadog-agent/pkg/otlp/model/attributes"
+)
+
+const (
+	logSourceName = "OTLP log ingestion"
+	logSource     = "otlp"
+
+	instrumentationScopeTag        = "instrumentation_scope"
+	instrumentationScopeVersionTag = "instrumentation_scope_version"
+)
+
+// Content keys of the log messages; the Datadog trace and span IDs are used
+// to correlate the logs with the traces.
+const (
+	contentMessage        = "message"
+	contentStatus         = "status"
+	contentSeverityText   = "otel.severity_text"
+	contentSeverityNumber = "otel.severity_number"
+	contentOTelTraceID    = "otel.trace_id"
+	contentOTelSpanID     = "otel.span_id"
+	contentTraceID        = "dd.trace_id"
+	contentSpanID         = "dd.span_id"
+)
+
+// exporter translates OTLP logs into logs agent messages and sends them to a
+// logs agent pipeline, where they go through the processing rules and the sender.
+type exporter struct {
+	logsAgentChannel chan *message.Message
+	logSource        *sources.LogSource
+}
+
+func newExporter(logsAgentChannel chan *message.Message) *exporter {
+	return &exporter{
+		logsAgentChannel: logsAgentChannel,
+		logSource: sources.NewLogSource(logSourceName, &config.LogsConfig{
+			Source: logSource,
+		}),
+	}
+}
+
+func (e *exporter) ConsumeLogs(ctx context.Context, ld plog.Logs) error {
+	rls := ld.ResourceLogs()
+	for i := 0; i < rls.Len(); i++ {
+		rl := rls.At(i)
+		resourceTags := tagsFromResource(rl.Resource())
+		service := serviceFromResource(rl.Resource())
+
+		sls := rl.ScopeLogs()
+		for j := 0; j < sls.Len(); j++ {
+			sl := sls.At(j)
+			tags := make([]string, 0, len(resourceTags)+2)
+			tags = append(tags, resourceTags...)
+			tags = append(tags, tagsFromScope(sl.Scope())...)
+
+			lrs := sl.LogRecords()
+			for k := 0; k < lrs.Len(); k++ {
+				msg, err := e.toMessage(lrs.At(k), service, tags)
+				if err != nil {
+					return err
+				}
+
+				select {
+				case e.logsAgentChannel <- msg:
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// toMessage converts a log record into a logs agent message. The body, the
+// attributes and the trace context of the record are encoded in a JSON content.
+func (e *exporter) toMessage(lr plog.LogRecord, service string, tags []string) (*message.Message, error) {
+	status := statusFromSeverity(lr.SeverityNumber(), lr.SeverityText())
+
+	content := make(map[string]interface{}, lr.Attributes().Len()+8)
+	lr.Attributes().Range(func(k string, v pcommon.Value) bool {
+		content[k] = v.AsString()
+		return true
+	})
+	content[contentMessage] = lr.Body().AsString()
+	content[contentStatus] = status
+	if lr.SeverityText() != "" {
+		content[contentSeverityText] = lr.SeverityText()
+	}
+	if lr.SeverityNumber() != plog.SeverityNumberUNDEFINED {
+		content[contentSeverityNumber] = int32(lr.SeverityNumber())
+	}
+	if traceID := lr.TraceID(); !traceID.IsEmpty() {
+		content[contentOTelTraceID] = traceID.HexString()
+		content[contentTraceID] = traceIDToDatadog(traceID)
+	}
+	if spanID := lr.SpanID(); !spanID.IsEmpty() {
+		content[contentOTelSpanID] = spanID.HexString()
+		content[contentSpanID] = spanIDToDatadog(spanID)
+	}
+
+	encoded, err := json.Marshal(content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode log record: %w", err)
+	}
+
+	origin := message.NewOrigin(e.logSource)
+	origin.SetTags(tags)
+	if service != "" {
+		origin.SetService(service)
+	}
+
+	msg := message.NewMessage(encoded, origin, status, time.Now().UnixNano())
+	msg.Timestamp = timestamp(lr)
+	return msg, nil
+}
+
+// tagsFromResource converts the resource attributes having a Datadog
+// convention, such as the service or the container ones, into their Datadog tag.
+func tagsFromResource(res pcommon.Resource) []string {
+	return attributes.TagsFromAttributes(res.Attributes())
+}
+
+func serviceFromResource(res pcommon.Resource) string {
+	if service, ok := res.Attributes().Get(conventions.AttributeServiceName); ok {
+		return service.AsString()
+	}
+	return ""
+}
+
+func tagsFromScope(scope pcommon.InstrumentationScope) []string {
+	var tags []string
+	if scope.Name() != "" {
+		tags = append(tags, instrumentationScopeTag+":"+scope.Name())
+	}
+	if scope.Version() != "" {
+		tags = append(tags, instrumentationScopeVersionTag+":"+scope.Version())
+	}
+	return tags
+}
+
+// statusFromSeverity maps the severity of a log record to a message status.
+// The severity number is used when set, the severity text otherwise.
+func statusFromSeverity(number plog.SeverityNumber, text string) string {
+	switch {
+	case number >= plog.SeverityNumberFATAL:
+		return message.StatusCritical
+	case number >= plog.SeverityNumberERROR:
+		return message.StatusError
+	case number >= plog.SeverityNumberWARN:
+		return message.StatusWarning
+	case number >= plog.SeverityNumberINFO:
+		return message.StatusInfo
+	case number >= plog.SeverityNumberTRACE:
+		return message.StatusDebug
+	}
+
+	switch strings.ToLower(text) {
+	case "fatal", "critical":
+		return message.StatusCritical
+	case "error":
+		return message.StatusError
+	case "warn", "warning":
+		return message.StatusWarning
+	case "debug", "trace":
+		return message.StatusDebug
+	default:
+		return message.StatusInfo
+	}
+}
+
+// timestamp returns the time of a log record, or the time it was observed by
+// the collection system when unknown.
+func timestamp(lr plog.LogRecord) time.Time {
+	ts := lr.Timestamp()
+	if ts == 0 {
+		ts = lr.ObservedTimestamp()
+	}
+	if ts == 0 {
+		return time.Time{}
+	}
+	return ts.AsTime().UTC()
+}
+
+// traceIDToDatadog converts an OpenTelemetry trace ID into a Datadog trace ID,
+// made of its lower 64 bits.
+func traceIDToDatadog(traceID pcommon.TraceID) string {
+	bytes := traceID.Bytes()
+	return strconv.FormatUint(binary.BigEndian.Uint64(bytes[8:]), 10)
+}
+
+// spanIDToDatadog converts an OpenTelemetry span ID into a Datadog span ID.
+func spanIDToDatadog(spanID pcommon.SpanID) string {
+	bytes := spanID.Bytes()
+	return strconv.FormatUint(binary.BigEndian.Uint64(bytes[:]), 10)
+}
@@ -0,0 +1,123 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2022-present Datadog, Inc.
+
+package logsagentexporter
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+
+	"github.com/DataDog/datadog-agent/pkg/logs/message"
+)
+
+func TestConsumeLogs(t *testing.T) {
+	ts := time.Date(2022, 8, 1, 12, 30, 0, 0, time.UTC)
+
+	ld := plog.NewLogs()
+	rl := ld.ResourceLogs().AppendEmpty()
+	rl.Resource().Attributes().UpsertString("service.name", "checkout")
+	rl.Resource().Attributes().UpsertString("deployment.environment", "prod")
+	sl := rl.ScopeLogs().AppendEmpty()
+	sl.Scope().SetName("github.com/example/logger")
+	sl.Scope().SetVersion("1.2.0")
+
+	lr := sl.LogRecords().AppendEmpty()
+	lr.Body().SetStringVal("payment refused")
+	lr.SetSeverityNumber(plog.SeverityNumberERROR2)
+	lr.SetSeverityText("ERROR")
+	lr.SetTimestamp(pcommon.NewTimestampFromTime(ts))
+	lr.Attributes().UpsertString("http.method", "POST")
+	lr.SetTraceID(pcommon.NewTraceID([16]byte{0x10, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0x01, 0x02}))
+	lr.SetSpanID(pcommon.NewSpanID([8]byte{0, 0, 0, 0, 0, 0, 0x01, 0x03}))
+
+	observed := sl.LogRecords().AppendEmpty()
+	observed.Body().SetStringVal("cart loaded")
+	observed.SetObservedTimestamp(pcommon.NewTimestampFromTime(ts.Add(time.Second)))
+
+	logsAgentChannel := make(chan *message.Message, 2)
+	exp := newExporter(logsAgentChannel)
+	require.NoError(t, exp.ConsumeLogs(context.Background(), ld))
+	require.Len(t, logsAgentChannel, 2)
+
+	msg := <-logsAgentChannel
+	assert.Equal(t, message.StatusError, msg.GetStatus())
+	assert.Equal(t, ts, msg.Timestamp)
+	assert.Equal(t, "checkout", msg.Origin.Service())
+	assert.Equal(t, "otlp", msg.Origin.Source())
+	assert.ElementsMatch(t, []string{
+		"service:checkout",
+		"env:prod",
+		"instrumentation_scope:github.com/example/logger",
+		"instrumentation_scope_version:1.2.0",
+	}, msg.Origin.Tags())
+
+	var content map[string]interface{}
+	require.NoError(t, json.Unmarshal(msg.Content, &content))
+	assert.Equal(t, map[string]interface{}{
+		"message":              "payment refused",
+		"status":               "error",
+		"http.method":          "POST",
+		"otel.severity_text":   "ERROR",
+		"otel.severity_number": float64(18),
+		"otel.trace_id":        "10000000000000000000000000000102",
+		"otel.span_id":         "0000000000000103",
+		"dd.trace_id":          "258",
+		"dd.span_id":           "259",
+	}, content)
+
+	msg = <-logsAgentChannel
+	assert.Equal(t, message.StatusInfo, msg.GetStatus())
+	assert.Equal(t, ts.Add(time.Second), msg.Timestamp)
+
+	content = nil
+	require.NoError(t, json.Unmarshal(msg.Content, &content))
+	assert.Equal(t, map[string]interface{}{
+		"message": "cart loaded",
+		"status":  "info",
+	}, content)
+}
+
+func TestConsumeLogsCanceled(t *testing.T) {
+	ld := plog.NewLogs()
+	ld.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords().AppendEmpty().Body().SetStringVal("dropped")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// nothing reads the unbuffered channel
+	exp := newExporter(make(chan *message.Message))
+	assert.ErrorIs(t, exp.ConsumeLogs(ctx, ld), context.Canceled)
+}
+
+func TestStatusFromSeverity(t *testing.T) {
+	for _, tc := range []struct {
+		number plog.SeverityNumber
+		text   string
+		status string
+	}{
+		{plog.SeverityNumberTRACE, "", message.StatusDebug},
+		{plog.SeverityNumberDEBUG4, "", message.StatusDebug},
+		{plog.SeverityNumberINFO, "", message.StatusInfo},
+		{plog.SeverityNumberWARN3, "", message.StatusWarning},
+		{plog.SeverityNumberERROR, "", message.StatusError},
+		{plog.SeverityNumberFATAL4, "", message.StatusCritical},
+		// the severity number takes precedence over the text
+		{plog.SeverityNumberINFO, "error", message.StatusInfo},
+		{plog.SeverityNumberUNDEFINED, "WARNING", message.StatusWarning},
+		{plog.SeverityNumberUNDEFINED, "Fatal", message.StatusCritical},
+		{plog.SeverityNumberUNDEFINED, "trace", message.StatusDebug},
+		{plog.SeverityNumberUNDEFINED, "", message.StatusInfo},
+		{plog.SeverityNumberUNDEFINED, "unknown", message.StatusInfo},
+	} {
+		assert.Equal(t, tc.status, statusFromSeverity(tc.number, tc.text), "%v %q", tc.number, tc.text)
+	}
+}
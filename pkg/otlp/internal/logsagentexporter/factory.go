@@ -0,0 +1,71 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2022-present Datadog, Inc.
+
+package logsagentexporter
+
+import (
+	"context"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/exporter/exporterhelper"
+
+	"github.com/DataDog/datadog-agent/pkg/logs/message"
+)
+
+const (
+	// TypeStr defines the logs agent exporter type string.
+	TypeStr   = "logsagent"
+	stability = component.StabilityLevelAlpha
+)
+
+// exporterConfig defines configuration for the logs agent exporter.
+type exporterConfig struct {
+	// squash ensures fields are correctly decoded in embedded struct
+	config.ExporterSettings      `mapstructure:",squash"`
+	exporterhelper.QueueSettings `mapstructure:",squash"`
+}
+
+var _ config.Exporter = (*exporterConfig)(nil)
+
+// Validate configuration
+func (e *exporterConfig) Validate() error {
+	return e.QueueSettings.Validate()
+}
+
+type factory struct {
+	logsAgentChannel chan *message.Message
+}
+
+// NewFactory creates a new logs agent exporter factory. The exported logs
+// are sent to logsAgentChannel, the input channel of a logs agent pipeline.
+func NewFactory(logsAgentChannel chan *message.Message) component.ExporterFactory {
+	f := &factory{logsAgentChannel: logsAgentChannel}
+
+	return component.NewExporterFactory(
+		TypeStr,
+		newDefaultConfig,
+		component.WithLogsExporterAndStabilityLevel(f.createLogsExporter, stability),
+	)
+}
+
+func newDefaultConfig() config.Exporter {
+	return &exporterConfig{
+		ExporterSettings: config.NewExporterSettings(config.NewComponentID(TypeStr)),
+		QueueSettings:    exporterhelper.NewDefaultQueueSettings(),
+	}
+}
+
+func (f *factory) createLogsExporter(_ context.Context, params component.ExporterCreateSettings, c config.Exporter) (component.LogsExporter, error) {
+	cfg := c.(*exporterConfig)
+
+	exp := newExporter(f.logsAgentChannel)
+
+	return exporterhelper.NewLogsExporter(cfg, params, exp.ConsumeLogs,
+		exporterhelper.WithQueue(cfg.QueueSettings),
+		// Disable timeout; ConsumeLogs only writes to the logs agent channel.
+		exporterhelper.WithTimeout(exporterhelper.TimeoutSettings{Timeout: 0}),
+	)
+}
@@ -0,0 +1,90 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package checks
+
+import (
+	"sort"
+	"strings"
+
+	model "github.com/DataDog/agent-payload/v5/process"
+
+	ddconfig "github.com/DataDog/datadog-agent/pkg/config"
+	"github.com/DataDog/datadog-agent/pkg/process/config"
+	"github.com/DataDog/datadog-agent/pkg/process/procutil"
+)
+
+// unifiedServiceTags maps the unified service tagging environment variables to their tag
+var unifiedServiceTags = map[string]string{
+	"DD_SERVICE": "service",
+	"DD_ENV":     "env",
+	"DD_VERSION": "version",
+}
+
+// envTagsProbeOptions returns the probe options to collect the environment variables used to tag processes
+func envTagsProbeOptions() []procutil.Option {
+	if !ddconfig.Datadog.GetBool("process_config.env_tags.enabled") {
+		return nil
+	}
+	return []procutil.Option{
+		procutil.WithEnvAllowlist(ddconfig.Datadog.GetStringSlice("process_config.env_tags.allowlist")),
+	}
+}
+
+// formatEnvTags converts the collected environment variables of a process into sorted tags.
+// The unified service tagging variables become the service, env and version tags, the others
+// are named after the lowercased variable name.
+func formatEnvTags(scrubber *config.DataScrubber, env map[string]string) []string {
+	if len(env) == 0 {
+		return nil
+	}
+
+	tags := make([]string, 0, len(env))
+	for name, value := range env {
+		if value == "" {
+			continue
+		}
+		tagName, ok := unifiedServiceTags[name]
+		if !ok {
+			tagName = strings.ToLower(name)
+		}
+		tags = append(tags, tagName+":"+scrubber.ScrubEnvVar(name, value))
+	}
+	sort.Strings(tags)
+
+	return tags
+}
+
+// addConnectionsEnvTags tags the connections with the environment tags of their process
+func addConnectionsEnvTags(scrubber *config.DataScrubber, conns *model.Connections, procs map[int32]*procutil.Process) {
+	if len(procs) == 0 {
+		return
+	}
+
+	tagIndexes := make(map[string]uint32, len(conns.Tags))
+	for i, tag := range conns.Tags {
+		tagIndexes[tag] = uint32(i)
+	}
+
+	tagsByPID := make(map[int32][]uint32)
+	for _, conn := range conns.Conns {
+		tags, ok := tagsByPID[conn.Pid]
+		if !ok {
+			if proc, found := procs[conn.Pid]; found {
+				for _, tag := range formatEnvTags(scrubber, proc.Env) {
+					index, known := tagIndexes[tag]
+					if !known {
+						index = uint32(len(conns.Tags))
+						conns.Tags = append(conns.Tags, tag)
+						tagIndexes[tag] = index
+					}
+					tags = append(tags, index)
+				}
+			}
+			tagsByPID[conn.Pid] = tags
+		}
+		conn.Tags = append(conn.Tags, tags...)
+	}
+}
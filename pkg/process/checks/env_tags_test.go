@@ -0,0 +1,55 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package checks
+
+import (
+	"testing"
+
+	model "github.com/DataDog/agent-payload/v5/process"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/DataDog/datadog-agent/pkg/process/config"
+	"github.com/DataDog/datadog-agent/pkg/process/procutil"
+)
+
+func TestFormatEnvTags(t *testing.T) {
+	scrubber := config.NewDefaultDataScrubber()
+
+	assert.Nil(t, formatEnvTags(scrubber, nil))
+	assert.Equal(t, []string{
+		"env:prod",
+		"service:billing",
+		"team:payments",
+		"vault_password:********",
+	}, formatEnvTags(scrubber, map[string]string{
+		"DD_SERVICE":     "billing",
+		"DD_ENV":         "prod",
+		"DD_VERSION":     "",
+		"TEAM":           "payments",
+		"VAULT_PASSWORD": "hunter2",
+	}))
+}
+
+func TestAddConnectionsEnvTags(t *testing.T) {
+	conns := &model.Connections{
+		Conns: makeConnections(3),
+		Tags:  []string{"tag0", "env:prod"},
+	}
+	conns.Conns[0].Tags = []uint32{0}
+
+	procs := map[int32]*procutil.Process{
+		1: {Pid: 1, Env: map[string]string{"DD_SERVICE": "billing", "DD_ENV": "prod"}},
+		2: {Pid: 2, Env: map[string]string{"DD_SERVICE": "billing"}},
+		3: {Pid: 3},
+	}
+
+	addConnectionsEnvTags(config.NewDefaultDataScrubber(), conns, procs)
+
+	assert.Equal(t, []string{"tag0", "env:prod", "service:billing"}, conns.Tags)
+	assert.Equal(t, []uint32{0, 1, 2}, conns.Conns[0].Tags)
+	assert.Equal(t, []uint32{2}, conns.Conns[1].Tags)
+	assert.Empty(t, conns.Conns[2].Tags)
+}
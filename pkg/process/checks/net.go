@@ -56,7 +56,7 @@ type ConnectionsCheck struct {
 
 // Init initializes a ConnectionsCheck instance.
 func (c *ConnectionsCheck) Init(cfg *config.AgentConfig, _ *model.SystemInfo) {
-	c.probe = newProcessProbe()
+	c.probe = newProcessProbe(envTagsProbeOptions()...)
 	c.notInitializedLogLimit = putil.NewLogLimit(1, time.Minute*10)
 
 	// We use the current process PID as the system-probe client ID
@@ -117,13 +117,14 @@ func (c *ConnectionsCheck) Run(cfg *config.AgentConfig, groupID int32) ([]model.
 	} else {
 		dockerproxy.NewFilter(procs).Filter(conns)
 	}
+	addConnectionsEnvTags(cfg.Scrubber, conns, procs)
 	// Resolve the Raddr side of connections for local containers
 	LocalResolver.Resolve(conns)
 
 	c.lastConnsByPID.Store(getConnectionsByPID(conns))
 
 	log.Debugf("collected connections in %s", time.Since(start))
-	return batchConnections(cfg, groupID, c.enrichConnections(conns.Conns), conns.Dns, c.networkID, conns.ConnTelemetryMap, conns.CompilationTelemetryByAsset, conns.Domains, conns.Routes, conns.Tags, conns.AgentConfiguration), nil
+	return batchConnections(cfg, groupID, conns.Conns, conns.Dns, c.networkID, conns.ConnTelemetryMap, conns.CompilationTelemetryByAsset, conns.Domains, conns.Routes, conns.Tags, conns.AgentConfiguration), nil
 }
 
 // Cleanup frees any resource held by the ConnectionsCheck before the agent exits
@@ -140,19 +141,6 @@ func (c *ConnectionsCheck) getConnections() (*model.Connections, error) {
 	return tu.GetConnections(c.tracerClientID)
 }
 
-func (c *ConnectionsCheck) enrichConnections(conns []*model.Connection) []*model.Connection {
-	// Process create-times required to construct unique process hash keys on the backend
-	createTimeForPID := ProcessNotify.GetCreateTimes(connectionPIDs(conns))
-	for _, conn := range conns {
-		if _, ok := createTimeForPID[conn.Pid]; !ok {
-			createTimeForPID[conn.Pid] = 0
-		}
-
-		conn.PidCreateTime = createTimeForPID[conn.Pid]
-	}
-	return conns
-}
-
 func (c *ConnectionsCheck) getLastConnectionsByPID() map[int32][]*model.Connection {
 	if result := c.lastConnsByPID.Load(); result != nil {
 		return result.(map[int32][]*model.Connection)
@@ -417,16 +405,3 @@ func groupSize(total, maxBatchSize int) int32 {
 	}
 	return int32(groupSize)
 }
-
-func connectionPIDs(conns []*model.Connection) []int32 {
-	ps := make(map[int32]struct{})
-	for _, c := range conns {
-		ps[c.Pid] = struct{}{}
-	}
-
-	pids := make([]int32, 0, len(ps))
-	for pid := range ps {
-		pids = append(pids, pid)
-	}
-	return pids
-}
@@ -61,12 +61,16 @@ type ProcessCheck struct {
 
 	maxBatchSize  int
 	maxBatchBytes int
+
+	// processGroups are the groups of processes whose stats are reported as metrics
+	processGroups []*processGroup
 }
 
 // Init initializes the singleton ProcessCheck.
 func (p *ProcessCheck) Init(_ *config.AgentConfig, info *model.SystemInfo) {
 	p.sysInfo = info
-	p.probe = newProcessProbe(procutil.WithPermission(Process.SysprobeProcessModuleEnabled))
+	options := append([]procutil.Option{procutil.WithPermission(Process.SysprobeProcessModuleEnabled)}, envTagsProbeOptions()...)
+	p.probe = newProcessProbe(options...)
 	p.containerProvider = util.GetSharedContainerProvider()
 
 	p.notInitializedLogLimit = util.NewLogLimit(1, time.Minute*10)
@@ -79,6 +83,8 @@ func (p *ProcessCheck) Init(_ *config.AgentConfig, info *model.SystemInfo) {
 
 	p.maxBatchSize = getMaxBatchSize()
 	p.maxBatchBytes = getMaxBatchBytes()
+
+	p.processGroups = loadProcessGroups()
 }
 
 // Name returns the name of the ProcessCheck.
@@ -170,6 +176,9 @@ func (p *ProcessCheck) run(cfg *config.AgentConfig, groupID int32, collectRealTi
 		return &RunResult{}, nil
 	}
 
+	// process groups are matched before the command lines are scrubbed
+	reportProcessGroups(statsd.Client, p.processGroups, procs, p.lastProcs, cpuTimes[0], p.lastCPUTime)
+
 	connsByPID := Connections.getLastConnectionsByPID()
 	procsByCtr := fmtProcesses(cfg, procs, p.lastProcs, pidToCid, cpuTimes[0], p.lastCPUTime, p.lastRun, connsByPID)
 	messages, totalProcs, totalContainers := createProcCtrMessages(procsByCtr, containers, cfg, p.maxBatchSize, p.maxBatchBytes, p.sysInfo, groupID, p.networkID)
@@ -332,6 +341,7 @@ func fmtProcesses(
 			InvoluntaryCtxSwitches: uint64(fp.Stats.CtxSwitches.Involuntary),
 			ContainerId:            ctrByProc[int(fp.Pid)],
 			Networks:               formatNetworks(connsByPID[fp.Pid], connCheckIntervalS),
+			ProcessContext:         formatEnvTags(cfg.Scrubber, fp.Env),
 		}
 		_, ok := procsByCtr[proc.ContainerId]
 		if !ok {
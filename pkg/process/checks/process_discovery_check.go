@@ -83,6 +83,12 @@ func (d *ProcessDiscoveryCheck) Run(cfg *config.AgentConfig, groupID int32) ([]m
 // Cleanup frees any resource held by the ProcessDiscoveryCheck before the agent exits
 func (d *ProcessDiscoveryCheck) Cleanup() {}
 
+// pidMapToProcDiscoveries converts the processes into process discoveries.
+// The ProcessDiscovery message has no field for tags, so the tags built from
+// the environment of a process (see formatEnvTags) are only reported by the
+// process check, in the ProcessContext of the Process message. It has no field
+// for the listening ports either, they are only exposed to autodiscovery by the
+// process listener.
 func pidMapToProcDiscoveries(pidMap map[int32]*procutil.Process) []*model.ProcessDiscovery {
 	pd := make([]*model.ProcessDiscovery, 0, len(pidMap))
 	for _, proc := range pidMap {
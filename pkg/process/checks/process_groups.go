@@ -0,0 +1,191 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package checks
+
+import (
+	"fmt"
+	"os/user"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/DataDog/datadog-go/v5/statsd"
+	"github.com/DataDog/gopsutil/cpu"
+
+	ddconfig "github.com/DataDog/datadog-agent/pkg/config"
+	"github.com/DataDog/datadog-agent/pkg/process/procutil"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// processGroupConfig is the configuration of a group of processes whose stats are reported as metrics
+type processGroupConfig struct {
+	Name         string   `mapstructure:"name"`
+	ProcessNames []string `mapstructure:"process_names"`
+	Exe          string   `mapstructure:"exe"`
+	Cmdline      string   `mapstructure:"cmdline"`
+	User         string   `mapstructure:"user"`
+	Tags         []string `mapstructure:"tags"`
+}
+
+// processGroup matches the processes having all the criteria of its configuration
+type processGroup struct {
+	processNames map[string]struct{}
+	exe          string
+	cmdline      *regexp.Regexp
+	user         string
+	uid          string
+	tags         []string
+}
+
+// processGroupStats holds the summed stats of the processes of a group
+type processGroupStats struct {
+	number     int
+	cpuPct     float64
+	rss        uint64
+	vms        uint64
+	threads    int64
+	openFiles  int64
+	knownFiles bool
+}
+
+// loadProcessGroups reads the process groups from the process_config.process_groups setting
+func loadProcessGroups() []*processGroup {
+	var configs []processGroupConfig
+	if err := ddconfig.Datadog.UnmarshalKey("process_config.process_groups", &configs); err != nil {
+		log.Errorf("Invalid process_config.process_groups setting: %s", err)
+		return nil
+	}
+
+	groups := make([]*processGroup, 0, len(configs))
+	for _, config := range configs {
+		group, err := newProcessGroup(config)
+		if err != nil {
+			log.Errorf("Ignoring process group %q: %s", config.Name, err)
+			continue
+		}
+		groups = append(groups, group)
+	}
+	return groups
+}
+
+func newProcessGroup(config processGroupConfig) (*processGroup, error) {
+	if config.Name == "" {
+		return nil, fmt.Errorf("missing name")
+	}
+	if len(config.ProcessNames) == 0 && config.Exe == "" && config.Cmdline == "" && config.User == "" {
+		return nil, fmt.Errorf("at least one of process_names, exe, cmdline or user is required")
+	}
+
+	group := &processGroup{
+		exe:  config.Exe,
+		user: config.User,
+		tags: append([]string{"process_name:" + config.Name}, config.Tags...),
+	}
+
+	if len(config.ProcessNames) > 0 {
+		group.processNames = make(map[string]struct{}, len(config.ProcessNames))
+		for _, name := range config.ProcessNames {
+			group.processNames[name] = struct{}{}
+		}
+	}
+
+	if config.Cmdline != "" {
+		cmdline, err := regexp.Compile(config.Cmdline)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cmdline pattern: %w", err)
+		}
+		group.cmdline = cmdline
+	}
+
+	// processes only hold the user id on Unix
+	if config.User != "" {
+		if u, err := user.Lookup(config.User); err == nil {
+			group.uid = u.Uid
+		} else {
+			log.Debugf("Unable to look up user %s of process group %s: %s", config.User, config.Name, err)
+		}
+	}
+
+	return group, nil
+}
+
+func (g *processGroup) match(p *procutil.Process) bool {
+	if g.processNames != nil {
+		if _, found := g.processNames[p.Name]; !found {
+			return false
+		}
+	}
+	if g.exe != "" && p.Exe != g.exe {
+		return false
+	}
+	if g.cmdline != nil && !g.cmdline.MatchString(strings.Join(p.Cmdline, " ")) {
+		return false
+	}
+	if g.user != "" && !g.matchUser(p) {
+		return false
+	}
+	return true
+}
+
+func (g *processGroup) matchUser(p *procutil.Process) bool {
+	if p.Username != "" {
+		return p.Username == g.user
+	}
+	return g.uid != "" && len(p.Uids) > 0 && strconv.Itoa(int(p.Uids[0])) == g.uid
+}
+
+// reportProcessGroups sums the stats of the processes of each group and sends them as metrics.
+// The CPU usage is computed from the stats of the previous run.
+func reportProcessGroups(
+	client statsd.ClientInterface,
+	groups []*processGroup,
+	procs, lastProcs map[int32]*procutil.Process,
+	syst2, syst1 cpu.TimesStat,
+) {
+	for _, group := range groups {
+		var stats processGroupStats
+		for pid, proc := range procs {
+			if proc.Stats == nil || !group.match(proc) {
+				continue
+			}
+
+			stats.number++
+			stats.threads += int64(proc.Stats.NumThreads)
+			if proc.Stats.MemInfo != nil {
+				stats.rss += proc.Stats.MemInfo.RSS
+				stats.vms += proc.Stats.MemInfo.VMS
+			}
+			// the count of open files is unknown without the permission to read it
+			if proc.Stats.OpenFdCount > 0 {
+				stats.openFiles += int64(proc.Stats.OpenFdCount)
+				stats.knownFiles = true
+			}
+			if last, found := lastProcs[pid]; found && hasCPUStats(proc.Stats, last.Stats) {
+				stats.cpuPct += float64(formatCPU(proc.Stats, last.Stats, syst2, syst1).TotalPct)
+			}
+		}
+
+		client.Gauge("system.processes.number", float64(stats.number), group.tags, 1) //nolint:errcheck
+		if stats.number == 0 {
+			continue
+		}
+		client.Gauge("system.processes.cpu.pct", stats.cpuPct, group.tags, 1)           //nolint:errcheck
+		client.Gauge("system.processes.mem.rss", float64(stats.rss), group.tags, 1)     //nolint:errcheck
+		client.Gauge("system.processes.mem.vms", float64(stats.vms), group.tags, 1)     //nolint:errcheck
+		client.Gauge("system.processes.threads", float64(stats.threads), group.tags, 1) //nolint:errcheck
+		if stats.knownFiles {
+			client.Gauge("system.processes.open_file_descriptors", float64(stats.openFiles), group.tags, 1) //nolint:errcheck
+		}
+	}
+}
+
+// hasCPUStats returns whether the CPU usage of a process can be computed from its stats of two runs
+func hasCPUStats(statsNow, statsBefore *procutil.Stats) bool {
+	if statsBefore == nil || statsNow.CreateTime != statsBefore.CreateTime {
+		return false
+	}
+	return statsNow.CPUPercent != nil || (statsNow.CPUTime != nil && statsBefore.CPUTime != nil)
+}
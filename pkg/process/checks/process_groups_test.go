@@ -0,0 +1,124 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package checks
+
+import (
+	"testing"
+
+	"github.com/DataDog/datadog-go/v5/statsd"
+	"github.com/DataDog/gopsutil/cpu"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	ddconfig "github.com/DataDog/datadog-agent/pkg/config"
+	"github.com/DataDog/datadog-agent/pkg/process/procutil"
+)
+
+type gaugeClient struct {
+	statsd.NoOpClient
+	gauges map[string]float64
+	tags   map[string][]string
+}
+
+func (c *gaugeClient) Gauge(name string, value float64, tags []string, rate float64) error {
+	c.gauges[name] = value
+	c.tags[name] = tags
+	return nil
+}
+
+func makeGroupProcess(pid int32, name, cmdline string, uid int32) *procutil.Process {
+	p := makeProcessWithCreateTime(pid, cmdline, 1639000000000)
+	p.Name = name
+	p.Exe = "/usr/sbin/" + name
+	p.Uids = []int32{uid}
+	p.Stats.CPUPercent = &procutil.CPUPercentStat{UserPct: 10, SystemPct: 5}
+	p.Stats.MemInfo = &procutil.MemoryInfoStat{RSS: 1000, VMS: 5000}
+	p.Stats.NumThreads = 4
+	p.Stats.OpenFdCount = 12
+	return p
+}
+
+func TestLoadProcessGroups(t *testing.T) {
+	cfg := ddconfig.Mock(t)
+	cfg.Set("process_config.process_groups", []map[string]interface{}{
+		{"name": "nginx", "process_names": []string{"nginx"}, "tags": []string{"team:web"}},
+		{"name": "kafka", "cmdline": "java .*kafka\\.Kafka"},
+		{"name": "invalid-pattern", "cmdline": "java ("},
+		{"name": "no-criteria"},
+		{"process_names": []string{"no-name"}},
+	})
+
+	groups := loadProcessGroups()
+	require.Len(t, groups, 2)
+	assert.Equal(t, []string{"process_name:nginx", "team:web"}, groups[0].tags)
+	assert.Equal(t, []string{"process_name:kafka"}, groups[1].tags)
+}
+
+func TestProcessGroupMatch(t *testing.T) {
+	nginx := makeGroupProcess(1, "nginx", "nginx: worker process", 33)
+
+	for _, tc := range []struct {
+		config   processGroupConfig
+		expected bool
+	}{
+		{processGroupConfig{Name: "g", ProcessNames: []string{"apache2", "nginx"}}, true},
+		{processGroupConfig{Name: "g", ProcessNames: []string{"apache2"}}, false},
+		{processGroupConfig{Name: "g", Exe: "/usr/sbin/nginx"}, true},
+		{processGroupConfig{Name: "g", Exe: "/usr/bin/nginx"}, false},
+		{processGroupConfig{Name: "g", Cmdline: "worker process$"}, true},
+		{processGroupConfig{Name: "g", Cmdline: "master process"}, false},
+		{processGroupConfig{Name: "g", ProcessNames: []string{"nginx"}, Cmdline: "master process"}, false},
+		{processGroupConfig{Name: "g", User: "root"}, false},
+	} {
+		group, err := newProcessGroup(tc.config)
+		require.NoError(t, err)
+		assert.Equal(t, tc.expected, group.match(nginx), "%+v", tc.config)
+	}
+
+	group := &processGroup{user: "www-data", uid: "33"}
+	assert.True(t, group.match(nginx))
+
+	// processes hold the user name on Windows
+	windowsProc := makeGroupProcess(2, "sqlservr.exe", "sqlservr.exe", 0)
+	windowsProc.Username = `NT SERVICE\MSSQLSERVER`
+	group = &processGroup{user: `NT SERVICE\MSSQLSERVER`}
+	assert.True(t, group.match(windowsProc))
+}
+
+func TestReportProcessGroups(t *testing.T) {
+	procs := map[int32]*procutil.Process{
+		1: makeGroupProcess(1, "nginx", "nginx: master process", 0),
+		2: makeGroupProcess(2, "nginx", "nginx: worker process", 33),
+		3: makeGroupProcess(3, "nginx", "nginx: worker process", 33),
+		4: makeGroupProcess(4, "redis-server", "redis-server *:6379", 999),
+	}
+	lastProcs := map[int32]*procutil.Process{
+		1: makeGroupProcess(1, "nginx", "nginx: master process", 0),
+		2: makeGroupProcess(2, "nginx", "nginx: worker process", 33),
+		// the process 3 started since the last run
+	}
+	// the open files can't be read without permission
+	procs[2].Stats.OpenFdCount = 0
+
+	nginx, err := newProcessGroup(processGroupConfig{Name: "nginx", ProcessNames: []string{"nginx"}})
+	require.NoError(t, err)
+	postgres, err := newProcessGroup(processGroupConfig{Name: "postgres", ProcessNames: []string{"postgres"}})
+	require.NoError(t, err)
+
+	client := &gaugeClient{gauges: map[string]float64{}, tags: map[string][]string{}}
+	reportProcessGroups(client, []*processGroup{nginx, postgres}, procs, lastProcs, cpu.TimesStat{}, cpu.TimesStat{})
+
+	// postgres is reported last, with no process
+	assert.Equal(t, float64(0), client.gauges["system.processes.number"])
+	assert.Equal(t, []string{"process_name:postgres"}, client.tags["system.processes.number"])
+
+	assert.Equal(t, float64(30), client.gauges["system.processes.cpu.pct"])
+	assert.Equal(t, float64(3000), client.gauges["system.processes.mem.rss"])
+	assert.Equal(t, float64(15000), client.gauges["system.processes.mem.vms"])
+	assert.Equal(t, float64(12), client.gauges["system.processes.threads"])
+	assert.Equal(t, float64(24), client.gauges["system.processes.open_file_descriptors"])
+	assert.Equal(t, []string{"process_name:nginx"}, client.tags["system.processes.cpu.pct"])
+}
@@ -176,6 +176,21 @@ func (ds *DataScrubber) ScrubCommand(cmdline []string) ([]string, bool) {
 	return newCmdline, changed
 }
 
+// ScrubEnvVar hides the value of an environment variable whose name matches a "sensitive word" pattern
+func (ds *DataScrubber) ScrubEnvVar(name, value string) string {
+	if !ds.Enabled {
+		return value
+	}
+
+	// the patterns match the " key=value" arguments of a command line
+	for _, pattern := range ds.SensitivePatterns {
+		if pattern.MatchString(" " + name + "=") {
+			return "********"
+		}
+	}
+	return value
+}
+
 // Strip away all arguments from the command line
 func (ds *DataScrubber) stripArguments(cmdline []string) []string {
 	// We will sometimes see the entire command line come in via the first element -- splitting guarantees removal
@@ -505,3 +505,23 @@ func benchmarkWithoutCache(b *testing.B) {
 	}
 	avoidOptimization = r
 }
+
+func TestScrubEnvVar(t *testing.T) {
+	scrubber := setupDataScrubber(t)
+
+	for _, tc := range []struct {
+		name, value, expected string
+	}{
+		{"DD_SERVICE", "billing", "billing"},
+		{"DD_ENV", "prod", "prod"},
+		{"DB_PASSWORD", "hunter2", "********"},
+		{"STRIPE_API_KEY", "sk live 1234", "********"},
+		{"datadog_api_key", "1234", "********"},
+		{"BLOCKED_FROM_YAML", "1234", "********"},
+	} {
+		assert.Equal(t, tc.expected, scrubber.ScrubEnvVar(tc.name, tc.value), tc.name)
+	}
+
+	scrubber.Enabled = false
+	assert.Equal(t, "hunter2", scrubber.ScrubEnvVar("DB_PASSWORD", "hunter2"))
+}
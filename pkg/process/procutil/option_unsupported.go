@@ -26,3 +26,13 @@ func WithPermission(elevatedPermissions bool) Option {
 func WithBootTimeRefreshInterval(bootTimeRefreshInterval time.Duration) Option {
 	return func(p Probe) {}
 }
+
+// WithEnvAllowlist configures the environment variables collected for each process
+func WithEnvAllowlist(names []string) Option {
+	return func(p Probe) {}
+}
+
+// WithListeningPorts configures whether the listening ports of each process are collected
+func WithListeningPorts(enabled bool) Option {
+	return func(p Probe) {}
+}
@@ -0,0 +1,234 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build linux
+// +build linux
+
+package procutil
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+	"github.com/DataDog/datadog-agent/pkg/util/native"
+)
+
+const (
+	netNSLinkPrefix  = "net:["
+	socketLinkPrefix = "socket:["
+
+	// socket states, see include/net/tcp_states.h
+	tcpListen = "0A"
+	// bound UDP sockets that are not connected to a peer are in the TCP_CLOSE state
+	udpUnconnected = "07"
+)
+
+// socketTables are the files of the socket tables in /proc/[pid]/net
+var socketTables = []struct {
+	name     string
+	protocol string
+}{
+	{name: "tcp", protocol: "tcp"},
+	{name: "tcp6", protocol: "tcp"},
+	{name: "udp", protocol: "udp"},
+	{name: "udp6", protocol: "udp"},
+}
+
+// getPorts retrieves the network namespace and the listening ports of a process.
+// The listening sockets of each network namespace are parsed once per collection
+// and cached in socketsByNetNS.
+func (p *probe) getPorts(pidPath string, socketsByNetNS map[uint64]map[uint64]Port) (uint64, []Port) {
+	netNS := p.getNetNS(pidPath)
+	if netNS == 0 {
+		return 0, nil
+	}
+
+	sockets, ok := socketsByNetNS[netNS]
+	if !ok {
+		sockets = p.getListeningSockets(pidPath)
+		socketsByNetNS[netNS] = sockets
+	}
+	if len(sockets) == 0 {
+		return netNS, nil
+	}
+
+	return netNS, p.getListeningPorts(pidPath, sockets)
+}
+
+// getNetNS retrieves the inode of the network namespace from the "ns/net" link for a process in procfs
+func (p *probe) getNetNS(pidPath string) uint64 {
+	path := filepath.Join(pidPath, "ns", "net")
+	if err := p.ensurePathReadable(path); err != nil {
+		return 0
+	}
+
+	link, err := os.Readlink(path)
+	if err != nil {
+		log.Debugf("Unable to read network namespace from %s: %s", pidPath, err)
+		return 0
+	}
+
+	inode, _ := parseInodeLink(link, netNSLinkPrefix)
+	return inode
+}
+
+// getListeningSockets parses the socket tables from "net" directory for a process in procfs.
+// The tables list the sockets of the network namespace of the process. It returns the
+// listening sockets indexed by inode.
+func (p *probe) getListeningSockets(pidPath string) map[uint64]Port {
+	sockets := make(map[uint64]Port)
+	for _, table := range socketTables {
+		path := filepath.Join(pidPath, "net", table.name)
+		f, err := os.Open(path)
+		if err != nil {
+			// the IPv6 tables are missing when IPv6 is disabled
+			log.Tracef("Unable to open socket table %s: %s", path, err)
+			continue
+		}
+
+		scanner := bufio.NewScanner(f)
+		scanner.Scan() // skip the header
+		for scanner.Scan() {
+			if inode, port, ok := parseSocketLine(scanner.Text(), table.protocol); ok {
+				sockets[inode] = port
+			}
+		}
+		f.Close()
+	}
+	return sockets
+}
+
+// getListeningPorts matches the sockets from "fd" directory for a process in procfs
+// against the listening sockets of its network namespace
+func (p *probe) getListeningPorts(pidPath string, sockets map[uint64]Port) []Port {
+	fdPath := filepath.Join(pidPath, "fd")
+	if err := p.ensurePathReadable(fdPath); err != nil {
+		return nil
+	}
+
+	d, err := os.Open(fdPath)
+	if err != nil {
+		return nil
+	}
+	defer d.Close()
+
+	fds, err := d.Readdirnames(-1)
+	if err != nil {
+		log.Debugf("Unable to list file descriptors from %s: %s", fdPath, err)
+		return nil
+	}
+
+	var ports []Port
+	seen := make(map[Port]struct{})
+	for _, fd := range fds {
+		link, err := os.Readlink(filepath.Join(fdPath, fd))
+		if err != nil {
+			continue
+		}
+		inode, ok := parseInodeLink(link, socketLinkPrefix)
+		if !ok {
+			continue
+		}
+		port, ok := sockets[inode]
+		if !ok {
+			continue
+		}
+		if _, ok := seen[port]; ok {
+			continue
+		}
+		seen[port] = struct{}{}
+		ports = append(ports, port)
+	}
+
+	sort.Slice(ports, func(i, j int) bool {
+		if ports[i].Port != ports[j].Port {
+			return ports[i].Port < ports[j].Port
+		}
+		if ports[i].Protocol != ports[j].Protocol {
+			return ports[i].Protocol < ports[j].Protocol
+		}
+		return ports[i].Address < ports[j].Address
+	})
+	return ports
+}
+
+// parseInodeLink extracts the inode of a link such as "socket:[12345]"
+func parseInodeLink(link, prefix string) (uint64, bool) {
+	if !strings.HasPrefix(link, prefix) || !strings.HasSuffix(link, "]") {
+		return 0, false
+	}
+	inode, err := strconv.ParseUint(link[len(prefix):len(link)-1], 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return inode, true
+}
+
+// parseSocketLine parses a line of a socket table, such as
+// "0: 00000000:1F90 00000000:0000 0A 00000000:00000000 00:00000000 00000000 0 0 23456 ..."
+// and returns the inode and the port of the socket when it is listening
+func parseSocketLine(line, protocol string) (uint64, Port, bool) {
+	fields := strings.Fields(line)
+	if len(fields) < 10 {
+		return 0, Port{}, false
+	}
+
+	switch state := fields[3]; {
+	case protocol == "tcp" && state != tcpListen:
+		return 0, Port{}, false
+	case protocol == "udp" && state != udpUnconnected:
+		return 0, Port{}, false
+	}
+
+	inode, err := strconv.ParseUint(fields[9], 10, 64)
+	if err != nil || inode == 0 {
+		return 0, Port{}, false
+	}
+
+	address, port, err := parseSocketAddress(fields[1])
+	if err != nil {
+		log.Tracef("Unable to parse socket address %s: %s", fields[1], err)
+		return 0, Port{}, false
+	}
+
+	return inode, Port{Protocol: protocol, Address: address, Port: port}, true
+}
+
+// parseSocketAddress parses an address of a socket table such as "0100007F:1F90".
+// The IP is made of 32 bits words in host byte order and the port is in network byte order.
+func parseSocketAddress(s string) (string, uint16, error) {
+	sep := strings.IndexByte(s, ':')
+	if sep < 0 {
+		return "", 0, fmt.Errorf("missing port")
+	}
+
+	port, err := strconv.ParseUint(s[sep+1:], 16, 16)
+	if err != nil {
+		return "", 0, err
+	}
+
+	hexIP := s[:sep]
+	if len(hexIP) != 8 && len(hexIP) != 32 {
+		return "", 0, fmt.Errorf("invalid IP length %d", len(hexIP))
+	}
+
+	ip := make(net.IP, len(hexIP)/2)
+	for i := 0; i < len(hexIP); i += 8 {
+		word, err := strconv.ParseUint(hexIP[i:i+8], 16, 32)
+		if err != nil {
+			return "", 0, err
+		}
+		native.Endian.PutUint32(ip[i/2:], uint32(word))
+	}
+
+	return ip.String(), uint16(port), nil
+}
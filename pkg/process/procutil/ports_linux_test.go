@@ -0,0 +1,102 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build linux
+// +build linux
+
+package procutil
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSocketAddress(t *testing.T) {
+	for _, tc := range []struct {
+		input   string
+		address string
+		port    uint16
+	}{
+		{"0100007F:1F90", "127.0.0.1", 8080},
+		{"00000000:0016", "0.0.0.0", 22},
+		{"00000000000000000000000000000000:0050", "::", 80},
+		{"00000000000000000000000001000000:1538", "::1", 5432},
+	} {
+		address, port, err := parseSocketAddress(tc.input)
+		require.NoError(t, err, tc.input)
+		assert.Equal(t, tc.address, address, tc.input)
+		assert.Equal(t, tc.port, port, tc.input)
+	}
+
+	for _, input := range []string{"0100007F", "0100007F:XYZ", "01007F:1F90", "0100007G:1F90"} {
+		_, _, err := parseSocketAddress(input)
+		assert.Error(t, err, input)
+	}
+}
+
+func TestParseSocketLine(t *testing.T) {
+	listening := "   0: 00000000:1F90 00000000:0000 0A 00000000:00000000 00:00000000 00000000  1000        0 23456 1 0000000000000000 100 0 0 10 0"
+	inode, port, ok := parseSocketLine(listening, "tcp")
+	require.True(t, ok)
+	assert.Equal(t, uint64(23456), inode)
+	assert.Equal(t, Port{Protocol: "tcp", Address: "0.0.0.0", Port: 8080}, port)
+
+	established := "   1: 0100007F:1F90 0100007F:D431 01 00000000:00000000 00:00000000 00000000  1000        0 23457 1 0000000000000000 20 4 30 10 -1"
+	_, _, ok = parseSocketLine(established, "tcp")
+	assert.False(t, ok)
+
+	bound := "  12: 3500007F:0035 00000000:0000 07 00000000:00000000 00:00000000 00000000   101        0 19890 2 0000000000000000 0"
+	inode, port, ok = parseSocketLine(bound, "udp")
+	require.True(t, ok)
+	assert.Equal(t, uint64(19890), inode)
+	assert.Equal(t, Port{Protocol: "udp", Address: "127.0.0.53", Port: 53}, port)
+
+	_, _, ok = parseSocketLine("  sl  local_address rem_address   st", "tcp")
+	assert.False(t, ok)
+}
+
+func TestParseInodeLink(t *testing.T) {
+	inode, ok := parseInodeLink("socket:[12345]", socketLinkPrefix)
+	assert.True(t, ok)
+	assert.Equal(t, uint64(12345), inode)
+
+	inode, ok = parseInodeLink("net:[4026531992]", netNSLinkPrefix)
+	assert.True(t, ok)
+	assert.Equal(t, uint64(4026531992), inode)
+
+	for _, link := range []string{"/dev/null", "pipe:[12345]", "socket:[abc]", "socket:[12345"} {
+		_, ok := parseInodeLink(link, socketLinkPrefix)
+		assert.False(t, ok, link)
+	}
+}
+
+func TestGetPortsLocalFS(t *testing.T) {
+	tcpListener, err := net.Listen("tcp4", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer tcpListener.Close()
+
+	udpConn, err := net.ListenPacket("udp4", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer udpConn.Close()
+
+	probe := getProbe(WithListeningPorts(true))
+	defer probe.Close()
+
+	pidPath := filepath.Join(probe.procRootLoc, strconv.Itoa(os.Getpid()))
+	if _, err := os.Stat(filepath.Join(pidPath, "net", "tcp")); err != nil {
+		t.Skip("socket tables are not available")
+	}
+
+	netNS, ports := probe.getPorts(pidPath, make(map[uint64]map[uint64]Port))
+	assert.NotZero(t, netNS)
+	assert.Contains(t, ports, Port{Protocol: "tcp", Address: "127.0.0.1", Port: uint16(tcpListener.Addr().(*net.TCPAddr).Port)})
+	assert.Contains(t, ports, Port{Protocol: "udp", Address: "127.0.0.1", Port: uint16(udpConn.LocalAddr().(*net.UDPAddr).Port)})
+}
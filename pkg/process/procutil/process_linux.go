@@ -90,6 +90,28 @@ func WithBootTimeRefreshInterval(bootTimeRefreshInterval time.Duration) Option {
 	}
 }
 
+// WithEnvAllowlist configures the environment variables collected for each process.
+// The environment is not read when the allowlist is empty.
+func WithEnvAllowlist(names []string) Option {
+	return func(p Probe) {
+		if linuxProbe, ok := p.(*probe); ok {
+			linuxProbe.envAllowlist = make(map[string]struct{}, len(names))
+			for _, name := range names {
+				linuxProbe.envAllowlist[name] = struct{}{}
+			}
+		}
+	}
+}
+
+// WithListeningPorts configures whether the listening ports of each process are collected
+func WithListeningPorts(enabled bool) Option {
+	return func(p Probe) {
+		if linuxProbe, ok := p.(*probe); ok {
+			linuxProbe.listeningPorts = enabled
+		}
+	}
+}
+
 // probe is a service that fetches process related info on current host
 type probe struct {
 	bootTime     *atomic.Uint64
@@ -104,6 +126,8 @@ type probe struct {
 	elevatedPermissions     bool
 	returnZeroPermStats     bool
 	bootTimeRefreshInterval time.Duration
+	envAllowlist            map[string]struct{}
+	listeningPorts          bool
 }
 
 // NewProcessProbe initializes a new Probe object
@@ -209,6 +233,11 @@ func (p *probe) ProcessesByPID(now time.Time, collectStats bool) (map[int32]*Pro
 		return nil, err
 	}
 
+	var socketsByNetNS map[uint64]map[uint64]Port
+	if p.listeningPorts {
+		socketsByNetNS = make(map[uint64]map[uint64]Port)
+	}
+
 	procsByPID := make(map[int32]*Process, len(pids))
 	for _, pid := range pids {
 		pathForPID := filepath.Join(p.procRootLoc, strconv.Itoa(int(pid)))
@@ -258,6 +287,12 @@ func (p *probe) ProcessesByPID(now time.Time, collectStats bool) (map[int32]*Pro
 				NumThreads:  statusInfo.numThreads,  // /proc/[pid]/status
 			},
 		}
+		if len(p.envAllowlist) > 0 {
+			proc.Env = p.getEnv(pathForPID) // /proc/[pid]/environ, requires permission checks
+		}
+		if p.listeningPorts {
+			proc.NetNS, proc.Ports = p.getPorts(pathForPID, socketsByNetNS) // /proc/[pid]/ns/net, /proc/[pid]/net/*, /proc/[pid]/fd, requires permission checks
+		}
 		if p.elevatedPermissions {
 			proc.Stats.OpenFdCount = p.getFDCount(pathForPID) // /proc/[pid]/fd, requires permission checks
 			proc.Stats.IOStat = p.parseIO(pathForPID)         // /proc/[pid]/io, requires permission checks
@@ -359,6 +394,37 @@ func (p *probe) getCmdline(pidPath string) []string {
 	return trimAndSplitBytes(cmdline)
 }
 
+// getEnv retrieves the allowlisted environment variables from "environ" file for a process in procfs
+func (p *probe) getEnv(pidPath string) map[string]string {
+	path := filepath.Join(pidPath, "environ")
+	if err := p.ensurePathReadable(path); err != nil {
+		return nil
+	}
+
+	environ, err := ioutil.ReadFile(path)
+	if err != nil {
+		log.Debugf("Unable to read process environment from %s: %s", pidPath, err)
+		return nil
+	}
+
+	var env map[string]string
+	for _, variable := range trimAndSplitBytes(environ) {
+		sep := strings.IndexByte(variable, '=')
+		if sep <= 0 {
+			continue
+		}
+		name := variable[:sep]
+		if _, ok := p.envAllowlist[name]; !ok {
+			continue
+		}
+		if env == nil {
+			env = make(map[string]string)
+		}
+		env[name] = variable[sep+1:]
+	}
+	return env
+}
+
 // parseIO retrieves io info from "io" file for a process in procfs
 func (p *probe) parseIO(pidPath string) *IOCountersStat {
 	path := filepath.Join(pidPath, "io")
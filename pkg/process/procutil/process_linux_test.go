@@ -647,6 +647,28 @@ func testParseIO(t *testing.T) {
 	}
 }
 
+func TestGetEnv(t *testing.T) {
+	pidPath := t.TempDir()
+	environ := "PATH=/usr/bin\x00DD_SERVICE=billing\x00DD_ENV=prod\x00DD_VERSION=\x00MALFORMED\x00=value\x00"
+	require.NoError(t, ioutil.WriteFile(filepath.Join(pidPath, "environ"), []byte(environ), 0400))
+
+	probe := getProbe(WithEnvAllowlist([]string{"DD_SERVICE", "DD_ENV", "DD_VERSION", "MALFORMED"}))
+	defer probe.Close()
+
+	assert.Equal(t, map[string]string{
+		"DD_SERVICE": "billing",
+		"DD_ENV":     "prod",
+		"DD_VERSION": "",
+	}, probe.getEnv(pidPath))
+
+	// no environment variable matches the allowlist
+	probe.envAllowlist = map[string]struct{}{"DD_TAGS": {}}
+	assert.Nil(t, probe.getEnv(pidPath))
+
+	// the environment of a process that has exited is not available
+	assert.Nil(t, probe.getEnv(filepath.Join(pidPath, "missing")))
+}
+
 func TestFetchFieldsWithoutPermission(t *testing.T) {
 	t.Skip("This test is not working in CI, but could be tested locally")
 	probe := getProbe()
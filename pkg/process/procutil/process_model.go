@@ -22,6 +22,9 @@ type Process struct {
 	Username string // (Windows only)
 	Uids     []int32
 	Gids     []int32
+	Env      map[string]string // allowlisted environment variables (Linux only)
+	Ports    []Port            // listening ports (Linux only)
+	NetNS    uint64            // network namespace inode, set with the listening ports (Linux only)
 
 	Stats *Stats
 }
@@ -36,6 +39,7 @@ func (p *Process) DeepCopy() *Process {
 		Cwd:      p.Cwd,
 		Exe:      p.Exe,
 		Username: p.Username,
+		NetNS:    p.NetNS,
 	}
 	copy.Cmdline = make([]string, len(p.Cmdline))
 	for i := range p.Cmdline {
@@ -49,12 +53,31 @@ func (p *Process) DeepCopy() *Process {
 	for i := range p.Gids {
 		copy.Gids[i] = p.Gids[i]
 	}
+	if p.Env != nil {
+		copy.Env = make(map[string]string, len(p.Env))
+		for k, v := range p.Env {
+			copy.Env[k] = v
+		}
+	}
+	if p.Ports != nil {
+		copy.Ports = make([]Port, len(p.Ports))
+		for i := range p.Ports {
+			copy.Ports[i] = p.Ports[i]
+		}
+	}
 	if p.Stats != nil {
 		copy.Stats = p.Stats.DeepCopy()
 	}
 	return copy
 }
 
+// Port is a TCP or UDP port a process listens on
+type Port struct {
+	Protocol string // "tcp" or "udp"
+	Address  string // local address, 0.0.0.0 or :: when listening on all interfaces
+	Port     uint16
+}
+
 // Stats holds all relevant stats metrics of a process
 type Stats struct {
 	CreateTime int64
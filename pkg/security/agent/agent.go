@@ -265,6 +265,9 @@ func (rsa *RuntimeSecurityAgent) GetStatus() map[string]interface{} {
 				}
 				base["selfTests"] = selfTests
 			}
+			if len(cfStatus.Rules) > 0 {
+				base["rules"] = cfStatus.Rules
+			}
 		}
 	}
 
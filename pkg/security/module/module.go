@@ -73,6 +73,9 @@ type Module struct {
 	policyOpts       rules.PolicyLoaderOpts
 	selfTester       *selftests.SelfTester
 	policyMonitor    *PolicyMonitor
+
+	// nextAggregationsFlush is when the event loop flushes the ended aggregations next
+	nextAggregationsFlush time.Time
 }
 
 // Register the runtime security agent module
@@ -395,7 +398,7 @@ func (m *Module) LoadPolicies(policyProviders []rules.PolicyProvider, sendLoaded
 	ruleIDs = append(ruleIDs, sprobe.AllCustomRuleIDs()...)
 
 	m.apiServer.Apply(ruleIDs)
-	m.rateLimiter.Apply(ruleIDs)
+	m.rateLimiter.Apply(ruleSet, sprobe.AllCustomRuleIDs())
 
 	m.displayReport(report)
 
@@ -459,7 +462,20 @@ func (m *Module) EventDiscarderFound(rs *rules.RuleSet, event eval.Event, field
 func (m *Module) HandleEvent(event *sprobe.Event) {
 	if ruleSet := m.GetRuleSet(); ruleSet != nil {
 		ruleSet.Evaluate(event)
+		m.flushAggregations(ruleSet)
+	}
+}
+
+// flushAggregations notifies the aggregations whose window ended. It is called
+// by the event loop, as notifying an aggregation uses the ruleset and the resolvers.
+func (m *Module) flushAggregations(ruleSet *rules.RuleSet) {
+	now := time.Now()
+	if now.Before(m.nextAggregationsFlush) {
+		return
 	}
+	m.nextAggregationsFlush = now.Add(m.config.StatsPollingInterval)
+
+	ruleSet.FlushAggregations(now)
 }
 
 // HandleCustomEvent is called by the probe when an event should be sent to Datadog but doesn't need evaluation
@@ -495,10 +511,76 @@ func (m *Module) RuleMatch(rule *rules.Rule, event eval.Event) {
 
 	// send if not selftest related events
 	if m.selfTester == nil || !m.selfTester.IsExpectedEvent(rule, event) {
-		m.SendEvent(rule, event, extTagsCb, service)
+		if !sprobe.HasHashAction(rule) {
+			m.SendEvent(rule, event, extTagsCb, service)
+			return
+		}
+
+		// the event is cloned as it is sent once its file is hashed, after having been released
+		clone := event.(*sprobe.Event).Clone().(*sprobe.Event)
+		m.sendHashedEvent(rule, []*sprobe.Event{clone}, clone, extTagsCb, service)
 	}
 }
 
+// SequenceMatch is called by the ruleset when a sequence rule matches, with the events of all its steps
+func (m *Module) SequenceMatch(rule *rules.Rule, events []eval.Event) {
+	sequence := make([]*sprobe.Event, 0, len(events))
+	for _, ev := range events {
+		if event, ok := ev.(*sprobe.Event); ok {
+			m.probe.OnRuleMatch(rule, event)
+			sequence = append(sequence, event)
+		}
+	}
+
+	if len(sequence) == 0 {
+		return
+	}
+
+	// the tags and the service are the ones of the last step
+	last := sequence[len(sequence)-1]
+	service := last.GetProcessServiceTag()
+	id := last.ContainerContext.ID
+
+	extTagsCb := func() []string {
+		return m.probe.GetResolvers().TagsResolver.Resolve(id)
+	}
+
+	m.sendHashedEvent(rule, sequence, sprobe.NewRuleSequenceEvent(sequence), extTagsCb, service)
+}
+
+// RuleAggregation is called by the ruleset when the aggregation window of a rule ends
+func (m *Module) RuleAggregation(rule *rules.Rule, aggregation *rules.Aggregation) {
+	var id string
+	var samples []*sprobe.Event
+	for _, sample := range []eval.Event{aggregation.First, aggregation.Last} {
+		if event, ok := sample.(*sprobe.Event); ok {
+			m.probe.OnRuleMatch(rule, event)
+			id = event.ContainerContext.ID
+			samples = append(samples, event)
+		}
+	}
+
+	extTagsCb := func() []string {
+		return m.probe.GetResolvers().TagsResolver.Resolve(id)
+	}
+
+	m.sendHashedEvent(rule, samples, sprobe.NewRuleAggregationEvent(aggregation), extTagsCb, "")
+}
+
+// sendHashedEvent sends an event once the files referenced by its cloned events
+// are hashed. The rate limiter is checked by the event loop, the event being
+// sent by a worker of the hash resolver when the rule has a hash action.
+func (m *Module) sendHashedEvent(rule *rules.Rule, clones []*sprobe.Event, event Event, extTagsCb func() []string, service string) {
+	if !m.rateLimiter.Allow(rule.ID) {
+		seclog.Tracef("Event on rule %s was dropped due to rate limiting", rule.ID)
+		return
+	}
+
+	m.probe.GetResolvers().HashResolver.ComputeHashes(rule, clones, func() {
+		m.apiServer.SendEvent(rule, event, extTagsCb, service)
+	})
+}
+
 // SendEvent sends an event to the backend after checking that the rate limiter allows it for the provided rule
 func (m *Module) SendEvent(rule *rules.Rule, event Event, extTagsCb func() []string, service string) {
 	if m.rateLimiter.Allow(rule.ID) {
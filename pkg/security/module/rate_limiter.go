@@ -74,29 +74,43 @@ func NewRateLimiter(client statsd.ClientInterface, opts LimiterOpts) *RateLimite
 	}
 }
 
-// Apply a set of rules
-func (rl *RateLimiter) Apply(rules []rules.RuleID) {
+// Apply a set of rules. The rules defining their own rate limit are already
+// limited by the rule set, they are not limited a second time.
+func (rl *RateLimiter) Apply(ruleSet *rules.RuleSet, customRuleIDs []rules.RuleID) {
 	rl.Lock()
 	defer rl.Unlock()
 
 	newLimiters := make(map[string]*Limiter)
-	for _, id := range rules {
-		if limiter, found := rl.limiters[id]; found {
-			newLimiters[id] = limiter
+	for id, rule := range ruleSet.GetRules() {
+		if rule.Definition.RateLimit != nil {
+			newLimiters[id] = NewLimiter(rate.Inf, 0)
 		} else {
-			limit := defaultLimit
-			burst := defaultBurst
-
-			if l, exists := rl.opts.Limits[id]; exists {
-				limit = rate.Limit(l.Limit)
-				burst = l.Burst
-			}
-			newLimiters[id] = NewLimiter(limit, burst)
+			newLimiters[id] = rl.getLimiter(id)
 		}
 	}
+	for _, id := range customRuleIDs {
+		newLimiters[id] = rl.getLimiter(id)
+	}
 	rl.limiters = newLimiters
 }
 
+// getLimiter returns the current limiter of a rule, or a new one if the rule
+// had no limiter or was limited by the rule set
+func (rl *RateLimiter) getLimiter(id rules.RuleID) *Limiter {
+	if limiter, found := rl.limiters[id]; found && limiter.limiter.Limit() != rate.Inf {
+		return limiter
+	}
+
+	limit := defaultLimit
+	burst := defaultBurst
+
+	if l, exists := rl.opts.Limits[id]; exists {
+		limit = rate.Limit(l.Limit)
+		burst = l.Burst
+	}
+	return NewLimiter(limit, burst)
+}
+
 // Allow returns true if a specific rule shall be allowed to sent a new event
 func (rl *RateLimiter) Allow(ruleID string) bool {
 	rl.RLock()
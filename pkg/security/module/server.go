@@ -13,6 +13,7 @@ import (
 	json "encoding/json"
 	"errors"
 	"fmt"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -272,6 +273,22 @@ func (a *APIServer) GetStatus(ctx context.Context, params *api.GetStatusParams)
 		SelfTests: a.module.selfTester.GetStatus(),
 	}
 
+	if ruleSet := a.module.GetRuleSet(); ruleSet != nil {
+		for id, stats := range ruleSet.GetRuleStats() {
+			apiStatus.Rules = append(apiStatus.Rules, &api.RuleStatus{
+				ID:           id,
+				Matched:      stats.Matched,
+				Notified:     stats.Notified,
+				RateLimited:  stats.RateLimited,
+				Deduplicated: stats.Deduplicated,
+				Aggregated:   stats.Aggregated,
+			})
+		}
+		sort.Slice(apiStatus.Rules, func(i, j int) bool {
+			return apiStatus.Rules[i].ID < apiStatus.Rules[j].ID
+		})
+	}
+
 	envErrors := a.probe.VerifyEnvironment()
 	if envErrors != nil {
 		apiStatus.Environment.Warnings = make([]string, len(envErrors.Errors))
@@ -231,7 +231,7 @@ func NewRuleSetLoadedEvent(rs *rules.RuleSet, err *multierror.Error) (*rules.Rul
 		policy.RulesLoaded = append(policy.RulesLoaded, &RuleLoaded{
 			ID:         rule.ID,
 			Version:    rule.Definition.Version,
-			Expression: rule.Definition.Expression,
+			Expression: rule.Definition.GetExpression(),
 		})
 	}
 
@@ -248,7 +248,7 @@ func NewRuleSetLoadedEvent(rs *rules.RuleSet, err *multierror.Error) (*rules.Rul
 				policy.RulesIgnored = append(policy.RulesIgnored, &RuleIgnored{
 					ID:         rerr.Definition.ID,
 					Version:    rerr.Definition.Version,
-					Expression: rerr.Definition.Expression,
+					Expression: rerr.Definition.GetExpression(),
 					Reason:     rerr.Err.Error(),
 				})
 			}
@@ -350,3 +350,52 @@ func NewSelfTestEvent(success []string, fails []string) (*rules.Rule, *CustomEve
 			Fails:     fails,
 		})
 }
+
+// RuleAggregationEvent is used to report the events of a rule that were aggregated during a window
+// easyjson:json
+type RuleAggregationEvent struct {
+	Timestamp   time.Time        `json:"date"`
+	WindowStart time.Time        `json:"window_start"`
+	Count       int              `json:"count"`
+	FirstEvent  *EventSerializer `json:"first_event"`
+	LastEvent   *EventSerializer `json:"last_event"`
+}
+
+// NewRuleAggregationEvent returns a populated custom event for a rule_aggregation event
+func NewRuleAggregationEvent(aggregation *rules.Aggregation) *CustomEvent {
+	event := RuleAggregationEvent{
+		Timestamp:   aggregation.End,
+		WindowStart: aggregation.Start,
+		Count:       aggregation.Count,
+	}
+
+	if first, ok := aggregation.First.(*Event); ok {
+		event.FirstEvent = NewEventSerializer(first)
+	}
+	if last, ok := aggregation.Last.(*Event); ok {
+		event.LastEvent = NewEventSerializer(last)
+	}
+
+	return newCustomEvent(model.CustomRuleAggregationEventType, event)
+}
+
+// RuleSequenceEvent is used to report the events of the steps of a matching sequence rule
+// easyjson:json
+type RuleSequenceEvent struct {
+	Timestamp time.Time          `json:"date"`
+	Events    []*EventSerializer `json:"events"`
+}
+
+// NewRuleSequenceEvent returns a populated custom event for a rule_sequence event
+func NewRuleSequenceEvent(events []*Event) *CustomEvent {
+	event := RuleSequenceEvent{
+		Timestamp: time.Now(),
+		Events:    make([]*EventSerializer, 0, len(events)),
+	}
+
+	for _, ev := range events {
+		event.Events = append(event.Events, NewEventSerializer(ev))
+	}
+
+	return newCustomEvent(model.CustomRuleSequenceEventType, event)
+}
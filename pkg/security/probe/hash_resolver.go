@@ -0,0 +1,297 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build linux
+// +build linux
+
+package probe
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/golang-lru/simplelru"
+	"golang.org/x/time/rate"
+
+	seclog "github.com/DataDog/datadog-agent/pkg/security/log"
+	"github.com/DataDog/datadog-agent/pkg/security/secl/model"
+	"github.com/DataDog/datadog-agent/pkg/security/secl/rules"
+	"github.com/DataDog/datadog-agent/pkg/security/utils"
+)
+
+const (
+	// hashCacheSize is the number of files whose digests are kept in cache
+	hashCacheSize = 1000
+	// hashWorkers is the number of files that can be hashed at the same time
+	hashWorkers = 4
+	// hashQueueSize is the number of events that can wait for their files to be hashed,
+	// the events matching while the queue is full are sent without digests
+	hashQueueSize = 64
+)
+
+// hashCacheKey identifies a version of a file, a modified file being hashed again
+type hashCacheKey struct {
+	mountID    uint32
+	inode      uint64
+	mtime      uint64
+	algorithms string
+}
+
+// hashedFile is the file referenced by a cloned event for the hash actions
+type hashedFile struct {
+	mountID uint32
+	inode   uint64
+	mtime   uint64
+	pid     uint32
+	// path is the path of the file in the mount namespace of the process
+	path string
+	// serializers are the serializers of the event the digests are added to
+	serializers []*FileSerializer
+}
+
+func newHashedFile(ev *Event, serializer *EventSerializer) *hashedFile {
+	file := ev.getHashedFile()
+	if file == nil {
+		return nil
+	}
+
+	path := ev.ResolveFilePath(file)
+	serializers := serializer.getHashedFileSerializers(model.EventType(ev.Type))
+	if path == "" || len(serializers) == 0 {
+		return nil
+	}
+
+	return &hashedFile{
+		mountID:     file.MountID,
+		inode:       file.Inode,
+		mtime:       file.MTime,
+		pid:         ev.PIDContext.Pid,
+		path:        path,
+		serializers: serializers,
+	}
+}
+
+// hashRequest holds the files to hash for the events of a rule match, and the
+// callback sending the events once hashed
+type hashRequest struct {
+	rule  *rules.Rule
+	files []*hashedFile
+	done  func()
+}
+
+// HashResolver computes in user space the digests of the files referenced by
+// the events matching rules with a hash action. The files are hashed by a pool
+// of workers, out of the event loop.
+type HashResolver struct {
+	sync.Mutex
+	cache    *simplelru.LRU
+	limiters map[rules.RuleID]*rate.Limiter
+	requests chan *hashRequest
+
+	// rootPath returns the path under which the files seen by a process can be opened
+	rootPath func(pid uint32) string
+}
+
+// NewHashResolver returns a new HashResolver
+func NewHashResolver() (*HashResolver, error) {
+	cache, err := simplelru.NewLRU(hashCacheSize, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return &HashResolver{
+		cache:    cache,
+		limiters: make(map[rules.RuleID]*rate.Limiter),
+		requests: make(chan *hashRequest, hashQueueSize),
+		rootPath: func(pid uint32) string {
+			return utils.RootPath(int32(pid))
+		},
+	}, nil
+}
+
+// Start the workers hashing the files
+func (r *HashResolver) Start(ctx context.Context) {
+	for i := 0; i < hashWorkers; i++ {
+		go func() {
+			for {
+				select {
+				case req := <-r.requests:
+					r.handleRequest(req)
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+}
+
+// HasHashAction returns whether the rule has a hash action
+func HasHashAction(rule *rules.Rule) bool {
+	for _, action := range rule.Definition.Actions {
+		if action.Hash != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// ComputeHashes runs the hash actions of the rule on the files referenced by the
+// events, which have to be clones, and calls done once their digests are added.
+// done is called right away when there is nothing to hash, or when too many
+// events are already waiting for their files to be hashed.
+func (r *HashResolver) ComputeHashes(rule *rules.Rule, events []*Event, done func()) {
+	var files []*hashedFile
+	for _, event := range events {
+		if event.snapshot != nil && event.snapshot.hashedFile != nil {
+			files = append(files, event.snapshot.hashedFile)
+		}
+	}
+
+	if len(files) == 0 || !HasHashAction(rule) {
+		done()
+		return
+	}
+
+	select {
+	case r.requests <- &hashRequest{rule: rule, files: files, done: done}:
+	default:
+		seclog.Debugf("too many files being hashed, sending the event of rule `%s` without digests", rule.ID)
+		done()
+	}
+}
+
+func (r *HashResolver) handleRequest(req *hashRequest) {
+	for _, action := range req.rule.Definition.Actions {
+		if action.Hash == nil {
+			continue
+		}
+
+		for _, file := range req.files {
+			if len(file.serializers[0].Hashes) > 0 {
+				continue
+			}
+
+			hashes, err := r.hash(req.rule.ID, action.Hash, file, r.rootPath(file.pid))
+			if err != nil {
+				seclog.Debugf("failed to hash `%s` for rule `%s`: %s", file.path, req.rule.ID, err)
+				continue
+			}
+
+			for _, serializer := range file.serializers {
+				serializer.Hashes = hashes
+			}
+		}
+	}
+
+	req.done()
+}
+
+func (r *HashResolver) hash(ruleID rules.RuleID, def *rules.HashDefinition, file *hashedFile, root string) ([]string, error) {
+	algorithms := def.GetAlgorithms()
+
+	names := make([]string, len(algorithms))
+	for i, algorithm := range algorithms {
+		names[i] = string(algorithm)
+	}
+
+	key := hashCacheKey{
+		mountID:    file.mountID,
+		inode:      file.inode,
+		mtime:      file.mtime,
+		algorithms: strings.Join(names, ","),
+	}
+
+	r.Lock()
+	if hashes, found := r.cache.Get(key); found {
+		r.Unlock()
+		return hashes.([]string), nil
+	}
+	allowed := r.getLimiter(ruleID, def.GetRateLimit()).Allow()
+	r.Unlock()
+
+	if !allowed {
+		return nil, fmt.Errorf("rate limit reached")
+	}
+
+	// the file is read without holding the lock, the workers hashing files concurrently
+	hashes, err := hashFile(filepath.Join(root, file.path), algorithms, def.GetMaxFileSize())
+	if err != nil {
+		return nil, err
+	}
+
+	r.Lock()
+	r.cache.Add(key, hashes)
+	r.Unlock()
+
+	return hashes, nil
+}
+
+func (r *HashResolver) getLimiter(ruleID rules.RuleID, limit float64) *rate.Limiter {
+	limiter, found := r.limiters[ruleID]
+	if !found || float64(limiter.Limit()) != limit {
+		limiter = rate.NewLimiter(rate.Limit(limit), int(math.Max(1, math.Ceil(limit))))
+		r.limiters[ruleID] = limiter
+	}
+	return limiter
+}
+
+// hashFile returns the digests of a file, formatted as <algorithm>:<hex digest>
+func hashFile(path string, algorithms []rules.HashAlgorithm, maxFileSize int64) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	if !info.Mode().IsRegular() {
+		return nil, fmt.Errorf("not a regular file")
+	}
+
+	if info.Size() > maxFileSize {
+		return nil, fmt.Errorf("file size %d exceeds the limit of %d bytes", info.Size(), maxFileSize)
+	}
+
+	hashers := make([]hash.Hash, len(algorithms))
+	writers := make([]io.Writer, len(algorithms))
+	for i, algorithm := range algorithms {
+		switch algorithm {
+		case rules.SHA1:
+			hashers[i] = sha1.New()
+		case rules.MD5:
+			hashers[i] = md5.New()
+		default:
+			hashers[i] = sha256.New()
+		}
+		writers[i] = hashers[i]
+	}
+
+	// the file may grow while being read
+	if _, err := io.Copy(io.MultiWriter(writers...), io.LimitReader(f, maxFileSize)); err != nil {
+		return nil, err
+	}
+
+	hashes := make([]string, len(algorithms))
+	for i, algorithm := range algorithms {
+		hashes[i] = string(algorithm) + ":" + hex.EncodeToString(hashers[i].Sum(nil))
+	}
+
+	return hashes, nil
+}
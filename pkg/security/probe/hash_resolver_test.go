@@ -0,0 +1,129 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build linux
+// +build linux
+
+package probe
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/DataDog/datadog-agent/pkg/security/secl/compiler/eval"
+	"github.com/DataDog/datadog-agent/pkg/security/secl/rules"
+)
+
+func TestHashResolver(t *testing.T) {
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "test"), []byte("hello"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	resolver, err := NewHashResolver()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	file := &hashedFile{mountID: 1, inode: 2, mtime: 3, path: "/test"}
+	def := &rules.HashDefinition{Algorithms: []rules.HashAlgorithm{rules.SHA256, rules.SHA1, rules.MD5}}
+
+	hashes, err := resolver.hash("rule", def, file, root)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"sha256:2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824",
+		"sha1:aaf4c61ddcc5e8a2dabede0f3b482cd9aea9434d",
+		"md5:5d41402abc4b2a76b9719d911017c592",
+	}, hashes)
+
+	t.Run("cache", func(t *testing.T) {
+		if err := os.Remove(filepath.Join(root, "test")); err != nil {
+			t.Fatal(err)
+		}
+
+		cached, err := resolver.hash("rule", def, file, root)
+		assert.NoError(t, err)
+		assert.Equal(t, hashes, cached)
+
+		modified := *file
+		modified.mtime++
+		_, err = resolver.hash("rule", def, &modified, root)
+		assert.Error(t, err)
+	})
+
+	t.Run("max-file-size", func(t *testing.T) {
+		if err := os.WriteFile(filepath.Join(root, "big"), make([]byte, 16), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		big := &hashedFile{mountID: 1, inode: 4, path: "/big"}
+		_, err := resolver.hash("rule", &rules.HashDefinition{MaxFileSize: 8}, big, root)
+		assert.Error(t, err)
+	})
+
+	t.Run("rate-limit", func(t *testing.T) {
+		if err := os.WriteFile(filepath.Join(root, "limited"), []byte("hello"), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		def := &rules.HashDefinition{RateLimit: 1}
+		_, err := resolver.hash("limited", def, &hashedFile{inode: 5, path: "/limited"}, root)
+		assert.NoError(t, err)
+		_, err = resolver.hash("limited", def, &hashedFile{inode: 6, path: "/limited"}, root)
+		assert.Error(t, err)
+	})
+
+	t.Run("workers", func(t *testing.T) {
+		resolver.rootPath = func(pid uint32) string { return root }
+
+		rule := &rules.Rule{Rule: &eval.Rule{ID: "workers"}, Definition: &rules.RuleDefinition{
+			Actions: []rules.ActionDefinition{{Hash: &rules.HashDefinition{Algorithms: []rules.HashAlgorithm{rules.MD5}}}},
+		}}
+
+		newClone := func(inode uint64, path string) (*Event, *FileSerializer) {
+			serializer := &FileSerializer{}
+			return &Event{snapshot: &eventSnapshot{hashedFile: &hashedFile{
+				inode:       inode,
+				path:        path,
+				serializers: []*FileSerializer{serializer},
+			}}}, serializer
+		}
+
+		// the queue is full, the event is sent right away without digests
+		for i := 0; i < hashQueueSize; i++ {
+			resolver.requests <- &hashRequest{rule: rule, done: func() {}}
+		}
+		clone, serializer := newClone(8, "/limited")
+
+		sent := false
+		resolver.ComputeHashes(rule, []*Event{clone}, func() { sent = true })
+		assert.True(t, sent)
+		assert.Empty(t, serializer.Hashes)
+
+		for i := 0; i < hashQueueSize; i++ {
+			<-resolver.requests
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		resolver.Start(ctx)
+
+		done := make(chan struct{})
+		clone, serializer = newClone(9, "/limited")
+		resolver.ComputeHashes(rule, []*Event{clone}, func() { close(done) })
+
+		select {
+		case <-done:
+		case <-time.After(10 * time.Second):
+			t.Fatal("the event wasn't sent")
+		}
+		assert.Equal(t, []string{"md5:5d41402abc4b2a76b9719d911017c592"}, serializer.Hashes)
+	})
+}
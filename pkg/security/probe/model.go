@@ -79,6 +79,18 @@ type Event struct {
 	pathResolutionError error
 	scrubber            *pconfig.DataScrubber
 	probe               *Probe
+
+	// snapshot holds the serialized event of a clone
+	snapshot *eventSnapshot
+}
+
+// eventSnapshot holds what is reported of a cloned event, captured while its
+// process cache entry and its lazily resolved fields were still valid
+type eventSnapshot struct {
+	serializer *EventSerializer
+	service    string
+	// hashedFile is the file hashed by the hash actions of the rules
+	hashedFile *hashedFile
 }
 
 // Retain the event
@@ -96,6 +108,71 @@ func (ev *Event) Release() {
 	}
 }
 
+// Clone returns a copy of the event that can be kept after its evaluation. The
+// process cache entry and the lazily resolved fields of the event being reused
+// once it is released, the copy is serialized at capture time and detached
+// from the process cache.
+func (ev *Event) Clone() eval.Event {
+	clone := *ev
+	serializer := NewEventSerializer(&clone)
+	clone.snapshot = &eventSnapshot{
+		serializer: serializer,
+		service:    ev.GetProcessServiceTag(),
+		hashedFile: newHashedFile(&clone, serializer),
+	}
+
+	if entry := ev.ProcessCacheEntry; entry != nil {
+		detached := &model.ProcessCacheEntry{}
+		detached.Process = entry.Process
+		detached.ArgsEntry, detached.EnvsEntry = nil, nil
+
+		if ev.Exec.Process == &entry.Process {
+			clone.Exec.Process = &detached.Process
+		}
+		if ev.Exit.Process == &entry.Process {
+			clone.Exit.Process = &detached.Process
+		}
+
+		clone.ProcessCacheEntry = detached
+		clone.ProcessContext = &detached.ProcessContext
+	}
+
+	return &clone
+}
+
+// getHashedFile returns the file referenced by the event for the hash action
+func (ev *Event) getHashedFile() *model.FileEvent {
+	switch ev.GetEventType() {
+	case model.ExecEventType:
+		if entry := ev.ResolveProcessCacheEntry(); entry != nil {
+			return &entry.Process.FileEvent
+		}
+	case model.FileOpenEventType:
+		return &ev.Open.File
+	case model.FileChmodEventType:
+		return &ev.Chmod.File
+	case model.FileChownEventType:
+		return &ev.Chown.File
+	case model.FileUtimesEventType:
+		return &ev.Utimes.File
+	case model.FileLinkEventType:
+		return &ev.Link.Source
+	case model.FileRenameEventType:
+		return &ev.Rename.New
+	case model.FileSetXAttrEventType:
+		return &ev.SetXAttr.File
+	case model.MMapEventType:
+		return &ev.MMap.File
+	case model.SpliceEventType:
+		return &ev.Splice.File
+	case model.LoadModuleEventType:
+		if !ev.LoadModule.LoadedFromMemory {
+			return &ev.LoadModule.File
+		}
+	}
+	return nil
+}
+
 // GetPathResolutionError returns the path resolution error as a string if there is one
 func (ev *Event) GetPathResolutionError() error {
 	return ev.pathResolutionError
@@ -486,6 +563,10 @@ func (ev *Event) ResolveProcessCacheEntry() *model.ProcessCacheEntry {
 
 // GetProcessServiceTag returns the service tag based on the process context
 func (ev *Event) GetProcessServiceTag() string {
+	if ev.snapshot != nil {
+		return ev.snapshot.service
+	}
+
 	entry := ev.ResolveProcessCacheEntry()
 	if entry == nil {
 		return ""
@@ -11,9 +11,13 @@ package probe
 import (
 	"errors"
 	"net"
+	"path/filepath"
 	"reflect"
 	"sort"
 	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
 
 	"github.com/DataDog/datadog-agent/pkg/security/secl/compiler/eval"
 	"github.com/DataDog/datadog-agent/pkg/security/secl/model"
@@ -177,3 +181,54 @@ func TestProcessArgsOptions(t *testing.T) {
 		t.Errorf("expected 5 options, got %d", len(options))
 	}
 }
+
+func TestEventClone(t *testing.T) {
+	processResolver, _ := NewProcessResolver(&Probe{}, nil, NewProcessResolverOpts(nil))
+	mountResolver, _ := NewMountResolver(nil)
+	resolvers := &Resolvers{
+		ProcessResolver: processResolver,
+		MountResolver:   mountResolver,
+	}
+
+	newEntry := func(pid uint32, path string) *model.ProcessCacheEntry {
+		entry := processResolver.NewProcessCacheEntry(model.PIDContext{Pid: pid, Tid: pid})
+		entry.FileEvent.FileFields = model.FileFields{MountID: pid, User: "root", Group: "root"}
+		entry.FileEvent.SetPathnameStr(path)
+		entry.FileEvent.SetBasenameStr(filepath.Base(path))
+		return entry
+	}
+
+	parent := newEntry(1, "/usr/bin/parent")
+	child := newEntry(2, "/usr/bin/child")
+	child.SetAncestor(parent)
+
+	event := NewEvent(resolvers, nil, nil)
+	event.Type = uint32(model.FileOpenEventType)
+	event.Timestamp = time.Now()
+	event.ProcessCacheEntry = child
+	event.ProcessContext = &child.ProcessContext
+	event.Open.File.FileFields = model.FileFields{MountID: 3, User: "root", Group: "root"}
+	event.Open.File.SetPathnameStr("/etc/passwd")
+	event.Open.File.SetBasenameStr("passwd")
+
+	expected, err := event.MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	clone := event.Clone().(*Event)
+
+	// the event and its process cache entries are reused
+	parent.Reset()
+	parent.FileEvent.SetPathnameStr("/usr/bin/other")
+	parent.FileEvent.MountID = 4
+	*event = Event{}
+
+	assert.Nil(t, clone.ProcessCacheEntry.Ancestor)
+
+	data, err := clone.MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.JSONEq(t, string(expected), string(data))
+}
@@ -33,6 +33,7 @@ type Resolvers struct {
 	UserGroupResolver *UserGroupResolver
 	TagsResolver      *TagsResolver
 	NamespaceResolver *NamespaceResolver
+	HashResolver      *HashResolver
 }
 
 // NewResolvers creates a new instance of Resolvers
@@ -62,6 +63,11 @@ func NewResolvers(config *config.Config, probe *Probe) (*Resolvers, error) {
 		return nil, err
 	}
 
+	hashResolver, err := NewHashResolver()
+	if err != nil {
+		return nil, err
+	}
+
 	resolvers := &Resolvers{
 		probe:             probe,
 		DentryResolver:    dentryResolver,
@@ -71,6 +77,7 @@ func NewResolvers(config *config.Config, probe *Probe) (*Resolvers, error) {
 		UserGroupResolver: userGroupResolver,
 		TagsResolver:      NewTagsResolver(config),
 		NamespaceResolver: namespaceResolver,
+		HashResolver:      hashResolver,
 	}
 
 	processResolver, err := NewProcessResolver(probe, resolvers, NewProcessResolverOpts(probe.config.EnvsWithValue))
@@ -189,6 +196,7 @@ func (r *Resolvers) Start(ctx context.Context) error {
 		return err
 	}
 	r.MountResolver.Start(ctx)
+	r.HashResolver.Start(ctx)
 
 	if err := r.TagsResolver.Start(ctx); err != nil {
 		return err
@@ -62,6 +62,8 @@ type FileSerializer struct {
 	Mtime *utils.EasyjsonTime `json:"modification_time,omitempty"`
 	// File change time
 	Ctime *utils.EasyjsonTime `json:"change_time,omitempty"`
+	// File digests, formatted as <algorithm>:<hex digest>
+	Hashes []string `json:"hashes,omitempty"`
 }
 
 // UserContextSerializer serializes a user context to JSON
@@ -529,13 +531,15 @@ func newFileSerializer(fe *model.FileEvent, e *Event, forceInode ...uint64) *Fil
 		inode = forceInode[0]
 	}
 
+	// copied as the file event can be part of a process cache entry that is reused
+	mountID := fe.MountID
 	mode := uint32(fe.FileFields.Mode)
 	return &FileSerializer{
 		Path:                e.ResolveFilePath(fe),
 		PathResolutionError: fe.GetPathResolutionError(),
 		Name:                e.ResolveFileBasename(fe),
 		Inode:               getUint64Pointer(&inode),
-		MountID:             getUint32Pointer(&fe.MountID),
+		MountID:             getUint32Pointer(&mountID),
 		Filesystem:          e.ResolveFileFilesystem(fe),
 		Mode:                getUint32Pointer(&mode), // only used by open events
 		UID:                 int64(fe.UID),
@@ -912,8 +916,36 @@ func serializeSyscallRetval(retval int64) string {
 	}
 }
 
+// getHashedFileSerializers returns the serializers of the file referenced by the event for the hash action
+func (s *EventSerializer) getHashedFileSerializers(eventType model.EventType) []*FileSerializer {
+	if s.FileEventSerializer == nil {
+		return nil
+	}
+
+	switch eventType {
+	case model.ExecEventType:
+		serializers := []*FileSerializer{&s.FileEventSerializer.FileSerializer}
+		if s.ProcessContextSerializer != nil && s.ProcessContextSerializer.ProcessSerializer != nil && s.ProcessContextSerializer.Executable != nil {
+			serializers = append(serializers, s.ProcessContextSerializer.Executable)
+		}
+		return serializers
+	case model.FileRenameEventType:
+		if s.FileEventSerializer.Destination != nil {
+			return []*FileSerializer{s.FileEventSerializer.Destination}
+		}
+		return nil
+	default:
+		return []*FileSerializer{&s.FileEventSerializer.FileSerializer}
+	}
+}
+
 // NewEventSerializer creates a new event serializer based on the event type
 func NewEventSerializer(event *Event) *EventSerializer {
+	// a cloned event is serialized when captured
+	if event.snapshot != nil {
+		return event.snapshot.serializer
+	}
+
 	var pc model.ProcessContext
 	if entry := event.ResolveProcessCacheEntry(); entry != nil {
 		pc = entry.ProcessContext
@@ -0,0 +1,151 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package eval
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// CloneableEvent is implemented by the events that can be kept after their
+// evaluation. Events that are reused between evaluations should implement it
+// to be part of sequences.
+type CloneableEvent interface {
+	Event
+	Clone() Event
+}
+
+// CloneEvent returns a copy of the event if it can be cloned, the event itself otherwise
+func CloneEvent(event Event) Event {
+	if cloneable, ok := event.(CloneableEvent); ok {
+		return cloneable.Clone()
+	}
+	return event
+}
+
+// sequenceInstance holds the progress of a sequence for a correlation key
+type sequenceInstance struct {
+	key    string
+	next   int
+	start  time.Time
+	events []Event
+	elem   *list.Element
+}
+
+// SequenceState tracks the progress of a sequence of steps for each correlation
+// key. A sequence has to complete within a given duration from its first step,
+// and the number of keys tracked at the same time is bounded, the oldest
+// sequences being evicted first.
+type SequenceState struct {
+	sync.Mutex
+
+	steps   int
+	within  time.Duration
+	maxSize int
+
+	instances map[string]*sequenceInstance
+	// order holds the instances by start time, the oldest first
+	order *list.List
+}
+
+// NewSequenceState returns a new SequenceState
+func NewSequenceState(steps int, within time.Duration, maxSize int) *SequenceState {
+	return &SequenceState{
+		steps:     steps,
+		within:    within,
+		maxSize:   maxSize,
+		instances: make(map[string]*sequenceInstance),
+		order:     list.New(),
+	}
+}
+
+// Advance records that the event matched the given step for the correlation key.
+// The first step starts, or restarts, a sequence. The other steps only advance a
+// sequence waiting for them. When the last step is reached, the sequence is
+// removed and the events of all its steps are returned.
+func (s *SequenceState) Advance(key string, step int, event Event, now time.Time) ([]Event, bool) {
+	s.Lock()
+	defer s.Unlock()
+
+	s.expire(now)
+
+	if step == 0 {
+		if s.steps == 1 {
+			return []Event{CloneEvent(event)}, true
+		}
+
+		s.remove(s.instances[key])
+
+		if len(s.instances) >= s.maxSize {
+			s.remove(s.order.Front().Value.(*sequenceInstance))
+		}
+
+		instance := &sequenceInstance{
+			key:    key,
+			next:   1,
+			start:  now,
+			events: []Event{CloneEvent(event)},
+		}
+		instance.elem = s.order.PushBack(instance)
+		s.instances[key] = instance
+
+		return nil, false
+	}
+
+	instance, found := s.instances[key]
+	if !found || instance.next != step {
+		return nil, false
+	}
+
+	instance.events = append(instance.events, CloneEvent(event))
+	instance.next++
+
+	if instance.next < s.steps {
+		return nil, false
+	}
+
+	s.remove(instance)
+
+	return instance.events, true
+}
+
+// Len returns the number of sequences in progress
+func (s *SequenceState) Len() int {
+	s.Lock()
+	defer s.Unlock()
+
+	return len(s.instances)
+}
+
+// Reset drops all the sequences in progress
+func (s *SequenceState) Reset() {
+	s.Lock()
+	defer s.Unlock()
+
+	s.instances = make(map[string]*sequenceInstance)
+	s.order.Init()
+}
+
+// expire removes the sequences that can't complete in time anymore
+func (s *SequenceState) expire(now time.Time) {
+	for elem := s.order.Front(); elem != nil; elem = s.order.Front() {
+		instance := elem.Value.(*sequenceInstance)
+		if now.Sub(instance.start) <= s.within {
+			return
+		}
+		s.remove(instance)
+	}
+}
+
+func (s *SequenceState) remove(instance *sequenceInstance) {
+	if instance == nil {
+		return
+	}
+
+	s.order.Remove(instance.elem)
+	delete(s.instances, instance.key)
+}
@@ -0,0 +1,107 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package eval
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSequenceState(t *testing.T) {
+	now := time.Now()
+	download, chmod, exec := &testEvent{id: "download"}, &testEvent{id: "chmod"}, &testEvent{id: "exec"}
+
+	t.Run("complete", func(t *testing.T) {
+		state := NewSequenceState(3, time.Minute, 10)
+
+		if _, completed := state.Advance("/tmp/payload", 0, download, now); completed {
+			t.Fatal("sequence shouldn't be completed")
+		}
+		if _, completed := state.Advance("/tmp/payload", 1, chmod, now.Add(time.Second)); completed {
+			t.Fatal("sequence shouldn't be completed")
+		}
+		events, completed := state.Advance("/tmp/payload", 2, exec, now.Add(2*time.Second))
+		if !completed {
+			t.Fatal("sequence should be completed")
+		}
+		if len(events) != 3 || events[0] != download || events[1] != chmod || events[2] != exec {
+			t.Fatalf("unexpected events: %v", events)
+		}
+		if state.Len() != 0 {
+			t.Fatal("completed sequence should be removed")
+		}
+	})
+
+	t.Run("out of order", func(t *testing.T) {
+		state := NewSequenceState(3, time.Minute, 10)
+
+		state.Advance("/tmp/payload", 0, download, now)
+		if _, completed := state.Advance("/tmp/payload", 2, exec, now); completed {
+			t.Fatal("sequence shouldn't be completed")
+		}
+		if _, completed := state.Advance("/tmp/other", 1, chmod, now); completed {
+			t.Fatal("sequence shouldn't be completed")
+		}
+		if state.Len() != 1 {
+			t.Fatalf("expected 1 sequence, got %d", state.Len())
+		}
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		state := NewSequenceState(2, time.Minute, 10)
+
+		state.Advance("/tmp/payload", 0, download, now)
+		if _, completed := state.Advance("/tmp/payload", 1, exec, now.Add(2*time.Minute)); completed {
+			t.Fatal("expired sequence shouldn't complete")
+		}
+		if state.Len() != 0 {
+			t.Fatal("expired sequence should be removed")
+		}
+	})
+
+	t.Run("restart", func(t *testing.T) {
+		state := NewSequenceState(2, time.Minute, 10)
+
+		state.Advance("/tmp/payload", 0, download, now)
+		state.Advance("/tmp/payload", 0, chmod, now.Add(50*time.Second))
+		events, completed := state.Advance("/tmp/payload", 1, exec, now.Add(90*time.Second))
+		if !completed {
+			t.Fatal("restarted sequence should be completed")
+		}
+		if events[0] != chmod {
+			t.Fatalf("unexpected first event: %v", events[0])
+		}
+	})
+
+	t.Run("bounded", func(t *testing.T) {
+		state := NewSequenceState(2, time.Minute, 2)
+
+		state.Advance("a", 0, download, now)
+		state.Advance("b", 0, download, now)
+		state.Advance("c", 0, download, now)
+		if state.Len() != 2 {
+			t.Fatalf("expected 2 sequences, got %d", state.Len())
+		}
+		if _, completed := state.Advance("a", 1, exec, now); completed {
+			t.Fatal("oldest sequence should have been evicted")
+		}
+		if _, completed := state.Advance("c", 1, exec, now); !completed {
+			t.Fatal("newest sequence should be completed")
+		}
+	})
+
+	t.Run("single step", func(t *testing.T) {
+		state := NewSequenceState(1, time.Minute, 2)
+
+		events, completed := state.Advance("a", 0, exec, now)
+		if !completed || len(events) != 1 {
+			t.Fatal("single step sequence should be completed")
+		}
+		if state.Len() != 0 {
+			t.Fatal("single step sequence shouldn't be tracked")
+		}
+	})
+}
@@ -110,6 +110,10 @@ const (
 	CustomTruncatedParentsEventType
 	// CustomSelfTestEventType is the custom event used to report the results of a self test run
 	CustomSelfTestEventType
+	// CustomRuleAggregationEventType is the custom event used to report the events of a rule aggregated during a window
+	CustomRuleAggregationEventType
+	// CustomRuleSequenceEventType is the custom event used to report the events of a matching sequence rule
+	CustomRuleSequenceEventType
 	// MaxAllEventType is used internally to get the maximum number of events.
 	MaxAllEventType
 )
@@ -205,6 +209,10 @@ func (t EventType) String() string {
 		return "truncated_parents"
 	case CustomSelfTestEventType:
 		return "self_test"
+	case CustomRuleAggregationEventType:
+		return "rule_aggregation"
+	case CustomRuleSequenceEventType:
+		return "rule_sequence"
 	default:
 		return "unknown"
 	}
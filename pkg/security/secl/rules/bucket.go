@@ -6,7 +6,6 @@
 package rules
 
 import (
-	"errors"
 	"sort"
 
 	"github.com/DataDog/datadog-agent/pkg/security/secl/compiler/eval"
@@ -20,10 +19,8 @@ type RuleBucket struct {
 
 // AddRule adds a rule to the bucket
 func (rb *RuleBucket) AddRule(rule *Rule) error {
-	for _, r := range rb.rules {
-		if r.ID == rule.ID {
-			return &ErrRuleLoad{Definition: rule.Definition, Err: errors.New("multiple definition with the same ID")}
-		}
+	if rb.hasRule(rule.ID) {
+		return &ErrRuleLoad{Definition: rule.Definition, Err: ErrDefinitionIDConflict}
 	}
 
 	for _, field := range rule.GetEvaluator().GetFields() {
@@ -40,6 +37,16 @@ func (rb *RuleBucket) AddRule(rule *Rule) error {
 	return nil
 }
 
+// hasRule returns whether the bucket holds a rule with the given ID
+func (rb *RuleBucket) hasRule(id string) bool {
+	for _, r := range rb.rules {
+		if r.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
 // GetRules returns the bucket rules
 func (rb *RuleBucket) GetRules() []*Rule {
 	return rb.rules
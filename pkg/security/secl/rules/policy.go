@@ -85,11 +85,16 @@ LOOP:
 			continue
 		}
 
-		if ruleDef.Expression == "" && !ruleDef.Disabled {
+		if ruleDef.Expression == "" && ruleDef.Sequence == nil && !ruleDef.Disabled {
 			errs = multierror.Append(errs, &ErrRuleLoad{Definition: ruleDef, Err: errors.New("no expression defined")})
 			continue
 		}
 
+		if ruleDef.Expression != "" && ruleDef.Sequence != nil {
+			errs = multierror.Append(errs, &ErrRuleLoad{Definition: ruleDef, Err: errors.New("both expression and sequence defined")})
+			continue
+		}
+
 		policy.AddRule(ruleDef)
 	}
 
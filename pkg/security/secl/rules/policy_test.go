@@ -764,3 +764,71 @@ func TestActionSetVariableInvalid(t *testing.T) {
 		}
 	})
 }
+
+func TestActionHash(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		testPolicy := &PolicyDef{
+			Rules: []*RuleDefinition{{
+				ID:         "test_rule",
+				Expression: `open.filename == "/tmp/test"`,
+				Actions: []ActionDefinition{{
+					Hash: &HashDefinition{
+						Algorithms: []HashAlgorithm{SHA256, MD5},
+					},
+				}},
+			}},
+		}
+
+		rs, err := loadPolicy(t, testPolicy, PolicyLoaderOpts{})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		hash := rs.GetRules()["test_rule"].Definition.Actions[0].Hash
+		assert.Equal(t, []HashAlgorithm{SHA256, MD5}, hash.GetAlgorithms())
+		assert.Equal(t, int64(DefaultHashMaxFileSize), hash.GetMaxFileSize())
+		assert.Equal(t, float64(DefaultHashRateLimit), hash.GetRateLimit())
+	})
+
+	t.Run("unknown-algorithm", func(t *testing.T) {
+		testPolicy := &PolicyDef{
+			Rules: []*RuleDefinition{{
+				ID:         "test_rule",
+				Expression: `open.filename == "/tmp/test"`,
+				Actions: []ActionDefinition{{
+					Hash: &HashDefinition{
+						Algorithms: []HashAlgorithm{"crc32"},
+					},
+				}},
+			}},
+		}
+
+		if _, err := loadPolicy(t, testPolicy, PolicyLoaderOpts{}); err == nil {
+			t.Error("expected policy to fail to load")
+		} else {
+			t.Log(err)
+		}
+	})
+
+	t.Run("both-set-and-hash", func(t *testing.T) {
+		testPolicy := &PolicyDef{
+			Rules: []*RuleDefinition{{
+				ID:         "test_rule",
+				Expression: `open.filename == "/tmp/test"`,
+				Actions: []ActionDefinition{{
+					Set: &SetDefinition{
+						Name:  "var1",
+						Value: true,
+					},
+					Hash: &HashDefinition{},
+				}},
+			}},
+		}
+
+		if _, err := loadPolicy(t, testPolicy, PolicyLoaderOpts{}); err == nil {
+			t.Error("expected policy to fail to load")
+		} else {
+			t.Log(err)
+		}
+	})
+}
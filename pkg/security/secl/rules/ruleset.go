@@ -66,15 +66,19 @@ type RuleID = string
 
 // RuleDefinition holds the definition of a rule
 type RuleDefinition struct {
-	ID                     RuleID             `yaml:"id"`
-	Version                string             `yaml:"version"`
-	Expression             string             `yaml:"expression"`
-	Description            string             `yaml:"description"`
-	Tags                   map[string]string  `yaml:"tags"`
-	AgentVersionConstraint string             `yaml:"agent_version"`
-	Disabled               bool               `yaml:"disabled"`
-	Combine                CombinePolicy      `yaml:"combine"`
-	Actions                []ActionDefinition `yaml:"actions"`
+	ID                     RuleID               `yaml:"id"`
+	Version                string               `yaml:"version"`
+	Expression             string               `yaml:"expression"`
+	Description            string               `yaml:"description"`
+	Tags                   map[string]string    `yaml:"tags"`
+	AgentVersionConstraint string               `yaml:"agent_version"`
+	Disabled               bool                 `yaml:"disabled"`
+	Combine                CombinePolicy        `yaml:"combine"`
+	Actions                []ActionDefinition   `yaml:"actions"`
+	Sequence               *SequenceDefinition  `yaml:"sequence"`
+	RateLimit              *RateLimitDefinition `yaml:"rate_limit"`
+	Dedup                  *DedupDefinition     `yaml:"dedup"`
+	Aggregate              *AggregateDefinition `yaml:"aggregate"`
 	Policy                 *Policy
 }
 
@@ -94,6 +98,16 @@ func (rd *RuleDefinition) MergeWith(rd2 *RuleDefinition) error {
 	switch rd2.Combine {
 	case OverridePolicy:
 		rd.Expression = rd2.Expression
+		rd.Sequence = rd2.Sequence
+		if rd2.RateLimit != nil {
+			rd.RateLimit = rd2.RateLimit
+		}
+		if rd2.Dedup != nil {
+			rd.Dedup = rd2.Dedup
+		}
+		if rd2.Aggregate != nil {
+			rd.Aggregate = rd2.Aggregate
+		}
 	default:
 		if !rd2.Disabled {
 			return &ErrRuleLoad{Definition: rd2, Err: ErrInternalIDConflict}
@@ -105,13 +119,22 @@ func (rd *RuleDefinition) MergeWith(rd2 *RuleDefinition) error {
 
 // ActionDefinition describes a rule action section
 type ActionDefinition struct {
-	Set *SetDefinition `yaml:"set"`
+	Set  *SetDefinition  `yaml:"set"`
+	Hash *HashDefinition `yaml:"hash"`
 }
 
 // Check returns an error if the action in invalid
 func (a *ActionDefinition) Check() error {
+	if a.Set != nil && a.Hash != nil {
+		return errors.New("only one of 'set' or 'hash' can be defined in an action")
+	}
+
+	if a.Hash != nil {
+		return a.Hash.Check()
+	}
+
 	if a.Set == nil {
-		return errors.New("missing 'set' section in action")
+		return errors.New("missing 'set' or 'hash' section in action")
 	}
 
 	if a.Set.Name == "" {
@@ -137,10 +160,90 @@ type SetDefinition struct {
 	Scope  Scope       `yaml:"scope"`
 }
 
+// HashAlgorithm describes a hash algorithm
+type HashAlgorithm string
+
+const (
+	// SHA256 hash algorithm
+	SHA256 HashAlgorithm = "sha256"
+	// SHA1 hash algorithm
+	SHA1 HashAlgorithm = "sha1"
+	// MD5 hash algorithm
+	MD5 HashAlgorithm = "md5"
+)
+
+const (
+	// DefaultHashMaxFileSize is the default maximum size of the files hashed by the 'hash' action
+	DefaultHashMaxFileSize = 4 * 1024 * 1024
+	// DefaultHashRateLimit is the default maximum number of files hashed per second for a rule
+	DefaultHashRateLimit = 10
+)
+
+// HashDefinition describes the 'hash' section of a rule action. The file
+// referenced by the event, such as the executed or the opened file, is hashed
+// in user space and its digests are added to the event.
+type HashDefinition struct {
+	Algorithms  []HashAlgorithm `yaml:"algorithms"`
+	MaxFileSize int64           `yaml:"max_file_size"`
+	RateLimit   float64         `yaml:"rate_limit"`
+}
+
+// Check returns an error if the hash action is invalid
+func (h *HashDefinition) Check() error {
+	for _, algorithm := range h.Algorithms {
+		switch algorithm {
+		case SHA256, SHA1, MD5:
+		default:
+			return fmt.Errorf("unknown hash algorithm '%s'", algorithm)
+		}
+	}
+
+	if h.MaxFileSize < 0 {
+		return errors.New("'max_file_size' can't be negative")
+	}
+
+	if h.RateLimit < 0 {
+		return errors.New("'rate_limit' can't be negative")
+	}
+
+	return nil
+}
+
+// GetAlgorithms returns the hash algorithms, sha256 by default
+func (h *HashDefinition) GetAlgorithms() []HashAlgorithm {
+	if len(h.Algorithms) == 0 {
+		return []HashAlgorithm{SHA256}
+	}
+	return h.Algorithms
+}
+
+// GetMaxFileSize returns the maximum size of the files to hash
+func (h *HashDefinition) GetMaxFileSize() int64 {
+	if h.MaxFileSize == 0 {
+		return DefaultHashMaxFileSize
+	}
+	return h.MaxFileSize
+}
+
+// GetRateLimit returns the maximum number of files hashed per second
+func (h *HashDefinition) GetRateLimit() float64 {
+	if h.RateLimit == 0 {
+		return DefaultHashRateLimit
+	}
+	return h.RateLimit
+}
+
 // Rule describes a rule of a ruleset
 type Rule struct {
 	*eval.Rule
 	Definition *RuleDefinition
+
+	// sequence is set for the rules matching the steps of a sequence
+	sequence *sequence
+	step     int
+
+	// throttler applies the rate limit, dedup and aggregation options of the rule
+	throttler *ruleThrottler
 }
 
 // RuleSetListener describes the methods implemented by an object used to be
@@ -150,6 +253,18 @@ type RuleSetListener interface {
 	EventDiscarderFound(rs *RuleSet, event eval.Event, field eval.Field, eventType eval.EventType)
 }
 
+// SequenceListener can be implemented by a RuleSetListener to be notified of
+// all the events of a matching sequence rule instead of the last one only.
+type SequenceListener interface {
+	SequenceMatch(rule *Rule, events []eval.Event)
+}
+
+// AggregationListener can be implemented by a RuleSetListener to be notified
+// of the summary of the events of the rules defining an aggregation window.
+type AggregationListener interface {
+	RuleAggregation(rule *Rule, aggregation *Aggregation)
+}
+
 // RuleSet holds a list of rules, grouped in bucket. An event can be evaluated
 // against it. If the rule matches, the listeners for this rule set are notified
 type RuleSet struct {
@@ -306,6 +421,10 @@ func (rs *RuleSet) AddRule(ruleDef *RuleDefinition) (*eval.Rule, error) {
 		tags = append(tags, k+":"+v)
 	}
 
+	if ruleDef.Sequence != nil {
+		return rs.addSequenceRule(ruleDef, tags)
+	}
+
 	rule := &Rule{
 		Rule: &eval.Rule{
 			ID:         ruleDef.ID,
@@ -315,26 +434,59 @@ func (rs *RuleSet) AddRule(ruleDef *RuleDefinition) (*eval.Rule, error) {
 		Definition: ruleDef,
 	}
 
+	throttler, err := rs.newRuleThrottler(ruleDef)
+	if err != nil {
+		return nil, &ErrRuleLoad{Definition: ruleDef, Err: err}
+	}
+	rule.throttler = throttler
+
+	if err := rs.compileRule(rule); err != nil {
+		return nil, err
+	}
+
+	if err := rs.addRuleToBuckets(rule); err != nil {
+		return nil, err
+	}
+
+	rs.rules[ruleDef.ID] = rule
+
+	if err := rs.addActionsFieldEvaluators(ruleDef); err != nil {
+		return nil, err
+	}
+
+	return rule.Rule, nil
+}
+
+// compileRule parses the rule and generates its evaluator, the rule is rejected
+// if its event type isn't enabled
+func (rs *RuleSet) compileRule(rule *Rule) error {
+	ruleDef := rule.Definition
+
 	if err := rule.Parse(); err != nil {
-		return nil, &ErrRuleLoad{Definition: ruleDef, Err: fmt.Errorf("syntax error: %w", err)}
+		return &ErrRuleLoad{Definition: ruleDef, Err: fmt.Errorf("syntax error: %w", err)}
 	}
 
 	if err := rule.GenEvaluator(rs.model, rs.replCtx()); err != nil {
-		return nil, &ErrRuleLoad{Definition: ruleDef, Err: err}
+		return &ErrRuleLoad{Definition: ruleDef, Err: err}
 	}
 
 	eventType, err := GetRuleEventType(rule.Rule)
 	if err != nil {
-		return nil, &ErrRuleLoad{Definition: ruleDef, Err: err}
+		return &ErrRuleLoad{Definition: ruleDef, Err: err}
 	}
 
 	// ignore event types not supported
 	if _, exists := rs.opts.EventTypeEnabled["*"]; !exists {
 		if _, exists := rs.opts.EventTypeEnabled[eventType]; !exists {
-			return nil, &ErrRuleLoad{Definition: ruleDef, Err: ErrEventTypeNotEnabled}
+			return &ErrRuleLoad{Definition: ruleDef, Err: ErrEventTypeNotEnabled}
 		}
 	}
 
+	return nil
+}
+
+// addRuleToBuckets adds a compiled rule to the bucket of its events
+func (rs *RuleSet) addRuleToBuckets(rule *Rule) error {
 	for _, event := range rule.GetEvaluator().EventTypes {
 		bucket, exists := rs.eventRuleBuckets[event]
 		if !exists {
@@ -343,29 +495,42 @@ func (rs *RuleSet) AddRule(ruleDef *RuleDefinition) (*eval.Rule, error) {
 		}
 
 		if err := bucket.AddRule(rule); err != nil {
-			return nil, err
+			return err
 		}
 	}
 
 	// Merge the fields of the new rule with the existing list of fields of the ruleset
 	rs.AddFields(rule.GetEvaluator().GetFields())
 
-	rs.rules[ruleDef.ID] = rule
+	return nil
+}
 
-	// Generate evaluator for fields that are used in variables
-	for _, action := range rule.Definition.Actions {
+// addActionsFieldEvaluators generates the evaluators of the fields used in variables
+func (rs *RuleSet) addActionsFieldEvaluators(ruleDef *RuleDefinition) error {
+	for _, action := range ruleDef.Actions {
 		if action.Set != nil && action.Set.Field != "" {
-			if _, found := rs.fieldEvaluators[action.Set.Field]; !found {
-				evaluator, err := rs.model.GetEvaluator(action.Set.Field, "")
-				if err != nil {
-					return nil, err
-				}
-				rs.fieldEvaluators[action.Set.Field] = evaluator
+			if _, err := rs.getFieldEvaluator(action.Set.Field); err != nil {
+				return err
 			}
 		}
 	}
 
-	return rule.Rule, nil
+	return nil
+}
+
+// getFieldEvaluator returns the evaluator of a field, generating it if needed
+func (rs *RuleSet) getFieldEvaluator(field eval.Field) (eval.Evaluator, error) {
+	if evaluator, found := rs.fieldEvaluators[field]; found {
+		return evaluator, nil
+	}
+
+	evaluator, err := rs.model.GetEvaluator(field, "")
+	if err != nil {
+		return nil, err
+	}
+	rs.fieldEvaluators[field] = evaluator
+
+	return evaluator, nil
 }
 
 // NotifyRuleMatch notifies all the ruleset listeners that an event matched a rule
@@ -378,6 +543,21 @@ func (rs *RuleSet) NotifyRuleMatch(rule *Rule, event eval.Event) {
 	}
 }
 
+// NotifySequenceMatch notifies all the ruleset listeners that a sequence of events matched a rule. The listeners
+// implementing SequenceListener get all the events of the sequence, the others get the last event only.
+func (rs *RuleSet) NotifySequenceMatch(rule *Rule, event eval.Event, events []eval.Event) {
+	rs.listenersLock.RLock()
+	defer rs.listenersLock.RUnlock()
+
+	for _, listener := range rs.listeners {
+		if sequenceListener, ok := listener.(SequenceListener); ok {
+			sequenceListener.SequenceMatch(rule, events)
+		} else {
+			listener.RuleMatch(rule, event)
+		}
+	}
+}
+
 // NotifyDiscarderFound notifies all the ruleset listeners that a discarder was found for an event
 func (rs *RuleSet) NotifyDiscarderFound(event eval.Event, field eval.Field, eventType eval.EventType) {
 	rs.listenersLock.RLock()
@@ -446,10 +626,13 @@ func (rs *RuleSet) GetEventApprovers(eventType eval.EventType, fieldCaps FieldCa
 func (rs *RuleSet) GetFieldValues(field eval.Field) []eval.FieldValue {
 	var values []eval.FieldValue
 
-	for _, rule := range rs.rules {
-		rv := rule.GetFieldValues(field)
-		if len(rv) > 0 {
-			values = append(values, rv...)
+	// the buckets hold the compiled rules, including the steps of the sequences
+	for _, bucket := range rs.eventRuleBuckets {
+		for _, rule := range bucket.rules {
+			rv := rule.GetFieldValues(field)
+			if len(rv) > 0 {
+				values = append(values, rv...)
+			}
 		}
 	}
 
@@ -519,7 +702,8 @@ func (rs *RuleSet) runRuleActions(ctx *eval.Context, rule *Rule) error {
 	return nil
 }
 
-// Evaluate the specified event against the set of rules
+// Evaluate the specified event against the set of rules, it returns true if the
+// event matched a rule or a step of a sequence
 func (rs *RuleSet) Evaluate(event eval.Event) bool {
 	ctx := rs.pool.Get(event.GetPointer())
 	defer rs.pool.Put(ctx)
@@ -535,14 +719,16 @@ func (rs *RuleSet) Evaluate(event eval.Event) bool {
 
 	for _, rule := range bucket.rules {
 		if rule.GetEvaluator().Eval(ctx) {
+			if rule.sequence != nil {
+				rs.advanceSequence(ctx, rule, event)
+				result = true
+				continue
+			}
+
 			rs.logger.Tracef("Rule `%s` matches with event `%s`\n", rule.ID, event)
 
-			rs.NotifyRuleMatch(rule, event)
+			rs.notifyMatch(ctx, rule, event, nil)
 			result = true
-
-			if err := rs.runRuleActions(ctx, rule); err != nil {
-				rs.logger.Errorf("Error while executing rule actions: %s", err)
-			}
 		}
 	}
 
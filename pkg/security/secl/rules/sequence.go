@@ -0,0 +1,192 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package rules
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/security/secl/compiler/eval"
+)
+
+// DefaultSequenceMaxSize is the default maximum number of correlation keys tracked by a sequence
+const DefaultSequenceMaxSize = 1000
+
+// SequenceDefinition describes a sequence of steps that have to match in order,
+// for the same correlation key, within a given duration
+type SequenceDefinition struct {
+	Steps          []*SequenceStepDefinition `yaml:"steps"`
+	Within         string                    `yaml:"within"`
+	CorrelationKey []string                  `yaml:"correlation_key"`
+	MaxSize        int                       `yaml:"max_size"`
+}
+
+// SequenceStepDefinition describes a step of a sequence. The correlation key of
+// the sequence is used when the step doesn't define its own.
+type SequenceStepDefinition struct {
+	Expression     string   `yaml:"expression"`
+	CorrelationKey []string `yaml:"correlation_key"`
+}
+
+// GetExpression returns the expression of the rule, sequence rules are reported
+// with the expressions of their steps
+func (rd *RuleDefinition) GetExpression() string {
+	if rd.Sequence == nil {
+		return rd.Expression
+	}
+	return rd.Sequence.String()
+}
+
+// String returns a description of the sequence, used to report it
+func (sd *SequenceDefinition) String() string {
+	steps := make([]string, len(sd.Steps))
+	for i, step := range sd.Steps {
+		steps[i] = step.Expression
+		if len(step.CorrelationKey) > 0 {
+			steps[i] += fmt.Sprintf(" by [%s]", strings.Join(step.CorrelationKey, ", "))
+		}
+	}
+	return fmt.Sprintf("sequence within %s by [%s]: %s", sd.Within, strings.Join(sd.CorrelationKey, ", "), strings.Join(steps, " -> "))
+}
+
+// sequence holds the rule notified when a sequence completes and its state
+type sequence struct {
+	rule  *Rule
+	keys  [][]eval.Evaluator
+	state *eval.SequenceState
+}
+
+// key returns the correlation key of the event for the given step
+func (s *sequence) key(ctx *eval.Context, step int) string {
+	values := make([]string, len(s.keys[step]))
+	for i, evaluator := range s.keys[step] {
+		values[i] = fmt.Sprintf("%v", evaluator.Eval(ctx))
+	}
+	return strings.Join(values, "\x00")
+}
+
+// stepRuleID returns the ID of the rule matching a step of a sequence
+func stepRuleID(id string, step int) string {
+	return fmt.Sprintf("%s_step%d", id, step)
+}
+
+func (rs *RuleSet) addSequenceRule(ruleDef *RuleDefinition, tags []string) (*eval.Rule, error) {
+	seqDef := ruleDef.Sequence
+
+	if len(seqDef.Steps) == 0 {
+		return nil, &ErrRuleLoad{Definition: ruleDef, Err: errors.New("no step defined in sequence")}
+	}
+
+	within, err := time.ParseDuration(seqDef.Within)
+	if err != nil || within <= 0 {
+		return nil, &ErrRuleLoad{Definition: ruleDef, Err: fmt.Errorf("invalid sequence duration `%s`", seqDef.Within)}
+	}
+
+	maxSize := seqDef.MaxSize
+	if maxSize <= 0 {
+		maxSize = DefaultSequenceMaxSize
+	}
+
+	rule := &Rule{
+		Rule: &eval.Rule{
+			ID:   ruleDef.ID,
+			Tags: tags,
+		},
+		Definition: ruleDef,
+	}
+
+	throttler, err := rs.newRuleThrottler(ruleDef)
+	if err != nil {
+		return nil, &ErrRuleLoad{Definition: ruleDef, Err: err}
+	}
+	rule.throttler = throttler
+
+	seq := &sequence{
+		rule:  rule,
+		keys:  make([][]eval.Evaluator, len(seqDef.Steps)),
+		state: eval.NewSequenceState(len(seqDef.Steps), within, maxSize),
+	}
+
+	var stepRules []*Rule
+	for i, stepDef := range seqDef.Steps {
+		if stepDef.Expression == "" {
+			return nil, &ErrRuleLoad{Definition: ruleDef, Err: fmt.Errorf("no expression defined for step %d", i)}
+		}
+
+		fields := stepDef.CorrelationKey
+		if len(fields) == 0 {
+			fields = seqDef.CorrelationKey
+		}
+		if i > 0 && len(fields) != len(seq.keys[0]) {
+			return nil, &ErrRuleLoad{Definition: ruleDef, Err: fmt.Errorf("correlation key of step %d doesn't match the one of the first step", i)}
+		}
+
+		for _, field := range fields {
+			evaluator, err := rs.getFieldEvaluator(field)
+			if err != nil {
+				return nil, &ErrRuleLoad{Definition: ruleDef, Err: fmt.Errorf("invalid correlation key `%s`: %w", field, err)}
+			}
+			seq.keys[i] = append(seq.keys[i], evaluator)
+		}
+
+		stepRules = append(stepRules, &Rule{
+			Rule: &eval.Rule{
+				ID:         stepRuleID(ruleDef.ID, i),
+				Expression: stepDef.Expression,
+				Tags:       tags,
+			},
+			Definition: ruleDef,
+			sequence:   seq,
+			step:       i,
+		})
+	}
+
+	// all the steps are checked before any of them is added to the buckets, so
+	// that an invalid step doesn't leave the previous ones advancing the sequence
+	for _, stepRule := range stepRules {
+		if err := rs.compileRule(stepRule); err != nil {
+			return nil, err
+		}
+
+		for _, event := range stepRule.GetEvaluator().EventTypes {
+			if bucket, exists := rs.eventRuleBuckets[event]; exists && bucket.hasRule(stepRule.ID) {
+				return nil, &ErrRuleLoad{Definition: ruleDef, Err: ErrDefinitionIDConflict}
+			}
+		}
+	}
+
+	for _, stepRule := range stepRules {
+		if err := rs.addRuleToBuckets(stepRule); err != nil {
+			return nil, err
+		}
+	}
+
+	rs.rules[ruleDef.ID] = rule
+
+	if err := rs.addActionsFieldEvaluators(ruleDef); err != nil {
+		return nil, err
+	}
+
+	return rule.Rule, nil
+}
+
+// advanceSequence records that the event matched a step of a sequence, the
+// listeners are notified and the actions run once the sequence completes
+func (rs *RuleSet) advanceSequence(ctx *eval.Context, stepRule *Rule, event eval.Event) {
+	seq := stepRule.sequence
+
+	events, completed := seq.state.Advance(seq.key(ctx, stepRule.step), stepRule.step, event, ctx.Now())
+	if !completed {
+		rs.logger.Tracef("Step %d of sequence `%s` matches with event `%s`\n", stepRule.step, seq.rule.ID, event)
+		return
+	}
+
+	rs.logger.Tracef("Sequence `%s` matches with event `%s`\n", seq.rule.ID, event)
+
+	rs.notifyMatch(ctx, seq.rule, event, events)
+}
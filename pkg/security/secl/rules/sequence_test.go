@@ -0,0 +1,241 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package rules
+
+import (
+	"testing"
+
+	"github.com/DataDog/datadog-agent/pkg/security/secl/compiler/eval"
+)
+
+type testSequenceHandler struct {
+	testHandler
+	matches [][]eval.Event
+}
+
+func (h *testSequenceHandler) SequenceMatch(rule *Rule, events []eval.Event) {
+	h.matches = append(h.matches, events)
+}
+
+type testRuleMatchHandler struct {
+	testHandler
+	matches []eval.Event
+}
+
+func (h *testRuleMatchHandler) RuleMatch(rule *Rule, event eval.Event) {
+	h.matches = append(h.matches, event)
+}
+
+func TestSequenceRule(t *testing.T) {
+	rs := newRuleSet()
+
+	sequenceHandler := &testSequenceHandler{testHandler: testHandler{model: &testModel{}, filters: make(map[string]testFieldValues)}}
+	ruleMatchHandler := &testRuleMatchHandler{testHandler: testHandler{model: &testModel{}, filters: make(map[string]testFieldValues)}}
+	rs.AddListener(sequenceHandler)
+	rs.AddListener(ruleMatchHandler)
+
+	ruleDef := &RuleDefinition{
+		ID: "mkdir_then_open",
+		Sequence: &SequenceDefinition{
+			Steps: []*SequenceStepDefinition{
+				{Expression: `mkdir.filename == "/tmp/test"`},
+				{Expression: `open.filename =~ "/tmp/test/*"`},
+			},
+			Within:         "1m",
+			CorrelationKey: []string{"process.name"},
+		},
+	}
+	if err := rs.AddRules([]*RuleDefinition{ruleDef}); err != nil {
+		t.Fatal(err)
+	}
+
+	if rule := rs.GetRules()["mkdir_then_open"]; rule == nil {
+		t.Fatal("sequence rule not found")
+	}
+
+	// the field values come from the steps of the sequence
+	if values := rs.GetFieldValues("mkdir.filename"); len(values) != 1 || values[0].Value != "/tmp/test" {
+		t.Errorf("unexpected field values: %v", values)
+	}
+
+	if expression := ruleDef.GetExpression(); expression != `sequence within 1m by [process.name]: mkdir.filename == "/tmp/test" -> open.filename =~ "/tmp/test/*"` {
+		t.Errorf("unexpected expression: %s", expression)
+	}
+
+	mkdir := &testEvent{
+		kind:    "mkdir",
+		process: testProcess{name: "abc"},
+		mkdir:   testMkdir{filename: "/tmp/test"},
+	}
+	openOther := &testEvent{
+		kind:    "open",
+		process: testProcess{name: "xyz"},
+		open:    testOpen{filename: "/tmp/test/file"},
+	}
+	open := &testEvent{
+		kind:    "open",
+		process: testProcess{name: "abc"},
+		open:    testOpen{filename: "/tmp/test/file"},
+	}
+
+	if !rs.Evaluate(open) {
+		t.Error("step event should be reported as matching")
+	}
+	if !rs.Evaluate(mkdir) {
+		t.Error("step event should be reported as matching")
+	}
+	rs.Evaluate(openOther)
+	if len(sequenceHandler.matches) != 0 || len(ruleMatchHandler.matches) != 0 {
+		t.Fatal("sequence shouldn't match for another correlation key")
+	}
+
+	rs.Evaluate(open)
+	if len(sequenceHandler.matches) != 1 {
+		t.Fatalf("expected 1 sequence match, got %d", len(sequenceHandler.matches))
+	}
+	if events := sequenceHandler.matches[0]; len(events) != 2 || events[0] != mkdir || events[1] != open {
+		t.Errorf("unexpected sequence events: %v", events)
+	}
+	if len(ruleMatchHandler.matches) != 1 || ruleMatchHandler.matches[0] != open {
+		t.Errorf("expected the last event to be notified: %v", ruleMatchHandler.matches)
+	}
+
+	// the sequence is reset once completed
+	rs.Evaluate(open)
+	if len(sequenceHandler.matches) != 1 {
+		t.Errorf("sequence shouldn't match again without its first step")
+	}
+}
+
+func TestSequenceRuleInvalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		sequence *SequenceDefinition
+	}{
+		{
+			name:     "no step",
+			sequence: &SequenceDefinition{Within: "1m"},
+		},
+		{
+			name: "invalid duration",
+			sequence: &SequenceDefinition{
+				Steps:  []*SequenceStepDefinition{{Expression: `mkdir.filename == "/tmp/test"`}},
+				Within: "abc",
+			},
+		},
+		{
+			name: "invalid correlation key",
+			sequence: &SequenceDefinition{
+				Steps:          []*SequenceStepDefinition{{Expression: `mkdir.filename == "/tmp/test"`}},
+				Within:         "1m",
+				CorrelationKey: []string{"process.unknown"},
+			},
+		},
+		{
+			name: "mismatching correlation keys",
+			sequence: &SequenceDefinition{
+				Steps: []*SequenceStepDefinition{
+					{Expression: `mkdir.filename == "/tmp/test"`, CorrelationKey: []string{"process.name"}},
+					{Expression: `open.filename == "/tmp/test"`, CorrelationKey: []string{"process.name", "process.uid"}},
+				},
+				Within: "1m",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rs := newRuleSet()
+			if _, err := rs.AddRule(&RuleDefinition{ID: "seq", Sequence: test.sequence}); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestSequenceRuleInvalidStep(t *testing.T) {
+	tests := []struct {
+		name  string
+		rules []*RuleDefinition
+		opts  func(*Opts)
+	}{
+		{
+			name: "syntax error",
+			rules: []*RuleDefinition{{
+				ID: "seq",
+				Sequence: &SequenceDefinition{
+					Steps: []*SequenceStepDefinition{
+						{Expression: `mkdir.filename == "/tmp/test"`},
+						{Expression: `open.filename ==`},
+					},
+					Within: "1m",
+				},
+			}},
+		},
+		{
+			name: "event type not enabled",
+			rules: []*RuleDefinition{{
+				ID: "seq",
+				Sequence: &SequenceDefinition{
+					Steps: []*SequenceStepDefinition{
+						{Expression: `mkdir.filename == "/tmp/test"`},
+						{Expression: `open.filename == "/tmp/test"`},
+					},
+					Within: "1m",
+				},
+			}},
+			opts: func(opts *Opts) {
+				opts.WithEventTypeEnabled(map[eval.EventType]bool{"mkdir": true})
+			},
+		},
+		{
+			name: "step ID conflict",
+			rules: []*RuleDefinition{
+				{ID: "seq_step1", Expression: `open.filename == "/tmp/other"`},
+				{
+					ID: "seq",
+					Sequence: &SequenceDefinition{
+						Steps: []*SequenceStepDefinition{
+							{Expression: `mkdir.filename == "/tmp/test"`},
+							{Expression: `open.filename == "/tmp/test"`},
+						},
+						Within: "1m",
+					},
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rs := newRuleSet()
+			if test.opts != nil {
+				test.opts(rs.opts)
+			}
+
+			for _, ruleDef := range test.rules[:len(test.rules)-1] {
+				if _, err := rs.AddRule(ruleDef); err != nil {
+					t.Fatal(err)
+				}
+			}
+			fields := append([]string{}, rs.fields...)
+
+			if _, err := rs.AddRule(test.rules[len(test.rules)-1]); err == nil {
+				t.Fatal("expected an error")
+			}
+
+			if _, exists := rs.GetRules()["seq"]; exists {
+				t.Error("the sequence rule shouldn't be added")
+			}
+			if _, exists := rs.eventRuleBuckets["mkdir"]; exists {
+				t.Error("the first step shouldn't be added to the buckets")
+			}
+			if len(rs.fields) != len(fields) {
+				t.Errorf("the fields of the steps shouldn't be added: %v", rs.fields)
+			}
+		})
+	}
+}
@@ -0,0 +1,339 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package rules
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/security/secl/compiler/eval"
+)
+
+// DefaultDedupMaxSize is the default maximum number of keys tracked to deduplicate the events of a rule
+const DefaultDedupMaxSize = 1000
+
+// RateLimitDefinition describes a token bucket limiting the number of events notified for a rule
+type RateLimitDefinition struct {
+	Limit float64 `yaml:"limit"`
+	Burst int     `yaml:"burst"`
+}
+
+// DedupDefinition describes how the events of a rule are deduplicated. Events
+// having the same values for the given fields are notified once per window.
+type DedupDefinition struct {
+	Fields  []string `yaml:"fields"`
+	Window  string   `yaml:"window"`
+	MaxSize int      `yaml:"max_size"`
+}
+
+// AggregateDefinition describes how the events of a rule are aggregated. The
+// first event of a window is notified, the following ones are summarized in
+// one aggregation notified at the end of the window.
+type AggregateDefinition struct {
+	Window string `yaml:"window"`
+}
+
+// Aggregation summarizes the events of a rule matching during a window
+type Aggregation struct {
+	Count int
+	Start time.Time
+	End   time.Time
+	First eval.Event
+	Last  eval.Event
+}
+
+// RuleStats holds the number of events matching a rule and what happened to them
+type RuleStats struct {
+	Matched      uint64
+	Notified     uint64
+	RateLimited  uint64
+	Deduplicated uint64
+	Aggregated   uint64
+}
+
+// tokenBucket is a token bucket refilled at limit tokens per second, up to burst tokens
+type tokenBucket struct {
+	limit  float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(limit float64, burst int) *tokenBucket {
+	if burst <= 0 {
+		burst = int(math.Max(1, math.Ceil(limit)))
+	}
+
+	return &tokenBucket{
+		limit:  limit,
+		burst:  float64(burst),
+		tokens: float64(burst),
+	}
+}
+
+func (b *tokenBucket) allow(now time.Time) bool {
+	if !b.last.IsZero() && now.After(b.last) {
+		b.tokens = math.Min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.limit)
+	}
+	b.last = now
+
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+
+	return true
+}
+
+// ruleThrottler applies the rate limit, deduplication and aggregation options of a rule
+type ruleThrottler struct {
+	sync.Mutex
+
+	rateLimit *tokenBucket
+
+	dedupKeys    []eval.Evaluator
+	dedupWindow  time.Duration
+	dedupMaxSize int
+	dedupSeen    map[string]time.Time
+
+	aggregateWindow time.Duration
+	aggregation     *Aggregation
+
+	stats RuleStats
+}
+
+func parseWindow(kind string, window string) (time.Duration, error) {
+	duration, err := time.ParseDuration(window)
+	if err != nil || duration <= 0 {
+		return 0, fmt.Errorf("invalid %s window `%s`", kind, window)
+	}
+	return duration, nil
+}
+
+// newRuleThrottler returns the throttler of a rule, nil if the rule doesn't define any option
+func (rs *RuleSet) newRuleThrottler(ruleDef *RuleDefinition) (*ruleThrottler, error) {
+	if ruleDef.RateLimit == nil && ruleDef.Dedup == nil && ruleDef.Aggregate == nil {
+		return nil, nil
+	}
+
+	t := &ruleThrottler{}
+
+	if ruleDef.RateLimit != nil {
+		if ruleDef.RateLimit.Limit <= 0 {
+			return nil, fmt.Errorf("invalid rate limit `%v`", ruleDef.RateLimit.Limit)
+		}
+		t.rateLimit = newTokenBucket(ruleDef.RateLimit.Limit, ruleDef.RateLimit.Burst)
+	}
+
+	if dedup := ruleDef.Dedup; dedup != nil {
+		if len(dedup.Fields) == 0 {
+			return nil, fmt.Errorf("no dedup field defined")
+		}
+
+		window, err := parseWindow("dedup", dedup.Window)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, field := range dedup.Fields {
+			evaluator, err := rs.getFieldEvaluator(field)
+			if err != nil {
+				return nil, fmt.Errorf("invalid dedup field `%s`: %w", field, err)
+			}
+			t.dedupKeys = append(t.dedupKeys, evaluator)
+		}
+
+		t.dedupWindow = window
+		t.dedupMaxSize = dedup.MaxSize
+		if t.dedupMaxSize <= 0 {
+			t.dedupMaxSize = DefaultDedupMaxSize
+		}
+		t.dedupSeen = make(map[string]time.Time)
+	}
+
+	if ruleDef.Aggregate != nil {
+		window, err := parseWindow("aggregate", ruleDef.Aggregate.Window)
+		if err != nil {
+			return nil, err
+		}
+		t.aggregateWindow = window
+	}
+
+	return t, nil
+}
+
+// allow returns whether the event should be notified, along with the
+// aggregation of the previous window if it just ended
+func (t *ruleThrottler) allow(ctx *eval.Context, event eval.Event, now time.Time) (bool, *Aggregation) {
+	t.Lock()
+	defer t.Unlock()
+
+	t.stats.Matched++
+
+	if t.dedupKeys != nil && t.isDuplicate(ctx, now) {
+		t.stats.Deduplicated++
+		return false, nil
+	}
+
+	var ended *Aggregation
+	if t.aggregateWindow != 0 {
+		ended = t.flush(now)
+
+		if t.aggregation != nil {
+			t.aggregation.Count++
+			t.aggregation.End = now
+			t.aggregation.Last = eval.CloneEvent(event)
+			t.stats.Aggregated++
+			return false, ended
+		}
+
+		first := eval.CloneEvent(event)
+		t.aggregation = &Aggregation{
+			Count: 1,
+			Start: now,
+			End:   now,
+			First: first,
+			Last:  first,
+		}
+	}
+
+	if t.rateLimit != nil && !t.rateLimit.allow(now) {
+		t.stats.RateLimited++
+		return false, ended
+	}
+
+	t.stats.Notified++
+
+	return true, ended
+}
+
+// isDuplicate returns whether an event with the same key was seen during the
+// window, and records the key otherwise
+func (t *ruleThrottler) isDuplicate(ctx *eval.Context, now time.Time) bool {
+	values := make([]string, len(t.dedupKeys))
+	for i, evaluator := range t.dedupKeys {
+		values[i] = fmt.Sprintf("%v", evaluator.Eval(ctx))
+	}
+	key := strings.Join(values, "\x00")
+
+	if seen, found := t.dedupSeen[key]; found && now.Sub(seen) <= t.dedupWindow {
+		return true
+	}
+
+	if len(t.dedupSeen) >= t.dedupMaxSize {
+		for k, seen := range t.dedupSeen {
+			if now.Sub(seen) > t.dedupWindow {
+				delete(t.dedupSeen, k)
+			}
+		}
+
+		// all the keys are still in their window, start over rather than growing
+		if len(t.dedupSeen) >= t.dedupMaxSize {
+			t.dedupSeen = make(map[string]time.Time)
+		}
+	}
+	t.dedupSeen[key] = now
+
+	return false
+}
+
+// flush ends the current aggregation if its window is over. Only the
+// aggregations of more than one event are returned, the first event having
+// already been notified.
+func (t *ruleThrottler) flush(now time.Time) *Aggregation {
+	if t.aggregation == nil || now.Sub(t.aggregation.Start) < t.aggregateWindow {
+		return nil
+	}
+
+	aggregation := t.aggregation
+	t.aggregation = nil
+
+	if aggregation.Count <= 1 {
+		return nil
+	}
+	return aggregation
+}
+
+func (t *ruleThrottler) getStats() RuleStats {
+	t.Lock()
+	defer t.Unlock()
+
+	return t.stats
+}
+
+// notifyMatch applies the throttling options of the rule before notifying the
+// listeners, then runs the actions of the rule. For sequences, events holds
+// all the events of the sequence.
+func (rs *RuleSet) notifyMatch(ctx *eval.Context, rule *Rule, event eval.Event, events []eval.Event) {
+	allowed := true
+
+	if rule.throttler != nil {
+		var ended *Aggregation
+		allowed, ended = rule.throttler.allow(ctx, event, ctx.Now())
+		if ended != nil {
+			rs.NotifyRuleAggregation(rule, ended)
+		}
+	}
+
+	if allowed {
+		if events != nil {
+			rs.NotifySequenceMatch(rule, event, events)
+		} else {
+			rs.NotifyRuleMatch(rule, event)
+		}
+	} else {
+		rs.logger.Tracef("Event `%s` on rule `%s` was throttled\n", event, rule.ID)
+	}
+
+	if err := rs.runRuleActions(ctx, rule); err != nil {
+		rs.logger.Errorf("Error while executing rule actions: %s", err)
+	}
+}
+
+// NotifyRuleAggregation notifies the ruleset listeners implementing AggregationListener that the
+// aggregation window of a rule ended
+func (rs *RuleSet) NotifyRuleAggregation(rule *Rule, aggregation *Aggregation) {
+	rs.listenersLock.RLock()
+	defer rs.listenersLock.RUnlock()
+
+	for _, listener := range rs.listeners {
+		if aggregationListener, ok := listener.(AggregationListener); ok {
+			aggregationListener.RuleAggregation(rule, aggregation)
+		}
+	}
+}
+
+// FlushAggregations notifies the aggregations whose window ended. Aggregations
+// are otherwise only flushed when their rule matches again.
+func (rs *RuleSet) FlushAggregations(now time.Time) {
+	for _, rule := range rs.rules {
+		if rule.throttler == nil || rule.throttler.aggregateWindow == 0 {
+			continue
+		}
+
+		rule.throttler.Lock()
+		ended := rule.throttler.flush(now)
+		rule.throttler.Unlock()
+
+		if ended != nil {
+			rs.NotifyRuleAggregation(rule, ended)
+		}
+	}
+}
+
+// GetRuleStats returns the statistics of the rules defining throttling options
+func (rs *RuleSet) GetRuleStats() map[RuleID]RuleStats {
+	stats := make(map[RuleID]RuleStats)
+	for id, rule := range rs.rules {
+		if rule.throttler != nil {
+			stats[id] = rule.throttler.getStats()
+		}
+	}
+	return stats
+}
@@ -0,0 +1,196 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package rules
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/security/secl/compiler/eval"
+)
+
+type testAggregationHandler struct {
+	testRuleMatchHandler
+	aggregations []*Aggregation
+}
+
+func (h *testAggregationHandler) RuleAggregation(rule *Rule, aggregation *Aggregation) {
+	h.aggregations = append(h.aggregations, aggregation)
+}
+
+func newTestThrottler(t *testing.T, rs *RuleSet, ruleDef *RuleDefinition) *ruleThrottler {
+	throttler, err := rs.newRuleThrottler(ruleDef)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return throttler
+}
+
+func TestThrottlerRateLimit(t *testing.T) {
+	rs := newRuleSet()
+	throttler := newTestThrottler(t, rs, &RuleDefinition{RateLimit: &RateLimitDefinition{Limit: 1, Burst: 2}})
+
+	event := &testEvent{kind: "open"}
+	ctx := eval.NewContext(event.GetPointer())
+	now := time.Now()
+
+	var allowed int
+	for i := 0; i < 5; i++ {
+		if ok, _ := throttler.allow(ctx, event, now); ok {
+			allowed++
+		}
+	}
+	if allowed != 2 {
+		t.Errorf("expected the burst to be allowed, got %d events", allowed)
+	}
+
+	if ok, _ := throttler.allow(ctx, event, now.Add(time.Second)); !ok {
+		t.Error("expected the bucket to be refilled")
+	}
+
+	stats := throttler.getStats()
+	if stats.Matched != 6 || stats.Notified != 3 || stats.RateLimited != 3 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+}
+
+func TestThrottlerDedup(t *testing.T) {
+	rs := newRuleSet()
+	throttler := newTestThrottler(t, rs, &RuleDefinition{Dedup: &DedupDefinition{Fields: []string{"process.name"}, Window: "10s", MaxSize: 2}})
+
+	now := time.Now()
+	allow := func(name string, at time.Time) bool {
+		event := &testEvent{kind: "open", process: testProcess{name: name}}
+		ok, _ := throttler.allow(eval.NewContext(event.GetPointer()), event, at)
+		return ok
+	}
+
+	if !allow("abc", now) {
+		t.Error("first event should be allowed")
+	}
+	if allow("abc", now.Add(time.Second)) {
+		t.Error("duplicate event should be dropped")
+	}
+	if !allow("xyz", now.Add(time.Second)) {
+		t.Error("event with another key should be allowed")
+	}
+	if !allow("abc", now.Add(11*time.Second)) {
+		t.Error("event should be allowed once the window is over")
+	}
+
+	// the number of keys is bounded
+	allow("def", now.Add(12*time.Second))
+	if len(throttler.dedupSeen) > 2 {
+		t.Errorf("expected at most 2 keys, got %d", len(throttler.dedupSeen))
+	}
+
+	if stats := throttler.getStats(); stats.Deduplicated != 1 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+}
+
+func TestThrottlerAggregate(t *testing.T) {
+	rs := newRuleSet()
+	throttler := newTestThrottler(t, rs, &RuleDefinition{Aggregate: &AggregateDefinition{Window: "1m"}})
+
+	now := time.Now()
+	events := []*testEvent{{id: "1"}, {id: "2"}, {id: "3"}}
+	allow := func(event *testEvent, at time.Time) (bool, *Aggregation) {
+		return throttler.allow(eval.NewContext(event.GetPointer()), event, at)
+	}
+
+	if ok, ended := allow(events[0], now); !ok || ended != nil {
+		t.Error("first event of the window should be notified")
+	}
+	if ok, _ := allow(events[1], now.Add(time.Second)); ok {
+		t.Error("events of the window should be aggregated")
+	}
+	if ok, _ := allow(events[2], now.Add(2*time.Second)); ok {
+		t.Error("events of the window should be aggregated")
+	}
+
+	ok, ended := allow(events[0], now.Add(2*time.Minute))
+	if !ok {
+		t.Error("first event of the next window should be notified")
+	}
+	if ended == nil {
+		t.Fatal("expected the previous window to be flushed")
+	}
+	if ended.Count != 3 || ended.First != events[0] || ended.Last != events[2] {
+		t.Errorf("unexpected aggregation: %+v", ended)
+	}
+
+	// a window with a single event doesn't produce an aggregation
+	if ended := throttler.flush(now.Add(4 * time.Minute)); ended != nil {
+		t.Errorf("unexpected aggregation: %+v", ended)
+	}
+}
+
+func TestRuleSetThrottling(t *testing.T) {
+	rs := newRuleSet()
+
+	handler := &testAggregationHandler{testRuleMatchHandler: testRuleMatchHandler{testHandler: testHandler{model: &testModel{}, filters: make(map[string]testFieldValues)}}}
+	rs.AddListener(handler)
+
+	ruleDefs := []*RuleDefinition{
+		{
+			ID:         "dedup",
+			Expression: `open.filename == "/etc/passwd"`,
+			Dedup:      &DedupDefinition{Fields: []string{"process.name"}, Window: "1m"},
+		},
+		{
+			ID:         "aggregate",
+			Expression: `mkdir.filename == "/tmp/test"`,
+			Aggregate:  &AggregateDefinition{Window: "1m"},
+		},
+	}
+	if err := rs.AddRules(ruleDefs); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 3; i++ {
+		rs.Evaluate(&testEvent{kind: "open", process: testProcess{name: "cron"}, open: testOpen{filename: "/etc/passwd"}})
+		rs.Evaluate(&testEvent{kind: "mkdir", mkdir: testMkdir{filename: "/tmp/test"}})
+	}
+
+	if len(handler.matches) != 2 {
+		t.Errorf("expected 2 notified events, got %d", len(handler.matches))
+	}
+
+	rs.FlushAggregations(time.Now().Add(2 * time.Minute))
+	if len(handler.aggregations) != 1 || handler.aggregations[0].Count != 3 {
+		t.Errorf("unexpected aggregations: %+v", handler.aggregations)
+	}
+
+	stats := rs.GetRuleStats()
+	if len(stats) != 2 {
+		t.Fatalf("expected stats for 2 rules, got %d", len(stats))
+	}
+	if stats["dedup"].Matched != 3 || stats["dedup"].Deduplicated != 2 {
+		t.Errorf("unexpected dedup stats: %+v", stats["dedup"])
+	}
+	if stats["aggregate"].Notified != 1 || stats["aggregate"].Aggregated != 2 {
+		t.Errorf("unexpected aggregate stats: %+v", stats["aggregate"])
+	}
+}
+
+func TestRuleThrottlingInvalid(t *testing.T) {
+	ruleDefs := []*RuleDefinition{
+		{ID: "rate", Expression: `open.filename == "/etc/passwd"`, RateLimit: &RateLimitDefinition{}},
+		{ID: "dedup_fields", Expression: `open.filename == "/etc/passwd"`, Dedup: &DedupDefinition{Window: "1m"}},
+		{ID: "dedup_window", Expression: `open.filename == "/etc/passwd"`, Dedup: &DedupDefinition{Fields: []string{"process.name"}}},
+		{ID: "dedup_field", Expression: `open.filename == "/etc/passwd"`, Dedup: &DedupDefinition{Fields: []string{"process.unknown"}, Window: "1m"}},
+		{ID: "aggregate", Expression: `open.filename == "/etc/passwd"`, Aggregate: &AggregateDefinition{Window: "-1s"}},
+	}
+
+	for _, ruleDef := range ruleDefs {
+		t.Run(ruleDef.ID, func(t *testing.T) {
+			if _, err := newRuleSet().AddRule(ruleDef); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
@@ -458,11 +458,12 @@ func (s *Serializer) SendContainerLifecycleEvent(msgs []ContainerLifecycleMessag
 		return errors.New("container lifecycle forwarder is not setup")
 	}
 
-	for _, msg := range msgs {
+	for i := range msgs {
+		msg := &msgs[i]
 		extraHeaders := make(http.Header)
 		extraHeaders.Set("Content-Type", protobufContentType)
 		msg.Host = hostname
-		encoded, err := proto.Marshal(&msg)
+		encoded, err := proto.Marshal(msg)
 		if err != nil {
 			return log.Errorf("Unable to encode message: %v", err)
 		}
@@ -52,6 +52,10 @@ func (lp *LifecycleProcessor) initFromAPIGatewayWebsocketEvent(event events.APIG
 }
 
 func (lp *LifecycleProcessor) initFromALBEvent(event events.ALBTargetGroupRequest) {
+	if !lp.DetectLambdaLibrary() && lp.InferredSpansEnabled {
+		lp.GetInferredSpan().EnrichInferredSpanWithALBEvent(event)
+	}
+
 	lp.addTag("function_trigger.event_source", "application-load-balancer")
 	lp.addTag("function_trigger.event_source_arn", trigger.ExtractAlbEventARN(event))
 	lp.addTags(trigger.GetTagsFromALBTargetGroupRequest(event))
@@ -149,7 +153,19 @@ func (lp *LifecycleProcessor) initFromSQSEvent(event events.SQSEvent) {
 }
 
 func (lp *LifecycleProcessor) initFromLambdaFunctionURLEvent(event events.LambdaFunctionURLRequest, region string, accountID string, functionName string) {
+	if !lp.DetectLambdaLibrary() && lp.InferredSpansEnabled {
+		lp.GetInferredSpan().EnrichInferredSpanWithLambdaFunctionURLEvent(event)
+	}
+
 	lp.addTag("function_trigger.event_source", "lambda-function-url")
 	lp.addTag("function_trigger.event_source_arn", fmt.Sprintf("arn:aws:lambda:%v:%v:url:%v", region, accountID, functionName))
 	lp.addTags(trigger.GetTagsFromLambdaFunctionURLRequest(event))
 }
+
+func (lp *LifecycleProcessor) initFromStepFunctionEvent(event inferredspan.StepFunctionEvent) {
+	if !lp.DetectLambdaLibrary() && lp.InferredSpansEnabled {
+		lp.GetInferredSpan().EnrichInferredSpanWithStepFunctionEvent(event)
+	}
+	lp.addTag("function_trigger.event_source", "states")
+	lp.addTag("function_trigger.event_source_arn", event.StateMachine.ID)
+}
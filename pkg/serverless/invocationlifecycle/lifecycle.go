@@ -139,6 +139,11 @@ func (lp *LifecycleProcessor) OnInvokeStart(startDetails *InvocationStartDetails
 		if err := json.Unmarshal(payloadBytes, &event); err == nil && arnParseErr == nil {
 			lp.initFromLambdaFunctionURLEvent(event, region, account, resource)
 		}
+	case trigger.StepFunctionEvent:
+		var event inferredspan.StepFunctionEvent
+		if err := json.Unmarshal(payloadBytes, &event); err == nil {
+			lp.initFromStepFunctionEvent(event)
+		}
 	default:
 		log.Debug("Skipping adding trigger types and inferred spans as a non-supported payload was received.")
 	}
@@ -866,6 +866,60 @@ func TestTriggerTypesLifecycleEventForEventBridge(t *testing.T) {
 	}, testProcessor.GetTags())
 }
 
+func TestTriggerTypesLifecycleEventForStepFunction(t *testing.T) {
+	startDetails := &InvocationStartDetails{
+		InvokeEventRawPayload: getEventFromFile("step-function.json"),
+		InvokedFunctionARN:    "arn:aws:lambda:us-east-1:123456789012:function:my-function",
+	}
+
+	testProcessor := &LifecycleProcessor{
+		DetectLambdaLibrary: func() bool { return false },
+		ProcessTrace:        func(*api.Payload) {},
+	}
+
+	testProcessor.OnInvokeStart(startDetails)
+	testProcessor.OnInvokeEnd(&InvocationEndDetails{
+		RequestID: "test-request-id",
+	})
+	assert.Equal(t, map[string]string{
+		"function_trigger.event_source_arn": "arn:aws:states:sa-east-1:425362996713:stateMachine:agocsTestSF",
+		"request_id":                        "test-request-id",
+		"function_trigger.event_source":     "states",
+	}, testProcessor.GetTags())
+}
+
+func TestInferredSpanLifecycleEventForALB(t *testing.T) {
+	startInvocationTime := time.Now()
+	startDetails := &InvocationStartDetails{
+		StartTime:             startInvocationTime,
+		InvokeEventRawPayload: getEventFromFile("application-load-balancer.json"),
+		InvokedFunctionARN:    "arn:aws:lambda:us-east-1:123456789012:function:my-function",
+	}
+
+	var tracePayloads []*api.Payload
+	testProcessor := &LifecycleProcessor{
+		DetectLambdaLibrary:  func() bool { return false },
+		ProcessTrace:         func(payload *api.Payload) { tracePayloads = append(tracePayloads, payload) },
+		InferredSpansEnabled: true,
+	}
+
+	testProcessor.OnInvokeStart(startDetails)
+	testProcessor.OnInvokeEnd(&InvocationEndDetails{
+		EndTime:            startInvocationTime.Add(time.Second),
+		RequestID:          "test-request-id",
+		ResponseRawPayload: `{"statusCode": 200}`,
+	})
+
+	assert.Len(t, tracePayloads, 2)
+	inferredSpan := tracePayloads[1].TracerPayload.Chunks[0].Spans[0]
+	assert.Equal(t, "aws.alb", inferredSpan.Name)
+	assert.Equal(t, "lambda-alb-123578498.us-east-2.elb.amazonaws.com", inferredSpan.Service)
+	assert.Equal(t, startInvocationTime.UnixNano(), inferredSpan.Start)
+	assert.Equal(t, time.Second.Nanoseconds(), inferredSpan.Duration)
+	assert.Equal(t, uint64(12345), inferredSpan.TraceID)
+	assert.Equal(t, uint64(67890), inferredSpan.ParentID)
+}
+
 // Helper function for reading test file
 func getEventFromFile(filename string) string {
 	event, err := os.ReadFile("../trace/testdata/event_samples/" + filename)
@@ -0,0 +1,244 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2022-present Datadog, Inc.
+
+package invocationlifecycle
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/DataDog/datadog-agent/pkg/config"
+	"github.com/DataDog/datadog-agent/pkg/trace/sampler"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// Trace propagation styles, see `serverless.trace_propagation_style_extract`
+const (
+	propagationStyleDatadog      = "datadog"
+	propagationStyleTraceContext = "tracecontext"
+	propagationStyleB3           = "b3"
+	propagationStyleB3Multi      = "b3multi"
+)
+
+// W3C Trace Context headers, see https://www.w3.org/TR/trace-context/
+const (
+	traceparentHeader = "traceparent"
+	tracestateHeader  = "tracestate"
+)
+
+// B3 headers, see https://github.com/openzipkin/b3-propagation
+const (
+	b3Header        = "b3"
+	b3TraceIDHeader = "x-b3-traceid"
+	b3SpanIDHeader  = "x-b3-spanid"
+	b3SampledHeader = "x-b3-sampled"
+	b3FlagsHeader   = "x-b3-flags"
+)
+
+// traceContext is the context of the upstream trace of an invocation
+type traceContext struct {
+	TraceID          uint64
+	ParentID         uint64
+	SamplingPriority sampler.SamplingPriority
+}
+
+// propagationStyles returns the trace propagation styles to extract, in order of precedence
+func propagationStyles() []string {
+	var styles []string
+	for _, value := range config.Datadog.GetStringSlice("serverless.trace_propagation_style_extract") {
+		// the environment variable holds a comma-separated list
+		for _, style := range strings.Split(value, ",") {
+			style = strings.ToLower(strings.TrimSpace(style))
+			switch style {
+			case "":
+			case propagationStyleDatadog, propagationStyleTraceContext, propagationStyleB3, propagationStyleB3Multi:
+				styles = append(styles, style)
+			default:
+				log.Debugf("Ignoring unknown trace propagation style %q", style)
+			}
+		}
+	}
+	return styles
+}
+
+// extractTraceContext extracts the trace context from a carrier whose keys are lowercased,
+// trying each style in order. It returns nil when no style matches.
+func extractTraceContext(carrier map[string]string, styles []string) *traceContext {
+	if len(carrier) == 0 {
+		return nil
+	}
+	for _, style := range styles {
+		var tc *traceContext
+		switch style {
+		case propagationStyleDatadog:
+			tc = extractDatadogContext(carrier)
+		case propagationStyleTraceContext:
+			tc = extractW3CContext(carrier)
+		case propagationStyleB3:
+			tc = extractB3SingleContext(carrier)
+		case propagationStyleB3Multi:
+			tc = extractB3MultiContext(carrier)
+		}
+		if tc != nil {
+			log.Debugf("Extracted trace context with the %s propagation style", style)
+			return tc
+		}
+	}
+	return nil
+}
+
+func extractDatadogContext(carrier map[string]string) *traceContext {
+	traceID, err := strconv.ParseUint(carrier[TraceIDHeader], 0, 64)
+	if err != nil {
+		return nil
+	}
+	tc := &traceContext{TraceID: traceID, SamplingPriority: sampler.PriorityNone}
+	if parentID, err := strconv.ParseUint(carrier[ParentIDHeader], 0, 64); err == nil {
+		tc.ParentID = parentID
+	} else {
+		log.Debug("Unable to parse parentID from the Datadog trace context")
+	}
+	if priority, err := strconv.ParseInt(carrier[SamplingPriorityHeader], 10, 8); err == nil {
+		tc.SamplingPriority = sampler.SamplingPriority(priority)
+	}
+	return tc
+}
+
+// extractW3CContext parses a traceparent such as "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01".
+// The Datadog sampling priority is read from the "dd" member of the tracestate when it agrees with the sampled flag.
+func extractW3CContext(carrier map[string]string) *traceContext {
+	parts := strings.Split(strings.TrimSpace(carrier[traceparentHeader]), "-")
+	if len(parts) < 4 || len(parts[0]) != 2 || parts[0] == "ff" || (parts[0] == "00" && len(parts) != 4) {
+		return nil
+	}
+	if len(parts[1]) != 32 || len(parts[2]) != 16 || len(parts[3]) != 2 {
+		return nil
+	}
+	traceID, ok := parseHexID(parts[1])
+	if !ok {
+		return nil
+	}
+	parentID, ok := parseHexID(parts[2])
+	if !ok {
+		return nil
+	}
+	flags, err := strconv.ParseUint(parts[3], 16, 8)
+	if err != nil {
+		return nil
+	}
+
+	tc := &traceContext{TraceID: traceID, ParentID: parentID, SamplingPriority: sampler.PriorityAutoDrop}
+	sampled := flags&0x1 == 1
+	if sampled {
+		tc.SamplingPriority = sampler.PriorityAutoKeep
+	}
+	if priority, ok := tracestatePriority(carrier[tracestateHeader]); ok && (priority > 0) == sampled {
+		tc.SamplingPriority = priority
+	}
+	return tc
+}
+
+// tracestatePriority reads the sampling priority from the "s" field of the "dd" member of a tracestate
+// such as "dd=s:2;o:rum,congo=t61rcWkgMzE"
+func tracestatePriority(tracestate string) (sampler.SamplingPriority, bool) {
+	for _, member := range strings.Split(tracestate, ",") {
+		member = strings.TrimSpace(member)
+		if !strings.HasPrefix(member, "dd=") {
+			continue
+		}
+		for _, field := range strings.Split(member[len("dd="):], ";") {
+			if !strings.HasPrefix(field, "s:") {
+				continue
+			}
+			if priority, err := strconv.ParseInt(field[len("s:"):], 10, 8); err == nil {
+				return sampler.SamplingPriority(priority), true
+			}
+		}
+	}
+	return sampler.PriorityNone, false
+}
+
+// extractB3SingleContext parses a b3 header such as "80f198ee56343ba864fe8b2a57d3eff7-e457b5a2e4d86bd1-1-05e3ac9a4f6e3b90".
+// A b3 header holding only the sampling state has no trace context.
+func extractB3SingleContext(carrier map[string]string) *traceContext {
+	parts := strings.Split(strings.TrimSpace(carrier[b3Header]), "-")
+	if len(parts) < 2 {
+		return nil
+	}
+	traceID, ok := parseB3TraceID(parts[0])
+	if !ok {
+		return nil
+	}
+	spanID, ok := parseHexID(parts[1])
+	if !ok || len(parts[1]) != 16 {
+		return nil
+	}
+
+	tc := &traceContext{TraceID: traceID, ParentID: spanID, SamplingPriority: sampler.PriorityNone}
+	if len(parts) > 2 {
+		switch parts[2] {
+		case "0":
+			tc.SamplingPriority = sampler.PriorityAutoDrop
+		case "1":
+			tc.SamplingPriority = sampler.PriorityAutoKeep
+		case "d":
+			tc.SamplingPriority = sampler.PriorityUserKeep
+		}
+	}
+	return tc
+}
+
+func extractB3MultiContext(carrier map[string]string) *traceContext {
+	traceID, ok := parseB3TraceID(carrier[b3TraceIDHeader])
+	if !ok {
+		return nil
+	}
+	spanID, ok := parseHexID(carrier[b3SpanIDHeader])
+	if !ok {
+		return nil
+	}
+
+	tc := &traceContext{TraceID: traceID, ParentID: spanID, SamplingPriority: sampler.PriorityNone}
+	switch strings.ToLower(carrier[b3SampledHeader]) {
+	case "0", "false":
+		tc.SamplingPriority = sampler.PriorityAutoDrop
+	case "1", "true":
+		tc.SamplingPriority = sampler.PriorityAutoKeep
+	}
+	// the debug flag implies an accept decision
+	if carrier[b3FlagsHeader] == "1" {
+		tc.SamplingPriority = sampler.PriorityUserKeep
+	}
+	return tc
+}
+
+// parseB3TraceID parses a 64 or 128 bit B3 trace ID
+func parseB3TraceID(s string) (uint64, bool) {
+	if len(s) != 16 && len(s) != 32 {
+		return 0, false
+	}
+	return parseHexID(s)
+}
+
+// parseHexID parses a non-zero hexadecimal ID. Datadog IDs are 64 bits long, the
+// lower 64 bits of 128 bit IDs are kept.
+func parseHexID(s string) (uint64, bool) {
+	if s == "" || len(s) > 32 {
+		return 0, false
+	}
+	for _, c := range s {
+		if !strings.ContainsRune("0123456789abcdefABCDEF", c) {
+			return 0, false
+		}
+	}
+	if len(s) > 16 {
+		s = s[len(s)-16:]
+	}
+	id, err := strconv.ParseUint(s, 16, 64)
+	if err != nil || id == 0 {
+		return 0, false
+	}
+	return id, true
+}
@@ -0,0 +1,171 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2022-present Datadog, Inc.
+
+package invocationlifecycle
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/DataDog/datadog-agent/pkg/config"
+	"github.com/DataDog/datadog-agent/pkg/trace/sampler"
+)
+
+var allPropagationStyles = []string{"datadog", "tracecontext", "b3multi", "b3"}
+
+func TestPropagationStyles(t *testing.T) {
+	mockConfig := config.Mock(t)
+	assert.Equal(t, allPropagationStyles, propagationStyles())
+
+	mockConfig.Set("serverless.trace_propagation_style_extract", []string{"TraceContext,b3", "unknown", "datadog"})
+	assert.Equal(t, []string{"tracecontext", "b3", "datadog"}, propagationStyles())
+}
+
+func TestPropagationStylesFromEnv(t *testing.T) {
+	t.Setenv("DD_TRACE_PROPAGATION_STYLE_EXTRACT", "b3multi,tracecontext")
+	config.Mock(t)
+	assert.Equal(t, []string{"b3multi", "tracecontext"}, propagationStyles())
+}
+
+func TestExtractTraceContext(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		carrier  map[string]string
+		styles   []string
+		expected *traceContext
+	}{
+		{
+			name:     "no carrier",
+			styles:   allPropagationStyles,
+			expected: nil,
+		},
+		{
+			name: "datadog",
+			carrier: map[string]string{
+				"x-datadog-trace-id":          "5736943178450432258",
+				"x-datadog-parent-id":         "1480558859903409531",
+				"x-datadog-sampling-priority": "2",
+			},
+			styles:   allPropagationStyles,
+			expected: &traceContext{TraceID: 5736943178450432258, ParentID: 1480558859903409531, SamplingPriority: sampler.PriorityUserKeep},
+		},
+		{
+			name: "datadog without sampling priority",
+			carrier: map[string]string{
+				"x-datadog-trace-id":  "5736943178450432258",
+				"x-datadog-parent-id": "1480558859903409531",
+			},
+			styles:   allPropagationStyles,
+			expected: &traceContext{TraceID: 5736943178450432258, ParentID: 1480558859903409531, SamplingPriority: sampler.PriorityNone},
+		},
+		{
+			name: "tracecontext",
+			carrier: map[string]string{
+				"traceparent": "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+			},
+			styles:   allPropagationStyles,
+			expected: &traceContext{TraceID: 0xa3ce929d0e0e4736, ParentID: 0xf067aa0ba902b7, SamplingPriority: sampler.PriorityAutoKeep},
+		},
+		{
+			name: "tracecontext with a datadog tracestate",
+			carrier: map[string]string{
+				"traceparent": "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+				"tracestate":  "congo=t61rcWkgMzE,dd=s:2;o:rum",
+			},
+			styles:   allPropagationStyles,
+			expected: &traceContext{TraceID: 0xa3ce929d0e0e4736, ParentID: 0xf067aa0ba902b7, SamplingPriority: sampler.PriorityUserKeep},
+		},
+		{
+			name: "tracecontext with a tracestate contradicting the sampled flag",
+			carrier: map[string]string{
+				"traceparent": "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00",
+				"tracestate":  "dd=s:2",
+			},
+			styles:   allPropagationStyles,
+			expected: &traceContext{TraceID: 0xa3ce929d0e0e4736, ParentID: 0xf067aa0ba902b7, SamplingPriority: sampler.PriorityAutoDrop},
+		},
+		{
+			name: "tracecontext of a future version",
+			carrier: map[string]string{
+				"traceparent": "01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra",
+			},
+			styles:   allPropagationStyles,
+			expected: &traceContext{TraceID: 0xa3ce929d0e0e4736, ParentID: 0xf067aa0ba902b7, SamplingPriority: sampler.PriorityAutoKeep},
+		},
+		{
+			name:     "invalid tracecontext version",
+			carrier:  map[string]string{"traceparent": "ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"},
+			styles:   allPropagationStyles,
+			expected: nil,
+		},
+		{
+			name:     "invalid tracecontext trace ID",
+			carrier:  map[string]string{"traceparent": "00-00000000000000000000000000000000-00f067aa0ba902b7-01"},
+			styles:   allPropagationStyles,
+			expected: nil,
+		},
+		{
+			name:     "b3 single header",
+			carrier:  map[string]string{"b3": "80f198ee56343ba864fe8b2a57d3eff7-e457b5a2e4d86bd1-d-05e3ac9a4f6e3b90"},
+			styles:   allPropagationStyles,
+			expected: &traceContext{TraceID: 0x64fe8b2a57d3eff7, ParentID: 0xe457b5a2e4d86bd1, SamplingPriority: sampler.PriorityUserKeep},
+		},
+		{
+			name:     "b3 single header without sampling state",
+			carrier:  map[string]string{"b3": "64fe8b2a57d3eff7-e457b5a2e4d86bd1"},
+			styles:   allPropagationStyles,
+			expected: &traceContext{TraceID: 0x64fe8b2a57d3eff7, ParentID: 0xe457b5a2e4d86bd1, SamplingPriority: sampler.PriorityNone},
+		},
+		{
+			name:     "b3 single header with only a sampling state",
+			carrier:  map[string]string{"b3": "0"},
+			styles:   allPropagationStyles,
+			expected: nil,
+		},
+		{
+			name: "b3 multi headers",
+			carrier: map[string]string{
+				"x-b3-traceid": "80f198ee56343ba864fe8b2a57d3eff7",
+				"x-b3-spanid":  "e457b5a2e4d86bd1",
+				"x-b3-sampled": "0",
+			},
+			styles:   allPropagationStyles,
+			expected: &traceContext{TraceID: 0x64fe8b2a57d3eff7, ParentID: 0xe457b5a2e4d86bd1, SamplingPriority: sampler.PriorityAutoDrop},
+		},
+		{
+			name: "b3 multi headers with debug flag",
+			carrier: map[string]string{
+				"x-b3-traceid": "64fe8b2a57d3eff7",
+				"x-b3-spanid":  "e457b5a2e4d86bd1",
+				"x-b3-flags":   "1",
+			},
+			styles:   allPropagationStyles,
+			expected: &traceContext{TraceID: 0x64fe8b2a57d3eff7, ParentID: 0xe457b5a2e4d86bd1, SamplingPriority: sampler.PriorityUserKeep},
+		},
+		{
+			name: "precedence of the first style",
+			carrier: map[string]string{
+				"x-datadog-trace-id":  "5736943178450432258",
+				"x-datadog-parent-id": "1480558859903409531",
+				"traceparent":         "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+			},
+			styles:   []string{"tracecontext", "datadog"},
+			expected: &traceContext{TraceID: 0xa3ce929d0e0e4736, ParentID: 0xf067aa0ba902b7, SamplingPriority: sampler.PriorityAutoKeep},
+		},
+		{
+			name: "style not enabled",
+			carrier: map[string]string{
+				"traceparent": "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+			},
+			styles:   []string{"datadog", "b3"},
+			expected: nil,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, extractTraceContext(tc.carrier, tc.styles))
+		})
+	}
+}
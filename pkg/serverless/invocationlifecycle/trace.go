@@ -37,7 +37,9 @@ type ExecutionStartInfo struct {
 }
 
 type invocationPayload struct {
-	Headers map[string]string `json:"headers"`
+	Headers map[string]string  `json:"headers"`
+	Records []invocationRecord `json:"Records"`
+	Detail  *datadogCarrier    `json:"detail"`
 }
 
 // startExecutionSpan records information from the start of the invocation.
@@ -52,28 +54,17 @@ func startExecutionSpan(executionContext *ExecutionStartInfo, inferredSpan *infe
 		executionContext.parentID = inferredSpan.Span.SpanID
 	}
 
-	if payload.Headers != nil {
-
-		traceID, err := strconv.ParseUint(payload.Headers[TraceIDHeader], 0, 64)
-		if err != nil {
-			log.Debug("Unable to parse traceID from payload headers")
-		} else {
-			executionContext.TraceID = traceID
-			if inferredSpansEnabled {
-				inferredSpan.Span.TraceID = traceID
-			}
-		}
-
-		parentID, err := strconv.ParseUint(payload.Headers[ParentIDHeader], 0, 64)
-		if err != nil {
-			log.Debug("Unable to parse parentID from payload headers")
+	samplingPriority := sampler.PriorityNone
+	carrier := payload.traceCarrier()
+	if tc := extractTraceContext(carrier, propagationStyles()); tc != nil {
+		executionContext.TraceID = tc.TraceID
+		if inferredSpansEnabled {
+			inferredSpan.Span.TraceID = tc.TraceID
+			inferredSpan.Span.ParentID = tc.ParentID
 		} else {
-			if inferredSpansEnabled {
-				inferredSpan.Span.ParentID = parentID
-			} else {
-				executionContext.parentID = parentID
-			}
+			executionContext.parentID = tc.ParentID
 		}
+		samplingPriority = tc.SamplingPriority
 	} else if startDetails.InvokeEventHeaders.TraceID != "" { // trace context from a direct invocation
 		traceID, err := strconv.ParseUint(startDetails.InvokeEventHeaders.TraceID, 0, 64)
 		if err != nil {
@@ -89,7 +80,10 @@ func startExecutionSpan(executionContext *ExecutionStartInfo, inferredSpan *infe
 			executionContext.parentID = parentID
 		}
 	}
-	executionContext.SamplingPriority = getSamplingPriority(payload.Headers[SamplingPriorityHeader], startDetails.InvokeEventHeaders.SamplingPriority)
+	if samplingPriority == sampler.PriorityNone {
+		samplingPriority = getSamplingPriority(carrier[SamplingPriorityHeader], startDetails.InvokeEventHeaders.SamplingPriority)
+	}
+	executionContext.SamplingPriority = samplingPriority
 }
 
 // endExecutionSpan builds the function execution span and sends it to the intake.
@@ -0,0 +1,155 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2022-present Datadog, Inc.
+
+package invocationlifecycle
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// datadogCarrierKey is the message attribute or field in which the tracers inject
+// the trace context headers as a JSON object
+const datadogCarrierKey = "_datadog"
+
+// invocationRecord holds the parts of the first record of SQS, SNS and Kinesis events carrying a trace context
+type invocationRecord struct {
+	// SQS
+	MessageAttributes map[string]sqsMessageAttribute `json:"messageAttributes"`
+	Body              string                         `json:"body"`
+	// SNS
+	SNS *snsEntity `json:"Sns"`
+	// Kinesis
+	Kinesis *kinesisRecord `json:"kinesis"`
+}
+
+type sqsMessageAttribute struct {
+	StringValue *string `json:"stringValue"`
+	BinaryValue []byte  `json:"binaryValue"`
+}
+
+type snsEntity struct {
+	Type              string                         `json:"Type"`
+	MessageAttributes map[string]snsMessageAttribute `json:"MessageAttributes"`
+}
+
+type snsMessageAttribute struct {
+	Type  string `json:"Type"`
+	Value string `json:"Value"`
+}
+
+type kinesisRecord struct {
+	// Data is base64 encoded
+	Data string `json:"data"`
+}
+
+// datadogCarrier is an EventBridge event detail or Kinesis record data in which the tracers injected a trace context
+type datadogCarrier struct {
+	Datadog map[string]string `json:"_datadog"`
+}
+
+// traceCarrier returns the carrier of the trace context of the invocation, with lowercased keys:
+// the headers of API Gateway, ALB and Lambda function URL events, the message attributes of SQS
+// and SNS events, the detail of EventBridge events or the data of Kinesis events.
+func (p invocationPayload) traceCarrier() map[string]string {
+	switch {
+	case len(p.Headers) > 0:
+		return lowercaseKeys(p.Headers)
+	case p.Detail != nil && len(p.Detail.Datadog) > 0:
+		return lowercaseKeys(p.Detail.Datadog)
+	case len(p.Records) == 0:
+		return nil
+	}
+
+	record := p.Records[0]
+	switch {
+	case record.SNS != nil:
+		return record.SNS.traceCarrier()
+	case record.Kinesis != nil:
+		return record.Kinesis.traceCarrier()
+	default:
+		return record.sqsTraceCarrier()
+	}
+}
+
+func (r invocationRecord) sqsTraceCarrier() map[string]string {
+	carrier := make(map[string]string)
+	for name, attribute := range r.MessageAttributes {
+		switch {
+		case name == datadogCarrierKey && attribute.StringValue != nil:
+			addDatadogCarrier(carrier, []byte(*attribute.StringValue))
+		case name == datadogCarrierKey:
+			addDatadogCarrier(carrier, attribute.BinaryValue)
+		case attribute.StringValue != nil:
+			carrier[strings.ToLower(name)] = *attribute.StringValue
+		}
+	}
+
+	// the message attributes of an SNS notification delivered to SQS are in the body
+	if len(carrier) == 0 && r.Body != "" {
+		var entity snsEntity
+		if err := json.Unmarshal([]byte(r.Body), &entity); err == nil && strings.ToLower(entity.Type) == "notification" {
+			return entity.traceCarrier()
+		}
+	}
+	return carrier
+}
+
+func (e *snsEntity) traceCarrier() map[string]string {
+	carrier := make(map[string]string)
+	for name, attribute := range e.MessageAttributes {
+		switch {
+		case name == datadogCarrierKey && attribute.Type == "Binary":
+			value, err := base64.StdEncoding.DecodeString(attribute.Value)
+			if err != nil {
+				log.Debugf("Unable to decode the %s SNS message attribute: %v", datadogCarrierKey, err)
+				continue
+			}
+			addDatadogCarrier(carrier, value)
+		case name == datadogCarrierKey:
+			addDatadogCarrier(carrier, []byte(attribute.Value))
+		case attribute.Type == "String":
+			carrier[strings.ToLower(name)] = attribute.Value
+		}
+	}
+	return carrier
+}
+
+func (r *kinesisRecord) traceCarrier() map[string]string {
+	data, err := base64.StdEncoding.DecodeString(r.Data)
+	if err != nil {
+		log.Debugf("Unable to decode the Kinesis record data: %v", err)
+		return nil
+	}
+	var dc datadogCarrier
+	if err := json.Unmarshal(data, &dc); err != nil {
+		// the data of the record is not necessarily JSON
+		return nil
+	}
+	return lowercaseKeys(dc.Datadog)
+}
+
+// addDatadogCarrier adds the trace context headers injected as a JSON object by the tracers to carrier
+func addDatadogCarrier(carrier map[string]string, value []byte) {
+	var headers map[string]string
+	if err := json.Unmarshal(value, &headers); err != nil {
+		log.Debugf("Unable to parse the %s trace context: %v", datadogCarrierKey, err)
+		return
+	}
+	for name, value := range headers {
+		carrier[strings.ToLower(name)] = value
+	}
+}
+
+func lowercaseKeys(m map[string]string) map[string]string {
+	lowercased := make(map[string]string, len(m))
+	for k, v := range m {
+		lowercased[strings.ToLower(k)] = v
+	}
+	return lowercased
+}
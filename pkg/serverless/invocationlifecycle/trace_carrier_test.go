@@ -0,0 +1,98 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2022-present Datadog, Inc.
+
+package invocationlifecycle
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testTraceparent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	testDatadogJSON = `{"x-datadog-trace-id":"5736943178450432258","x-datadog-parent-id":"1480558859903409531"}`
+)
+
+var testDatadogCarrier = map[string]string{
+	"x-datadog-trace-id":  "5736943178450432258",
+	"x-datadog-parent-id": "1480558859903409531",
+}
+
+func TestTraceCarrier(t *testing.T) {
+	encodedDatadog := base64.StdEncoding.EncodeToString([]byte(testDatadogJSON))
+
+	for _, tc := range []struct {
+		name     string
+		payload  string
+		expected map[string]string
+	}{
+		{
+			name:     "no carrier",
+			payload:  `{"resource":"/users/create","path":"/users/create","httpMethod":"GET"}`,
+			expected: nil,
+		},
+		{
+			name:     "API Gateway headers",
+			payload:  `{"httpMethod":"GET","headers":{"Accept":"*/*","Traceparent":"` + testTraceparent + `"}}`,
+			expected: map[string]string{"accept": "*/*", "traceparent": testTraceparent},
+		},
+		{
+			name:     "SQS message attributes",
+			payload:  `{"Records":[{"eventSource":"aws:sqs","body":"hello","messageAttributes":{"traceparent":{"stringValue":"` + testTraceparent + `","dataType":"String"},"count":{"binaryValue":"AQ==","dataType":"Binary"}}}]}`,
+			expected: map[string]string{"traceparent": testTraceparent},
+		},
+		{
+			name:     "SQS datadog message attribute",
+			payload:  `{"Records":[{"eventSource":"aws:sqs","body":"hello","messageAttributes":{"_datadog":{"stringValue":"{\"x-datadog-trace-id\":\"5736943178450432258\",\"x-datadog-parent-id\":\"1480558859903409531\"}","dataType":"String"}}}]}`,
+			expected: testDatadogCarrier,
+		},
+		{
+			name:     "SQS binary datadog message attribute",
+			payload:  `{"Records":[{"eventSource":"aws:sqs","body":"hello","messageAttributes":{"_datadog":{"binaryValue":"` + encodedDatadog + `","dataType":"Binary"}}}]}`,
+			expected: testDatadogCarrier,
+		},
+		{
+			name:     "SNS notification delivered to SQS",
+			payload:  `{"Records":[{"eventSource":"aws:sqs","body":"{\"Type\":\"Notification\",\"TopicArn\":\"arn:aws:sns:us-east-1:123456789012:topic\",\"MessageAttributes\":{\"traceparent\":{\"Type\":\"String\",\"Value\":\"` + testTraceparent + `\"}}}","messageAttributes":{}}]}`,
+			expected: map[string]string{"traceparent": testTraceparent},
+		},
+		{
+			name:     "SNS message attributes",
+			payload:  `{"Records":[{"EventSource":"aws:sns","Sns":{"Type":"Notification","MessageAttributes":{"b3":{"Type":"String","Value":"64fe8b2a57d3eff7-e457b5a2e4d86bd1-1"},"size":{"Type":"Number","Value":"3"}}}}]}`,
+			expected: map[string]string{"b3": "64fe8b2a57d3eff7-e457b5a2e4d86bd1-1"},
+		},
+		{
+			name:     "SNS binary datadog message attribute",
+			payload:  `{"Records":[{"EventSource":"aws:sns","Sns":{"Type":"Notification","MessageAttributes":{"_datadog":{"Type":"Binary","Value":"` + encodedDatadog + `"}}}}]}`,
+			expected: testDatadogCarrier,
+		},
+		{
+			name:     "EventBridge detail",
+			payload:  `{"detail-type":"OrderCreated","source":"orders","detail":{"id":12,"_datadog":{"X-Datadog-Trace-Id":"5736943178450432258","x-datadog-parent-id":"1480558859903409531"}}}`,
+			expected: testDatadogCarrier,
+		},
+		{
+			name:     "Kinesis record data",
+			payload:  `{"Records":[{"eventSource":"aws:kinesis","kinesis":{"data":"` + base64.StdEncoding.EncodeToString([]byte(`{"order":12,"_datadog":`+testDatadogJSON+`}`)) + `"}}]}`,
+			expected: testDatadogCarrier,
+		},
+		{
+			name:     "Kinesis record data not in JSON",
+			payload:  `{"Records":[{"eventSource":"aws:kinesis","kinesis":{"data":"` + base64.StdEncoding.EncodeToString([]byte("plain text")) + `"}}]}`,
+			expected: nil,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			carrier := convertRawPayload(tc.payload).traceCarrier()
+			if tc.expected == nil {
+				assert.Empty(t, carrier)
+			} else {
+				assert.Equal(t, tc.expected, carrier)
+			}
+		})
+	}
+}
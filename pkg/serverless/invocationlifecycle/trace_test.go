@@ -200,6 +200,40 @@ func TestStartExecutionSpanWithHeadersAndInferredSpan(t *testing.T) {
 
 	assert.NotEqual(t, 0, currentExecutionInfo.SpanID)
 }
+
+func TestStartExecutionSpanWithW3CHeaders(t *testing.T) {
+	currentExecutionInfo := &ExecutionStartInfo{}
+	testString := `{"resource":"/users/create","path":"/users/create","httpMethod":"GET","headers":{"Accept":"*/*","traceparent":"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01","tracestate":"dd=s:2"}}`
+	startDetails := &InvocationStartDetails{
+		StartTime:          timeNow(),
+		InvokeEventHeaders: LambdaInvokeEventHeaders{},
+	}
+	startExecutionSpan(currentExecutionInfo, nil, testString, startDetails, false)
+	assert.Equal(t, uint64(0xa3ce929d0e0e4736), currentExecutionInfo.TraceID)
+	assert.Equal(t, uint64(0xf067aa0ba902b7), currentExecutionInfo.parentID)
+	assert.Equal(t, sampler.PriorityUserKeep, currentExecutionInfo.SamplingPriority)
+}
+
+func TestStartExecutionSpanWithSQSMessageAttributesAndInferredSpan(t *testing.T) {
+	currentExecutionInfo := &ExecutionStartInfo{}
+	testString := `{"Records":[{"eventSource":"aws:sqs","body":"hello","messageAttributes":{"b3":{"stringValue":"64fe8b2a57d3eff7-e457b5a2e4d86bd1-0","dataType":"String"}}}]}`
+	startTime := timeNow()
+	startDetails := &InvocationStartDetails{
+		StartTime:          startTime,
+		InvokeEventHeaders: LambdaInvokeEventHeaders{},
+	}
+	inferredSpan := &inferredspan.InferredSpan{}
+	inferredSpan.Span = &pb.Span{
+		SpanID: 1304592378509342580,
+		Start:  startTime.UnixNano(),
+	}
+	startExecutionSpan(currentExecutionInfo, inferredSpan, testString, startDetails, true)
+	assert.Equal(t, uint64(0x64fe8b2a57d3eff7), currentExecutionInfo.TraceID)
+	assert.Equal(t, uint64(1304592378509342580), currentExecutionInfo.parentID)
+	assert.Equal(t, sampler.PriorityAutoDrop, currentExecutionInfo.SamplingPriority)
+	assert.Equal(t, uint64(0x64fe8b2a57d3eff7), inferredSpan.Span.TraceID)
+	assert.Equal(t, uint64(0xe457b5a2e4d86bd1), inferredSpan.Span.ParentID)
+}
 func TestEndExecutionSpanWithNoError(t *testing.T) {
 	currentExecutionInfo := &ExecutionStartInfo{}
 	defer os.Unsetenv(functionNameEnvVar)
@@ -19,6 +19,8 @@ const (
 	eventSourceArn   = "event_source_arn"
 	eventType        = "event_type"
 	eventVersion     = "event_version"
+	executionARN     = "execution_arn"
+	executionName    = "execution_name"
 	httpURL          = "http.url"
 	httpMethod       = "http.method"
 	httpProtocol     = "http.protocol"
@@ -36,15 +38,21 @@ const (
 	receiptHandle    = "receipt_handle"
 	requestID        = "request_id"
 	resourceNames    = "resource_names"
+	retryCount       = "retry_count"
 	senderID         = "sender_id"
 	sentTimestamp    = "SentTimestamp"
 	shardID          = "shardid"
 	sizeBytes        = "size_bytes"
 	stage            = "stage"
+	stateMachineARN  = "state_machine_arn"
+	stateMachineName = "state_machine_name"
+	stateName        = "state_name"
 	streamName       = "streamname"
 	streamViewType   = "stream_view_type"
 	subject          = "subject"
 	tableName        = "tablename"
+	targetGroupARN   = "target_group_arn"
+	targetGroupName  = "target_group_name"
 	topicName        = "topicname"
 	topicARN         = "topic_arn"
 
@@ -54,6 +62,13 @@ const (
 	// invocationType is used to look for the invocation type
 	// in the payload headers
 	invocationType = "X-Amz-Invocation-Type"
+
+	// Below are the headers set by application load balancers,
+	// they are lowercased in the event payload
+	albHost          = "host"
+	albUserAgent     = "user-agent"
+	albForwardedFor  = "x-forwarded-for"
+	albTargetGroupID = "targetgroup"
 )
 
 // EventBridgeEvent is used for unmarshalling a EventBridge event.
@@ -63,3 +78,22 @@ type EventBridgeEvent struct {
 	Source     string `json:"source"`
 	StartTime  string `json:"time"`
 }
+
+// StepFunctionEvent is used for unmarshalling the context objects of a Step
+// Functions execution, passed to a task with "$$.Execution", "$$.State" and
+// "$$.StateMachine". AWS Go libraries do not provide this type of event for deserialization.
+type StepFunctionEvent struct {
+	Execution struct {
+		ID   string `json:"Id"`
+		Name string `json:"Name"`
+	} `json:"Execution"`
+	State struct {
+		Name        string `json:"Name"`
+		EnteredTime string `json:"EnteredTime"`
+		RetryCount  int    `json:"RetryCount"`
+	} `json:"State"`
+	StateMachine struct {
+		ID   string `json:"Id"`
+		Name string `json:"Name"`
+	} `json:"StateMachine"`
+}
@@ -109,6 +109,77 @@ func (inferredSpan *InferredSpan) EnrichInferredSpanWithAPIGatewayWebsocketEvent
 	inferredSpan.IsAsync = eventPayload.Headers[invocationType] == "Event"
 }
 
+// EnrichInferredSpanWithALBEvent uses the parsed event
+// payload to enrich the current inferred span. It applies a
+// specific set of data to the span expected from an ALB event.
+// Load balancers do not send the time of the request, the span
+// starts with the invocation.
+func (inferredSpan *InferredSpan) EnrichInferredSpanWithALBEvent(eventPayload events.ALBTargetGroupRequest) {
+	log.Debug("Enriching an inferred span for an Application Load Balancer")
+	targetGroupArn := eventPayload.RequestContext.ELB.TargetGroupArn
+	parsedTargetGroupName := parseTargetGroupName(targetGroupArn)
+	host := albHeader(eventPayload, albHost)
+	resource := fmt.Sprintf("%s %s", eventPayload.HTTPMethod, eventPayload.Path)
+	httpurl := fmt.Sprintf("%s%s", host, eventPayload.Path)
+
+	service := host
+	if service == "" {
+		service = parsedTargetGroupName
+	}
+
+	inferredSpan.Span.Name = "aws.alb"
+	inferredSpan.Span.Service = service
+	inferredSpan.Span.Resource = resource
+	inferredSpan.Span.Type = "http"
+	inferredSpan.Span.Start = inferredSpan.CurrentInvocationStartTime.UnixNano()
+	inferredSpan.Span.Meta = map[string]string{
+		endpoint:        eventPayload.Path,
+		httpURL:         httpurl,
+		httpMethod:      eventPayload.HTTPMethod,
+		httpUserAgent:   albHeader(eventPayload, albUserAgent),
+		operationName:   "aws.alb",
+		resourceNames:   resource,
+		targetGroupARN:  targetGroupArn,
+		targetGroupName: parsedTargetGroupName,
+	}
+
+	// the client is the first address of the forwarding chain
+	if forwardedFor := albHeader(eventPayload, albForwardedFor); forwardedFor != "" {
+		inferredSpan.Span.Meta[httpSourceIP] = strings.TrimSpace(strings.Split(forwardedFor, ",")[0])
+	}
+}
+
+// EnrichInferredSpanWithLambdaFunctionURLEvent uses the parsed event
+// payload to enrich the current inferred span. It applies a
+// specific set of data to the span expected from a Lambda Function URL event.
+func (inferredSpan *InferredSpan) EnrichInferredSpanWithLambdaFunctionURLEvent(eventPayload events.LambdaFunctionURLRequest) {
+	log.Debug("Enriching an inferred span for a Lambda Function URL")
+	requestContext := eventPayload.RequestContext
+	http := requestContext.HTTP
+	path := requestContext.HTTP.Path
+	resource := fmt.Sprintf("%s %s", http.Method, path)
+	httpurl := fmt.Sprintf("%s%s", requestContext.DomainName, path)
+	startTime := calculateStartTime(requestContext.TimeEpoch)
+
+	inferredSpan.Span.Name = "aws.lambda.url"
+	inferredSpan.Span.Service = requestContext.DomainName
+	inferredSpan.Span.Resource = resource
+	inferredSpan.Span.Type = "http"
+	inferredSpan.Span.Start = startTime
+	inferredSpan.Span.Meta = map[string]string{
+		apiID:         requestContext.APIID,
+		endpoint:      path,
+		httpURL:       httpurl,
+		httpMethod:    http.Method,
+		httpProtocol:  http.Protocol,
+		httpSourceIP:  http.SourceIP,
+		httpUserAgent: http.UserAgent,
+		operationName: "aws.lambda.url",
+		requestID:     requestContext.RequestID,
+		resourceNames: resource,
+	}
+}
+
 // EnrichInferredSpanWithSNSEvent uses the parsed event
 // payload to enrich the current inferred span. It applies a
 // specific set of data to the span expected from an SNS event.
@@ -261,6 +332,55 @@ func (inferredSpan *InferredSpan) EnrichInferredSpanWithDynamoDBEvent(eventPaylo
 	}
 }
 
+// EnrichInferredSpanWithStepFunctionEvent uses the parsed event
+// payload to enrich the current inferred span. It applies a
+// specific set of data to the span expected from a Step Functions event.
+// The execution waits for the task to complete, the span is not async.
+func (inferredSpan *InferredSpan) EnrichInferredSpanWithStepFunctionEvent(eventPayload StepFunctionEvent) {
+	log.Debug("Enriching an inferred span for a Step Functions task")
+	stateMachine := eventPayload.StateMachine
+	execution := eventPayload.Execution
+	state := eventPayload.State
+
+	inferredSpan.Span.Name = "aws.stepfunctions"
+	inferredSpan.Span.Service = "stepfunctions"
+	inferredSpan.Span.Start = formatISOStartTime(state.EnteredTime)
+	inferredSpan.Span.Resource = stateMachine.Name
+	inferredSpan.Span.Type = "web"
+	inferredSpan.Span.Meta = map[string]string{
+		operationName:    "aws.stepfunctions",
+		resourceNames:    stateMachine.Name,
+		stateMachineARN:  stateMachine.ID,
+		stateMachineName: stateMachine.Name,
+		executionARN:     execution.ID,
+		executionName:    execution.Name,
+		stateName:        state.Name,
+		retryCount:       strconv.Itoa(state.RetryCount),
+	}
+}
+
+// albHeader returns the value of a header of an ALB event, which
+// holds multi value headers when the target group enables them
+func albHeader(eventPayload events.ALBTargetGroupRequest, name string) string {
+	if value, ok := eventPayload.Headers[name]; ok {
+		return value
+	}
+	if values := eventPayload.MultiValueHeaders[name]; len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// parseTargetGroupName extracts the name of a target group from its ARN, such as
+// "arn:aws:elasticloadbalancing:us-east-2:123456789012:targetgroup/lambda-xyz/123abc"
+func parseTargetGroupName(targetGroupArn string) string {
+	splitArn := strings.Split(targetGroupArn, "/")
+	if len(splitArn) < 2 || !strings.HasSuffix(splitArn[0], albTargetGroupID) {
+		return ""
+	}
+	return splitArn[1]
+}
+
 // CalculateStartTime converts AWS event timeEpochs to nanoseconds
 func calculateStartTime(epoch int64) int64 {
 	return epoch * 1e6
@@ -178,6 +178,79 @@ func TestEnrichInferredSpanWithAPIGatewayWebsocketDisconnectEvent(t *testing.T)
 	assert.Equal(t, "dev", span.Meta[stage])
 }
 
+func TestEnrichInferredSpanWithALBEvent(t *testing.T) {
+	var albEvent events.ALBTargetGroupRequest
+	_ = json.Unmarshal(getEventFromFile("application-load-balancer.json"), &albEvent)
+	inferredSpan := mockInferredSpan()
+	inferredSpan.CurrentInvocationStartTime = time.Unix(1651863561, 0)
+	inferredSpan.EnrichInferredSpanWithALBEvent(albEvent)
+
+	span := inferredSpan.Span
+	assert.Equal(t, uint64(7353030974370088224), span.TraceID)
+	assert.Equal(t, uint64(8048964810003407541), span.SpanID)
+	assert.Equal(t, int64(1651863561000000000), span.Start)
+	assert.Equal(t, "lambda-alb-123578498.us-east-2.elb.amazonaws.com", span.Service)
+	assert.Equal(t, "aws.alb", span.Name)
+	assert.Equal(t, "GET /lambda", span.Resource)
+	assert.Equal(t, "http", span.Type)
+	assert.Equal(t, "/lambda", span.Meta[endpoint])
+	assert.Equal(t, "lambda-alb-123578498.us-east-2.elb.amazonaws.com/lambda", span.Meta[httpURL])
+	assert.Equal(t, "GET", span.Meta[httpMethod])
+	assert.Equal(t, "72.12.164.125", span.Meta[httpSourceIP])
+	assert.Equal(t, "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/71.0.3578.98 Safari/537.36", span.Meta[httpUserAgent])
+	assert.Equal(t, "aws.alb", span.Meta[operationName])
+	assert.Equal(t, "GET /lambda", span.Meta[resourceNames])
+	assert.Equal(t, "arn:aws:elasticloadbalancing:us-east-2:123456789012:targetgroup/lambda-xyz/123abc", span.Meta[targetGroupARN])
+	assert.Equal(t, "lambda-xyz", span.Meta[targetGroupName])
+	assert.False(t, inferredSpan.IsAsync)
+}
+
+func TestEnrichInferredSpanWithALBMultiValueHeadersEvent(t *testing.T) {
+	albEvent := events.ALBTargetGroupRequest{
+		HTTPMethod: "POST",
+		Path:       "/orders",
+		MultiValueHeaders: map[string][]string{
+			"x-forwarded-for": {"72.12.164.125, 10.0.0.1"},
+		},
+	}
+	albEvent.RequestContext.ELB.TargetGroupArn = "arn:aws:elasticloadbalancing:us-east-2:123456789012:targetgroup/orders/123abc"
+	inferredSpan := mockInferredSpan()
+	inferredSpan.EnrichInferredSpanWithALBEvent(albEvent)
+
+	span := inferredSpan.Span
+	assert.Equal(t, "orders", span.Service)
+	assert.Equal(t, "POST /orders", span.Resource)
+	assert.Equal(t, "72.12.164.125", span.Meta[httpSourceIP])
+	assert.Equal(t, "orders", span.Meta[targetGroupName])
+}
+
+func TestEnrichInferredSpanWithLambdaFunctionURLEvent(t *testing.T) {
+	var lambdaURLEvent events.LambdaFunctionURLRequest
+	_ = json.Unmarshal(getEventFromFile("lambda-function-url.json"), &lambdaURLEvent)
+	inferredSpan := mockInferredSpan()
+	inferredSpan.EnrichInferredSpanWithLambdaFunctionURLEvent(lambdaURLEvent)
+
+	span := inferredSpan.Span
+	assert.Equal(t, uint64(7353030974370088224), span.TraceID)
+	assert.Equal(t, uint64(8048964810003407541), span.SpanID)
+	assert.Equal(t, int64(1656527213249000000), span.Start)
+	assert.Equal(t, "test123.lambda-url.sa-east-1.on.aws", span.Service)
+	assert.Equal(t, "aws.lambda.url", span.Name)
+	assert.Equal(t, "GET /", span.Resource)
+	assert.Equal(t, "http", span.Type)
+	assert.Equal(t, "test123", span.Meta[apiID])
+	assert.Equal(t, "/", span.Meta[endpoint])
+	assert.Equal(t, "test123.lambda-url.sa-east-1.on.aws/", span.Meta[httpURL])
+	assert.Equal(t, "GET", span.Meta[httpMethod])
+	assert.Equal(t, "HTTP/1.1", span.Meta[httpProtocol])
+	assert.Equal(t, "2600:4040:2489:a900:ec48:b628:3b62:28d6", span.Meta[httpSourceIP])
+	assert.Equal(t, "curl/7.79.1", span.Meta[httpUserAgent])
+	assert.Equal(t, "aws.lambda.url", span.Meta[operationName])
+	assert.Equal(t, "d60989c6-2e4f-4672-8838-25d0a6bcb14d", span.Meta[requestID])
+	assert.Equal(t, "GET /", span.Meta[resourceNames])
+	assert.False(t, inferredSpan.IsAsync)
+}
+
 func TestEnrichInferredSpanWithSNSEvent(t *testing.T) {
 	var snsRequest events.SNSEvent
 	_ = json.Unmarshal(getEventFromFile("sns.json"), &snsRequest)
@@ -317,6 +390,37 @@ func TestEnrichInferredSpanWithDynamoDBEvent(t *testing.T) {
 	assert.True(t, inferredSpan.IsAsync)
 }
 
+func TestEnrichInferredSpanWithStepFunctionEvent(t *testing.T) {
+	var stepFunctionEvent StepFunctionEvent
+	_ = json.Unmarshal(getEventFromFile("step-function.json"), &stepFunctionEvent)
+	inferredSpan := mockInferredSpan()
+	inferredSpan.EnrichInferredSpanWithStepFunctionEvent(stepFunctionEvent)
+
+	span := inferredSpan.Span
+	assert.Equal(t, uint64(7353030974370088224), span.TraceID)
+	assert.Equal(t, uint64(8048964810003407541), span.SpanID)
+	assert.Equal(t, formatISOStartTime("2022-12-08T21:08:19.224Z"), span.Start)
+	assert.Equal(t, "stepfunctions", span.Service)
+	assert.Equal(t, "aws.stepfunctions", span.Name)
+	assert.Equal(t, "agocsTestSF", span.Resource)
+	assert.Equal(t, "web", span.Type)
+	assert.Equal(t, "aws.stepfunctions", span.Meta[operationName])
+	assert.Equal(t, "agocsTestSF", span.Meta[resourceNames])
+	assert.Equal(t, "arn:aws:states:sa-east-1:425362996713:stateMachine:agocsTestSF", span.Meta[stateMachineARN])
+	assert.Equal(t, "agocsTestSF", span.Meta[stateMachineName])
+	assert.Equal(t, "arn:aws:states:sa-east-1:425362996713:execution:agocsTestSF:aa6c9316-713a-41d4-9c30-61131716744f", span.Meta[executionARN])
+	assert.Equal(t, "aa6c9316-713a-41d4-9c30-61131716744f", span.Meta[executionName])
+	assert.Equal(t, "agocsTest1", span.Meta[stateName])
+	assert.Equal(t, "2", span.Meta[retryCount])
+	assert.False(t, inferredSpan.IsAsync)
+}
+
+func TestParseTargetGroupName(t *testing.T) {
+	assert.Equal(t, "lambda-xyz", parseTargetGroupName("arn:aws:elasticloadbalancing:us-east-2:123456789012:targetgroup/lambda-xyz/123abc"))
+	assert.Equal(t, "", parseTargetGroupName("arn:aws:elasticloadbalancing:us-east-2:123456789012:loadbalancer/app/my-alb/50dc6c495c0c9188"))
+	assert.Equal(t, "", parseTargetGroupName(""))
+}
+
 func TestFormatISOStartTime(t *testing.T) {
 	isotime := "2022-01-31T14:13:41.637Z"
 	startTime := formatISOStartTime(isotime)
@@ -65,6 +65,10 @@ const (
 	// LambdaFunctionURLEvent describes an event from an HTTP lambda function URL invocation
 	LambdaFunctionURLEvent
 
+	// StepFunctionEvent describes an event from a Step Functions task invocation
+	// passing the execution, state and state machine context objects
+	StepFunctionEvent
+
 	// Unknown describes an unknown event type
 	Unknown
 )
@@ -140,6 +144,10 @@ func GetEventType(payload map[string]interface{}) AWSEventType {
 		return LambdaFunctionURLEvent
 	}
 
+	if isStepFunctionEvent(payload) {
+		return StepFunctionEvent
+	}
+
 	return Unknown
 }
 
@@ -247,6 +255,12 @@ func isLambdaFunctionURLEvent(event map[string]interface{}) bool {
 	return strings.Contains(lambdaURL, "lambda-url")
 }
 
+func isStepFunctionEvent(event map[string]interface{}) bool {
+	return json.GetNestedValue(event, "execution", "id") != nil &&
+		json.GetNestedValue(event, "state", "name") != nil &&
+		json.GetNestedValue(event, "statemachine", "id") != nil
+}
+
 func eventRecordsKeyExists(event map[string]interface{}, key string) bool {
 	records, ok := json.GetNestedValue(event, "records").([]interface{})
 	if !ok {
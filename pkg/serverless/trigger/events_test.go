@@ -33,6 +33,7 @@ func TestEventPayloadParsing(t *testing.T) {
 		"sns.json":                       isSNSEvent,
 		"sqs.json":                       isSQSEvent,
 		"lambdaurl.json":                 isLambdaFunctionURLEvent,
+		"step-function.json":             isStepFunctionEvent,
 	}
 	for testFile, testFunc := range testCases {
 		file, err := os.Open(fmt.Sprintf("%v/%v", testDir, testFile))
@@ -65,6 +66,7 @@ func TestEventPayloadParsingWrong(t *testing.T) {
 		"sns.json":                       isSNSEvent,
 		"sqs.json":                       isSQSEvent,
 		"lambdaurl.json":                 isLambdaFunctionURLEvent,
+		"step-function.json":             isStepFunctionEvent,
 	}
 	for correctTestFile, testFunc := range testCases {
 		wrongTestFiles, err := os.ReadDir(testDir)
@@ -106,6 +108,7 @@ func TestGetEventType(t *testing.T) {
 		"sns.json":                       SNSEvent,
 		"sqs.json":                       SQSEvent,
 		"lambdaurl.json":                 LambdaFunctionURLEvent,
+		"step-function.json":             StepFunctionEvent,
 	}
 
 	for testFile, expectedEventType := range testCases {
@@ -31,6 +31,8 @@ const (
 	SecretsInformer InformerName = "secrets"
 	// WebhooksInformer holds the name of the informer
 	WebhooksInformer InformerName = "webhooks"
+	// ValidatingWebhooksInformer holds the name of the informer
+	ValidatingWebhooksInformer InformerName = "validatingwebhooks"
 	// ServicesInformer holds the name of the informer
 	ServicesInformer InformerName = "services"
 )
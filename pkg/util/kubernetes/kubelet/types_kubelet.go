@@ -120,12 +120,14 @@ type Conditions struct {
 
 // ContainerStatus contains fields for unmarshalling a Pod.Status.Containers
 type ContainerStatus struct {
-	Name    string         `json:"name"`
-	Image   string         `json:"image"`
-	ImageID string         `json:"imageID"`
-	ID      string         `json:"containerID"`
-	Ready   bool           `json:"ready"`
-	State   ContainerState `json:"state"`
+	Name         string         `json:"name"`
+	Image        string         `json:"image"`
+	ImageID      string         `json:"imageID"`
+	ID           string         `json:"containerID"`
+	Ready        bool           `json:"ready"`
+	RestartCount int            `json:"restartCount"`
+	State        ContainerState `json:"state"`
+	LastState    ContainerState `json:"lastState"`
 }
 
 // IsPending returns if the container doesn't have an ID
@@ -149,7 +151,8 @@ type ContainerState struct {
 
 // ContainerStateWaiting is a waiting state of a container.
 type ContainerStateWaiting struct {
-	Reason string `json:"reason"`
+	Reason  string `json:"reason"`
+	Message string `json:"message"`
 }
 
 // ContainerStateRunning is a running state of a container.
@@ -160,6 +163,8 @@ type ContainerStateRunning struct {
 // ContainerStateTerminated is a terminated state of a container.
 type ContainerStateTerminated struct {
 	ExitCode   int32     `json:"exitCode"`
+	Reason     string    `json:"reason"`
+	Message    string    `json:"message"`
 	StartedAt  time.Time `json:"startedAt"`
 	FinishedAt time.Time `json:"finishedAt"`
 }
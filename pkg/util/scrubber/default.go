@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+	"sync"
 )
 
 // DefaultScrubber is the scrubber used by the package-level cleaning functions.
@@ -22,6 +23,13 @@ import (
 // See default.go for details of these replacers.
 var DefaultScrubber = &Scrubber{}
 
+var (
+	// strippedKeysReplacers are the replacers added by AddStrippedKeys, they
+	// are also added to the scrubbers initialized by AddDefaultReplacers
+	strippedKeysReplacers      []Replacer
+	strippedKeysReplacersMutex sync.Mutex
+)
+
 func init() {
 	AddDefaultReplacers(DefaultScrubber)
 }
@@ -35,64 +43,77 @@ func AddDefaultReplacers(scrubber *Scrubber) {
 		Regex: regexp.MustCompile(`(api_?key=)\b[a-zA-Z0-9]+([a-zA-Z0-9]{5})\b`),
 		Hints: []string{"api_key", "apikey"},
 		Repl:  []byte(`$1***************************$2`),
+		Name:  "api_key",
 	}
 	hintedAPPKeyReplacer := Replacer{
 		// If hinted, mask the value regardless if it doesn't match 40-char hexadecimal string
 		Regex: regexp.MustCompile(`(ap(?:p|plication)_?key=)\b[a-zA-Z0-9]+([a-zA-Z0-9]{5})\b`),
 		Hints: []string{"app_key", "appkey", "application_key"},
 		Repl:  []byte(`$1***********************************$2`),
+		Name:  "app_key",
 	}
 	hintedBearerReplacer := Replacer{
 		Regex: regexp.MustCompile(`\bBearer [a-fA-F0-9]{59}([a-fA-F0-9]{5})\b`),
 		Hints: []string{"Bearer"},
 		Repl:  []byte(`Bearer ***********************************************************$1`),
+		Name:  "bearer_token",
 	}
 	apiKeyReplacerYAML := Replacer{
 		Regex: regexp.MustCompile(`(\-|\:|,|\[|\{)(\s+)?\b[a-fA-F0-9]{27}([a-fA-F0-9]{5})\b`),
 		Repl:  []byte(`$1$2"***************************$3"`),
+		Name:  "api_key",
 	}
 	apiKeyReplacer := Replacer{
 		Regex: regexp.MustCompile(`\b[a-fA-F0-9]{27}([a-fA-F0-9]{5})\b`),
 		Repl:  []byte(`***************************$1`),
+		Name:  "api_key",
 	}
 	appKeyReplacerYAML := Replacer{
 		Regex: regexp.MustCompile(`(\-|\:|,|\[|\{)(\s+)?\b[a-fA-F0-9]{35}([a-fA-F0-9]{5})\b`),
 		Repl:  []byte(`$1$2"***********************************$3"`),
+		Name:  "app_key",
 	}
 	appKeyReplacer := Replacer{
 		Regex: regexp.MustCompile(`\b[a-fA-F0-9]{35}([a-fA-F0-9]{5})\b`),
 		Repl:  []byte(`***********************************$1`),
+		Name:  "app_key",
 	}
 	// URI Generic Syntax
 	// https://tools.ietf.org/html/rfc3986
 	uriPasswordReplacer := Replacer{
 		Regex: regexp.MustCompile(`([A-Za-z][A-Za-z0-9+-.]+\:\/\/|\b)([^\:]+)\:([^\s]+)\@`),
 		Repl:  []byte(`$1$2:********@`),
+		Name:  "uri_password",
 	}
 	passwordReplacer := Replacer{
 		Regex: matchYAMLKeyPart(`(pass(word)?|pwd)`),
 		Hints: []string{"pass", "pwd"},
 		Repl:  []byte(`$1 "********"`),
+		Name:  "password",
 	}
 	tokenReplacer := Replacer{
 		Regex: matchYAMLKeyEnding(`token`),
 		Hints: []string{"token"},
 		Repl:  []byte(`$1 "********"`),
+		Name:  "token",
 	}
 	snmpReplacer := Replacer{
 		Regex: matchYAMLKey(`(community_string|authKey|privKey|community|authentication_key|privacy_key)`),
 		Hints: []string{"community_string", "authKey", "privKey", "community", "authentication_key", "privacy_key"},
 		Repl:  []byte(`$1 "********"`),
+		Name:  "snmp_credentials",
 	}
 	snmpMultilineReplacer := Replacer{
 		Regex: matchYAMLKeyWithListValue("(community_strings)"),
 		Hints: []string{"community_strings"},
 		Repl:  []byte(`$1 "********"`),
+		Name:  "snmp_credentials",
 	}
 	certReplacer := Replacer{
 		Regex: matchCert(),
 		Hints: []string{"BEGIN"},
 		Repl:  []byte(`********`),
+		Name:  "certificate",
 	}
 	scrubber.AddReplacer(SingleLine, hintedAPIKeyReplacer)
 	scrubber.AddReplacer(SingleLine, hintedAPPKeyReplacer)
@@ -107,6 +128,12 @@ func AddDefaultReplacers(scrubber *Scrubber) {
 	scrubber.AddReplacer(SingleLine, snmpReplacer)
 	scrubber.AddReplacer(MultiLine, snmpMultilineReplacer)
 	scrubber.AddReplacer(MultiLine, certReplacer)
+
+	strippedKeysReplacersMutex.Lock()
+	defer strippedKeysReplacersMutex.Unlock()
+	for _, r := range strippedKeysReplacers {
+		scrubber.AddReplacer(SingleLine, r)
+	}
 }
 
 func matchYAMLKeyPart(part string) *regexp.Regexp {
@@ -198,15 +225,29 @@ func ScrubLine(url string) string {
 	return DefaultScrubber.ScrubLine(url)
 }
 
+// HideKeyExceptLastFiveChars masks a key the way the api_key replacers do, only
+// keeping its last 5 characters. Keys that short are entirely masked.
+func HideKeyExceptLastFiveChars(key string) string {
+	if len(key) <= 5 {
+		return strings.Repeat("*", len(key))
+	}
+	return "***************************" + key[len(key)-5:]
+}
+
 // AddStrippedKeys adds to the set of YAML keys that will be recognized and have
-// their values stripped.  This modifies the DefaultScrubber directly.
+// their values stripped.  This modifies the DefaultScrubber directly, the
+// scrubbers initialized afterwards with AddDefaultReplacers strip them too.
 func AddStrippedKeys(strippedKeys []string) {
 	if len(strippedKeys) > 0 {
 		configReplacer := Replacer{
 			Regex: matchYAMLKey(fmt.Sprintf("(%s)", strings.Join(strippedKeys, "|"))),
 			Hints: strippedKeys,
 			Repl:  []byte(`$1 "********"`),
+			Name:  "stripped_key",
 		}
+		strippedKeysReplacersMutex.Lock()
+		strippedKeysReplacers = append(strippedKeysReplacers, configReplacer)
+		strippedKeysReplacersMutex.Unlock()
 		DefaultScrubber.AddReplacer(SingleLine, configReplacer)
 	}
 }
@@ -413,6 +413,13 @@ func TestYamlConfig(t *testing.T) {
 	AddStrippedKeys([]string{"foobar"})
 
 	assertClean(t, contents, `foobar: "********"`)
+
+	// scrubbers initialized afterwards strip the key too
+	scrubber := New()
+	AddDefaultReplacers(scrubber)
+	cleaned, err = scrubber.ScrubBytes([]byte(contents))
+	assert.Nil(t, err)
+	assert.Equal(t, `foobar: "********"`, string(cleaned))
 }
 
 func TestCertConfig(t *testing.T) {
@@ -525,3 +532,10 @@ func TestBearerToken(t *testing.T) {
 		`AuthBearer 2fe663014abcd1850076f6d68c0355666db98758262870811cace007cd4a62ba`,
 		`AuthBearer 2fe663014abcd1850076f6d68c0355666db98758262870811cace007cd4a62ba`)
 }
+
+func TestHideKeyExceptLastFiveChars(t *testing.T) {
+	assert.Equal(t, "***************************defab", HideKeyExceptLastFiveChars("abcdefabcdefabcdefabcdefabcdefab"))
+	assert.Equal(t, "***************************-key1", HideKeyExceptLastFiveChars("my-other-key1"))
+	assert.Equal(t, "****", HideKeyExceptLastFiveChars("key1"))
+	assert.Equal(t, "", HideKeyExceptLastFiveChars(""))
+}
@@ -34,8 +34,22 @@ type Replacer struct {
 	// ReplFunc, if set, is called with the matched bytes (see regexp#Regexp.ReplaceAllFunc). Only
 	// one of Repl and ReplFunc should be set.
 	ReplFunc func(b []byte) []byte
+	// Name, if given, identifies the kind of information replaced when reporting redactions.  The
+	// Regex is used instead otherwise.
+	Name string
 }
 
+// name returns the name reported for the replacements made by the replacer
+func (r Replacer) name() string {
+	if r.Name != "" {
+		return r.Name
+	}
+	return r.Regex.String()
+}
+
+// Redactions counts the replacements made by a scrubber, per replacer name.
+type Redactions map[string]int
+
 // ReplacerKind modifies how a Replacer is applied
 type ReplacerKind int
 
@@ -98,24 +112,37 @@ func (c *Scrubber) ScrubFile(filePath string) ([]byte, error) {
 		return nil, err
 	}
 	defer file.Close()
-	return c.scrubReader(file)
+	return c.scrubReader(file, nil)
 }
 
 // ScrubBytes scrubs credentials from slice of bytes
 func (c *Scrubber) ScrubBytes(file []byte) ([]byte, error) {
 	r := bytes.NewReader(file)
-	return c.scrubReader(r)
+	return c.scrubReader(r, nil)
+}
+
+// ScrubBytesWithRedactions scrubs credentials from slice of bytes and reports
+// the replacements made
+func (c *Scrubber) ScrubBytesWithRedactions(file []byte) ([]byte, Redactions, error) {
+	redactions := make(Redactions)
+	r := bytes.NewReader(file)
+	cleaned, err := c.scrubReader(r, redactions)
+	if err != nil {
+		return nil, nil, err
+	}
+	return cleaned, redactions, nil
 }
 
 // ScrubLine scrubs credentials from a single line of text.  It can be safely
 // applied to URLs or to strings containing URLs.  It does not run multi-line
 // replacers, and should not be used on multi-line inputs.
 func (c *Scrubber) ScrubLine(message string) string {
-	return string(c.scrub([]byte(message), c.singleLineReplacers))
+	return string(c.scrub([]byte(message), c.singleLineReplacers, nil))
 }
 
-// scrubReader applies the cleaning algorithm to a Reader
-func (c *Scrubber) scrubReader(file io.Reader) ([]byte, error) {
+// scrubReader applies the cleaning algorithm to a Reader, counting the
+// replacements made in redactions if it isn't nil
+func (c *Scrubber) scrubReader(file io.Reader, redactions Redactions) ([]byte, error) {
 	var cleanedFile []byte
 
 	scanner := bufio.NewScanner(file)
@@ -128,7 +155,7 @@ func (c *Scrubber) scrubReader(file io.Reader) ([]byte, error) {
 		if blankRegex.Match(b) {
 			cleanedFile = append(cleanedFile, byte('\n'))
 		} else if !commentRegex.Match(b) {
-			b = c.scrub(b, c.singleLineReplacers)
+			b = c.scrub(b, c.singleLineReplacers, redactions)
 			if !first {
 				cleanedFile = append(cleanedFile, byte('\n'))
 			}
@@ -143,13 +170,14 @@ func (c *Scrubber) scrubReader(file io.Reader) ([]byte, error) {
 	}
 
 	// Then we apply multiline replacers on the cleaned file
-	cleanedFile = c.scrub(cleanedFile, c.multiLineReplacers)
+	cleanedFile = c.scrub(cleanedFile, c.multiLineReplacers, redactions)
 
 	return cleanedFile, nil
 }
 
-// scrub applies the given replacers to the given data.
-func (c *Scrubber) scrub(data []byte, replacers []Replacer) []byte {
+// scrub applies the given replacers to the given data, counting the
+// replacements made in redactions if it isn't nil.
+func (c *Scrubber) scrub(data []byte, replacers []Replacer, redactions Redactions) []byte {
 	for _, repl := range replacers {
 		containsHint := false
 		for _, hint := range repl.Hints {
@@ -159,6 +187,11 @@ func (c *Scrubber) scrub(data []byte, replacers []Replacer) []byte {
 			}
 		}
 		if len(repl.Hints) == 0 || containsHint {
+			if redactions != nil {
+				if matches := len(repl.Regex.FindAllIndex(data, -1)); matches > 0 {
+					redactions[repl.name()] += matches
+				}
+			}
 			if repl.ReplFunc != nil {
 				data = repl.Regex.ReplaceAllFunc(data, repl.ReplFunc)
 			} else {
@@ -137,6 +137,7 @@ func (c *collector) parsePods(pods []*kubelet.Pod) []workloadmeta.CollectorEvent
 			Owners:                     owners,
 			PersistentVolumeClaimNames: pod.GetPersistentVolumeClaimNames(),
 			Containers:                 podContainers,
+			ContainerStatuses:          parseContainerStatuses(pod.Status.GetAllContainers()),
 			Ready:                      kubelet.IsPodReady(pod),
 			Phase:                      pod.Status.Phase,
 			IP:                         pod.Status.PodIP,
@@ -251,6 +252,44 @@ func (c *collector) parsePodContainers(
 	return podContainers, events
 }
 
+func parseContainerStatuses(containerStatuses []kubelet.ContainerStatus) []workloadmeta.KubernetesContainerStatus {
+	statuses := make([]workloadmeta.KubernetesContainerStatus, 0, len(containerStatuses))
+
+	for _, container := range containerStatuses {
+		_, containerID := containers.SplitEntityName(container.ID)
+		status := workloadmeta.KubernetesContainerStatus{
+			Name:            container.Name,
+			ID:              containerID,
+			Ready:           container.Ready,
+			RestartCount:    container.RestartCount,
+			Termination:     parseContainerTermination(container.State.Terminated),
+			LastTermination: parseContainerTermination(container.LastState.Terminated),
+		}
+
+		if st := container.State.Waiting; st != nil {
+			status.WaitingReason = st.Reason
+			status.WaitingMessage = st.Message
+		}
+
+		statuses = append(statuses, status)
+	}
+
+	return statuses
+}
+
+func parseContainerTermination(st *kubelet.ContainerStateTerminated) *workloadmeta.KubernetesContainerTermination {
+	if st == nil {
+		return nil
+	}
+
+	return &workloadmeta.KubernetesContainerTermination{
+		ExitCode:   st.ExitCode,
+		Reason:     st.Reason,
+		Message:    st.Message,
+		FinishedAt: st.FinishedAt,
+	}
+}
+
 func findContainerSpec(name string, specs []kubelet.ContainerSpec) *kubelet.ContainerSpec {
 	for _, spec := range specs {
 		if spec.Name == name {
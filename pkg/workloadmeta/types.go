@@ -352,6 +352,59 @@ func (o OrchestratorContainer) String(_ bool) string {
 	return fmt.Sprintln("Name:", o.Name, "ID:", o.ID)
 }
 
+// KubernetesContainerStatus is the status of a container of a Kubernetes pod
+// as reported by the kubelet. Unlike OrchestratorContainer, it includes
+// containers that haven't been created by the runtime yet.
+type KubernetesContainerStatus struct {
+	Name            string
+	ID              string
+	Ready           bool
+	RestartCount    int
+	WaitingReason   string
+	WaitingMessage  string
+	Termination     *KubernetesContainerTermination
+	LastTermination *KubernetesContainerTermination
+}
+
+// Running returns true if the container is neither waiting nor terminated.
+func (s KubernetesContainerStatus) Running() bool {
+	return s.WaitingReason == "" && s.Termination == nil
+}
+
+// String returns a string representation of KubernetesContainerStatus.
+func (s KubernetesContainerStatus) String(_ bool) string {
+	var sb strings.Builder
+	_, _ = fmt.Fprintln(&sb, "Name:", s.Name, "ID:", s.ID, "Ready:", s.Ready, "Restart Count:", s.RestartCount)
+
+	if s.WaitingReason != "" {
+		_, _ = fmt.Fprintln(&sb, "Waiting:", s.WaitingReason, s.WaitingMessage)
+	}
+
+	if s.Termination != nil {
+		_, _ = fmt.Fprint(&sb, "Terminated: ", s.Termination.String())
+	}
+
+	if s.LastTermination != nil {
+		_, _ = fmt.Fprint(&sb, "Last Terminated: ", s.LastTermination.String())
+	}
+
+	return sb.String()
+}
+
+// KubernetesContainerTermination describes the termination of a container of
+// a Kubernetes pod.
+type KubernetesContainerTermination struct {
+	ExitCode   int32
+	Reason     string
+	Message    string
+	FinishedAt time.Time
+}
+
+// String returns a string representation of KubernetesContainerTermination.
+func (t KubernetesContainerTermination) String() string {
+	return fmt.Sprintln("Exit Code:", t.ExitCode, "Reason:", t.Reason, "Finished At:", t.FinishedAt)
+}
+
 // Container is an Entity representing a containerized workload.
 type Container struct {
 	EntityID
@@ -439,6 +492,7 @@ type KubernetesPod struct {
 	Owners                     []KubernetesPodOwner
 	PersistentVolumeClaimNames []string
 	Containers                 []OrchestratorContainer
+	ContainerStatuses          []KubernetesContainerStatus
 	Ready                      bool
 	Phase                      string
 	IP                         string
@@ -492,6 +546,13 @@ func (p KubernetesPod) String(verbose bool) string {
 		}
 	}
 
+	if verbose && len(p.ContainerStatuses) > 0 {
+		_, _ = fmt.Fprintln(&sb, "----------- Container Statuses -----------")
+		for _, c := range p.ContainerStatuses {
+			_, _ = fmt.Fprint(&sb, c.String(verbose))
+		}
+	}
+
 	_, _ = fmt.Fprintln(&sb, "----------- Pod Info -----------")
 	_, _ = fmt.Fprintln(&sb, "Ready:", p.Ready)
 	_, _ = fmt.Fprintln(&sb, "Phase:", p.Phase)